@@ -5,15 +5,25 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/apikey"
 	"github.com/alexduzi/labcloudrun/internal/client"
 	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	g "github.com/alexduzi/labcloudrun/internal/grpc"
+	"github.com/alexduzi/labcloudrun/internal/history"
 	h "github.com/alexduzi/labcloudrun/internal/http"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/alexduzi/labcloudrun/internal/quota"
+	"github.com/alexduzi/labcloudrun/internal/webhook"
+	"google.golang.org/grpc"
 )
 
 // @title Weather API
@@ -29,6 +39,16 @@ import (
 
 // @BasePath /
 // @schemes http https
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Required when API_KEYS or API_KEYS_FILE is set. It may also be sent in the api_key query parameter
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT as "Bearer <token>", accepted instead of an API key when JWT_JWKS_URL or JWT_KEY_FILE is set. It may also be sent in the access_token query parameter
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -36,19 +56,95 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	slog.Info("Configuration loaded", "port", cfg.Port)
+	slog.Info("Configuration loaded", "port", cfg.Port, "cep_provider", cfg.CepProvider, "weather_provider", cfg.WeatherProvider, "weather_strategy", cfg.WeatherStrategy)
 
 	// Initialize clients with config
-	cepApiApiClient := client.NewCepClient(cfg)
-	weatherApiClient := client.NewWeatherClient(cfg)
+	cepApiApiClient, err := client.NewCepProvider(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize CEP provider: %v", err)
+	}
+
+	// The WeatherAPI quota is only tracked when WeatherAPI is called
+	var weatherQuota *quota.Tracker
+	if slices.Contains(client.WeatherProviders(cfg), client.ProviderWeatherAPI) {
+		if weatherQuota, err = quota.Load(cfg); err != nil {
+			log.Fatalf("Failed to load weatherapi quota: %v", err)
+		}
+	}
+	weatherApiClient, err := client.NewWeatherProvider(cfg, weatherQuota)
+	if err != nil {
+		log.Fatalf("Failed to initialize weather provider: %v", err)
+	}
+
+	var handlerOpts []h.HandlerOption
+	if weatherQuota != nil {
+		handlerOpts = append(handlerOpts, h.WithWeatherQuota(weatherQuota))
+		weatherQuota.Start()
+	}
+	if cfg.GeoMunicipalitiesFile != "" {
+		municipalities, err := geo.LoadFile(cfg.GeoMunicipalitiesFile)
+		if err != nil {
+			log.Fatalf("Failed to load municipalities: %v", err)
+		}
+		handlerOpts = append(handlerOpts, h.WithMunicipalities(municipalities))
+	}
+
+	if cfg.WebhookStorePath != "" {
+		store, err := webhook.OpenStore(cfg.WebhookStorePath)
+		if err != nil {
+			log.Fatalf("Failed to open webhook store: %v", err)
+		}
+		handlerOpts = append(handlerOpts, h.WithWebhookStore(store))
+	}
+
+	if cfg.HistoryStorePath != "" {
+		store, err := history.OpenStore(cfg.HistoryStorePath)
+		if err != nil {
+			log.Fatalf("Failed to open history store: %v", err)
+		}
+		handlerOpts = append(handlerOpts, h.WithHistoryStore(store))
+	}
+
+	apiKeys, err := apikey.Load(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load api keys: %v", err)
+	}
+	if apiKeys != nil {
+		handlerOpts = append(handlerOpts, h.WithAPIKeys(apiKeys))
+		apiKeys.Start()
+	}
+
+	tokens, err := jwtauth.Load(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load jwt settings: %v", err)
+	}
+	if tokens != nil {
+		handlerOpts = append(handlerOpts, h.WithBearerTokens(tokens))
+	}
 
 	// Initialize HTTP handler
-	h := h.NewHttpHandler(cfg, cepApiApiClient, weatherApiClient)
+	h := h.NewHttpHandler(cfg, cepApiApiClient, weatherApiClient, handlerOpts...)
 
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Port),
 		Handler: h.SetupRouter().Handler(),
 	}
+	srv.RegisterOnShutdown(h.CloseStreams)
+
+	webhooks := h.Webhooks()
+	if webhooks != nil {
+		webhooks.Start()
+	}
+
+	recorder := h.History()
+	if recorder != nil {
+		recorder.Start()
+	}
+
+	prewarm := h.Prewarm()
+	if prewarm != nil {
+		prewarm.Start()
+	}
 
 	go func() {
 		slog.Info("server starting at", "addr", srv.Addr)
@@ -58,6 +154,25 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	if cfg.GrpcEnabled {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GrpcPort))
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+
+		// gRPC shares the handler's service, so both APIs use the same clients
+		grpcServer = g.NewServer(h.Service(), apiKeys, tokens)
+
+		go func() {
+			slog.Info("grpc server starting at", "addr", listener.Addr().String())
+
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Error("grpc server failed to start", "err", err)
+			}
+		}()
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
@@ -67,9 +182,53 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if prewarm != nil {
+		if err := prewarm.Stop(ctx); err != nil {
+			slog.Error("pre-warm jobs did not stop in time", "err", err)
+		}
+	}
+
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("server forced to shutdown", "err", err)
 	} else {
 		slog.Info("server gracefully stopped")
 	}
+
+	if webhooks != nil {
+		if err := webhooks.Close(); err != nil {
+			slog.Error("webhook store failed to close", "err", err)
+		}
+	}
+
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			slog.Error("history store failed to close", "err", err)
+		}
+	}
+
+	if apiKeys != nil {
+		if err := apiKeys.Close(); err != nil {
+			slog.Error("api key usage failed to save", "err", err)
+		}
+	}
+
+	if weatherQuota != nil {
+		if err := weatherQuota.Close(); err != nil {
+			slog.Error("weatherapi quota usage failed to save", "err", err)
+		}
+	}
 }
@@ -0,0 +1,87 @@
+// Command cepindex builds the binary CEP index used by CEP_PROVIDER=offline
+// and CEP_PROVIDER=tiered from a CSV export.
+//
+// Usage:
+//
+//	go run ./cmd/cepindex -in ceps.csv -out ceps.idx [-delimiter ';']
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/alexduzi/labcloudrun/internal/cepindex"
+)
+
+func main() {
+	in := flag.String("in", "", "CSV export with a header row and at least a cep column")
+	out := flag.String("out", "", "path of the index file to write")
+	delimiter := flag.String("delimiter", ",", "CSV field delimiter")
+	flag.Parse()
+
+	if *in == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	comma, size := utf8.DecodeRuneInString(*delimiter)
+	if size == 0 || size != len(*delimiter) {
+		log.Fatalf("delimiter must be a single character, got %q", *delimiter)
+	}
+
+	count, err := build(*in, *out, comma)
+	if err != nil {
+		log.Fatalf("Failed to build CEP index: %v", err)
+	}
+
+	log.Printf("Wrote %d CEPs to %s", count, *out)
+}
+
+func build(in, out string, comma rune) (int, error) {
+	source, err := os.Open(in)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	records, err := cepindex.ReadCSV(bufio.NewReader(source), comma)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", in, err)
+	}
+
+	// Write next to the target and rename, so a running service never sees a
+	// half-written index
+	tmp, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	if err := cepindex.Write(writer, records); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), out); err != nil {
+		return 0, err
+	}
+
+	return len(records), nil
+}
@@ -23,19 +23,188 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/temperature/{cep}": {
+        "/admin/api-keys/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the API keys, in configuration order, with their routes, quotas and the requests counted in the current UTC day and month. Secrets are not included. Requires an admin key.",
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the usage of the API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.APIKeyUsage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "api key required or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "api key not allowed on this route",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "api keys are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cep/search": {
             "get": {
-                "description": "Get temperature information by Brazilian postal code (CEP)",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find the CEPs of a street through ViaCEP's reverse lookup. City and street need at least 3 characters.\nWith ?expand=temperature each result also carries its city's current temperature.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "cep"
+                ],
+                "summary": "Search CEPs by address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "SP",
+                        "description": "State abbreviation (UF)",
+                        "name": "uf",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "São Paulo",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "Paulista",
+                        "description": "Street name, or part of it",
+                        "name": "street",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Results per page (max 50)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "temperature",
+                        "description": "Comma separated extra sections to include (temperature)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AddressSearchResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid uf, city or street, or invalid pagination",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "address search is not available offline (CEP_PROVIDER=offline)",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cep/{cep}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validate a Brazilian postal code (CEP) and return its normalized address, without looking up the weather.\nResponses carry Cache-Control and ETag headers; send If-None-Match to get 304 Not Modified.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
                 "tags": [
-                    "weather"
+                    "cep"
                 ],
-                "summary": "Get Temperature by CEP",
+                "summary": "Get address by CEP",
                 "parameters": [
                     {
                         "type": "string",
@@ -44,23 +213,55 @@ const docTemplate = `{
                         "name": "cep",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Temperature in Celsius, Fahrenheit and Kelvin",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.TemperatureResponse"
+                            "$ref": "#/definitions/model.Address"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=\u003cCEP_CACHE_MAX_AGE\u003e"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the address"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "404": {
                         "description": "can not find zipcode",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
                     "422": {
-                        "description": "invalid zipcode",
+                        "description": "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -68,83 +269,1539 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/api/v1/cep/{cep}/region": {
             "get": {
-                "description": "Check if the service is healthy and running",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolve the UF and region that own a Brazilian postal code (CEP) range, without calling ViaCEP",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "health"
+                    "cep"
+                ],
+                "summary": "Get UF and region by CEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "01310100",
+                        "description": "Brazilian postal code (CEP)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Health Check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.StatusResponse"
+                            "$ref": "#/definitions/model.CepRegionResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/readiness": {
-            "get": {
-                "description": "Check if the service is ready to accept traffic",
+        "/api/v1/convert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Convert a value into one or more units of the same quantity.\ntemperature: C, F, K, Ra (Rankine), Re (Réaumur); speed: kph, mph, m/s, kn, bft (Beaufort force 0-12); pressure: mb, hPa, inHg, mmHg; precipitation: mm, in.\nUnits are case insensitive and accept names and common spellings (celsius, km/h, knots). Values that are physically impossible, such as temperatures below absolute zero or negative speeds, are rejected.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "health"
+                    "conversion"
+                ],
+                "summary": "Convert units",
+                "parameters": [
+                    {
+                        "description": "Value, source unit and target units",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ConvertRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Readiness Check",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Value in each target unit",
                         "schema": {
-                            "$ref": "#/definitions/model.StatusResponse"
+                            "$ref": "#/definitions/model.ConvertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "unknown unit, incompatible units or value out of range",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "invalid zipcode"
-                }
-            }
         },
-        "model.StatusResponse": {
-            "type": "object",
-            "properties": {
-                "service": {
-                    "type": "string",
-                    "example": "lab-cloudrun-api"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "healthy"
-                },
-                "timestamp": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+        "/api/v1/observations/{cep}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the weather observations recorded for the city of a CEP, oldest first. Every observation fetched from the weather providers is recorded, with the provider that returned it, and kept for HISTORY_RETENTION, at most HISTORY_MAX_PER_LOCATION per location.\nfrom and to are RFC 3339 times matched against the observation time; to defaults to now and from to 24 hours before to.",
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get the observation history of a CEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "01001000",
+                        "description": "CEP",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-09T17:30:00Z",
+                        "description": "Start of the period, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-01-10T17:30:00Z",
+                        "description": "End of the period, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ObservationHistoryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "can not find zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid zipcode or period",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "observation history is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.TemperatureResponse": {
-            "type": "object",
+        "/api/v1/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the webhook subscriptions of the caller's API key or token, oldest first, with the temperature of their last check. Secrets are not included.",
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Subscription"
+                            }
+                        }
+                    },
+                    "501": {
+                        "description": "webhook subscriptions are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a webhook called when the temperature at the city of a CEP meets a condition: above or below threshold °C (notified when the condition starts to hold, including on the first check), or delta, a change of at least threshold °C since the last notification.\nConditions are checked every WEBHOOK_EVAL_INTERVAL. Each delivery is a POST of model.WebhookPayload signed with the returned secret: X-Webhook-Signature is sha256= and the hex HMAC-SHA256 of X-Webhook-Timestamp, a dot and the body.\nFailed deliveries are retried with exponential backoff up to WEBHOOK_MAX_ATTEMPTS times, then listed in /api/v1/subscriptions/dead-letters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Subscribe to a temperature condition",
+                "parameters": [
+                    {
+                        "description": "CEP, condition, threshold in °C and webhook URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription, with the secret that signs its deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "can not find zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "subscription limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid zipcode, condition or url",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "webhook subscriptions are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the deliveries of the caller's subscriptions given up after their last attempt, oldest first, with the payload, the number of attempts and the last error. Only the latest 1000 are kept.",
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List failed webhook deliveries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeadLetter"
+                            }
+                        }
+                    },
+                    "501": {
+                        "description": "webhook subscriptions are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "404": {
+                        "description": "subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "webhook subscriptions are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Delete a subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "webhook subscriptions are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/temperature": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get temperature information by latitude and longitude. Coordinates must be inside the configured area (GEO_BOUNDING_BOX, Brazil by default).\nWith ?expand=municipality the response also carries the nearest municipality and its CEP prefix; ?expand=providers, comfort and condition work as in the CEP route.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get Temperature by coordinates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "example": -23.5614,
+                        "description": "Latitude in decimal degrees",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": -46.6559,
+                        "description": "Longitude in decimal degrees",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "municipality",
+                        "description": "Comma separated extra sections to include (providers, comfort, condition, municipality)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Temperature in Celsius, Fahrenheit and Kelvin",
+                        "schema": {
+                            "$ref": "#/definitions/model.TemperatureResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid coordinates, or coordinates outside the supported area",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/temperature/city/{uf}/{city}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get temperature information by municipality name and UF. Names are matched ignoring accents, case and hyphens (\"sao jose dos campos\" finds \"São José dos Campos\").\nAn unknown name answers 404 with suggestions pointing at this route.\n?expand= accepts providers, comfort, condition and municipality, as in the coordinates route.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get Temperature by city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "SP",
+                        "description": "Brazilian state (UF)",
+                        "name": "uf",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "Campinas",
+                        "description": "Municipality name",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "municipality",
+                        "description": "Comma separated extra sections to include (providers, comfort, condition, municipality)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Temperature in Celsius, Fahrenheit and Kelvin",
+                        "schema": {
+                            "$ref": "#/definitions/model.TemperatureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "can not find city",
+                        "schema": {
+                            "$ref": "#/definitions/model.CityLookupResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid uf",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/temperature/{cep}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get temperature information by Brazilian postal code (CEP).\nWith ?expand=providers the response also carries the source and each weather provider's contribution (see model.ExpandedTemperatureResponse).\nWith ?expand=comfort it carries heat index, wind chill, humidex, dew point and an approximate WBGT computed from temperature, humidity and wind.\nWith ?expand=condition it carries the sky condition, described in the negotiated language.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/xml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get Temperature by CEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "01310100",
+                        "description": "Brazilian postal code (CEP)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "providers",
+                        "description": "Comma separated extra sections to include (providers, comfort, condition)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Temperature in Celsius, Fahrenheit and Kelvin",
+                        "schema": {
+                            "$ref": "#/definitions/model.TemperatureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "can not find zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "none of the Accept media types is supported",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/temperature/{cep}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams the temperature at the city of a CEP as Server-Sent Events, or as WebSocket messages when the request is a WebSocket upgrade.\nA ` + "`" + `temperature` + "`" + ` event is sent on connect and then only when the observation changes; the city is polled every STREAM_POLL_INTERVAL by a single poller shared by all its subscribers. An ` + "`" + `error` + "`" + ` event reports a failed poll, and a ` + "`" + `heartbeat` + "`" + ` event is sent every STREAM_HEARTBEAT_INTERVAL.\nOver WebSocket each message is ` + "`" + `{\"type\": \"\u003cevent\u003e\", \"data\": {...}}` + "`" + `.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Live temperature by CEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "01310100",
+                        "description": "Brazilian postal code (CEP)",
+                        "name": "cep",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "temperature events; error events carry a model.ErrorResponse and heartbeat events a model.StreamHeartbeat",
+                        "schema": {
+                            "$ref": "#/definitions/model.TemperatureUpdate"
+                        }
+                    },
+                    "404": {
+                        "description": "can not find zipcode",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "server is shutting down",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Query the address, current weather, daily forecast and alerts of CEPs in one round trip, for example ` + "`" + `{ location(cep: \"01001000\") { address { city uf } current { temperature { celsius } } forecast(days: 3) { days { date max { celsius } } } alerts { event severity } } }` + "`" + `.\nSend a JSON array of requests to run them as a batch: the response is an array in the same order, and upstream lookups are shared by the whole batch. GET accepts query, operationName and variables (JSON) as query parameters.\nQueries deeper than GRAPHQL_MAX_DEPTH or more complex than GRAPHQL_MAX_COMPLEXITY are rejected before running: location, current, forecast and alerts cost 10, other fields 1, and forecast's selection counts once per day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "GraphQL endpoint",
+                "parameters": [
+                    {
+                        "description": "GraphQL request, or a JSON array of them",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "pt-BR",
+                        "description": "Response language: en (default), pt-BR or es",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result, or an array of results for a batch",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "malformed request or batch larger than GRAPHQL_MAX_BATCH",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the service is healthy and running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health Check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Prometheus metrics: Go runtime and process metrics and, with the WeatherAPI quota guard, the quota usage in the current billing period",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Metrics",
+                "responses": {
+                    "200": {
+                        "description": "metrics in the Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readiness": {
+            "get": {
+                "description": "Check if the service is ready to accept traffic. With the WeatherAPI quota guard the response carries the quota usage, and the status is degraded past its soft limit; the service still takes traffic then",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness Check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StatusResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.ErrorLocation"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "can not find zipcode"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graphql.ErrorLocation": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "graphql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ location(cep: \"01001000\") { address { city uf } current { temperature { celsius } } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
+        },
+        "model.APIKeyUsage": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "daily_quota": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "daily_used": {
+                    "type": "integer",
+                    "example": 312
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:32:11Z"
+                },
+                "monthly_quota": {
+                    "type": "integer",
+                    "example": 20000
+                },
+                "monthly_used": {
+                    "type": "integer",
+                    "example": 4821
+                },
+                "name": {
+                    "type": "string",
+                    "example": "partner"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "/api/v1/temperature/*"
+                    ]
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 18230
+                }
+            }
+        },
+        "model.Address": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "complement": {
+                    "type": "string",
+                    "example": "de 612 a 1510 - lado par"
+                },
+                "ddd": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "ibge": {
+                    "type": "string",
+                    "example": "3550308"
+                },
+                "neighborhood": {
+                    "type": "string",
+                    "example": "Bela Vista"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Sudeste"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Avenida Paulista"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.AddressSearchResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AddressSearchResult"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 23
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.AddressSearchResult": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "complement": {
+                    "type": "string",
+                    "example": "de 612 a 1510 - lado par"
+                },
+                "ddd": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "ibge": {
+                    "type": "string",
+                    "example": "3550308"
+                },
+                "neighborhood": {
+                    "type": "string",
+                    "example": "Bela Vista"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Sudeste"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Avenida Paulista"
+                },
+                "temperature": {
+                    "$ref": "#/definitions/model.TemperatureResponse"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.CepRegionResponse": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Sudeste"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.CityLookupResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "can not find city"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CitySuggestion"
+                    }
+                }
+            }
+        },
+        "model.CitySuggestion": {
+            "type": "object",
+            "properties": {
+                "ibge": {
+                    "type": "string",
+                    "example": "3509502"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Campinas"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/api/v1/temperature/city/SP/Campinas"
+                }
+            }
+        },
+        "model.Condition": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 1003
+                },
+                "is_day": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Partly cloudy"
+                }
+            }
+        },
+        "model.ConvertRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to",
+                "value"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "C"
+                },
+                "to": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "F",
+                        "K"
+                    ]
+                },
+                "value": {
+                    "type": "number",
+                    "example": -40
+                }
+            }
+        },
+        "model.ConvertResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "C"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "temperature"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ConvertedValue"
+                    }
+                },
+                "value": {
+                    "type": "number",
+                    "example": -40
+                }
+            }
+        },
+        "model.ConvertedValue": {
+            "type": "object",
+            "properties": {
+                "unit": {
+                    "type": "string",
+                    "example": "F"
+                },
+                "value": {
+                    "type": "number",
+                    "example": -40
+                }
+            }
+        },
+        "model.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "failed_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:36:02Z"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "status 503"
+                },
+                "payload": {
+                    "$ref": "#/definitions/model.WebhookPayload"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/temperature"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "invalid zipcode"
+                }
+            }
+        },
+        "model.ObservationHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01001-000"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-01-09T17:30:00Z"
+                },
+                "observations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RecordedObservation"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-01-10T17:30:00Z"
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.ObservationLocation": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "Brazil"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": -23.5475
+                },
+                "lon": {
+                    "type": "number",
+                    "example": -46.6361
+                },
+                "name": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
+                }
+            }
+        },
+        "model.RecordedObservation": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "$ref": "#/definitions/model.Condition"
+                },
+                "fetched_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:32:11Z"
+                },
+                "humidity_pct": {
+                    "type": "number",
+                    "example": 36
+                },
+                "location": {
+                    "$ref": "#/definitions/model.ObservationLocation"
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:30:00Z"
+                },
+                "precip_mm": {
+                    "type": "number",
+                    "example": 0.02
+                },
+                "pressure_mb": {
+                    "type": "number",
+                    "example": 1015
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "weatherapi"
+                },
+                "temp_C": {
+                    "type": "number",
+                    "example": 28.5
+                },
+                "wind": {
+                    "$ref": "#/definitions/model.Wind"
+                }
+            }
+        },
+        "model.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string",
+                    "example": "lab-cloudrun-api"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "upstream_quota": {
+                    "$ref": "#/definitions/model.UpstreamQuota"
+                }
+            }
+        },
+        "model.Subscription": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "above"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:30:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f0c3a9e2b7d4c18"
+                },
+                "last_checked_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:35:00Z"
+                },
+                "last_notified_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:35:00Z"
+                },
+                "last_temp_C": {
+                    "type": "number",
+                    "example": 7.5
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_6b1f0e8d5c4a39271e0f8d7c6b5a4938"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 8
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/temperature"
+                }
+            }
+        },
+        "model.SubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "cep",
+                "condition",
+                "threshold",
+                "url"
+            ],
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310100"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "above",
+                        "below",
+                        "delta"
+                    ],
+                    "example": "above"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 8
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/temperature"
+                }
+            }
+        },
+        "model.TemperatureResponse": {
+            "type": "object",
+            "properties": {
+                "temp_C": {
+                    "type": "number",
+                    "example": 28.5
+                },
+                "temp_F": {
+                    "type": "number",
+                    "example": 83.3
+                },
+                "temp_K": {
+                    "type": "number",
+                    "example": 301.65
+                }
+            }
+        },
+        "model.TemperatureUpdate": {
+            "type": "object",
             "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "humidity_pct": {
+                    "type": "number",
+                    "example": 36
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:30:00Z"
+                },
                 "temp_C": {
                     "type": "number",
                     "example": 28.5
@@ -156,9 +1813,126 @@ const docTemplate = `{
                 "temp_K": {
                     "type": "number",
                     "example": 301.65
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.UpstreamQuota": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "period_end": {
+                    "type": "string",
+                    "example": "2026-02-01T00:00:00Z"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "weatherapi"
+                },
+                "soft_limit": {
+                    "type": "integer",
+                    "example": 800000
+                },
+                "state": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "used": {
+                    "type": "integer",
+                    "example": 412093
+                }
+            }
+        },
+        "model.WebhookPayload": {
+            "type": "object",
+            "properties": {
+                "cep": {
+                    "type": "string",
+                    "example": "01310-100"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "São Paulo"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "above"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "temperature.above"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9d2e7b1c4a5f3e60"
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2026-01-10T17:30:00Z"
+                },
+                "previous_temp_C": {
+                    "type": "number",
+                    "example": 7.5
+                },
+                "subscription_id": {
+                    "type": "string",
+                    "example": "5f0c3a9e2b7d4c18"
+                },
+                "temp_C": {
+                    "type": "number",
+                    "example": 8.4
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 8
+                },
+                "uf": {
+                    "type": "string",
+                    "example": "SP"
+                }
+            }
+        },
+        "model.Wind": {
+            "type": "object",
+            "properties": {
+                "direction_deg": {
+                    "type": "number",
+                    "example": 309
+                },
+                "gust_kph": {
+                    "type": "number",
+                    "example": 10.7
+                },
+                "speed_kph": {
+                    "type": "number",
+                    "example": 8.6
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Required when API_KEYS or API_KEYS_FILE is set. It may also be sent in the api_key query parameter",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT as \"Bearer \u003ctoken\u003e\", accepted instead of an API key when JWT_JWKS_URL or JWT_KEY_FILE is set. It may also be sent in the access_token query parameter",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
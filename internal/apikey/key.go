@@ -0,0 +1,141 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+)
+
+// minKeyLength keeps guessable keys out of the configuration
+const minKeyLength = 16
+
+var (
+	ErrMissing       = errors.New("api key required")
+	ErrInvalid       = errors.New("invalid api key")
+	ErrForbidden     = errors.New("api key not allowed on this route")
+	ErrQuotaExceeded = errors.New("api key quota exceeded")
+)
+
+// Key is an API key with the routes it may call and its quotas. Routes are
+// gin route paths, such as /api/v1/temperature/:cep, or gRPC methods; a
+// trailing * matches any suffix and no routes means every route. Zero quotas
+// are unlimited. Only admin keys may call the admin routes
+type Key struct {
+	Name         string
+	Secret       string
+	Routes       []string
+	DailyQuota   int
+	MonthlyQuota int
+	Admin        bool
+}
+
+// fileKey is a key as written in API_KEYS_FILE. Quotas left out take
+// API_KEY_DAILY_QUOTA and API_KEY_MONTHLY_QUOTA
+type fileKey struct {
+	Name         string   `json:"name"`
+	Key          string   `json:"key"`
+	Routes       []string `json:"routes"`
+	DailyQuota   *int     `json:"daily_quota"`
+	MonthlyQuota *int     `json:"monthly_quota"`
+	Admin        bool     `json:"admin"`
+}
+
+// Allows reports whether the key may call route
+func (k Key) Allows(route string) bool {
+	return len(k.Routes) == 0 || MatchRoute(k.Routes, route)
+}
+
+// MatchRoute reports whether route is one of patterns, where a trailing *
+// matches any suffix
+func MatchRoute(patterns []string, route string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			return strings.HasPrefix(route, prefix)
+		}
+		return route == pattern
+	})
+}
+
+// loadKeys reads the keys of API_KEYS, with the default quotas and every
+// route, and those of API_KEYS_FILE
+func loadKeys(cfg *config.Config) ([]Key, error) {
+	var keys []Key
+
+	names := make([]string, 0, len(cfg.APIKeys))
+	for name := range cfg.APIKeys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		keys = append(keys, Key{
+			Name:         name,
+			Secret:       cfg.APIKeys[name],
+			DailyQuota:   cfg.APIKeyDailyQuota,
+			MonthlyQuota: cfg.APIKeyMonthlyQuota,
+		})
+	}
+
+	if cfg.APIKeysFile != "" {
+		data, err := os.ReadFile(cfg.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("read api keys: %w", err)
+		}
+
+		var fileKeys []fileKey
+		if err := json.Unmarshal(data, &fileKeys); err != nil {
+			return nil, fmt.Errorf("parse api keys %s: %w", cfg.APIKeysFile, err)
+		}
+
+		for _, fk := range fileKeys {
+			key := Key{
+				Name:         fk.Name,
+				Secret:       fk.Key,
+				Routes:       fk.Routes,
+				DailyQuota:   cfg.APIKeyDailyQuota,
+				MonthlyQuota: cfg.APIKeyMonthlyQuota,
+				Admin:        fk.Admin,
+			}
+			if fk.DailyQuota != nil {
+				key.DailyQuota = *fk.DailyQuota
+			}
+			if fk.MonthlyQuota != nil {
+				key.MonthlyQuota = *fk.MonthlyQuota
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, validateKeys(keys)
+}
+
+// validateKeys checks that names and secrets are set and unique, secrets
+// are long enough and quotas are not negative
+func validateKeys(keys []Key) error {
+	names := make(map[string]bool, len(keys))
+	secrets := make(map[string]bool, len(keys))
+
+	for _, key := range keys {
+		switch {
+		case key.Name == "":
+			return errors.New("api key without a name")
+		case names[key.Name]:
+			return fmt.Errorf("api key %q: duplicated name", key.Name)
+		case len(key.Secret) < minKeyLength:
+			return fmt.Errorf("api key %q: key must have at least %d characters", key.Name, minKeyLength)
+		case secrets[key.Secret]:
+			return fmt.Errorf("api key %q: key already used by another name", key.Name)
+		case key.DailyQuota < 0 || key.MonthlyQuota < 0:
+			return fmt.Errorf("api key %q: quotas must not be negative", key.Name)
+		}
+
+		names[key.Name] = true
+		secrets[key.Secret] = true
+	}
+	return nil
+}
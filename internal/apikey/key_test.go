@@ -0,0 +1,115 @@
+package apikey
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []string
+		route  string
+		want   bool
+	}{
+		{name: "no routes allow every route", route: "/api/v1/convert", want: true},
+		{name: "exact route", routes: []string{"/api/v1/temperature/:cep"}, route: "/api/v1/temperature/:cep", want: true},
+		{name: "exact route does not match others", routes: []string{"/api/v1/temperature/:cep"}, route: "/api/v1/temperature/:cep/stream", want: false},
+		{name: "prefix route", routes: []string{"/api/v1/temperature*"}, route: "/api/v1/temperature/city/:uf/:city", want: true},
+		{name: "gRPC method", routes: []string{"/weather.v1.WeatherService/*"}, route: "/weather.v1.WeatherService/GetForecast", want: true},
+		{name: "route not listed", routes: []string{"/api/v1/cep/*", "/graphql"}, route: "/api/v1/convert", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			key := Key{Name: "partner", Routes: tt.routes}
+
+			// act & assert
+			assert.Equal(t, tt.want, key.Allows(tt.route))
+		})
+	}
+}
+
+func TestLoadKeys_EnvAndFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "partner", "key": "partner-key-0123456789", "routes": ["/api/v1/temperature/*"], "daily_quota": 10},
+		{"name": "ops", "key": "ops-key-0123456789abc", "admin": true, "daily_quota": 0, "monthly_quota": 0}
+	]`), 0o600))
+	cfg := &config.Config{
+		APIKeys:            map[string]string{"web": "web-key-0123456789ab", "mobile": "mobile-key-0123456789"},
+		APIKeysFile:        path,
+		APIKeyDailyQuota:   1000,
+		APIKeyMonthlyQuota: 20000,
+	}
+
+	// act
+	keys, err := loadKeys(cfg)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []Key{
+		{Name: "mobile", Secret: "mobile-key-0123456789", DailyQuota: 1000, MonthlyQuota: 20000},
+		{Name: "web", Secret: "web-key-0123456789ab", DailyQuota: 1000, MonthlyQuota: 20000},
+		{Name: "partner", Secret: "partner-key-0123456789", Routes: []string{"/api/v1/temperature/*"}, DailyQuota: 10, MonthlyQuota: 20000},
+		{Name: "ops", Secret: "ops-key-0123456789abc", Admin: true},
+	}, keys)
+}
+
+func TestLoadKeys_FileErrors(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"name":`), 0o600))
+
+	for name, path := range map[string]string{
+		"missing file": filepath.Join(dir, "missing.json"),
+		"invalid json": invalid,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// act
+			_, err := loadKeys(&config.Config{APIKeysFile: path})
+
+			// assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateKeys(t *testing.T) {
+	valid := Key{Name: "web", Secret: "web-key-0123456789ab"}
+
+	tests := []struct {
+		name    string
+		keys    []Key
+		wantErr string
+	}{
+		{name: "valid", keys: []Key{valid}},
+		{name: "no name", keys: []Key{{Secret: valid.Secret}}, wantErr: "without a name"},
+		{name: "duplicated name", keys: []Key{valid, {Name: "web", Secret: "another-key-0123456789"}}, wantErr: "duplicated name"},
+		{name: "short secret", keys: []Key{{Name: "web", Secret: "short"}}, wantErr: "at least 16 characters"},
+		{name: "reused secret", keys: []Key{valid, {Name: "mobile", Secret: valid.Secret}}, wantErr: "already used"},
+		{name: "negative quota", keys: []Key{{Name: "web", Secret: valid.Secret, DailyQuota: -1}}, wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			err := validateKeys(tt.keys)
+
+			// assert
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"sync"
+	"time"
+)
+
+// Meter counts the requests of callers that are not configured keys, such
+// as bearer token subjects, against daily and monthly quotas shared by all
+// of them. Usage is kept in memory only
+type Meter struct {
+	mu      sync.Mutex
+	daily   int
+	monthly int
+	usage   map[string]*counters
+	month   string
+	now     func() time.Time
+}
+
+// NewMeter builds a meter with the daily and monthly quotas; 0 is unlimited
+func NewMeter(daily, monthly int) *Meter {
+	return &Meter{
+		daily:   daily,
+		monthly: monthly,
+		usage:   make(map[string]*counters),
+		now:     time.Now,
+	}
+}
+
+// AllowN counts n requests of name against the quotas, as Registry.AllowN
+// does for keys. When a month starts the callers of the previous one are
+// forgotten, so the usage does not grow with every name ever seen
+func (m *Meter) AllowN(name string, n int) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now().UTC()
+	if month := now.Format("2006-01"); month != m.month {
+		for other, usage := range m.usage {
+			if usage.Month != month {
+				delete(m.usage, other)
+			}
+		}
+		m.month = month
+	}
+
+	usage, ok := m.usage[name]
+	if !ok {
+		usage = &counters{}
+		m.usage[name] = usage
+	}
+
+	decision, err := usage.charge(m.daily, m.monthly, n, now)
+	if usage.Monthly == 0 {
+		delete(m.usage, name)
+	}
+	return decision, err
+}
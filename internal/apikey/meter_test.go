@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeter_AllowN(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	meter := NewMeter(2, 3)
+	meter.now = func() time.Time { return now }
+
+	// act
+	first, firstErr := meter.AllowN("web-app", 2)
+	_, exceededErr := meter.AllowN("web-app", 1)
+	other, otherErr := meter.AllowN("mobile-app", 1)
+
+	// assert
+	require.NoError(t, firstErr)
+	assert.Equal(t, Decision{Limit: 2, Remaining: 0, Reset: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}, first)
+	assert.ErrorIs(t, exceededErr, ErrQuotaExceeded)
+	require.NoError(t, otherErr)
+	assert.Equal(t, 1, other.Remaining, "each name has its own quotas")
+}
+
+func TestMeter_ForgetsThePreviousMonth(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	meter := NewMeter(0, 3)
+	meter.now = func() time.Time { return now }
+	_, err := meter.AllowN("web-app", 3)
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+
+	// act
+	_, checkErr := meter.AllowN("mobile-app", 0)
+
+	// assert
+	require.NoError(t, checkErr)
+	assert.Empty(t, meter.usage)
+}
@@ -0,0 +1,298 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/atomicfile"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// counters is the usage of a key, as persisted in API_KEY_USAGE_FILE. Day
+// and Month name the UTC windows Daily and Monthly count
+type counters struct {
+	Day        string     `json:"day"`
+	Daily      int        `json:"daily"`
+	Month      string     `json:"month"`
+	Monthly    int        `json:"monthly"`
+	Total      int64      `json:"total"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type entry struct {
+	key   Key
+	usage counters
+}
+
+// Decision is the quota window closest to running out after a request:
+// its limit, the requests left and when it resets. Limit is zero when the
+// key has no quota
+type Decision struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Registry authenticates API keys and counts their requests against their
+// quotas. Usage is kept in memory and, with a usage file, saved every
+// interval and on Close
+type Registry struct {
+	mu      sync.Mutex
+	entries []*entry
+	bySum   map[string]*entry
+	dirty   bool
+	now     func() time.Time
+
+	usagePath string
+	interval  time.Duration
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Load builds the registry from API_KEYS and API_KEYS_FILE, restoring the
+// usage saved in API_KEY_USAGE_FILE. It returns nil when no key is
+// configured, leaving the API open
+func Load(cfg *config.Config) (*Registry, error) {
+	keys, err := loadKeys(cfg)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	registry := NewRegistry(keys)
+	registry.usagePath = cfg.APIKeyUsageFile
+	registry.interval = cfg.APIKeyUsageFlushInterval
+
+	if registry.usagePath != "" {
+		if err := registry.restore(); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// NewRegistry builds a registry of keys without persistence
+func NewRegistry(keys []Key) *Registry {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	registry := &Registry{
+		bySum:  make(map[string]*entry, len(keys)),
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, key := range keys {
+		e := &entry{key: key}
+		registry.entries = append(registry.entries, e)
+		registry.bySum[checksum(key.Secret)] = e
+	}
+	return registry
+}
+
+// checksum indexes the keys, so looking one up does not compare secrets
+// byte by byte
+func checksum(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate returns the key whose secret is secret
+func (r *Registry) Authenticate(secret string) (Key, error) {
+	if secret == "" {
+		return Key{}, ErrMissing
+	}
+
+	e, ok := r.bySum[checksum(secret)]
+	if !ok {
+		return Key{}, ErrInvalid
+	}
+	return e.key, nil
+}
+
+// Allow checks that key may call route and counts the request against its
+// quotas. Rejected requests are not counted
+func (r *Registry) Allow(key Key, route string) (Decision, error) {
+	return r.AllowN(key, route, 1)
+}
+
+// AllowN is Allow for a call worth n requests, such as a batch of n
+// lookups. It is rejected unless all n fit in the quotas, and with n zero
+// only checks the key and the route
+func (r *Registry) AllowN(key Key, route string, n int) (Decision, error) {
+	if !key.Allows(route) {
+		return Decision{}, ErrForbidden
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.bySum[checksum(key.Secret)]
+	if !ok {
+		return Decision{}, ErrInvalid
+	}
+
+	decision, err := e.usage.charge(e.key.DailyQuota, e.key.MonthlyQuota, n, r.now().UTC())
+	if err == nil && n > 0 {
+		r.dirty = true
+	}
+	return decision, err
+}
+
+// charge counts n requests against the daily and monthly quotas, unless
+// they do not all fit
+func (c *counters) charge(daily, monthly, n int, now time.Time) (Decision, error) {
+	c.roll(now)
+
+	decision := c.decision(daily, monthly, now)
+	if n == 0 {
+		return decision, nil
+	}
+	if decision.Limit > 0 && decision.Remaining < n {
+		return decision, ErrQuotaExceeded
+	}
+
+	c.Daily += n
+	c.Monthly += n
+	c.Total += int64(n)
+	c.LastUsedAt = &now
+
+	return c.decision(daily, monthly, now), nil
+}
+
+// roll starts new windows when the day or the month changed
+func (c *counters) roll(now time.Time) {
+	if day := now.Format(time.DateOnly); c.Day != day {
+		c.Day, c.Daily = day, 0
+	}
+	if month := now.Format("2006-01"); c.Month != month {
+		c.Month, c.Monthly = month, 0
+	}
+}
+
+// decision picks the window with fewer requests left
+func (c *counters) decision(daily, monthly int, now time.Time) Decision {
+	year, month, day := now.Date()
+	windows := []Decision{
+		{Limit: daily, Remaining: daily - c.Daily, Reset: time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)},
+		{Limit: monthly, Remaining: monthly - c.Monthly, Reset: time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var closest Decision
+	for _, window := range windows {
+		if window.Limit == 0 {
+			continue
+		}
+		window.Remaining = max(window.Remaining, 0)
+		if closest.Limit == 0 || window.Remaining < closest.Remaining {
+			closest = window
+		}
+	}
+	return closest
+}
+
+// Usage returns the quotas and the usage of every key, in configuration
+// order, without their secrets
+func (r *Registry) Usage() []model.APIKeyUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC()
+	usage := make([]model.APIKeyUsage, 0, len(r.entries))
+	for _, e := range r.entries {
+		e.usage.roll(now)
+		usage = append(usage, model.APIKeyUsage{
+			Name:         e.key.Name,
+			Routes:       e.key.Routes,
+			Admin:        e.key.Admin,
+			DailyQuota:   e.key.DailyQuota,
+			DailyUsed:    e.usage.Daily,
+			MonthlyQuota: e.key.MonthlyQuota,
+			MonthlyUsed:  e.usage.Monthly,
+			Total:        e.usage.Total,
+			LastUsedAt:   e.usage.LastUsedAt,
+		})
+	}
+	return usage
+}
+
+// Start saves the usage every interval, until Close. Without a usage file
+// it does nothing
+func (r *Registry) Start() {
+	if r.usagePath == "" {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.save(); err != nil {
+					slog.Error("Failed to save api key usage", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the periodic saves and saves the usage a last time
+func (r *Registry) Close() error {
+	r.cancel()
+	r.wg.Wait()
+
+	if r.usagePath == "" {
+		return nil
+	}
+	return r.save()
+}
+
+// restore reads the usage file, keyed by key name. A missing or unreadable
+// file is an empty usage; entries of keys no longer configured are dropped
+func (r *Registry) restore() error {
+	saved, err := atomicfile.ReadJSON[map[string]counters](r.usagePath)
+	if err != nil {
+		return fmt.Errorf("read api key usage: %w", err)
+	}
+
+	for _, e := range r.entries {
+		e.usage = saved[e.key.Name]
+	}
+	return nil
+}
+
+// save writes the usage file if anything was counted since the last save
+func (r *Registry) save() error {
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+
+	saved := make(map[string]counters, len(r.entries))
+	for _, e := range r.entries {
+		saved[e.key.Name] = e.usage
+	}
+	r.dirty = false
+	r.mu.Unlock()
+
+	if err := atomicfile.WriteJSON(r.usagePath, saved); err != nil {
+		r.mu.Lock()
+		r.dirty = true
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package apikey
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+	now      time.Time
+	registry *Registry
+	partner  Key
+	ops      Key
+}
+
+func (s *RegistryTestSuite) SetupTest() {
+	s.now = time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	s.partner = Key{Name: "partner", Secret: "partner-key-0123456789", Routes: []string{"/api/v1/temperature/*"}, DailyQuota: 2, MonthlyQuota: 3}
+	s.ops = Key{Name: "ops", Secret: "ops-key-0123456789abc", Admin: true}
+
+	s.registry = NewRegistry([]Key{s.partner, s.ops})
+	s.registry.now = func() time.Time { return s.now }
+}
+
+func (s *RegistryTestSuite) TestAuthenticate() {
+	// act
+	key, err := s.registry.Authenticate(s.partner.Secret)
+	_, missingErr := s.registry.Authenticate("")
+	_, invalidErr := s.registry.Authenticate("unknown-key-0123456789")
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), s.partner, key)
+	assert.ErrorIs(s.T(), missingErr, ErrMissing)
+	assert.ErrorIs(s.T(), invalidErr, ErrInvalid)
+}
+
+func (s *RegistryTestSuite) TestAllow_CountsUntilTheDailyQuota() {
+	// act
+	first, firstErr := s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+	second, secondErr := s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+	third, thirdErr := s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+
+	// assert
+	require.NoError(s.T(), firstErr)
+	require.NoError(s.T(), secondErr)
+	assert.ErrorIs(s.T(), thirdErr, ErrQuotaExceeded)
+
+	reset := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(s.T(), Decision{Limit: 2, Remaining: 1, Reset: reset}, first)
+	assert.Equal(s.T(), Decision{Limit: 2, Remaining: 0, Reset: reset}, second)
+	assert.Equal(s.T(), Decision{Limit: 2, Remaining: 0, Reset: reset}, third)
+
+	usage := s.registry.Usage()
+	assert.Equal(s.T(), 2, usage[0].DailyUsed)
+	assert.Equal(s.T(), int64(2), usage[0].Total)
+}
+
+func (s *RegistryTestSuite) TestAllow_MonthlyQuotaOutlastsTheDay() {
+	// arrange
+	for range 2 {
+		_, err := s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+		require.NoError(s.T(), err)
+	}
+	s.now = s.now.Add(2 * time.Hour)
+
+	// act
+	decision, err := s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+
+	// assert: a new month started, so both windows reset
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, decision.Remaining)
+
+	// arrange
+	_, _ = s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+	s.now = s.now.Add(24 * time.Hour)
+
+	// act
+	decision, err = s.registry.Allow(s.partner, "/api/v1/temperature/:cep")
+
+	// assert: the day reset, but the month has a single request left
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), Decision{Limit: 3, Remaining: 0, Reset: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}, decision)
+}
+
+func (s *RegistryTestSuite) TestAllowN() {
+	// act
+	checked, checkErr := s.registry.AllowN(s.partner, "/api/v1/temperature/:cep", 0)
+	_, tooManyErr := s.registry.AllowN(s.partner, "/api/v1/temperature/:cep", 3)
+	decision, err := s.registry.AllowN(s.partner, "/api/v1/temperature/:cep", 2)
+
+	// assert
+	require.NoError(s.T(), checkErr)
+	assert.Equal(s.T(), 2, checked.Remaining, "zero only checks the key")
+	assert.ErrorIs(s.T(), tooManyErr, ErrQuotaExceeded, "a call either fits whole or is not counted")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, decision.Remaining)
+	assert.Equal(s.T(), int64(2), s.registry.Usage()[0].Total)
+}
+
+func (s *RegistryTestSuite) TestAllow_ForbiddenRoutesAreNotCounted() {
+	// act
+	_, err := s.registry.Allow(s.partner, "/api/v1/convert")
+
+	// assert
+	assert.ErrorIs(s.T(), err, ErrForbidden)
+	assert.Zero(s.T(), s.registry.Usage()[0].Total)
+}
+
+func (s *RegistryTestSuite) TestAllow_WithoutQuotas() {
+	// act
+	decision, err := s.registry.Allow(s.ops, "/api/v1/convert")
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Zero(s.T(), decision.Limit)
+}
+
+func (s *RegistryTestSuite) TestUsage_HidesSecrets() {
+	// arrange
+	_, err := s.registry.Allow(s.ops, "/graphql")
+	require.NoError(s.T(), err)
+
+	// act
+	usage := s.registry.Usage()
+
+	// assert
+	require.Len(s.T(), usage, 2)
+	assert.Equal(s.T(), "partner", usage[0].Name)
+	assert.Equal(s.T(), []string{"/api/v1/temperature/*"}, usage[0].Routes)
+	assert.Equal(s.T(), 2, usage[0].DailyQuota)
+	assert.Nil(s.T(), usage[0].LastUsedAt)
+	assert.Equal(s.T(), "ops", usage[1].Name)
+	assert.True(s.T(), usage[1].Admin)
+	assert.Equal(s.T(), 1, usage[1].MonthlyUsed)
+	assert.Equal(s.T(), s.now, *usage[1].LastUsedAt)
+}
+
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func TestLoad_WithoutKeys(t *testing.T) {
+	// act
+	registry, err := Load(&config.Config{})
+
+	// assert
+	require.NoError(t, err)
+	assert.Nil(t, registry)
+}
+
+func TestLoad_RestoresSavedUsage(t *testing.T) {
+	// arrange
+	usagePath := filepath.Join(t.TempDir(), "usage", "api-keys.json")
+	cfg := &config.Config{
+		APIKeys:                  map[string]string{"web": "web-key-0123456789ab"},
+		APIKeyDailyQuota:         10,
+		APIKeyUsageFile:          usagePath,
+		APIKeyUsageFlushInterval: time.Minute,
+	}
+
+	registry, err := Load(cfg)
+	require.NoError(t, err)
+	registry.Start()
+
+	key, err := registry.Authenticate("web-key-0123456789ab")
+	require.NoError(t, err)
+	for range 3 {
+		_, err := registry.Allow(key, "/api/v1/convert")
+		require.NoError(t, err)
+	}
+	require.NoError(t, registry.Close())
+
+	// act
+	restored, err := Load(cfg)
+
+	// assert
+	require.NoError(t, err)
+	usage := restored.Usage()
+	assert.Equal(t, 3, usage[0].DailyUsed)
+	assert.Equal(t, int64(3), usage[0].Total)
+
+	decision, err := restored.Allow(key, "/api/v1/convert")
+	require.NoError(t, err)
+	assert.Equal(t, 6, decision.Remaining)
+}
+
+func TestLoad_UnreadableUsageFileStartsEmpty(t *testing.T) {
+	// arrange
+	usagePath := filepath.Join(t.TempDir(), "api-keys.json")
+	require.NoError(t, os.WriteFile(usagePath, []byte("not json"), 0o600))
+
+	// act
+	registry, err := Load(&config.Config{
+		APIKeys:         map[string]string{"web": "web-key-0123456789ab"},
+		APIKeyUsageFile: usagePath,
+	})
+
+	// assert
+	require.NoError(t, err)
+	usage := registry.Usage()
+	require.Len(t, usage, 1)
+	assert.Zero(t, usage[0].Total)
+}
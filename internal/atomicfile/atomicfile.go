@@ -0,0 +1,80 @@
+// Package atomicfile keeps small JSON state files, such as usage counters,
+// that must survive restarts and crashes
+package atomicfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// WriteJSON replaces the file at path with v as JSON. The data goes to a
+// temporary file in the same directory, flushed to disk before it is renamed
+// over path, so after a crash path holds either the old or the new content
+func WriteJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes the rename to disk. Not every platform can sync a
+// directory, so failures are ignored
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
+
+// ReadJSON reads the JSON file at path. A missing file is the zero value,
+// and so is a file that does not parse, with a warning: the state is
+// rebuilt from scratch instead of keeping the service from starting
+func ReadJSON[T any](path string) (T, error) {
+	var v T
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return v, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		slog.Warn("Ignoring unreadable state file", "path", path, "error", err)
+		var zero T
+		return zero, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,71 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counters struct {
+	Daily   int `json:"daily"`
+	Monthly int `json:"monthly"`
+}
+
+func TestWriteJSON_ReplacesTheFile(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data", "usage.json")
+	require.NoError(t, WriteJSON(path, counters{Daily: 1, Monthly: 1}))
+
+	// act
+	err := WriteJSON(path, counters{Daily: 2, Monthly: 5})
+	read, readErr := ReadJSON[counters](path)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, readErr)
+	assert.Equal(t, counters{Daily: 2, Monthly: 5}, read)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary file is left behind")
+	assert.Equal(t, "usage.json", entries[0].Name())
+}
+
+func TestReadJSON_MissingFile(t *testing.T) {
+	// act
+	read, err := ReadJSON[map[string]counters](filepath.Join(t.TempDir(), "usage.json"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, read)
+}
+
+func TestReadJSON_UnreadableFileStartsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"truncated", `{"daily": 3, "mon`},
+		{"wrong type", `["daily"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			path := filepath.Join(t.TempDir(), "usage.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o600))
+
+			// act
+			read, err := ReadJSON[counters](path)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, counters{}, read)
+		})
+	}
+}
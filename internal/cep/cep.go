@@ -0,0 +1,85 @@
+// Package cep parses, validates and formats Brazilian postal codes (CEP) and
+// maps them to their UF and region
+package cep
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidFormat = errors.New("cep must have 8 digits (00000000, 00000-000 or 00.000-000)")
+	ErrUnknownRange  = errors.New("cep does not belong to any UF range")
+)
+
+// cepPattern accepts "01001000", "01001-000" and "01.001-000"
+var cepPattern = regexp.MustCompile(`^(\d{2})\.?(\d{3})-?(\d{3})$`)
+
+// CEP is a validated postal code stored in its canonical form: 8 digits
+// without punctuation. The zero value is an empty, invalid CEP
+type CEP string
+
+// Parse validates a raw CEP and returns it in canonical form. Besides the
+// format, the code must fall inside one of the ranges Correios assigns to a
+// UF, so impossible CEPs such as 00000-000 are rejected before any lookup
+func Parse(value string) (CEP, error) {
+	parts := cepPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if parts == nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidFormat, value)
+	}
+
+	digits := parts[1] + parts[2] + parts[3]
+
+	number, _ := strconv.ParseUint(digits, 10, 32)
+	if _, ok := findRange(uint32(number)); !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownRange, value)
+	}
+
+	return CEP(digits), nil
+}
+
+// MustParse is like Parse but panics on invalid input
+func MustParse(value string) CEP {
+	c, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the canonical 8 digit form, e.g. "01001000"
+func (c CEP) String() string {
+	return string(c)
+}
+
+// Formatted returns the CEP as written by Correios, e.g. "01001-000"
+func (c CEP) Formatted() string {
+	if len(c) != 8 {
+		return string(c)
+	}
+	return string(c[:5]) + "-" + string(c[5:])
+}
+
+// IsZero reports whether the CEP is empty
+func (c CEP) IsZero() bool {
+	return c == ""
+}
+
+// MarshalText encodes the CEP in its formatted form, which also makes it
+// render as "01001-000" in JSON
+func (c CEP) MarshalText() ([]byte, error) {
+	return []byte(c.Formatted()), nil
+}
+
+// UnmarshalText accepts any format supported by Parse
+func (c *CEP) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
@@ -0,0 +1,218 @@
+package cep
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_ValidFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Digits only", "01001000"},
+		{"With hyphen", "01001-000"},
+		{"With dot and hyphen", "01.001-000"},
+		{"Surrounding spaces", " 01001-000 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			c, err := Parse(tt.value)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, CEP("01001000"), c)
+		})
+	}
+}
+
+func TestParse_InvalidFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Empty", ""},
+		{"Too short", "0100100"},
+		{"Too long", "010010000"},
+		{"Letters", "0100A000"},
+		{"Misplaced hyphen", "0100-1000"},
+		{"Misplaced dot", "010.01-000"},
+		{"Very long number", "111111111111111111111111111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			c, err := Parse(tt.value)
+
+			// assert
+			assert.ErrorIs(t, err, ErrInvalidFormat)
+			assert.True(t, c.IsZero())
+		})
+	}
+}
+
+func TestParse_OutsideUFRanges(t *testing.T) {
+	tests := []string{"00000-000", "00999-999", "00000000", "00.500-123"}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			// act
+			c, err := Parse(value)
+
+			// assert
+			assert.ErrorIs(t, err, ErrUnknownRange)
+			assert.True(t, c.IsZero())
+		})
+	}
+}
+
+func TestParse_RangeBoundaries(t *testing.T) {
+	tests := []string{"01000-000", "19999-999", "20000-000", "69999-999", "99999-999"}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			_, err := Parse(value)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCEP_StringAndFormatted(t *testing.T) {
+	// arrange
+	c := MustParse("01.310-100")
+
+	// assert
+	assert.Equal(t, "01310100", c.String())
+	assert.Equal(t, "01310-100", c.Formatted())
+}
+
+func TestCEP_FormattedZeroValue(t *testing.T) {
+	var c CEP
+
+	assert.Equal(t, "", c.Formatted())
+	assert.True(t, c.IsZero())
+}
+
+func TestMustParse_PanicsOnInvalidCEP(t *testing.T) {
+	assert.Panics(t, func() {
+		MustParse("00000-000")
+	})
+}
+
+func TestCEP_TextMarshalling(t *testing.T) {
+	// arrange
+	c := MustParse("01001000")
+
+	// act
+	text, err := c.MarshalText()
+
+	var decoded CEP
+	decodeErr := decoded.UnmarshalText([]byte("01.001-000"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "01001-000", string(text))
+	assert.NoError(t, decodeErr)
+	assert.Equal(t, c, decoded)
+}
+
+func TestCEP_JSONMarshalling(t *testing.T) {
+	// arrange
+	payload := struct {
+		Cep CEP `json:"cep"`
+	}{Cep: MustParse("01001000")}
+
+	// act
+	data, err := json.Marshal(payload)
+
+	// assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cep":"01001-000"}`, string(data))
+}
+
+func TestCEP_JSONUnmarshalling(t *testing.T) {
+	var payload struct {
+		Cep CEP `json:"cep"`
+	}
+
+	err := json.Unmarshal([]byte(`{"cep":"01.001-000"}`), &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, CEP("01001000"), payload.Cep)
+
+	err = json.Unmarshal([]byte(`{"cep":"00000-000"}`), &payload)
+	assert.ErrorIs(t, err, ErrUnknownRange)
+}
+
+func TestLookup_ReturnsStateAndRegion(t *testing.T) {
+	tests := []struct {
+		cep    string
+		uf     string
+		region string
+	}{
+		{"01001-000", "SP", RegionSudeste},
+		{"20040-020", "RJ", RegionSudeste},
+		{"40020-000", "BA", RegionNordeste},
+		{"69005-010", "AM", RegionNorte},
+		{"69301-000", "RR", RegionNorte},
+		{"69400-000", "AM", RegionNorte},
+		{"70040-010", "DF", RegionCentroOeste},
+		{"72800-000", "GO", RegionCentroOeste},
+		{"73000-000", "DF", RegionCentroOeste},
+		{"74000-000", "GO", RegionCentroOeste},
+		{"80010-000", "PR", RegionSul},
+		{"90010-000", "RS", RegionSul},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			// act
+			state, ok := Lookup(MustParse(tt.cep))
+
+			// assert
+			assert.True(t, ok)
+			assert.Equal(t, tt.uf, state.UF)
+			assert.Equal(t, tt.region, state.Region)
+		})
+	}
+}
+
+func TestLookup_InvalidCEP(t *testing.T) {
+	for _, c := range []CEP{"", "00000000", "abc"} {
+		state, ok := Lookup(c)
+
+		assert.False(t, ok)
+		assert.Equal(t, State{}, state)
+	}
+}
+
+func TestRanges_EveryUFHasAState(t *testing.T) {
+	for _, r := range ufRanges {
+		_, ok := states[r.uf]
+		assert.True(t, ok, "UF %s has no state", r.uf)
+	}
+	assert.Len(t, states, 27)
+}
+
+func TestStateOf(t *testing.T) {
+	state, ok := StateOf(" sp ")
+	assert.True(t, ok)
+	assert.Equal(t, State{UF: "SP", Name: "São Paulo", Region: RegionSudeste}, state)
+
+	_, ok = StateOf("XX")
+	assert.False(t, ok)
+}
+
+func TestCEP_MatchesUF(t *testing.T) {
+	c := MustParse("01001-000")
+
+	assert.True(t, c.MatchesUF("SP"))
+	assert.True(t, c.MatchesUF("sp"))
+	assert.False(t, c.MatchesUF("RJ"))
+	assert.False(t, c.MatchesUF(""))
+	assert.False(t, CEP("").MatchesUF("SP"))
+}
@@ -0,0 +1,62 @@
+package cep
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+//go:embed uf_ranges.csv
+var ufRangesCSV []byte
+
+// ufRange is a contiguous block of CEPs assigned by Correios to a single UF
+type ufRange struct {
+	uf    string
+	start uint32
+	end   uint32
+}
+
+// ufRanges is sorted by start so lookups can binary search it
+var ufRanges = mustLoadRanges(ufRangesCSV)
+
+func mustLoadRanges(data []byte) []ufRange {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("cep: invalid embedded UF ranges: %v", err))
+	}
+
+	ranges := make([]ufRange, 0, len(records))
+	for _, record := range records[1:] {
+		start, err := strconv.ParseUint(record[1], 10, 32)
+		if err != nil {
+			panic(fmt.Sprintf("cep: invalid range start %q: %v", record[1], err))
+		}
+		end, err := strconv.ParseUint(record[2], 10, 32)
+		if err != nil {
+			panic(fmt.Sprintf("cep: invalid range end %q: %v", record[2], err))
+		}
+		ranges = append(ranges, ufRange{uf: record[0], start: uint32(start), end: uint32(end)})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	return ranges
+}
+
+// findRange returns the UF range containing number, if any
+func findRange(number uint32) (ufRange, bool) {
+	i := sort.Search(len(ufRanges), func(i int) bool {
+		return ufRanges[i].end >= number
+	})
+
+	if i < len(ufRanges) && ufRanges[i].start <= number {
+		return ufRanges[i], true
+	}
+
+	return ufRange{}, false
+}
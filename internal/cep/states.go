@@ -0,0 +1,86 @@
+package cep
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Macro-regions defined by IBGE
+const (
+	RegionNorte       = "Norte"
+	RegionNordeste    = "Nordeste"
+	RegionCentroOeste = "Centro-Oeste"
+	RegionSudeste     = "Sudeste"
+	RegionSul         = "Sul"
+)
+
+// State is a federative unit (UF) and the region it belongs to
+type State struct {
+	UF     string `json:"uf" example:"SP"`
+	Name   string `json:"name" example:"São Paulo"`
+	Region string `json:"region" example:"Sudeste"`
+}
+
+var states = map[string]State{
+	"AC": {"AC", "Acre", RegionNorte},
+	"AL": {"AL", "Alagoas", RegionNordeste},
+	"AM": {"AM", "Amazonas", RegionNorte},
+	"AP": {"AP", "Amapá", RegionNorte},
+	"BA": {"BA", "Bahia", RegionNordeste},
+	"CE": {"CE", "Ceará", RegionNordeste},
+	"DF": {"DF", "Distrito Federal", RegionCentroOeste},
+	"ES": {"ES", "Espírito Santo", RegionSudeste},
+	"GO": {"GO", "Goiás", RegionCentroOeste},
+	"MA": {"MA", "Maranhão", RegionNordeste},
+	"MG": {"MG", "Minas Gerais", RegionSudeste},
+	"MS": {"MS", "Mato Grosso do Sul", RegionCentroOeste},
+	"MT": {"MT", "Mato Grosso", RegionCentroOeste},
+	"PA": {"PA", "Pará", RegionNorte},
+	"PB": {"PB", "Paraíba", RegionNordeste},
+	"PE": {"PE", "Pernambuco", RegionNordeste},
+	"PI": {"PI", "Piauí", RegionNordeste},
+	"PR": {"PR", "Paraná", RegionSul},
+	"RJ": {"RJ", "Rio de Janeiro", RegionSudeste},
+	"RN": {"RN", "Rio Grande do Norte", RegionNordeste},
+	"RO": {"RO", "Rondônia", RegionNorte},
+	"RR": {"RR", "Roraima", RegionNorte},
+	"RS": {"RS", "Rio Grande do Sul", RegionSul},
+	"SC": {"SC", "Santa Catarina", RegionSul},
+	"SE": {"SE", "Sergipe", RegionNordeste},
+	"SP": {"SP", "São Paulo", RegionSudeste},
+	"TO": {"TO", "Tocantins", RegionNorte},
+}
+
+// StateOf returns the state for a UF abbreviation, ignoring case
+func StateOf(uf string) (State, bool) {
+	state, ok := states[strings.ToUpper(strings.TrimSpace(uf))]
+	return state, ok
+}
+
+// Lookup returns the state whose CEP range contains c, without any network call
+func Lookup(c CEP) (State, bool) {
+	number, err := strconv.ParseUint(c.String(), 10, 32)
+	if err != nil {
+		return State{}, false
+	}
+
+	r, ok := findRange(uint32(number))
+	if !ok {
+		return State{}, false
+	}
+
+	return states[r.uf], true
+}
+
+// UF returns the abbreviation of the state that owns the CEP range, or ""
+// when the CEP is not valid
+func (c CEP) UF() string {
+	state, _ := Lookup(c)
+	return state.UF
+}
+
+// MatchesUF reports whether uf is the state expected for the CEP range
+func (c CEP) MatchesUF(uf string) bool {
+	expected := c.UF()
+	return expected != "" && strings.EqualFold(expected, strings.TrimSpace(uf))
+}
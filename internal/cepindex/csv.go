@@ -0,0 +1,86 @@
+package cepindex
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+)
+
+// csvColumns maps accepted header names, in ViaCEP's vocabulary or the one
+// of model.Address, to the record field they fill
+var csvColumns = map[string]func(*Record, string){
+	"logradouro":   func(r *Record, v string) { r.Street = v },
+	"street":       func(r *Record, v string) { r.Street = v },
+	"complemento":  func(r *Record, v string) { r.Complement = v },
+	"complement":   func(r *Record, v string) { r.Complement = v },
+	"bairro":       func(r *Record, v string) { r.Neighborhood = v },
+	"neighborhood": func(r *Record, v string) { r.Neighborhood = v },
+	"localidade":   func(r *Record, v string) { r.City = v },
+	"city":         func(r *Record, v string) { r.City = v },
+	"uf":           func(r *Record, v string) { r.UF = strings.ToUpper(v) },
+	"ibge":         func(r *Record, v string) { r.Ibge = v },
+	"ddd":          func(r *Record, v string) { r.Ddd = v },
+}
+
+// ReadCSV reads records from a CSV export with a header row. The cep column
+// is required; other known columns are optional and unknown ones are ignored
+func ReadCSV(r io.Reader, comma rune) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty CSV")
+		}
+		return nil, err
+	}
+
+	cepColumn := -1
+	setters := make(map[int]func(*Record, string))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "cep" {
+			cepColumn = i
+		} else if setter, ok := csvColumns[name]; ok {
+			setters[i] = setter
+		}
+	}
+	if cepColumn < 0 {
+		return nil, errors.New("CSV has no cep column")
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if cepColumn >= len(row) {
+			return nil, fmt.Errorf("line %d: missing cep", line)
+		}
+
+		code, err := cep.Parse(row[cepColumn])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		record := Record{Cep: code}
+		for i, setter := range setters {
+			if i < len(row) {
+				setter(&record, strings.TrimSpace(row[i]))
+			}
+		}
+		records = append(records, record)
+	}
+}
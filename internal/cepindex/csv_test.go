@@ -0,0 +1,76 @@
+package cepindex
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV_Fixture(t *testing.T) {
+	// arrange
+	file, err := os.Open("testdata/ceps.csv")
+	require.NoError(t, err)
+	defer file.Close()
+
+	// act
+	records, err := ReadCSV(file, ',')
+
+	// assert
+	require.NoError(t, err)
+	assert.Len(t, records, 6)
+	assert.Equal(t, Record{
+		Cep:          "01310100",
+		Street:       "Avenida Paulista",
+		Complement:   "de 612 a 1510 - lado par",
+		Neighborhood: "Bela Vista",
+		City:         "São Paulo",
+		UF:           "SP",
+		Ibge:         "3550308",
+		Ddd:          "11",
+	}, records[1])
+	assert.Equal(t, "até 299/300", records[5].Complement)
+}
+
+func TestReadCSV_SemicolonAndAliases(t *testing.T) {
+	// arrange
+	data := "\ufeffCEP;city;street;extra;uf\n01001000;São Paulo;Praça da Sé;x;sp\n"
+
+	// act
+	records, err := ReadCSV(strings.NewReader(data), ';')
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []Record{{Cep: "01001000", Street: "Praça da Sé", City: "São Paulo", UF: "SP"}}, records)
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{"Empty", "", "empty CSV"},
+		{"No cep column", "logradouro,uf\nRua A,SP\n", "no cep column"},
+		{"Invalid cep", "cep,uf\n01001000,SP\n123,SP\n", "line 3"},
+		{"Impossible cep", "cep,uf\n00000-000,SP\n", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ReadCSV(strings.NewReader(tt.data), ',')
+
+			assert.Nil(t, records)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestReadCSV_InvalidCepWrapsParseError(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader("cep\n00000000\n"), ',')
+
+	assert.ErrorIs(t, err, cep.ErrUnknownRange)
+}
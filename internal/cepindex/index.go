@@ -0,0 +1,290 @@
+// Package cepindex stores addresses in a compact binary file that can be
+// queried by CEP without loading it into memory.
+//
+// The file starts with a fixed header, followed by one fixed-size record per
+// CEP sorted by CEP, followed by a pool of deduplicated strings:
+//
+//	header  magic "CEPX" | version uint16 | reserved uint16 | count uint32 | pool offset uint32
+//	record  cep uint32 | street, complement, neighborhood, city, uf, ibge, ddd uint32 (pool offsets)
+//	pool    uvarint length | UTF-8 bytes, repeated
+//
+// All integers are big endian. Fixed-size records let Lookup binary search
+// the file through io.ReaderAt, so an index with millions of CEPs costs a
+// handful of reads per lookup and no memory beyond the open file
+package cepindex
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+)
+
+const (
+	magic      = "CEPX"
+	version    = 1
+	headerSize = 16
+	fieldCount = 7
+	recordSize = 4 + fieldCount*4
+
+	// maxStringLength bounds a single pool string, so a corrupt length
+	// cannot make a lookup read an arbitrary amount of data
+	maxStringLength = 1 << 12
+	stringReadAhead = 64
+)
+
+var ErrInvalidIndex = errors.New("invalid CEP index")
+
+// Record is an address stored in the index
+type Record struct {
+	Cep          cep.CEP
+	Street       string
+	Complement   string
+	Neighborhood string
+	City         string
+	UF           string
+	Ibge         string
+	Ddd          string
+}
+
+func (r Record) fields() [fieldCount]string {
+	return [fieldCount]string{r.Street, r.Complement, r.Neighborhood, r.City, r.UF, r.Ibge, r.Ddd}
+}
+
+func (r *Record) setFields(values [fieldCount]string) {
+	r.Street, r.Complement, r.Neighborhood, r.City, r.UF, r.Ibge, r.Ddd =
+		values[0], values[1], values[2], values[3], values[4], values[5], values[6]
+}
+
+// Write encodes records as an index. Records are sorted by CEP, and a CEP may
+// appear only once
+func Write(w io.Writer, records []Record) error {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cep < sorted[j].Cep
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Cep == sorted[i-1].Cep {
+			return fmt.Errorf("duplicate cep %s", sorted[i].Cep.Formatted())
+		}
+	}
+
+	var pool []byte
+	offsets := make(map[string]uint32)
+	intern := func(value string) (uint32, error) {
+		if offset, ok := offsets[value]; ok {
+			return offset, nil
+		}
+		if len(value) > maxStringLength {
+			return 0, fmt.Errorf("value longer than %d bytes: %.32q", maxStringLength, value)
+		}
+		offset := uint32(len(pool))
+		pool = binary.AppendUvarint(pool, uint64(len(value)))
+		pool = append(pool, value...)
+		offsets[value] = offset
+		return offset, nil
+	}
+
+	body := make([]byte, 0, len(sorted)*recordSize)
+	for _, record := range sorted {
+		number, err := cepNumber(record.Cep)
+		if err != nil {
+			return err
+		}
+		body = binary.BigEndian.AppendUint32(body, number)
+
+		for _, value := range record.fields() {
+			offset, err := intern(value)
+			if err != nil {
+				return fmt.Errorf("cep %s: %w", record.Cep.Formatted(), err)
+			}
+			body = binary.BigEndian.AppendUint32(body, offset)
+		}
+	}
+
+	poolOffset := uint64(headerSize) + uint64(len(body))
+	if poolOffset+uint64(len(pool)) > 1<<32-1 {
+		return errors.New("index larger than 4GB")
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = binary.BigEndian.AppendUint16(header, version)
+	header = binary.BigEndian.AppendUint16(header, 0)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(sorted)))
+	header = binary.BigEndian.AppendUint32(header, uint32(poolOffset))
+
+	for _, chunk := range [][]byte{header, body, pool} {
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Index is a read-only view of an index file
+type Index struct {
+	r          io.ReaderAt
+	size       int64
+	count      int
+	poolOffset int64
+	closer     io.Closer
+}
+
+// Open opens the index file at path. Lookups read the file on demand
+func Open(path string) (*Index, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	index, err := New(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	index.closer = file
+
+	return index, nil
+}
+
+// New reads the header of an index of the given size stored in r
+func New(r io.ReaderAt, size int64) (*Index, error) {
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIndex, err)
+	}
+
+	if string(header[:4]) != magic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidIndex, header[:4])
+	}
+	if v := binary.BigEndian.Uint16(header[4:6]); v != version {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidIndex, v)
+	}
+
+	count := int64(binary.BigEndian.Uint32(header[8:12]))
+	poolOffset := int64(binary.BigEndian.Uint32(header[12:16]))
+	if poolOffset != headerSize+count*recordSize || poolOffset > size {
+		return nil, fmt.Errorf("%w: truncated records", ErrInvalidIndex)
+	}
+
+	return &Index{r: r, size: size, count: int(count), poolOffset: poolOffset}, nil
+}
+
+// Len returns the number of CEPs in the index
+func (i *Index) Len() int {
+	return i.count
+}
+
+// Lookup returns the record for code. The boolean is false when the index
+// has no such CEP
+func (i *Index) Lookup(code cep.CEP) (Record, bool, error) {
+	number, err := cepNumber(code)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	record := make([]byte, recordSize)
+	var readErr error
+	readRecord := func(n int) uint32 {
+		if _, err := i.r.ReadAt(record, headerSize+int64(n)*recordSize); err != nil {
+			readErr = err
+			return 0
+		}
+		return binary.BigEndian.Uint32(record)
+	}
+
+	n := sort.Search(i.count, func(n int) bool {
+		return readErr != nil || readRecord(n) >= number
+	})
+	found := n < i.count && readRecord(n) == number
+	if readErr != nil {
+		return Record{}, false, fmt.Errorf("%w: %v", ErrInvalidIndex, readErr)
+	}
+	if !found {
+		return Record{}, false, nil
+	}
+
+	var values [fieldCount]string
+	for f := range values {
+		offset := int64(binary.BigEndian.Uint32(record[4+f*4:]))
+		if values[f], err = i.readString(offset); err != nil {
+			return Record{}, false, err
+		}
+	}
+
+	result := Record{Cep: code}
+	result.setFields(values)
+
+	return result, true, nil
+}
+
+func (i *Index) readString(offset int64) (string, error) {
+	start := i.poolOffset + offset
+	if start >= i.size {
+		return "", fmt.Errorf("%w: string offset %d out of range", ErrInvalidIndex, offset)
+	}
+
+	// Most values are short, so a single small read usually covers both the
+	// length prefix and the bytes
+	buf, err := i.readAt(start, stringReadAhead)
+	if err != nil {
+		return "", err
+	}
+
+	length, n := binary.Uvarint(buf)
+	if n <= 0 || length > maxStringLength {
+		return "", fmt.Errorf("%w: bad string at offset %d", ErrInvalidIndex, offset)
+	}
+
+	end := n + int(length)
+	if end > len(buf) {
+		if buf, err = i.readAt(start, int64(end)); err != nil {
+			return "", err
+		}
+		if end > len(buf) {
+			return "", fmt.Errorf("%w: truncated string at offset %d", ErrInvalidIndex, offset)
+		}
+	}
+
+	return string(buf[n:end]), nil
+}
+
+// readAt reads up to length bytes at offset, stopping at the end of the index
+func (i *Index) readAt(offset, length int64) ([]byte, error) {
+	buf := make([]byte, min(length, i.size-offset))
+	if _, err := i.r.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIndex, err)
+	}
+	return buf, nil
+}
+
+// Close releases the file opened by Open
+func (i *Index) Close() error {
+	if i.closer == nil {
+		return nil
+	}
+	return i.closer.Close()
+}
+
+func cepNumber(code cep.CEP) (uint32, error) {
+	number, err := strconv.ParseUint(code.String(), 10, 32)
+	if err != nil || len(code) != 8 {
+		return 0, fmt.Errorf("%w: %q", cep.ErrInvalidFormat, code)
+	}
+	return uint32(number), nil
+}
@@ -0,0 +1,217 @@
+package cepindex
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleRecords() []Record {
+	return []Record{
+		{Cep: "20040020", Street: "Praça Pio X", Neighborhood: "Centro", City: "Rio de Janeiro", UF: "RJ", Ibge: "3304557", Ddd: "21"},
+		{Cep: "01001000", Street: "Praça da Sé", Complement: "lado ímpar", Neighborhood: "Sé", City: "São Paulo", UF: "SP", Ibge: "3550308", Ddd: "11"},
+		{Cep: "01310100", Street: "Avenida Paulista", Complement: "de 612 a 1510 - lado par", Neighborhood: "Bela Vista", City: "São Paulo", UF: "SP", Ibge: "3550308", Ddd: "11"},
+	}
+}
+
+func buildIndex(t *testing.T, records []Record) *Index {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, records))
+
+	index, err := New(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	return index
+}
+
+func TestIndex_LookupEveryRecord(t *testing.T) {
+	// arrange
+	records := sampleRecords()
+	index := buildIndex(t, records)
+
+	// assert
+	assert.Equal(t, 3, index.Len())
+	for _, expected := range records {
+		record, ok, err := index.Lookup(expected.Cep)
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, record)
+	}
+}
+
+func TestIndex_LookupMissing(t *testing.T) {
+	// arrange
+	index := buildIndex(t, sampleRecords())
+
+	// before the first, between two and after the last record
+	for _, code := range []cep.CEP{"01000000", "01100000", "99999999"} {
+		// act
+		record, ok, err := index.Lookup(code)
+
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, Record{}, record)
+	}
+}
+
+func TestIndex_Empty(t *testing.T) {
+	index := buildIndex(t, nil)
+
+	_, ok, err := index.Lookup("01001000")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestIndex_LookupInvalidCep(t *testing.T) {
+	index := buildIndex(t, sampleRecords())
+
+	_, ok, err := index.Lookup("0100")
+
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, cep.ErrInvalidFormat)
+}
+
+func TestIndex_ManyRecords(t *testing.T) {
+	// arrange
+	var records []Record
+	for i := 0; i < 5000; i++ {
+		records = append(records, Record{
+			Cep:    cep.CEP(fmt.Sprintf("%08d", 1000000+i*7)),
+			Street: fmt.Sprintf("Rua %d", i),
+			City:   "São Paulo",
+			UF:     "SP",
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, records))
+	index, err := New(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	// assert
+	for _, i := range []int{0, 1, 2500, 4999} {
+		record, ok, err := index.Lookup(records[i].Cep)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, records[i], record)
+	}
+
+	_, ok, err := index.Lookup(cep.CEP(fmt.Sprintf("%08d", 1000000+1)))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// cidade e UF repetidas são gravadas uma única vez
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("São Paulo")))
+}
+
+func TestIndex_LongValue(t *testing.T) {
+	// arrange
+	long := strings.Repeat("a", 1000)
+	index := buildIndex(t, []Record{{Cep: "01001000", Complement: long}})
+
+	// act
+	record, ok, err := index.Lookup("01001000")
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, long, record.Complement)
+}
+
+func TestWrite_RejectsDuplicates(t *testing.T) {
+	records := append(sampleRecords(), Record{Cep: "01001000"})
+
+	err := Write(&bytes.Buffer{}, records)
+
+	assert.ErrorContains(t, err, "duplicate cep 01001-000")
+}
+
+func TestWrite_RejectsValueTooLong(t *testing.T) {
+	err := Write(&bytes.Buffer{}, []Record{{Cep: "01001000", Street: strings.Repeat("a", maxStringLength+1)}})
+
+	assert.Error(t, err)
+}
+
+func TestNew_InvalidIndex(t *testing.T) {
+	var valid bytes.Buffer
+	require.NoError(t, Write(&valid, sampleRecords()))
+
+	badVersion := bytes.Clone(valid.Bytes())
+	badVersion[5] = 9
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Bad magic", append([]byte("NOPE"), valid.Bytes()[4:]...)},
+		{"Bad version", badVersion},
+		{"Truncated", valid.Bytes()[:headerSize+recordSize]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := New(bytes.NewReader(tt.data), int64(len(tt.data)))
+
+			assert.Nil(t, index)
+			assert.ErrorIs(t, err, ErrInvalidIndex)
+		})
+	}
+}
+
+func TestIndex_CorruptStringOffset(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleRecords()[:1]))
+
+	data := buf.Bytes()
+	copy(data[headerSize+4:], []byte{0xff, 0xff, 0xff, 0x00})
+	index, err := New(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	// act
+	_, ok, err := index.Lookup("20040020")
+
+	// assert
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, ErrInvalidIndex)
+}
+
+func TestOpen_File(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "ceps.idx")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, Write(file, sampleRecords()))
+	require.NoError(t, file.Close())
+
+	// act
+	index, err := Open(path)
+	require.NoError(t, err)
+	defer index.Close()
+
+	record, ok, err := index.Lookup("01310100")
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Avenida Paulista", record.Street)
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	index, err := Open(filepath.Join(t.TempDir(), "missing.idx"))
+
+	assert.Nil(t, index)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+const (
+	// maxCacheEntries bounds each cache; expired entries are swept first
+	maxCacheEntries = 10000
+	// maxTrackedCeps bounds the request counters; past it every count is
+	// halved, so CEPs no longer requested fade out
+	maxTrackedCeps = 10000
+)
+
+type cacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlCache is a concurrency-safe map whose entries expire after ttl
+type ttlCache[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry[V]
+	now     func() time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, entries: map[string]cacheEntry[V]{}, now: time.Now}
+}
+
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// getStale returns the entry of key even if it expired
+func (c *ttlCache[V]) getStale(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	return entry.value, ok
+}
+
+func (c *ttlCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxCacheEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// CachedCepClient keeps the addresses found by another CEP client for a
+// while, and counts the lookups of each CEP so the most requested ones can
+// be refreshed ahead of time
+type CachedCepClient struct {
+	next  CepClientInterface
+	cache *ttlCache[*model.ViacepResponse]
+
+	mu       sync.Mutex
+	requests map[cep.CEP]uint64
+}
+
+func NewCachedCepClient(next CepClientInterface, ttl time.Duration) *CachedCepClient {
+	return &CachedCepClient{
+		next:     next,
+		cache:    newTTLCache[*model.ViacepResponse](ttl),
+		requests: map[cep.CEP]uint64{},
+	}
+}
+
+func (c *CachedCepClient) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	c.count(code)
+
+	if response, ok := c.cache.get(code.String()); ok {
+		copied := *response
+		return &copied, nil
+	}
+	return c.Refresh(ctx, code)
+}
+
+// SearchAddress is not cached
+func (c *CachedCepClient) SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error) {
+	return c.next.SearchAddress(ctx, uf, city, street)
+}
+
+// Refresh looks code up again and caches the address. Unknown CEPs are not
+// cached, and refreshes do not count as requests
+func (c *CachedCepClient) Refresh(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	response, err := c.next.GetCep(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Erro == nil {
+		cached := *response
+		c.cache.set(code.String(), &cached)
+	}
+	return response, nil
+}
+
+// TopRequested returns up to n CEPs, the most requested first
+func (c *CachedCepClient) TopRequested(n int) []cep.CEP {
+	c.mu.Lock()
+	codes := make([]cep.CEP, 0, len(c.requests))
+	counts := make(map[cep.CEP]uint64, len(c.requests))
+	for code, count := range c.requests {
+		codes = append(codes, code)
+		counts[code] = count
+	}
+	c.mu.Unlock()
+
+	slices.SortFunc(codes, func(a, b cep.CEP) int {
+		if counts[a] != counts[b] {
+			return cmp.Compare(counts[b], counts[a])
+		}
+		return cmp.Compare(a.String(), b.String())
+	})
+	return codes[:min(n, len(codes))]
+}
+
+func (c *CachedCepClient) count(code cep.CEP) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.requests[code]; !ok && len(c.requests) >= maxTrackedCeps {
+		for tracked, count := range c.requests {
+			if count /= 2; count == 0 {
+				delete(c.requests, tracked)
+			} else {
+				c.requests[tracked] = count
+			}
+		}
+	}
+	c.requests[code]++
+}
+
+// CachedWeatherClient keeps the observations of each city, in each state and
+// language, for a while. Coordinate lookups and forecasts are not cached
+type CachedWeatherClient struct {
+	next       WeatherClientInterface
+	cache      *ttlCache[*model.Observation]
+	serveStale func() bool
+}
+
+func NewCachedWeatherClient(next WeatherClientInterface, ttl time.Duration) *CachedWeatherClient {
+	return &CachedWeatherClient{next: next, cache: newTTLCache[*model.Observation](ttl)}
+}
+
+// weatherCacheKey folds the city name and adds the state, which tells cities
+// of the same name apart, and the language, which changes the condition text
+func weatherCacheKey(ctx context.Context, uf, city string) string {
+	return string(i18n.FromContext(ctx)) + "|" + strings.ToUpper(uf) + "|" + geo.NormalizeName(city)
+}
+
+// ServeStaleWhen serves expired observations, instead of looking the weather
+// up again, while stale returns true, such as past an upstream quota
+func (c *CachedWeatherClient) ServeStaleWhen(stale func() bool) {
+	c.serveStale = stale
+}
+
+func (c *CachedWeatherClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	return c.GetCityWeather(ctx, "", city)
+}
+
+func (c *CachedWeatherClient) GetCityWeather(ctx context.Context, uf, city string) (*model.Observation, error) {
+	key := weatherCacheKey(ctx, uf, city)
+	observation, ok := c.cache.get(key)
+	if !ok && c.serveStale != nil && c.serveStale() {
+		observation, ok = c.cache.getStale(key)
+	}
+	if ok {
+		copied := *observation
+		return &copied, nil
+	}
+	return c.Refresh(ctx, uf, city)
+}
+
+// GetFreshCityWeather skips the cached observation, for readers that poll
+// and must see new observations, such as streams. While expired observations
+// are served it answers as GetCityWeather does
+func (c *CachedWeatherClient) GetFreshCityWeather(ctx context.Context, uf, city string) (*model.Observation, error) {
+	if c.serveStale != nil && c.serveStale() {
+		return c.GetCityWeather(ctx, uf, city)
+	}
+	return c.Refresh(ctx, uf, city)
+}
+
+// Refresh looks the weather in city, in the state uf, up again, in the
+// language of ctx, and caches it
+func (c *CachedWeatherClient) Refresh(ctx context.Context, uf, city string) (*model.Observation, error) {
+	observation, err := GetCityWeather(ctx, c.next, uf, city)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := *observation
+	c.cache.set(weatherCacheKey(ctx, uf, city), &cached)
+	return observation, nil
+}
+
+func (c *CachedWeatherClient) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	return c.next.GetWeatherByCoordinates(ctx, lat, lon)
+}
+
+func (c *CachedWeatherClient) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	forecaster, ok := c.next.(ForecastClientInterface)
+	if !ok {
+		return nil, cErrors.ForecastUnsupported
+	}
+	return forecaster.GetForecast(ctx, lat, lon, days)
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedCepClient_GetCep(t *testing.T) {
+	// arrange
+	stub := NewCepClientStub(nil)
+	stub.On("GetCep", mock.Anything, cep.MustParse("01001000")).Return(model.GetViacepResponseMock("01001-000"), nil).Once()
+	cached := NewCachedCepClient(stub, time.Minute)
+
+	// act
+	first, err := cached.GetCep(context.Background(), cep.MustParse("01001000"))
+	require.NoError(t, err)
+	first.Localidade = "changed by the caller"
+	second, err := cached.GetCep(context.Background(), cep.MustParse("01001-000"))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "São Paulo", second.Localidade)
+	stub.AssertNumberOfCalls(t, "GetCep", 1)
+}
+
+func TestCachedCepClient_Expires(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	stub := NewCepClientStub(nil)
+	stub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	cached := NewCachedCepClient(stub, time.Minute)
+	cached.cache.now = func() time.Time { return now }
+
+	// act
+	_, _ = cached.GetCep(context.Background(), cep.MustParse("01001000"))
+	now = now.Add(time.Minute)
+	_, err := cached.GetCep(context.Background(), cep.MustParse("01001000"))
+
+	// assert
+	require.NoError(t, err)
+	stub.AssertNumberOfCalls(t, "GetCep", 2)
+}
+
+func TestCachedCepClient_UnknownCepsAreNotCached(t *testing.T) {
+	// arrange
+	notFound := "true"
+	stub := NewCepClientStub(nil)
+	stub.On("GetCep", mock.Anything, mock.Anything).Return(&model.ViacepResponse{Erro: &notFound}, nil)
+	cached := NewCachedCepClient(stub, time.Minute)
+
+	// act
+	_, _ = cached.GetCep(context.Background(), cep.MustParse("99999999"))
+	_, _ = cached.GetCep(context.Background(), cep.MustParse("99999999"))
+
+	// assert
+	stub.AssertNumberOfCalls(t, "GetCep", 2)
+}
+
+func TestCachedCepClient_Refresh(t *testing.T) {
+	// arrange
+	stub := NewCepClientStub(nil)
+	stub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	cached := NewCachedCepClient(stub, time.Minute)
+
+	// act
+	_, err := cached.Refresh(context.Background(), cep.MustParse("01001000"))
+	_, _ = cached.GetCep(context.Background(), cep.MustParse("01001000"))
+
+	// assert
+	require.NoError(t, err)
+	stub.AssertNumberOfCalls(t, "GetCep", 1)
+	assert.Equal(t, []cep.CEP{cep.MustParse("01001000")}, cached.TopRequested(10), "only the request is counted")
+}
+
+func TestCachedCepClient_TopRequested(t *testing.T) {
+	// arrange
+	stub := NewCepClientStub(nil)
+	stub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	cached := NewCachedCepClient(stub, time.Minute)
+
+	for code, requests := range map[string]int{"01001000": 3, "20040020": 5, "30130010": 1, "40020000": 3} {
+		for range requests {
+			_, _ = cached.GetCep(context.Background(), cep.MustParse(code))
+		}
+	}
+
+	// act
+	top := cached.TopRequested(3)
+
+	// assert
+	assert.Equal(t, []cep.CEP{cep.MustParse("20040020"), cep.MustParse("01001000"), cep.MustParse("40020000")}, top)
+	assert.Len(t, cached.TopRequested(10), 4)
+}
+
+func TestCachedWeatherClient_GetWeather(t *testing.T) {
+	// arrange
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 25), nil)
+	cached := NewCachedWeatherClient(stub, time.Minute)
+	portuguese := i18n.WithLang(context.Background(), i18n.Portuguese)
+
+	// act
+	_, _ = cached.GetWeather(context.Background(), "São Paulo")
+	_, _ = cached.GetWeather(context.Background(), "sao paulo")
+	_, _ = cached.GetWeather(portuguese, "São Paulo")
+	result, err := cached.GetWeather(portuguese, "SÃO PAULO")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, result.TemperatureC)
+	stub.AssertNumberOfCalls(t, "GetWeather", 2)
+}
+
+func TestCachedWeatherClient_Refresh(t *testing.T) {
+	// arrange
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 25), nil).Once()
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 27), nil).Once()
+	cached := NewCachedWeatherClient(stub, time.Minute)
+
+	// act
+	_, _ = cached.GetCityWeather(context.Background(), "SP", "São Paulo")
+	_, err := cached.Refresh(context.Background(), "SP", "São Paulo")
+	result, _ := cached.GetCityWeather(context.Background(), "SP", "São Paulo")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 27.0, result.TemperatureC)
+	stub.AssertNumberOfCalls(t, "GetWeather", 2)
+}
+
+func TestCachedWeatherClient_KeepsStatesApart(t *testing.T) {
+	// arrange
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, "Bom Jesus").Return(observation("weatherapi", 31), nil).Once()
+	stub.On("GetWeather", mock.Anything, "Bom Jesus").Return(observation("weatherapi", 18), nil).Once()
+	cached := NewCachedWeatherClient(stub, time.Minute)
+
+	// act
+	inPiaui, _ := cached.GetCityWeather(context.Background(), "PI", "Bom Jesus")
+	inRioGrande, _ := cached.GetCityWeather(context.Background(), "RS", "Bom Jesus")
+	again, err := cached.GetCityWeather(context.Background(), "pi", "Bom Jesus")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 31.0, inPiaui.TemperatureC)
+	assert.Equal(t, 18.0, inRioGrande.TemperatureC)
+	assert.Equal(t, 31.0, again.TemperatureC)
+	stub.AssertNumberOfCalls(t, "GetWeather", 2)
+}
+
+func TestCachedWeatherClient_GetFreshCityWeather(t *testing.T) {
+	// arrange
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 25), nil).Once()
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 27), nil).Once()
+	cached := NewCachedWeatherClient(stub, time.Minute)
+
+	// act
+	_, _ = cached.GetCityWeather(context.Background(), "SP", "São Paulo")
+	fresh, err := cached.GetFreshCityWeather(context.Background(), "SP", "São Paulo")
+	result, _ := cached.GetCityWeather(context.Background(), "SP", "São Paulo")
+
+	// assert: the fresh observation also renews the cache
+	require.NoError(t, err)
+	assert.Equal(t, 27.0, fresh.TemperatureC)
+	assert.Equal(t, 27.0, result.TemperatureC)
+	stub.AssertNumberOfCalls(t, "GetWeather", 2)
+}
+
+func TestCachedWeatherClient_FailuresAreNotCached(t *testing.T) {
+	// arrange
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(nil, cErrors.WeatherClientInternalError).Once()
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 25), nil).Once()
+	cached := NewCachedWeatherClient(stub, time.Minute)
+
+	// act
+	_, err := cached.GetWeather(context.Background(), "São Paulo")
+	result, retryErr := cached.GetWeather(context.Background(), "São Paulo")
+
+	// assert
+	assert.ErrorIs(t, err, cErrors.WeatherClientInternalError)
+	require.NoError(t, retryErr)
+	assert.Equal(t, 25.0, result.TemperatureC)
+}
+
+func TestCachedWeatherClient_ServeStaleWhen(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	stub := NewWeatherClientStub(nil)
+	stub.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 25), nil)
+	cached := NewCachedWeatherClient(stub, time.Minute)
+	cached.cache.now = func() time.Time { return now }
+	stale := false
+	cached.ServeStaleWhen(func() bool { return stale })
+
+	// act
+	_, _ = cached.GetWeather(context.Background(), "São Paulo")
+	now = now.Add(2 * time.Minute)
+	stale = true
+	result, err := cached.GetWeather(context.Background(), "São Paulo")
+	_, _ = cached.GetWeather(context.Background(), "Rio de Janeiro")
+
+	// assert: the expired entry is served; unknown cities are still looked up
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, result.TemperatureC)
+	stub.AssertNumberOfCalls(t, "GetWeather", 2)
+}
@@ -5,16 +5,19 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
 	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
 	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/alexduzi/labcloudrun/internal/model"
 )
 
 type CepClientInterface interface {
-	GetCep(ctx context.Context, cep string) (*model.ViacepResponse, error)
+	GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error)
+	SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error)
 }
 
 type CepClient struct {
@@ -31,34 +34,54 @@ func NewCepClient(cfg *config.Config) *CepClient {
 	}
 }
 
-func (c CepClient) GetCep(ctx context.Context, cep string) (*model.ViacepResponse, error) {
-	cepApiUrl := strings.Replace(c.config.ViaCEPBaseURL, "{cep}", cep, 1)
+func (c CepClient) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	cepApiUrl := strings.Replace(c.config.ViaCEPBaseURL, "{cep}", code.String(), 1)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", cepApiUrl, nil)
-	if err != nil {
+	var cepRes model.ViacepResponse
+	if err := c.get(ctx, cepApiUrl, &cepRes); err != nil {
 		return nil, err
 	}
 
+	return &cepRes, nil
+}
+
+// SearchAddress finds the CEPs of a street through ViaCEP's reverse lookup.
+// ViaCEP answers 400 when city or street have fewer than 3 characters
+func (c CepClient) SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error) {
+	searchUrl := strings.NewReplacer(
+		"{uf}", url.PathEscape(uf),
+		"{city}", url.PathEscape(city),
+		"{street}", url.PathEscape(street),
+	).Replace(c.config.ViaCEPSearchURL)
+
+	var searchRes []model.ViacepResponse
+	if err := c.get(ctx, searchUrl, &searchRes); err != nil {
+		return nil, err
+	}
+
+	return searchRes, nil
+}
+
+func (c CepClient) get(ctx context.Context, apiUrl string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiUrl, nil)
+	if err != nil {
+		return err
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, cErrors.NewCepClientHTTPError(resp.StatusCode)
+		return cErrors.NewCepClientHTTPError(resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var cepRes model.ViacepResponse
-	err = json.Unmarshal(body, &cepRes)
-	if err != nil {
-		return nil, err
-	}
-
-	return &cepRes, nil
+	return json.Unmarshal(body, target)
 }
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/cepindex"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// OfflineCepClient answers CEP lookups from a local index built by
+// cmd/cepindex, for air-gapped environments and as a warm tier
+type OfflineCepClient struct {
+	index *cepindex.Index
+}
+
+func NewOfflineCepClient(index *cepindex.Index) *OfflineCepClient {
+	return &OfflineCepClient{index: index}
+}
+
+// OpenOfflineCepClient opens the index file at path
+func OpenOfflineCepClient(path string) (*OfflineCepClient, error) {
+	index, err := cepindex.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewOfflineCepClient(index), nil
+}
+
+// GetCep mirrors ViaCEP: an unknown CEP is a response with Erro set
+func (o OfflineCepClient) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	record, ok, err := o.index.Lookup(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		erro := "true"
+		return &model.ViacepResponse{Erro: &erro}, nil
+	}
+
+	response := &model.ViacepResponse{
+		Cep:         record.Cep.Formatted(),
+		Logradouro:  record.Street,
+		Complemento: record.Complement,
+		Bairro:      record.Neighborhood,
+		Localidade:  record.City,
+		Uf:          record.UF,
+		Ibge:        record.Ibge,
+		Ddd:         record.Ddd,
+	}
+	if state, ok := cep.StateOf(record.UF); ok {
+		response.Estado = state.Name
+		response.Regiao = state.Region
+	}
+
+	return response, nil
+}
+
+// SearchAddress is not supported: the index is keyed by CEP only
+func (o OfflineCepClient) SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error) {
+	return nil, cErrors.CepSearchUnsupported
+}
+
+// Close releases the index file
+func (o OfflineCepClient) Close() error {
+	return o.index.Close()
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/cepindex"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var offlineRecords = []cepindex.Record{
+	{Cep: "01001000", Street: "Praça da Sé", Complement: "lado ímpar", Neighborhood: "Sé", City: "São Paulo", UF: "SP", Ibge: "3550308", Ddd: "11"},
+	{Cep: "20040020", Street: "Praça Pio X", Neighborhood: "Centro", City: "Rio de Janeiro", UF: "RJ", Ibge: "3304557", Ddd: "21"},
+}
+
+// writeOfflineIndex grava um índice com offlineRecords e retorna o caminho
+func writeOfflineIndex(t *testing.T) string {
+	var buf bytes.Buffer
+	require.NoError(t, cepindex.Write(&buf, offlineRecords))
+
+	path := filepath.Join(t.TempDir(), "ceps.idx")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	return path
+}
+
+func openOfflineClient(t *testing.T) *OfflineCepClient {
+	offline, err := OpenOfflineCepClient(writeOfflineIndex(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { offline.Close() })
+
+	return offline
+}
+
+func TestOfflineCepClient_GetCep_Found(t *testing.T) {
+	// arrange
+	offline := openOfflineClient(t)
+
+	// act
+	result, err := offline.GetCep(context.Background(), cep.MustParse("01001-000"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ViacepResponse{
+		Cep:         "01001-000",
+		Logradouro:  "Praça da Sé",
+		Complemento: "lado ímpar",
+		Bairro:      "Sé",
+		Localidade:  "São Paulo",
+		Uf:          "SP",
+		Estado:      "São Paulo",
+		Regiao:      "Sudeste",
+		Ibge:        "3550308",
+		Ddd:         "11",
+	}, result)
+}
+
+func TestOfflineCepClient_GetCep_NotFound(t *testing.T) {
+	// arrange
+	offline := openOfflineClient(t)
+
+	// act
+	result, err := offline.GetCep(context.Background(), cep.MustParse("01310-100"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Erro)
+}
+
+func TestOfflineCepClient_GetCep_CanceledContext(t *testing.T) {
+	offline := openOfflineClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := offline.GetCep(ctx, cep.MustParse("01001-000"))
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestOfflineCepClient_SearchAddress_Unsupported(t *testing.T) {
+	offline := openOfflineClient(t)
+
+	result, err := offline.SearchAddress(context.Background(), "SP", "São Paulo", "Praça da Sé")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.CepSearchUnsupported)
+}
+
+func TestTieredCepClient_WarmHit(t *testing.T) {
+	// arrange
+	online := NewCepClientStub(&config.Config{})
+	tiered := NewTieredCepClient(openOfflineClient(t), online)
+
+	// act
+	result, err := tiered.GetCep(context.Background(), cep.MustParse("20040-020"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Praça Pio X", result.Logradouro)
+	online.AssertNotCalled(t, "GetCep", mock.Anything, mock.Anything)
+}
+
+func TestTieredCepClient_WarmMissFallsBackOnline(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	code := cep.MustParse("01310-100")
+
+	online := NewCepClientStub(&config.Config{})
+	online.On("GetCep", ctx, code).Return(model.GetViacepResponseMock("01310-100"), nil)
+
+	tiered := NewTieredCepClient(openOfflineClient(t), online)
+
+	// act
+	result, err := tiered.GetCep(ctx, code)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "01310-100", result.Cep)
+	online.AssertExpectations(t)
+}
+
+func TestTieredCepClient_WarmErrorFallsBackOnline(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	code := cep.MustParse("01001-000")
+
+	warm := NewCepClientStub(&config.Config{})
+	warm.On("GetCep", ctx, code).Return(nil, cepindex.ErrInvalidIndex)
+
+	online := NewCepClientStub(&config.Config{})
+	online.On("GetCep", ctx, code).Return(model.GetViacepResponseMock("01001-000"), nil)
+
+	tiered := NewTieredCepClient(warm, online)
+
+	// act
+	result, err := tiered.GetCep(ctx, code)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "01001-000", result.Cep)
+	online.AssertExpectations(t)
+}
+
+func TestTieredCepClient_SearchAddressGoesOnline(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	results := []model.ViacepResponse{*model.GetViacepResponseMock("01001-000")}
+
+	online := NewCepClientStub(&config.Config{})
+	online.On("SearchAddress", ctx, "SP", "São Paulo", "Praça da Sé").Return(results, nil)
+
+	tiered := NewTieredCepClient(openOfflineClient(t), online)
+
+	// act
+	result, err := tiered.SearchAddress(ctx, "SP", "São Paulo", "Praça da Sé")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, results, result)
+}
+
+func TestNewCepProvider(t *testing.T) {
+	path := writeOfflineIndex(t)
+
+	testCases := []struct {
+		name     string
+		provider string
+		expected CepClientInterface
+	}{
+		{"Default provider", "", &CepClient{}},
+		{"Online", CepProviderOnline, &CepClient{}},
+		{"Offline", CepProviderOffline, &OfflineCepClient{}},
+		{"Tiered", CepProviderTiered, &TieredCepClient{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewCepProvider(&config.Config{CepProvider: tc.provider, CepOfflineIndex: path})
+
+			assert.NoError(t, err)
+			assert.IsType(t, tc.expected, provider)
+
+			if offline, ok := provider.(*OfflineCepClient); ok {
+				offline.Close()
+			}
+		})
+	}
+}
+
+func TestNewCepProvider_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{"Unknown provider", &config.Config{CepProvider: "carrier-pigeon"}},
+		{"Offline without index", &config.Config{CepProvider: CepProviderOffline}},
+		{"Tiered with missing index", &config.Config{CepProvider: CepProviderTiered, CepOfflineIndex: filepath.Join(t.TempDir(), "missing.idx")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewCepProvider(tc.cfg)
+
+			assert.Nil(t, provider)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewCepProvider(&config.Config{CepProvider: "carrier-pigeon"})
+	assert.ErrorIs(t, err, cErrors.CepProviderUnknown)
+}
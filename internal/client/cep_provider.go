@@ -0,0 +1,40 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+)
+
+const (
+	CepProviderOnline  = "online"
+	CepProviderOffline = "offline"
+	CepProviderTiered  = "tiered"
+)
+
+// NewCepProvider builds the CEP client selected by CEP_PROVIDER: ViaCEP
+// (online), the local index (offline) or the index in front of ViaCEP (tiered)
+func NewCepProvider(cfg *config.Config) (CepClientInterface, error) {
+	switch cfg.CepProvider {
+	case CepProviderOnline, "":
+		return NewCepClient(cfg), nil
+	case CepProviderOffline, CepProviderTiered:
+		if cfg.CepOfflineIndex == "" {
+			return nil, errors.New("CEP_OFFLINE_INDEX is required when CEP_PROVIDER is offline or tiered")
+		}
+
+		offline, err := OpenOfflineCepClient(cfg.CepOfflineIndex)
+		if err != nil {
+			return nil, fmt.Errorf("open CEP index: %w", err)
+		}
+
+		if cfg.CepProvider == CepProviderOffline {
+			return offline, nil
+		}
+		return NewTieredCepClient(offline, NewCepClient(cfg)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", cErrors.CepProviderUnknown, cfg.CepProvider)
+	}
+}
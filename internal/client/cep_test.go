@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCepClient_GetCep_UsesCanonicalCep(t *testing.T) {
+	// arrange
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"cep":"01001-000","localidade":"São Paulo","uf":"SP"}`))
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPBaseURL: server.URL + "/ws/{cep}/json/"})
+
+	// act
+	result, err := client.GetCep(context.Background(), cep.MustParse("01.001-000"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "/ws/01001000/json/", path)
+	assert.Equal(t, &model.ViacepResponse{Cep: "01001-000", Localidade: "São Paulo", Uf: "SP"}, result)
+}
+
+func TestCepClient_GetCep_HTTPError(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPBaseURL: server.URL + "/ws/{cep}/json/"})
+
+	// act
+	result, err := client.GetCep(context.Background(), cep.MustParse("01001000"))
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.CepClientBadRequest)
+}
+
+func TestCepClient_SearchAddress_Success(t *testing.T) {
+	// arrange
+	var requestURI string
+	fixture := serveFixture(t, "viacep/search_sp_sao_paulo_paulista.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		fixture(w, r)
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPSearchURL: server.URL + "/ws/{uf}/{city}/{street}/json/"})
+
+	// act
+	result, err := client.SearchAddress(context.Background(), "SP", "São Paulo", "Avenida Paulista")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "/ws/SP/S%C3%A3o%20Paulo/Avenida%20Paulista/json/", requestURI)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "01310-100", result[1].Cep)
+	assert.Equal(t, "Avenida Paulista", result[1].Logradouro)
+}
+
+func TestCepClient_SearchAddress_NoResults(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPSearchURL: server.URL + "/ws/{uf}/{city}/{street}/json/"})
+
+	// act
+	result, err := client.SearchAddress(context.Background(), "SP", "São Paulo", "Rua Inexistente")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestCepClient_SearchAddress_BadRequest(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewCepClient(&config.Config{ViaCEPSearchURL: server.URL + "/ws/{uf}/{city}/{street}/json/"})
+
+	// act
+	result, err := client.SearchAddress(context.Background(), "SP", "SP", "Av")
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.CepClientBadRequest)
+}
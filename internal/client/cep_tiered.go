@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// TieredCepClient serves CEPs from a warm tier, usually the offline index,
+// and only calls the online provider when the warm tier misses or fails
+type TieredCepClient struct {
+	warm   CepClientInterface
+	online CepClientInterface
+}
+
+func NewTieredCepClient(warm, online CepClientInterface) *TieredCepClient {
+	return &TieredCepClient{warm: warm, online: online}
+}
+
+func (t TieredCepClient) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	response, err := t.warm.GetCep(ctx, code)
+	if err == nil && response.Erro == nil {
+		return response, nil
+	}
+
+	if err != nil {
+		slog.Warn("Warm CEP tier failed, falling back to online provider", "cep", code, "error", err)
+	}
+
+	return t.online.GetCep(ctx, code)
+}
+
+// SearchAddress always goes to the online provider
+func (t TieredCepClient) SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error) {
+	return t.online.SearchAddress(ctx, uf, city, street)
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+const (
+	StrategySingle    = "single"
+	StrategyFailover  = "failover"
+	StrategyConsensus = "consensus"
+)
+
+// WeightedProvider is a weather client taking part in a composite lookup
+type WeightedProvider struct {
+	Name    string
+	Client  WeatherClientInterface
+	Weight  float64
+	Timeout time.Duration
+}
+
+// CompositeWeatherClient queries several providers, either in order until one
+// succeeds (failover) or all at once returning the weighted median (consensus)
+type CompositeWeatherClient struct {
+	strategy         string
+	providers        []WeightedProvider
+	outlierThreshold float64
+}
+
+func NewCompositeWeatherClient(strategy string, providers []WeightedProvider, outlierThreshold float64) *CompositeWeatherClient {
+	for i := range providers {
+		if providers[i].Weight <= 0 {
+			providers[i].Weight = 1
+		}
+	}
+
+	return &CompositeWeatherClient{
+		strategy:         strategy,
+		providers:        providers,
+		outlierThreshold: outlierThreshold,
+	}
+}
+
+// weatherQuery is a lookup every provider can answer, so failover and
+// consensus work the same for cities and coordinates
+type weatherQuery struct {
+	location string
+	get      func(ctx context.Context, client WeatherClientInterface) (*model.Observation, error)
+}
+
+func (c CompositeWeatherClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	return c.run(ctx, weatherQuery{
+		location: city,
+		get: func(ctx context.Context, client WeatherClientInterface) (*model.Observation, error) {
+			return client.GetWeather(ctx, city)
+		},
+	})
+}
+
+func (c CompositeWeatherClient) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	return c.run(ctx, weatherQuery{
+		location: fmt.Sprintf("%.4f,%.4f", lat, lon),
+		get: func(ctx context.Context, client WeatherClientInterface) (*model.Observation, error) {
+			return client.GetWeatherByCoordinates(ctx, lat, lon)
+		},
+	})
+}
+
+// GetForecast asks the providers that support forecasts in order until one
+// succeeds, whatever the strategy: daily forecasts are not averaged
+func (c CompositeWeatherClient) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	var errs []error
+
+	for _, provider := range c.providers {
+		forecaster, ok := provider.Client.(ForecastClientInterface)
+		if !ok {
+			continue
+		}
+
+		providerCtx, cancel := ctx, context.CancelFunc(func() {})
+		if provider.Timeout > 0 {
+			providerCtx, cancel = context.WithTimeout(ctx, provider.Timeout)
+		}
+		forecast, err := forecaster.GetForecast(providerCtx, lat, lon, days)
+		cancel()
+
+		if err == nil {
+			return forecast, nil
+		}
+
+		slog.Warn("Weather provider failed", "provider", provider.Name, "location", fmt.Sprintf("%.4f,%.4f", lat, lon), "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, cErrors.ForecastUnsupported
+	}
+	return nil, fmt.Errorf("%w: %w", cErrors.WeatherProvidersUnavailable, errors.Join(errs...))
+}
+
+func (c CompositeWeatherClient) run(ctx context.Context, query weatherQuery) (*model.Observation, error) {
+	if c.strategy == StrategyConsensus {
+		return c.consensus(ctx, query)
+	}
+	return c.failover(ctx, query)
+}
+
+func (c CompositeWeatherClient) failover(ctx context.Context, query weatherQuery) (*model.Observation, error) {
+	var errs []error
+	contributions := make([]model.ProviderContribution, 0, len(c.providers))
+
+	for _, provider := range c.providers {
+		result := provider.fetch(ctx, query)
+		contributions = append(contributions, result.contribution(provider))
+
+		if result.err == nil {
+			observation := *result.observation
+			observation.Consensus = &model.Consensus{
+				Strategy:      StrategyFailover,
+				Contributions: contributions,
+			}
+			return &observation, nil
+		}
+
+		slog.Warn("Weather provider failed", "provider", provider.Name, "location", query.location, "error", result.err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, result.err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %w", cErrors.WeatherProvidersUnavailable, errors.Join(errs...))
+}
+
+func (c CompositeWeatherClient) consensus(ctx context.Context, query weatherQuery) (*model.Observation, error) {
+	results := make([]providerResult, len(c.providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range c.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = provider.fetch(ctx, query)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		errs          []error
+		samples       []weightedSample
+		base          *model.Observation
+		contributions = make([]model.ProviderContribution, len(c.providers))
+	)
+
+	for i, provider := range c.providers {
+		contributions[i] = results[i].contribution(provider)
+
+		if results[i].err != nil {
+			slog.Warn("Weather provider failed", "provider", provider.Name, "location", query.location, "error", results[i].err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name, results[i].err))
+			continue
+		}
+
+		samples = append(samples, weightedSample{value: results[i].observation.TemperatureC, weight: provider.Weight})
+		if base == nil {
+			base = results[i].observation
+		}
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%w: %w", cErrors.WeatherProvidersUnavailable, errors.Join(errs...))
+	}
+
+	median := weightedMedian(samples)
+	low, high := samples[0].value, samples[len(samples)-1].value
+
+	for i := range contributions {
+		if t := contributions[i].TemperatureC; t != nil && math.Abs(*t-median) > c.outlierThreshold {
+			contributions[i].Outlier = true
+			slog.Warn("Weather provider is an outlier", "provider", contributions[i].Provider, "temp_c", *t, "median_c", median)
+		}
+	}
+
+	observation := *base
+	observation.Source = StrategyConsensus
+	observation.TemperatureC = median
+	observation.Consensus = &model.Consensus{
+		Strategy:      StrategyConsensus,
+		SpreadC:       math.Round((high-low)*100) / 100,
+		Contributions: contributions,
+	}
+
+	return &observation, nil
+}
+
+type providerResult struct {
+	observation *model.Observation
+	err         error
+	latency     time.Duration
+}
+
+// fetch calls the provider, giving up once its own timeout expires even if
+// the underlying client ignores the context
+func (p WeightedProvider) fetch(ctx context.Context, query weatherQuery) providerResult {
+	start := time.Now()
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	// each call has its own channel with room for its only result, so the
+	// goroutine finishes even when fetch already gave up on it
+	done := make(chan providerResult, 1)
+	go func() {
+		observation, err := query.get(ctx, p.Client)
+		done <- providerResult{observation: observation, err: err}
+	}()
+
+	var result providerResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = providerResult{err: ctx.Err()}
+	}
+
+	result.latency = time.Since(start)
+	return result
+}
+
+func (r providerResult) contribution(p WeightedProvider) model.ProviderContribution {
+	contribution := model.ProviderContribution{
+		Provider:  p.Name,
+		Weight:    p.Weight,
+		LatencyMs: r.latency.Milliseconds(),
+	}
+
+	if r.err != nil {
+		contribution.Error = r.err.Error()
+	} else {
+		temperature := r.observation.TemperatureC
+		contribution.TemperatureC = &temperature
+	}
+
+	return contribution
+}
+
+type weightedSample struct {
+	value  float64
+	weight float64
+}
+
+// weightedMedian sorts samples in place and returns their weighted median,
+// averaging the two middle values when the weight splits exactly in half
+func weightedMedian(samples []weightedSample) float64 {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].value < samples[j].value
+	})
+
+	var total float64
+	for _, s := range samples {
+		total += s.weight
+	}
+
+	half := total / 2
+	var cumulative float64
+	for i, s := range samples {
+		cumulative += s.weight
+		if cumulative > half {
+			return s.value
+		}
+		if cumulative == half && i+1 < len(samples) {
+			return (s.value + samples[i+1].value) / 2
+		}
+	}
+
+	return samples[len(samples)-1].value
+}
@@ -0,0 +1,328 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompositeWeatherClientTestSuite struct {
+	suite.Suite
+	primary   *WeatherClientStub
+	secondary *WeatherClientStub
+	tertiary  *WeatherClientStub
+}
+
+func (suite *CompositeWeatherClientTestSuite) SetupTest() {
+	suite.primary = NewWeatherClientStub(nil)
+	suite.secondary = NewWeatherClientStub(nil)
+	suite.tertiary = NewWeatherClientStub(nil)
+}
+
+func (suite *CompositeWeatherClientTestSuite) providers() []WeightedProvider {
+	return []WeightedProvider{
+		{Name: "primary", Client: suite.primary, Weight: 1, Timeout: time.Second},
+		{Name: "secondary", Client: suite.secondary, Weight: 1, Timeout: time.Second},
+		{Name: "tertiary", Client: suite.tertiary, Weight: 1, Timeout: time.Second},
+	}
+}
+
+func observation(source string, tempC float64) *model.Observation {
+	return &model.Observation{
+		Source:       source,
+		Location:     model.ObservationLocation{Name: "São Paulo"},
+		TemperatureC: tempC,
+	}
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestFailover_PrimarySucceeds() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("primary", 25), nil)
+
+	client := NewCompositeWeatherClient(StrategyFailover, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "primary", result.Source)
+	assert.Equal(suite.T(), 25.0, result.TemperatureC)
+	assert.Equal(suite.T(), StrategyFailover, result.Consensus.Strategy)
+	assert.Len(suite.T(), result.Consensus.Contributions, 1)
+
+	suite.secondary.AssertNotCalled(suite.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestFailover_FallsBackToSecondary() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 26), nil)
+
+	client := NewCompositeWeatherClient(StrategyFailover, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "secondary", result.Source)
+	assert.Equal(suite.T(), 26.0, result.TemperatureC)
+
+	contributions := result.Consensus.Contributions
+	assert.Len(suite.T(), contributions, 2)
+	assert.Equal(suite.T(), "primary", contributions[0].Provider)
+	assert.Equal(suite.T(), "Weather API internal error", contributions[0].Error)
+	assert.Nil(suite.T(), contributions[0].TemperatureC)
+	assert.Equal(suite.T(), "secondary", contributions[1].Provider)
+	assert.Equal(suite.T(), 26.0, *contributions[1].TemperatureC)
+
+	suite.tertiary.AssertNotCalled(suite.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestFailover_AllProvidersFail() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientNotFound)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientBadRequest)
+
+	client := NewCompositeWeatherClient(StrategyFailover, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherProvidersUnavailable)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientInternalError)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientNotFound)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientBadRequest)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestFailover_ProviderTimeout() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").After(500*time.Millisecond).Return(observation("primary", 25), nil)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 26), nil)
+
+	providers := suite.providers()
+	providers[0].Timeout = 20 * time.Millisecond
+
+	client := NewCompositeWeatherClient(StrategyFailover, providers, 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "secondary", result.Source)
+	assert.Equal(suite.T(), context.DeadlineExceeded.Error(), result.Consensus.Contributions[0].Error)
+}
+
+// currentOnly hides GetForecast from a stub, like a provider without forecasts
+type currentOnly struct {
+	WeatherClientInterface
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestForecast_SkipsProvidersWithoutForecast() {
+	suite.secondary.On("GetForecast", mock.Anything, -23.5, -46.6, 3).Return(model.GetForecastMock("São Paulo", 3), nil)
+
+	providers := suite.providers()
+	providers[0].Client = currentOnly{suite.primary}
+
+	client := NewCompositeWeatherClient(StrategyConsensus, providers, 3)
+	result, err := client.GetForecast(context.Background(), -23.5, -46.6, 3)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result.Days, 3)
+	suite.tertiary.AssertNotCalled(suite.T(), "GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestForecast_AllProvidersFail() {
+	suite.primary.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, cErrors.WeatherClientBadRequest)
+	suite.tertiary.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, cErrors.WeatherClientInternalError)
+
+	client := NewCompositeWeatherClient(StrategyFailover, suite.providers(), 3)
+	result, err := client.GetForecast(context.Background(), -23.5, -46.6, 3)
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherProvidersUnavailable)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientBadRequest)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestForecast_Unsupported() {
+	providers := []WeightedProvider{{Name: "primary", Client: currentOnly{suite.primary}}}
+
+	client := NewCompositeWeatherClient(StrategyFailover, providers, 3)
+	result, err := client.GetForecast(context.Background(), -23.5, -46.6, 3)
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.ForecastUnsupported)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_ReturnsMedianAndSpread() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("primary", 25), nil)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 26.5), nil)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("tertiary", 26), nil)
+
+	client := NewCompositeWeatherClient(StrategyConsensus, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StrategyConsensus, result.Source)
+	assert.Equal(suite.T(), 26.0, result.TemperatureC)
+	assert.Equal(suite.T(), "São Paulo", result.Location.Name)
+	assert.Equal(suite.T(), 1.5, result.Consensus.SpreadC)
+	assert.Len(suite.T(), result.Consensus.Contributions, 3)
+
+	for _, contribution := range result.Consensus.Contributions {
+		assert.False(suite.T(), contribution.Outlier, contribution.Provider)
+	}
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_FlagsOutliers() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("primary", 25), nil)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 25.4), nil)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("tertiary", 35), nil)
+
+	client := NewCompositeWeatherClient(StrategyConsensus, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 25.4, result.TemperatureC)
+	assert.Equal(suite.T(), 10.0, result.Consensus.SpreadC)
+
+	contributions := result.Consensus.Contributions
+	assert.False(suite.T(), contributions[0].Outlier)
+	assert.False(suite.T(), contributions[1].Outlier)
+	assert.True(suite.T(), contributions[2].Outlier)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_HonoursWeights() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("primary", 20), nil)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 30), nil)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("tertiary", 31), nil)
+
+	providers := suite.providers()
+	providers[0].Weight = 3
+
+	client := NewCompositeWeatherClient(StrategyConsensus, providers, 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 20.0, result.TemperatureC)
+	assert.Equal(suite.T(), 3.0, result.Consensus.Contributions[0].Weight)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_IgnoresFailedProviders() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("secondary", 24), nil)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(observation("tertiary", 26), nil)
+
+	client := NewCompositeWeatherClient(StrategyConsensus, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 25.0, result.TemperatureC)
+	assert.Equal(suite.T(), 2.0, result.Consensus.SpreadC)
+	assert.Equal(suite.T(), "Weather API internal error", result.Consensus.Contributions[0].Error)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_AllProvidersFail() {
+	suite.primary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+	suite.tertiary.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+
+	client := NewCompositeWeatherClient(StrategyConsensus, suite.providers(), 3)
+	result, err := client.GetWeather(context.Background(), "São Paulo")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherProvidersUnavailable)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestFailover_ByCoordinates() {
+	suite.primary.On("GetWeatherByCoordinates", mock.Anything, -23.55, -46.63).Return(nil, cErrors.WeatherClientInternalError)
+	suite.secondary.On("GetWeatherByCoordinates", mock.Anything, -23.55, -46.63).Return(observation("secondary", 24), nil)
+
+	client := NewCompositeWeatherClient(StrategyFailover, suite.providers(), 3)
+	result, err := client.GetWeatherByCoordinates(context.Background(), -23.55, -46.63)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "secondary", result.Source)
+	assert.Len(suite.T(), result.Consensus.Contributions, 2)
+
+	suite.primary.AssertNotCalled(suite.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (suite *CompositeWeatherClientTestSuite) TestConsensus_ByCoordinates() {
+	suite.primary.On("GetWeatherByCoordinates", mock.Anything, -23.55, -46.63).Return(observation("primary", 24), nil)
+	suite.secondary.On("GetWeatherByCoordinates", mock.Anything, -23.55, -46.63).Return(observation("secondary", 25), nil)
+	suite.tertiary.On("GetWeatherByCoordinates", mock.Anything, -23.55, -46.63).Return(observation("tertiary", 26), nil)
+
+	client := NewCompositeWeatherClient(StrategyConsensus, suite.providers(), 3)
+	result, err := client.GetWeatherByCoordinates(context.Background(), -23.55, -46.63)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StrategyConsensus, result.Source)
+	assert.Equal(suite.T(), 25.0, result.TemperatureC)
+}
+
+func TestCompositeWeatherClientSuite(t *testing.T) {
+	suite.Run(t, new(CompositeWeatherClientTestSuite))
+}
+
+func TestWeightedMedian(t *testing.T) {
+	testCases := []struct {
+		name     string
+		samples  []weightedSample
+		expected float64
+	}{
+		{"Single sample", []weightedSample{{25, 1}}, 25},
+		{"Odd count", []weightedSample{{30, 1}, {10, 1}, {20, 1}}, 20},
+		{"Even count averages middle values", []weightedSample{{10, 1}, {20, 1}}, 15},
+		{"Heavy sample wins", []weightedSample{{10, 1}, {20, 1}, {30, 5}}, 30},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, weightedMedian(tc.samples))
+		})
+	}
+}
+
+func TestNewWeatherProvider_Composite(t *testing.T) {
+	cfg := &config.Config{
+		WeatherProvider:         ProviderOpenMeteo,
+		WeatherStrategy:         StrategyFailover,
+		WeatherProviders:        []string{ProviderWeatherAPI, ProviderOpenMeteo},
+		WeatherProviderWeights:  map[string]float64{ProviderWeatherAPI: 2},
+		WeatherProviderTimeout:  5 * time.Second,
+		WeatherProviderTimeouts: map[string]time.Duration{ProviderOpenMeteo: 3 * time.Second},
+	}
+
+	provider, err := NewWeatherProvider(cfg, nil)
+
+	assert.NoError(t, err)
+	composite, ok := provider.(*CompositeWeatherClient)
+	assert.True(t, ok)
+	assert.Equal(t, StrategyFailover, composite.strategy)
+	assert.Len(t, composite.providers, 2)
+
+	assert.Equal(t, ProviderOpenMeteo, composite.providers[0].Name)
+	assert.Equal(t, 1.0, composite.providers[0].Weight)
+	assert.Equal(t, 3*time.Second, composite.providers[0].Timeout)
+
+	assert.Equal(t, ProviderWeatherAPI, composite.providers[1].Name)
+	assert.Equal(t, 2.0, composite.providers[1].Weight)
+	assert.Equal(t, 5*time.Second, composite.providers[1].Timeout)
+}
+
+func TestNewWeatherProvider_UnknownStrategy(t *testing.T) {
+	provider, err := NewWeatherProvider(&config.Config{WeatherStrategy: "roundrobin"}, nil)
+
+	assert.Nil(t, provider)
+	assert.ErrorIs(t, err, cErrors.WeatherStrategyUnknown)
+}
+
+func TestNewWeatherProvider_CompositeWithUnknownProvider(t *testing.T) {
+	cfg := &config.Config{
+		WeatherStrategy:  StrategyConsensus,
+		WeatherProviders: []string{ProviderWeatherAPI, "unknown"},
+	}
+
+	provider, err := NewWeatherProvider(cfg, nil)
+
+	assert.Nil(t, provider)
+	assert.ErrorIs(t, err, cErrors.WeatherProviderUnknown)
+}
@@ -11,10 +11,20 @@ var (
 	CepClientInternalError   = errors.New("CEP API internal error")
 	CepClientUnexpectedError = errors.New("unexpected error from CEP API")
 
+	CepProviderUnknown   = errors.New("unknown CEP provider")
+	CepSearchUnsupported = errors.New("address search is not available offline")
+
 	WeatherClientBadRequest      = errors.New("invalid request to Weather API")
 	WeatherClientNotFound        = errors.New("Weather API returned not found")
 	WeatherClientInternalError   = errors.New("Weather API internal error")
 	WeatherClientUnexpectedError = errors.New("unexpected error from Weather API")
+
+	WeatherProviderUnknown      = errors.New("unknown weather provider")
+	WeatherStrategyUnknown      = errors.New("unknown weather strategy")
+	WeatherProvidersUnavailable = errors.New("all weather providers failed")
+	ForecastUnsupported         = errors.New("weather provider does not support forecasts")
+	WeatherQuotaSoftLimited     = errors.New("weather provider quota past its soft limit")
+	WeatherQuotaExhausted       = errors.New("weather provider monthly quota exhausted")
 )
 
 func NewCepClientHTTPError(statusCode int) error {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
 	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/alexduzi/labcloudrun/internal/model"
 	"github.com/stretchr/testify/mock"
@@ -25,10 +26,18 @@ func NewCepClientStub(cfg *config.Config) *CepClientStub {
 	}
 }
 
-func (c *CepClientStub) GetCep(ctx context.Context, cep string) (*model.ViacepResponse, error) {
-	args := c.Called(ctx, cep)
+func (c *CepClientStub) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	args := c.Called(ctx, code)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.ViacepResponse), nil
 }
+
+func (c *CepClientStub) SearchAddress(ctx context.Context, uf, city, street string) ([]model.ViacepResponse, error) {
+	args := c.Called(ctx, uf, city, street)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ViacepResponse), nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
 	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/alexduzi/labcloudrun/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -44,13 +45,13 @@ func (suite *CepClientStubTestSuite) TestNewCepClientStub() {
 func (suite *CepClientStubTestSuite) TestGetCep_ReturnsNil() {
 	ctx := context.Background()
 
-	cep := "01310100"
+	code := cep.MustParse("01310100")
 
-	resMock := model.GetViacepResponseMock(cep)
+	resMock := model.GetViacepResponseMock(code.Formatted())
 
-	suite.client.On("GetCep", ctx, cep).Return(resMock, nil)
+	suite.client.On("GetCep", ctx, code).Return(resMock, nil)
 
-	result, err := suite.client.GetCep(ctx, cep)
+	result, err := suite.client.GetCep(ctx, code)
 
 	assert.NotNil(suite.T(), result)
 	assert.Nil(suite.T(), err)
@@ -78,14 +79,14 @@ func (suite *CepClientStubTestSuite) TestGetCep_WithDifferentCeps() {
 		resMock.Cep = tc.cep
 
 		if tc.cep != "" {
-			suite.client.On("GetCep", ctx, tc.cep).Return(resMock, nil)
+			suite.client.On("GetCep", ctx, cep.CEP(tc.cep)).Return(resMock, nil)
 		} else {
-			suite.client.On("GetCep", ctx, tc.cep).Return(resMock, fmt.Errorf("Parameter cep is missing."))
+			suite.client.On("GetCep", ctx, cep.CEP(tc.cep)).Return(resMock, fmt.Errorf("Parameter cep is missing."))
 		}
 
 		suite.Run(tc.name, func() {
 
-			result, err := suite.client.GetCep(ctx, tc.cep)
+			result, err := suite.client.GetCep(ctx, cep.CEP(tc.cep))
 			if tc.cep != "" {
 				assert.NotNil(suite.T(), result)
 				assert.Nil(suite.T(), err)
@@ -110,15 +111,15 @@ func (suite *CepClientStubTestSuite) TestGetCep_WithContext() {
 		{"Context.TODO", context.TODO()},
 	}
 
-	cep := "01310100"
+	code := cep.MustParse("01310100")
 
-	resMock := model.GetViacepResponseMock(cep)
+	resMock := model.GetViacepResponseMock(code.Formatted())
 
 	for _, tc := range testCases {
-		suite.client.On("GetCep", tc.ctx, cep).Return(resMock, nil)
+		suite.client.On("GetCep", tc.ctx, code).Return(resMock, nil)
 
 		suite.Run(tc.name, func() {
-			result, err := suite.client.GetCep(tc.ctx, cep)
+			result, err := suite.client.GetCep(tc.ctx, code)
 			assert.NotNil(suite.T(), result)
 			assert.Nil(suite.T(), err)
 		})
@@ -127,6 +128,37 @@ func (suite *CepClientStubTestSuite) TestGetCep_WithContext() {
 	suite.client.AssertExpectations(suite.T())
 }
 
+// TestSearchAddress_ReturnsResults testa SearchAddress com resultados configurados
+func (suite *CepClientStubTestSuite) TestSearchAddress_ReturnsResults() {
+	ctx := context.Background()
+
+	results := []model.ViacepResponse{*model.GetViacepResponseMock("01001-000")}
+
+	suite.client.On("SearchAddress", ctx, "SP", "São Paulo", "Praça da Sé").Return(results, nil)
+
+	result, err := suite.client.SearchAddress(ctx, "SP", "São Paulo", "Praça da Sé")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), results, result)
+
+	suite.client.AssertExpectations(suite.T())
+}
+
+// TestSearchAddress_ReturnsError testa SearchAddress retornando erro
+func (suite *CepClientStubTestSuite) TestSearchAddress_ReturnsError() {
+	ctx := context.Background()
+	expectedErr := fmt.Errorf("search failed")
+
+	suite.client.On("SearchAddress", ctx, "SP", "São Paulo", "Paulista").Return(nil, expectedErr)
+
+	result, err := suite.client.SearchAddress(ctx, "SP", "São Paulo", "Paulista")
+
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), expectedErr, err)
+
+	suite.client.AssertExpectations(suite.T())
+}
+
 // TestGetCep_ImplementsInterface verifica se CepClientStub implementa CepClientInterface
 func (suite *CepClientStubTestSuite) TestGetCep_ImplementsInterface() {
 	var _ CepClientInterface = suite.client
@@ -178,15 +210,15 @@ func TestCepClientStub_GetCep_MultipleCallsConsistency(t *testing.T) {
 	client := NewCepClientStub(cfg)
 	ctx := context.Background()
 
-	cep := "01310100"
+	code := cep.MustParse("01310100")
 
-	resMock := model.GetViacepResponseMock(cep)
+	resMock := model.GetViacepResponseMock(code.Formatted())
 
-	client.On("GetCep", ctx, cep).Return(resMock, nil)
+	client.On("GetCep", ctx, code).Return(resMock, nil)
 
 	// Múltiplas chamadas devem retornar o mesmo resultado
 	for i := 0; i < 5; i++ {
-		result, err := client.GetCep(ctx, cep)
+		result, err := client.GetCep(ctx, code)
 		assert.NotNil(t, result)
 		assert.Nil(t, err)
 	}
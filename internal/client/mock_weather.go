@@ -25,10 +25,26 @@ func NewWeatherClientStub(cfg *config.Config) *WeatherClientStub {
 	}
 }
 
-func (w *WeatherClientStub) GetWeather(ctx context.Context, city string) (*model.WeatherResponse, error) {
+func (w *WeatherClientStub) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
 	args := w.Called(ctx, city)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*model.WeatherResponse), nil
+	return args.Get(0).(*model.Observation), nil
+}
+
+func (w *WeatherClientStub) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	args := w.Called(ctx, lat, lon)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Observation), nil
+}
+
+func (w *WeatherClientStub) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	args := w.Called(ctx, lat, lon, days)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Forecast), nil
 }
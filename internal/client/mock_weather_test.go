@@ -51,7 +51,7 @@ func (suite *WeatherClientStubTestSuite) TestGetWeather_WithDifferentCities() {
 		{"Cidade vazia", ""},
 	}
 
-	resMock := &model.WeatherResponse{}
+	resMock := &model.Observation{}
 
 	for _, tc := range testCases {
 		resMock.Location.Name = tc.city
@@ -87,7 +87,7 @@ func (suite *WeatherClientStubTestSuite) TestGetWeather_WithContext() {
 		{"Context.TODO", context.TODO()},
 	}
 
-	resMock := &model.WeatherResponse{}
+	resMock := &model.Observation{}
 
 	for _, tc := range testCases {
 		suite.client.On("GetWeather", tc.ctx, "São Paulo").Return(resMock, nil)
@@ -102,6 +102,23 @@ func (suite *WeatherClientStubTestSuite) TestGetWeather_WithContext() {
 	suite.client.AssertExpectations(suite.T())
 }
 
+func (suite *WeatherClientStubTestSuite) TestGetWeatherByCoordinates() {
+	ctx := context.Background()
+
+	suite.client.On("GetWeatherByCoordinates", ctx, -23.55, -46.63).Return(&model.Observation{TemperatureC: 25}, nil)
+	suite.client.On("GetWeatherByCoordinates", ctx, 0.0, 0.0).Return(nil, fmt.Errorf("No matching location found."))
+
+	result, err := suite.client.GetWeatherByCoordinates(ctx, -23.55, -46.63)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 25.0, result.TemperatureC)
+
+	result, err = suite.client.GetWeatherByCoordinates(ctx, 0, 0)
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+
+	suite.client.AssertExpectations(suite.T())
+}
+
 func (suite *WeatherClientStubTestSuite) TestGetWeather_ImplementsInterface() {
 	var _ WeatherClientInterface = suite.client
 }
@@ -156,7 +173,7 @@ func TestWeatherClientStub_GetWeather_MultipleCallsConsistency(t *testing.T) {
 	client := NewWeatherClientStub(cfg)
 	ctx := context.Background()
 
-	resMock := &model.WeatherResponse{}
+	resMock := &model.Observation{}
 	resMock.Location.Name = "São Paulo"
 
 	client.On("GetWeather", ctx, "São Paulo").Return(resMock, nil)
@@ -246,7 +263,7 @@ func TestWeatherClientStub_GetWeather_WithSpecialCharacters(t *testing.T) {
 		"Florianópolis",
 	}
 
-	resMock := &model.WeatherResponse{}
+	resMock := &model.Observation{}
 
 	for _, city := range testCases {
 		resMock.Location.Name = city
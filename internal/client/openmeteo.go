@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// OpenMeteoClient fetches weather from Open-Meteo, which needs no API key
+// but only works by coordinates, so cities are geocoded first
+type OpenMeteoClient struct {
+	config *config.Config
+	client *http.Client
+}
+
+func NewOpenMeteoClient(cfg *config.Config) *OpenMeteoClient {
+	return &OpenMeteoClient{
+		config: cfg,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (o OpenMeteoClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	geocoding, err := o.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(geocoding.Results) == 0 {
+		return nil, cErrors.WeatherClientNotFound
+	}
+	place := geocoding.Results[0]
+
+	forecast, err := o.forecast(ctx, place.Latitude, place.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	return openMeteoToObservation(*forecast, &place, i18n.FromContext(ctx)), nil
+}
+
+// GetWeatherByCoordinates skips geocoding, so the location carries only the
+// coordinates and timezone Open-Meteo reports
+func (o OpenMeteoClient) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	forecast, err := o.forecast(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	return openMeteoToObservation(*forecast, nil, i18n.FromContext(ctx)), nil
+}
+
+// GetForecast reads the daily variables of the forecast API, up to 16 days
+func (o OpenMeteoClient) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	forecastUrl := fmt.Sprintf("%s?latitude=%s&longitude=%s&daily=%s&forecast_days=%d&timezone=auto",
+		o.config.OpenMeteoBaseURL,
+		strconv.FormatFloat(lat, 'f', 4, 64),
+		strconv.FormatFloat(lon, 'f', 4, 64),
+		openMeteoDailyFields,
+		days)
+
+	var dailyRes model.OpenMeteoDailyResponse
+	if err := o.get(ctx, forecastUrl, &dailyRes); err != nil {
+		return nil, err
+	}
+
+	return openMeteoToForecast(dailyRes, i18n.FromContext(ctx)), nil
+}
+
+func (o OpenMeteoClient) geocode(ctx context.Context, city string) (*model.OpenMeteoGeocodingResponse, error) {
+	geocodingUrl := fmt.Sprintf("%s?name=%s&count=1&language=pt&countryCode=BR&format=json",
+		o.config.OpenMeteoGeocodingURL,
+		url.QueryEscape(city))
+
+	var geocodingRes model.OpenMeteoGeocodingResponse
+	if err := o.get(ctx, geocodingUrl, &geocodingRes); err != nil {
+		return nil, err
+	}
+
+	return &geocodingRes, nil
+}
+
+func (o OpenMeteoClient) forecast(ctx context.Context, lat, lon float64) (*model.OpenMeteoForecastResponse, error) {
+	forecastUrl := fmt.Sprintf("%s?latitude=%s&longitude=%s&current=%s&timezone=auto",
+		o.config.OpenMeteoBaseURL,
+		strconv.FormatFloat(lat, 'f', 4, 64),
+		strconv.FormatFloat(lon, 'f', 4, 64),
+		openMeteoCurrentFields)
+
+	var forecastRes model.OpenMeteoForecastResponse
+	if err := o.get(ctx, forecastUrl, &forecastRes); err != nil {
+		return nil, err
+	}
+
+	return &forecastRes, nil
+}
+
+func (o OpenMeteoClient) get(ctx context.Context, apiUrl string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cErrors.NewWeatherClientHTTPError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, target)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// openMeteoCurrentFields are the current variables requested from Open-Meteo,
+// matching the fields read by openMeteoToObservation
+const openMeteoCurrentFields = "temperature_2m,relative_humidity_2m,is_day,precipitation,weather_code,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m"
+
+// openMeteoDailyFields are the daily variables read by openMeteoToForecast
+const openMeteoDailyFields = "temperature_2m_max,temperature_2m_min,precipitation_sum,weather_code"
+
+// wmoCondition describes a WMO weather interpretation code, as used by
+// Open-Meteo, in lang. Unknown codes have no text
+func wmoCondition(code int, lang i18n.Lang) string {
+	text, _ := lang.Lookup("wmo." + strconv.Itoa(code))
+	return text
+}
+
+// openMeteoToObservation adapts an Open-Meteo forecast response, describing
+// the condition in lang. The place comes from the geocoding lookup and may be
+// nil when querying by coordinates
+func openMeteoToObservation(forecast model.OpenMeteoForecastResponse, place *model.OpenMeteoPlace, lang i18n.Lang) *model.Observation {
+	current := forecast.Current
+
+	observation := &model.Observation{
+		Source:     ProviderOpenMeteo,
+		ObservedAt: parseOpenMeteoTime(current.Time, forecast.UtcOffsetSeconds),
+		Location: model.ObservationLocation{
+			Lat:      forecast.Latitude,
+			Lon:      forecast.Longitude,
+			Timezone: forecast.Timezone,
+		},
+		TemperatureC: current.Temperature2m,
+		HumidityPct:  current.RelativeHumidity2m,
+		Wind: model.Wind{
+			SpeedKph:     current.WindSpeed10m,
+			DirectionDeg: current.WindDirection10m,
+			GustKph:      current.WindGusts10m,
+		},
+		PressureMb:      current.PressureMsl,
+		PrecipitationMm: current.Precipitation,
+		Condition: model.Condition{
+			Text:  wmoCondition(current.WeatherCode, lang),
+			Code:  current.WeatherCode,
+			IsDay: current.IsDay == 1,
+		},
+	}
+
+	if place != nil {
+		observation.Location.Name = place.Name
+		observation.Location.Region = place.Admin1
+		observation.Location.Country = place.Country
+	}
+
+	return observation
+}
+
+// openMeteoToForecast adapts the daily arrays of an Open-Meteo response,
+// stopping at the shortest one
+func openMeteoToForecast(response model.OpenMeteoDailyResponse, lang i18n.Lang) *model.Forecast {
+	daily := response.Daily
+
+	forecast := &model.Forecast{
+		Source: ProviderOpenMeteo,
+		Location: model.ObservationLocation{
+			Lat:      response.Latitude,
+			Lon:      response.Longitude,
+			Timezone: response.Timezone,
+		},
+		Days: []model.ForecastDay{},
+	}
+
+	n := min(len(daily.Time), len(daily.Temperature2mMax), len(daily.Temperature2mMin),
+		len(daily.PrecipitationSum), len(daily.WeatherCode))
+	for i := range n {
+		forecast.Days = append(forecast.Days, model.ForecastDay{
+			Date:            daily.Time[i],
+			MinC:            daily.Temperature2mMin[i],
+			MaxC:            daily.Temperature2mMax[i],
+			PrecipitationMm: daily.PrecipitationSum[i],
+			Condition: model.Condition{
+				Text:  wmoCondition(daily.WeatherCode[i], lang),
+				Code:  daily.WeatherCode[i],
+				IsDay: true,
+			},
+		})
+	}
+
+	return forecast
+}
+
+// parseOpenMeteoTime reads Open-Meteo's local ISO8601 timestamps (without
+// seconds or zone) using the offset reported alongside them
+func parseOpenMeteoTime(value string, utcOffsetSeconds int) time.Time {
+	location := time.FixedZone("", utcOffsetSeconds)
+
+	observedAt, err := time.ParseInLocation("2006-01-02T15:04", value, location)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return observedAt.UTC()
+}
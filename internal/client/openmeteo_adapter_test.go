@@ -0,0 +1,120 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadOpenMeteoFixtures(t *testing.T) (model.OpenMeteoForecastResponse, model.OpenMeteoGeocodingResponse) {
+	var forecast model.OpenMeteoForecastResponse
+	assert.NoError(t, json.Unmarshal(readFixture(t, "openmeteo/forecast_sao_paulo.json"), &forecast))
+
+	var geocoding model.OpenMeteoGeocodingResponse
+	assert.NoError(t, json.Unmarshal(readFixture(t, "openmeteo/geocoding_sao_paulo.json"), &geocoding))
+
+	return forecast, geocoding
+}
+
+func TestOpenMeteoToObservation_RecordedResponse(t *testing.T) {
+	// arrange
+	forecast, geocoding := loadOpenMeteoFixtures(t)
+
+	// act
+	observation := openMeteoToObservation(forecast, &geocoding.Results[0], i18n.English)
+
+	// assert
+	assert.Equal(t, ProviderOpenMeteo, observation.Source)
+	assert.Equal(t, time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC), observation.ObservedAt)
+
+	assert.Equal(t, "São Paulo", observation.Location.Name)
+	assert.Equal(t, "São Paulo", observation.Location.Region)
+	assert.Equal(t, "Brasil", observation.Location.Country)
+	assert.Equal(t, -23.5, observation.Location.Lat)
+	assert.Equal(t, -46.625, observation.Location.Lon)
+	assert.Equal(t, "America/Sao_Paulo", observation.Location.Timezone)
+
+	assert.Equal(t, 31.4, observation.TemperatureC)
+	assert.Equal(t, 41.0, observation.HumidityPct)
+	assert.Equal(t, 9.4, observation.Wind.SpeedKph)
+	assert.Equal(t, 315.0, observation.Wind.DirectionDeg)
+	assert.Equal(t, 22.7, observation.Wind.GustKph)
+	assert.Equal(t, 1013.2, observation.PressureMb)
+	assert.Equal(t, 0.0, observation.PrecipitationMm)
+
+	assert.Equal(t, "Partly cloudy", observation.Condition.Text)
+	assert.Equal(t, 2, observation.Condition.Code)
+	assert.True(t, observation.Condition.IsDay)
+}
+
+func TestOpenMeteoToObservation_WithoutPlace(t *testing.T) {
+	// arrange
+	forecast, _ := loadOpenMeteoFixtures(t)
+
+	// act
+	observation := openMeteoToObservation(forecast, nil, i18n.English)
+
+	// assert
+	assert.Empty(t, observation.Location.Name)
+	assert.Equal(t, -23.5, observation.Location.Lat)
+	assert.Equal(t, 31.4, observation.TemperatureC)
+}
+
+func TestOpenMeteoToObservation_WMOConditions(t *testing.T) {
+	testCases := []struct {
+		code     int
+		expected string
+	}{
+		{0, "Clear sky"},
+		{3, "Overcast"},
+		{45, "Fog"},
+		{63, "Moderate rain"},
+		{82, "Violent rain showers"},
+		{95, "Thunderstorm"},
+		{42, ""},
+	}
+
+	forecast, _ := loadOpenMeteoFixtures(t)
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			forecast.Current.WeatherCode = tc.code
+
+			observation := openMeteoToObservation(forecast, nil, i18n.English)
+
+			assert.Equal(t, tc.code, observation.Condition.Code)
+			assert.Equal(t, tc.expected, observation.Condition.Text)
+		})
+	}
+}
+
+func TestOpenMeteoToObservation_LocalizedConditions(t *testing.T) {
+	forecast, _ := loadOpenMeteoFixtures(t)
+	forecast.Current.WeatherCode = 63
+
+	assert.Equal(t, "Chuva moderada", openMeteoToObservation(forecast, nil, i18n.Portuguese).Condition.Text)
+	assert.Equal(t, "Lluvia moderada", openMeteoToObservation(forecast, nil, i18n.Spanish).Condition.Text)
+}
+
+func TestParseOpenMeteoTime(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		offset   int
+		expected time.Time
+	}{
+		{"Brasília time", "2026-01-10T14:30", -10800, time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC)},
+		{"UTC", "2026-01-10T14:30", 0, time.Date(2026, 1, 10, 14, 30, 0, 0, time.UTC)},
+		{"Invalid value", "yesterday", 0, time.Time{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseOpenMeteoTime(tc.value, tc.offset))
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// readFixture lê uma resposta gravada de um provedor em testdata
+func readFixture(t *testing.T, name string) []byte {
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+// serveFixture responde com uma resposta gravada de um provedor
+func serveFixture(t *testing.T, name string) http.HandlerFunc {
+	body := readFixture(t, name)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
+
+type OpenMeteoClientTestSuite struct {
+	suite.Suite
+	geocoding http.HandlerFunc
+	forecast  http.HandlerFunc
+	requests  []*http.Request
+	server    *httptest.Server
+	client    *OpenMeteoClient
+}
+
+func (suite *OpenMeteoClientTestSuite) SetupTest() {
+	suite.geocoding = serveFixture(suite.T(), "openmeteo/geocoding_sao_paulo.json")
+	suite.forecast = serveFixture(suite.T(), "openmeteo/forecast_sao_paulo.json")
+	suite.requests = nil
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		suite.requests = append(suite.requests, r)
+		suite.geocoding(w, r)
+	})
+	mux.HandleFunc("/v1/forecast", func(w http.ResponseWriter, r *http.Request) {
+		suite.requests = append(suite.requests, r)
+		suite.forecast(w, r)
+	})
+	suite.server = httptest.NewServer(mux)
+
+	suite.client = NewOpenMeteoClient(&config.Config{
+		WeatherProvider:       ProviderOpenMeteo,
+		OpenMeteoBaseURL:      suite.server.URL + "/v1/forecast",
+		OpenMeteoGeocodingURL: suite.server.URL + "/v1/search",
+	})
+}
+
+func (suite *OpenMeteoClientTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_Success() {
+	result, err := suite.client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), result)
+	assert.Equal(suite.T(), ProviderOpenMeteo, result.Source)
+	assert.Equal(suite.T(), 31.4, result.TemperatureC)
+	assert.Equal(suite.T(), "Partly cloudy", result.Condition.Text)
+	assert.Equal(suite.T(), "São Paulo", result.Location.Name)
+	assert.Equal(suite.T(), "São Paulo", result.Location.Region)
+	assert.Equal(suite.T(), "Brasil", result.Location.Country)
+	assert.Equal(suite.T(), -23.5, result.Location.Lat)
+	assert.Equal(suite.T(), -46.625, result.Location.Lon)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_SendsExpectedQueries() {
+	_, err := suite.client.GetWeather(context.Background(), "São Paulo")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.requests, 2)
+
+	geocoding := suite.requests[0].URL.Query()
+	assert.Equal(suite.T(), "São Paulo", geocoding.Get("name"))
+	assert.Equal(suite.T(), "BR", geocoding.Get("countryCode"))
+	assert.Equal(suite.T(), "1", geocoding.Get("count"))
+
+	forecast := suite.requests[1].URL.Query()
+	assert.Equal(suite.T(), "-23.5475", forecast.Get("latitude"))
+	assert.Equal(suite.T(), "-46.6361", forecast.Get("longitude"))
+	assert.Equal(suite.T(), openMeteoCurrentFields, forecast.Get("current"))
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_CityNotFound() {
+	suite.geocoding = serveFixture(suite.T(), "openmeteo/geocoding_empty.json")
+
+	result, err := suite.client.GetWeather(context.Background(), "Cidade Inexistente")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientNotFound)
+	assert.Len(suite.T(), suite.requests, 1)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_GeocodingBadRequest() {
+	suite.geocoding = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	result, err := suite.client.GetWeather(context.Background(), "")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientBadRequest)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_ForecastInternalError() {
+	suite.forecast = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	result, err := suite.client.GetWeather(context.Background(), "São Paulo")
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientInternalError)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeather_InvalidJSON() {
+	suite.forecast = func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{invalid"))
+	}
+
+	result, err := suite.client.GetWeather(context.Background(), "São Paulo")
+
+	assert.Nil(suite.T(), result)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeatherByCoordinates_SkipsGeocoding() {
+	result, err := suite.client.GetWeatherByCoordinates(context.Background(), -22.9068, -43.1729)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 31.4, result.TemperatureC)
+	assert.Equal(suite.T(), "", result.Location.Name)
+	assert.Len(suite.T(), suite.requests, 1)
+
+	forecast := suite.requests[0].URL.Query()
+	assert.Equal(suite.T(), "/v1/forecast", suite.requests[0].URL.Path)
+	assert.Equal(suite.T(), "-22.9068", forecast.Get("latitude"))
+	assert.Equal(suite.T(), "-43.1729", forecast.Get("longitude"))
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetWeatherByCoordinates_ForecastError() {
+	suite.forecast = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	result, err := suite.client.GetWeatherByCoordinates(context.Background(), 95, 0)
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, cErrors.WeatherClientBadRequest)
+}
+
+func (suite *OpenMeteoClientTestSuite) TestGetForecast_Success() {
+	suite.forecast = serveFixture(suite.T(), "openmeteo/daily_sao_paulo.json")
+	ctx := i18n.WithLang(context.Background(), i18n.Portuguese)
+
+	result, err := suite.client.GetForecast(ctx, -23.5475, -46.6361, 3)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.requests, 1)
+
+	query := suite.requests[0].URL.Query()
+	assert.Equal(suite.T(), openMeteoDailyFields, query.Get("daily"))
+	assert.Equal(suite.T(), "3", query.Get("forecast_days"))
+
+	assert.Equal(suite.T(), ProviderOpenMeteo, result.Source)
+	assert.Equal(suite.T(), "America/Sao_Paulo", result.Location.Timezone)
+	assert.Len(suite.T(), result.Days, 3)
+	assert.Equal(suite.T(), model.ForecastDay{
+		Date:            "2026-01-12",
+		MinC:            18.6,
+		MaxC:            27.1,
+		PrecipitationMm: 12.3,
+		Condition:       model.Condition{Text: "Trovoada", Code: 95, IsDay: true},
+	}, result.Days[2])
+	assert.Nil(suite.T(), result.Alerts, "Open-Meteo does not report alerts")
+}
+
+func (suite *OpenMeteoClientTestSuite) TestOpenMeteoClient_ImplementsInterface() {
+	var _ WeatherClientInterface = suite.client
+	var _ ForecastClientInterface = suite.client
+}
+
+func TestOpenMeteoClientSuite(t *testing.T) {
+	suite.Run(t, new(OpenMeteoClientTestSuite))
+}
+
+func TestNewWeatherProvider(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider string
+		expected WeatherClientInterface
+	}{
+		{"Default provider", "", &WeatherClient{}},
+		{"WeatherAPI", ProviderWeatherAPI, &WeatherClient{}},
+		{"Open-Meteo", ProviderOpenMeteo, &OpenMeteoClient{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewWeatherProvider(&config.Config{WeatherProvider: tc.provider}, nil)
+
+			assert.NoError(t, err)
+			assert.IsType(t, tc.expected, provider)
+		})
+	}
+}
+
+func TestNewWeatherProvider_Unknown(t *testing.T) {
+	provider, err := NewWeatherProvider(&config.Config{WeatherProvider: "unknown"}, nil)
+
+	assert.Nil(t, provider)
+	assert.ErrorIs(t, err, cErrors.WeatherProviderUnknown)
+}
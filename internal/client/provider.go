@@ -0,0 +1,98 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/quota"
+)
+
+const (
+	ProviderWeatherAPI = "weatherapi"
+	ProviderOpenMeteo  = "openmeteo"
+)
+
+// NewWeatherProvider builds the weather client selected by WEATHER_PROVIDER,
+// wrapped in a composite client when WEATHER_STRATEGY is failover or consensus.
+// WeatherAPI calls are counted by tracker, when not nil
+func NewWeatherProvider(cfg *config.Config, tracker *quota.Tracker) (WeatherClientInterface, error) {
+	switch cfg.WeatherStrategy {
+	case StrategySingle, "":
+		return newWeatherProvider(cfg, cfg.WeatherProvider, tracker, false)
+	case StrategyFailover, StrategyConsensus:
+		return newCompositeWeatherProvider(cfg, tracker)
+	default:
+		return nil, fmt.Errorf("%w: %q", cErrors.WeatherStrategyUnknown, cfg.WeatherStrategy)
+	}
+}
+
+// WeatherProviders names the providers NewWeatherProvider calls
+func WeatherProviders(cfg *config.Config) []string {
+	switch cfg.WeatherStrategy {
+	case StrategySingle, "":
+		if cfg.WeatherProvider == "" {
+			return []string{ProviderWeatherAPI}
+		}
+		return []string{cfg.WeatherProvider}
+	default:
+		return providerOrder(cfg)
+	}
+}
+
+func newWeatherProvider(cfg *config.Config, name string, tracker *quota.Tracker, composite bool) (WeatherClientInterface, error) {
+	switch name {
+	case ProviderWeatherAPI, "":
+		if tracker == nil {
+			return NewWeatherClient(cfg), nil
+		}
+		return NewQuotaGuard(cfg, NewWeatherClient(cfg), tracker, composite), nil
+	case ProviderOpenMeteo:
+		return NewOpenMeteoClient(cfg), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", cErrors.WeatherProviderUnknown, name)
+	}
+}
+
+func newCompositeWeatherProvider(cfg *config.Config, tracker *quota.Tracker) (WeatherClientInterface, error) {
+	var providers []WeightedProvider
+
+	for _, name := range providerOrder(cfg) {
+		weatherClient, err := newWeatherProvider(cfg, name, tracker, true)
+		if err != nil {
+			return nil, err
+		}
+
+		timeout, ok := cfg.WeatherProviderTimeouts[name]
+		if !ok {
+			timeout = cfg.WeatherProviderTimeout
+		}
+
+		providers = append(providers, WeightedProvider{
+			Name:    name,
+			Client:  weatherClient,
+			Weight:  cfg.WeatherProviderWeights[name],
+			Timeout: timeout,
+		})
+	}
+
+	return NewCompositeWeatherClient(cfg.WeatherStrategy, providers, cfg.WeatherOutlierThreshold), nil
+}
+
+// providerOrder puts the primary provider first, followed by the remaining
+// WEATHER_PROVIDERS entries in the order they were configured
+func providerOrder(cfg *config.Config) []string {
+	var order []string
+	if cfg.WeatherProvider != "" {
+		order = append(order, cfg.WeatherProvider)
+	}
+
+	for _, name := range cfg.WeatherProviders {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+
+	return order
+}
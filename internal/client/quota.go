@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/quota"
+)
+
+// QuotaGuard counts the calls of a weather client against its monthly
+// quota. Past the soft limit it asks the fallback client first, when there
+// is one, or refuses with WeatherQuotaSoftLimited, so a composite client
+// moves on to the other providers and the weather cache answers only from
+// its entries. At the quota the client is no longer called and
+// the guard fails with WeatherQuotaExhausted
+type QuotaGuard struct {
+	next           WeatherClientInterface
+	fallback       WeatherClientInterface
+	refuseWhenSoft bool
+	tracker        *quota.Tracker
+}
+
+// NewQuotaGuard guards next with tracker. In the fallback mode a single
+// provider degrades to Open-Meteo, while a provider of a composite client
+// steps aside for the others; in the cache mode the weather cache serves
+// expired observations and the misses are refused, keeping the rest of the
+// quota
+func NewQuotaGuard(cfg *config.Config, next WeatherClientInterface, tracker *quota.Tracker, composite bool) *QuotaGuard {
+	guard := &QuotaGuard{next: next, tracker: tracker}
+	switch cfg.WeatherAPIQuotaSoftMode {
+	case config.WeatherQuotaSoftFallback:
+		if composite {
+			guard.refuseWhenSoft = true
+		} else {
+			guard.fallback = NewOpenMeteoClient(cfg)
+		}
+	case config.WeatherQuotaSoftCache:
+		guard.refuseWhenSoft = true
+	}
+	return guard
+}
+
+func (g *QuotaGuard) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	return guarded(g, func(c WeatherClientInterface) (*model.Observation, error) {
+		return c.GetWeather(ctx, city)
+	})
+}
+
+func (g *QuotaGuard) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	return guarded(g, func(c WeatherClientInterface) (*model.Observation, error) {
+		return c.GetWeatherByCoordinates(ctx, lat, lon)
+	})
+}
+
+func (g *QuotaGuard) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	return guarded(g, func(c WeatherClientInterface) (*model.Forecast, error) {
+		forecaster, ok := c.(ForecastClientInterface)
+		if !ok {
+			return nil, cErrors.ForecastUnsupported
+		}
+		return forecaster.GetForecast(ctx, lat, lon, days)
+	})
+}
+
+// guarded runs call on the fallback past the soft limit and, if that is not
+// possible or fails, on the guarded client while the quota lasts
+func guarded[T any](g *QuotaGuard, call func(WeatherClientInterface) (T, error)) (T, error) {
+	var zero T
+	var fallbackErr error
+
+	if state := g.tracker.State(); state != quota.StateOK {
+		switch {
+		case g.fallback != nil:
+			result, err := call(g.fallback)
+			if err == nil {
+				return result, nil
+			}
+			fallbackErr = err
+		case g.refuseWhenSoft && state == quota.StateSoftLimited:
+			return zero, cErrors.WeatherQuotaSoftLimited
+		}
+	}
+
+	if !g.tracker.Take() {
+		return zero, errors.Join(cErrors.WeatherQuotaExhausted, fallbackErr)
+	}
+	return call(g.next)
+}
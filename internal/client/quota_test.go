@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exhaust counts n calls on tracker
+func exhaust(t *testing.T, tracker *quota.Tracker, n int) {
+	for range n {
+		require.True(t, tracker.Take())
+	}
+}
+
+func TestQuotaGuard_CountsTheCalls(t *testing.T) {
+	// arrange
+	primary := NewWeatherClientStub(nil)
+	primary.On("GetWeather", mock.Anything, "Recife").Return(observation("weatherapi", 30), nil)
+	tracker := quota.NewTracker("weatherapi", 10, 8, 1)
+	guard := &QuotaGuard{next: primary, tracker: tracker}
+
+	// act
+	result, err := guard.GetWeather(context.Background(), "Recife")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "weatherapi", result.Source)
+	assert.Equal(t, int64(1), tracker.Status().Used)
+}
+
+func TestQuotaGuard_FallsBackPastTheSoftLimit(t *testing.T) {
+	// arrange
+	primary := NewWeatherClientStub(nil)
+	primary.On("GetWeather", mock.Anything, mock.Anything).Return(observation("weatherapi", 30), nil)
+	fallback := NewWeatherClientStub(nil)
+	fallback.On("GetWeather", mock.Anything, "Recife").Return(observation("openmeteo", 29), nil)
+	fallback.On("GetWeather", mock.Anything, "Olinda").Return(nil, cErrors.WeatherClientInternalError)
+	tracker := quota.NewTracker("weatherapi", 10, 2, 1)
+	exhaust(t, tracker, 2)
+	guard := &QuotaGuard{next: primary, fallback: fallback, tracker: tracker}
+
+	// act
+	degraded, err := guard.GetWeather(context.Background(), "Recife")
+	retried, retryErr := guard.GetWeather(context.Background(), "Olinda")
+
+	// assert: a failed fallback still reaches the primary while the quota lasts
+	require.NoError(t, err)
+	assert.Equal(t, "openmeteo", degraded.Source)
+	require.NoError(t, retryErr)
+	assert.Equal(t, "weatherapi", retried.Source)
+	assert.Equal(t, int64(3), tracker.Status().Used)
+}
+
+func TestQuotaGuard_StepsAsideInACompositeClient(t *testing.T) {
+	// arrange
+	primary := NewWeatherClientStub(nil)
+	tracker := quota.NewTracker("weatherapi", 10, 2, 1)
+	exhaust(t, tracker, 2)
+	guard := &QuotaGuard{next: primary, refuseWhenSoft: true, tracker: tracker}
+
+	// act
+	result, err := guard.GetWeatherByCoordinates(context.Background(), -8.05, -34.9)
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.WeatherQuotaSoftLimited)
+	primary.AssertNotCalled(t, "GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQuotaGuard_StopsAtTheQuota(t *testing.T) {
+	// arrange
+	primary := NewWeatherClientStub(nil)
+	fallback := NewWeatherClientStub(nil)
+	fallback.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, 3).Return(nil, cErrors.WeatherClientInternalError)
+	tracker := quota.NewTracker("weatherapi", 2, 1, 1)
+	exhaust(t, tracker, 2)
+	guard := &QuotaGuard{next: primary, fallback: fallback, tracker: tracker}
+
+	// act
+	result, err := guard.GetForecast(context.Background(), -8.05, -34.9, 3)
+
+	// assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.WeatherQuotaExhausted)
+	assert.ErrorIs(t, err, cErrors.WeatherClientInternalError)
+	primary.AssertNotCalled(t, "GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, uint64(1), tracker.Rejected())
+}
+
+func TestQuotaGuard_CacheModeRefusesPastTheSoftLimit(t *testing.T) {
+	// arrange
+	primary := NewWeatherClientStub(nil)
+	tracker := quota.NewTracker("weatherapi", 10, 2, 1)
+	exhaust(t, tracker, 2)
+	guard := NewQuotaGuard(&config.Config{WeatherAPIQuotaSoftMode: config.WeatherQuotaSoftCache}, primary, tracker, false)
+
+	// act
+	result, err := guard.GetWeather(context.Background(), "Recife")
+
+	// assert: misses of the weather cache keep the rest of the quota
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, cErrors.WeatherQuotaSoftLimited)
+	primary.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
+	assert.Equal(t, int64(2), tracker.Status().Used)
+}
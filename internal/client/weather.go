@@ -7,15 +7,53 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
 	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
 	"github.com/alexduzi/labcloudrun/internal/model"
 )
 
+// weatherAPILanguages maps response languages to WeatherAPI's lang codes.
+// English is WeatherAPI's default and needs no parameter
+var weatherAPILanguages = map[i18n.Lang]string{
+	i18n.Portuguese: "pt",
+	i18n.Spanish:    "es",
+}
+
 type WeatherClientInterface interface {
-	GetWeather(ctx context.Context, city string) (*model.WeatherResponse, error)
+	GetWeather(ctx context.Context, city string) (*model.Observation, error)
+	GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error)
+}
+
+// CityWeatherClientInterface is implemented by the weather clients that keep
+// observations by city, as the weather cache and the observation history,
+// which need the state to tell cities of the same name apart
+type CityWeatherClientInterface interface {
+	GetCityWeather(ctx context.Context, uf, city string) (*model.Observation, error)
+}
+
+// GetCityWeather looks the weather in city, in the state uf, up with c, by
+// the name alone when c does not keep track of states
+func GetCityWeather(ctx context.Context, c WeatherClientInterface, uf, city string) (*model.Observation, error) {
+	if located, ok := c.(CityWeatherClientInterface); ok {
+		return located.GetCityWeather(ctx, uf, city)
+	}
+	return c.GetWeather(ctx, city)
+}
+
+// FreshWeatherClientInterface is implemented by the weather clients that may
+// answer from a cache, to look the weather up again and renew the cache
+type FreshWeatherClientInterface interface {
+	GetFreshCityWeather(ctx context.Context, uf, city string) (*model.Observation, error)
+}
+
+// ForecastClientInterface is implemented by the weather clients that can
+// return a daily forecast
+type ForecastClientInterface interface {
+	GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error)
 }
 
 type WeatherClient struct {
@@ -32,37 +70,76 @@ func NewWeatherClient(cfg *config.Config) *WeatherClient {
 	}
 }
 
-func (w WeatherClient) GetWeather(ctx context.Context, city string) (*model.WeatherResponse, error) {
+func (w WeatherClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	return w.current(ctx, city)
+}
+
+// GetWeatherByCoordinates uses WeatherAPI's "lat,lon" query form
+func (w WeatherClient) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	return w.current(ctx, coordinatesQuery(lat, lon))
+}
+
+// GetForecast reads WeatherAPI's forecast.json, which carries up to 14 days
+// depending on the plan and the alerts in effect
+func (w WeatherClient) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	forecastUrl := fmt.Sprintf("%s?key=%s&q=%s&days=%d&aqi=no&alerts=yes",
+		w.config.WeatherForecastURL,
+		w.config.WeatherAPIKey,
+		url.QueryEscape(coordinatesQuery(lat, lon)),
+		days)
+
+	var forecastRes model.WeatherForecastResponse
+	if err := w.get(ctx, forecastUrl, &forecastRes); err != nil {
+		return nil, err
+	}
+
+	return weatherAPIToForecast(forecastRes), nil
+}
+
+func (w WeatherClient) current(ctx context.Context, query string) (*model.Observation, error) {
 	weatherApiUrl := fmt.Sprintf("%s?key=%s&q=%s&aqi=no",
 		w.config.WeatherBaseURL,
 		w.config.WeatherAPIKey,
-		url.QueryEscape(city))
+		url.QueryEscape(query))
 
-	req, err := http.NewRequestWithContext(ctx, "GET", weatherApiUrl, nil)
-	if err != nil {
+	var weatherRes model.WeatherResponse
+	if err := w.get(ctx, weatherApiUrl, &weatherRes); err != nil {
 		return nil, err
 	}
 
+	return weatherAPIToObservation(weatherRes), nil
+}
+
+// get requests apiUrl in the language of ctx and decodes the JSON body
+func (w WeatherClient) get(ctx context.Context, apiUrl string, target any) error {
+	if lang, ok := weatherAPILanguages[i18n.FromContext(ctx)]; ok {
+		apiUrl += "&lang=" + lang
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiUrl, nil)
+	if err != nil {
+		return err
+	}
+
 	resp, err := w.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, cErrors.NewWeatherClientHTTPError(resp.StatusCode)
+		return cErrors.NewWeatherClientHTTPError(resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var weatherRes model.WeatherResponse
-	err = json.Unmarshal(body, &weatherRes)
-	if err != nil {
-		return nil, err
-	}
+	return json.Unmarshal(body, target)
+}
 
-	return &weatherRes, nil
+// coordinatesQuery is WeatherAPI's "lat,lon" query form
+func coordinatesQuery(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', 4, 64) + "," + strconv.FormatFloat(lon, 'f', 4, 64)
 }
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherClient_GetWeather_RecordedResponse(t *testing.T) {
+	// arrange
+	var query string
+	fixture := serveFixture(t, "weatherapi/current_sao_paulo.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		fixture(w, r)
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{
+		WeatherAPIKey:  "test-api-key",
+		WeatherBaseURL: server.URL,
+	})
+
+	// act
+	observation, err := client.GetWeather(context.Background(), "São Paulo")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "São Paulo", query)
+	assert.Equal(t, ProviderWeatherAPI, observation.Source)
+	assert.Equal(t, 32.2, observation.TemperatureC)
+	assert.Equal(t, 36.0, observation.HumidityPct)
+}
+
+func TestWeatherClient_GetWeather_HTTPError(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherBaseURL: server.URL})
+
+	// act
+	observation, err := client.GetWeather(context.Background(), "Nowhere")
+
+	// assert
+	assert.Nil(t, observation)
+	assert.ErrorIs(t, err, cErrors.WeatherClientBadRequest)
+}
+
+func TestWeatherClient_GetWeatherByCoordinates(t *testing.T) {
+	// arrange
+	var query string
+	fixture := serveFixture(t, "weatherapi/current_sao_paulo.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		fixture(w, r)
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{WeatherBaseURL: server.URL})
+
+	// act
+	observation, err := client.GetWeatherByCoordinates(context.Background(), -23.55052, -46.633308)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "-23.5505,-46.6333", query)
+	assert.Equal(t, 32.2, observation.TemperatureC)
+}
+
+func TestWeatherClient_GetWeather_Language(t *testing.T) {
+	tests := []struct {
+		lang     i18n.Lang
+		expected string
+	}{
+		{i18n.English, ""},
+		{i18n.Portuguese, "pt"},
+		{i18n.Spanish, "es"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			// arrange
+			var lang string
+			fixture := serveFixture(t, "weatherapi/current_sao_paulo.json")
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lang = r.URL.Query().Get("lang")
+				fixture(w, r)
+			}))
+			defer server.Close()
+
+			client := NewWeatherClient(&config.Config{WeatherBaseURL: server.URL})
+			ctx := i18n.WithLang(context.Background(), tt.lang)
+
+			// act
+			_, err := client.GetWeather(ctx, "São Paulo")
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, lang)
+		})
+	}
+}
+
+func TestWeatherClient_GetForecast(t *testing.T) {
+	// arrange
+	var query url.Values
+	fixture := serveFixture(t, "weatherapi/forecast_sao_paulo.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fixture(w, r)
+	}))
+	defer server.Close()
+
+	client := NewWeatherClient(&config.Config{
+		WeatherAPIKey:      "test-api-key",
+		WeatherForecastURL: server.URL,
+	})
+
+	// act
+	forecast, err := client.GetForecast(context.Background(), -23.55052, -46.633308, 2)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "-23.5505,-46.6333", query.Get("q"))
+	assert.Equal(t, "2", query.Get("days"))
+	assert.Equal(t, ProviderWeatherAPI, forecast.Source)
+	assert.Equal(t, "Sao Paulo", forecast.Location.Name)
+	assert.Len(t, forecast.Days, 2)
+	assert.Equal(t, "2026-01-11", forecast.Days[1].Date)
+	assert.Equal(t, 19.9, forecast.Days[1].MinC)
+	assert.Equal(t, 28.7, forecast.Days[1].MaxC)
+	assert.Equal(t, 6.1, forecast.Days[1].PrecipitationMm)
+	assert.Equal(t, "Moderate rain", forecast.Days[1].Condition.Text)
+	assert.Equal(t, "yes", query.Get("alerts"))
+	assert.Len(t, forecast.Alerts, 1)
+	assert.Equal(t, "Heavy rain warning", forecast.Alerts[0].Event)
+	assert.Equal(t, "Moderate", forecast.Alerts[0].Severity)
+	assert.Equal(t, time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC), forecast.Alerts[0].Expires.UTC())
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// weatherAPIToObservation adapts a WeatherAPI.com current.json response
+func weatherAPIToObservation(weather model.WeatherResponse) *model.Observation {
+	return &model.Observation{
+		Source:     ProviderWeatherAPI,
+		ObservedAt: time.Unix(int64(weather.Current.LastUpdatedEpoch), 0).UTC(),
+		Location: model.ObservationLocation{
+			Name:     weather.Location.Name,
+			Region:   weather.Location.Region,
+			Country:  weather.Location.Country,
+			Lat:      weather.Location.Lat,
+			Lon:      weather.Location.Lon,
+			Timezone: weather.Location.TzID,
+		},
+		TemperatureC: weather.Current.TempC,
+		HumidityPct:  float64(weather.Current.Humidity),
+		Wind: model.Wind{
+			SpeedKph:     weather.Current.WindKph,
+			DirectionDeg: float64(weather.Current.WindDegree),
+			GustKph:      weather.Current.GustKph,
+		},
+		PressureMb:      weather.Current.PressureMb,
+		PrecipitationMm: weather.Current.PrecipMm,
+		Condition: model.Condition{
+			Text:  weather.Current.Condition.Text,
+			Code:  weather.Current.Condition.Code,
+			IsDay: weather.Current.IsDay == 1,
+		},
+	}
+}
+
+// weatherAPIToForecast adapts a WeatherAPI.com forecast.json response
+func weatherAPIToForecast(weather model.WeatherForecastResponse) *model.Forecast {
+	forecast := &model.Forecast{
+		Source: ProviderWeatherAPI,
+		Location: model.ObservationLocation{
+			Name:     weather.Location.Name,
+			Region:   weather.Location.Region,
+			Country:  weather.Location.Country,
+			Lat:      weather.Location.Lat,
+			Lon:      weather.Location.Lon,
+			Timezone: weather.Location.TzID,
+		},
+		Days:   []model.ForecastDay{},
+		Alerts: []model.Alert{},
+	}
+
+	for _, day := range weather.Forecast.Forecastday {
+		forecast.Days = append(forecast.Days, model.ForecastDay{
+			Date:            day.Date,
+			MinC:            day.Day.MintempC,
+			MaxC:            day.Day.MaxtempC,
+			PrecipitationMm: day.Day.TotalprecipMm,
+			Condition: model.Condition{
+				Text:  day.Day.Condition.Text,
+				Code:  day.Day.Condition.Code,
+				IsDay: true,
+			},
+		})
+	}
+
+	for _, alert := range weather.Alerts.Alert {
+		forecast.Alerts = append(forecast.Alerts, model.Alert{
+			Event:       alert.Event,
+			Headline:    alert.Headline,
+			Severity:    alert.Severity,
+			Urgency:     alert.Urgency,
+			Areas:       alert.Areas,
+			Description: alert.Desc,
+			Instruction: alert.Instruction,
+			Effective:   parseAlertTime(alert.Effective),
+			Expires:     parseAlertTime(alert.Expires),
+		})
+	}
+
+	return forecast
+}
+
+// parseAlertTime reads the RFC 3339 timestamps of WeatherAPI alerts; empty
+// or malformed values are dropped
+func parseAlertTime(value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherAPIToObservation_RecordedResponse(t *testing.T) {
+	// arrange
+	var weather model.WeatherResponse
+	err := json.Unmarshal(readFixture(t, "weatherapi/current_sao_paulo.json"), &weather)
+	assert.NoError(t, err)
+
+	// act
+	observation := weatherAPIToObservation(weather)
+
+	// assert
+	assert.Equal(t, ProviderWeatherAPI, observation.Source)
+	assert.Equal(t, time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC), observation.ObservedAt)
+
+	assert.Equal(t, "Sao Paulo", observation.Location.Name)
+	assert.Equal(t, "Sao Paulo", observation.Location.Region)
+	assert.Equal(t, "Brazil", observation.Location.Country)
+	assert.Equal(t, -23.5333, observation.Location.Lat)
+	assert.Equal(t, -46.6167, observation.Location.Lon)
+	assert.Equal(t, "America/Sao_Paulo", observation.Location.Timezone)
+
+	assert.Equal(t, 32.2, observation.TemperatureC)
+	assert.Equal(t, 36.0, observation.HumidityPct)
+	assert.Equal(t, 8.6, observation.Wind.SpeedKph)
+	assert.Equal(t, 309.0, observation.Wind.DirectionDeg)
+	assert.Equal(t, 10.7, observation.Wind.GustKph)
+	assert.Equal(t, 1015.0, observation.PressureMb)
+	assert.Equal(t, 0.02, observation.PrecipitationMm)
+
+	assert.Equal(t, "Partly cloudy", observation.Condition.Text)
+	assert.Equal(t, 1003, observation.Condition.Code)
+	assert.True(t, observation.Condition.IsDay)
+	assert.Nil(t, observation.Consensus)
+}
+
+func TestWeatherAPIToObservation_MatchesMock(t *testing.T) {
+	// arrange
+	weather := model.GetWeatherResponseMock("São Paulo")
+	expected := model.GetObservationMock("Sao Paulo")
+
+	// act
+	observation := weatherAPIToObservation(*weather)
+
+	// assert
+	assert.Equal(t, expected, observation)
+}
+
+func TestWeatherAPIToObservation_Night(t *testing.T) {
+	// arrange
+	weather := model.GetWeatherResponseMock("São Paulo")
+	weather.Current.IsDay = 0
+
+	// act
+	observation := weatherAPIToObservation(*weather)
+
+	// assert
+	assert.False(t, observation.Condition.IsDay)
+}
@@ -1,18 +1,180 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 )
 
+// Route groups rate limited separately
+const (
+	RateLimitGroupAPI     = "api"
+	RateLimitGroupGraphQL = "graphql"
+	RateLimitGroupStream  = "stream"
+	RateLimitGroupAdmin   = "admin"
+)
+
+// RateLimit is a token bucket refilled at Rate tokens per second, holding at
+// most Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// What the WeatherAPI quota guard does past the soft limit: move the calls
+// to the secondary provider or serve expired cache entries
+const (
+	WeatherQuotaSoftFallback = "fallback"
+	WeatherQuotaSoftCache    = "cache"
+)
+
+// How to handle a ViaCEP UF that disagrees with the CEP range
+const (
+	CepUFMismatchWarn   = "warn"
+	CepUFMismatchReject = "reject"
+)
+
 type Config struct {
-	Port           string
-	WeatherAPIKey  string
-	ViaCEPBaseURL  string
-	WeatherBaseURL string
-	GinMode        string
+	Port                  string
+	WeatherAPIKey         string
+	ViaCEPBaseURL         string
+	ViaCEPSearchURL       string
+	WeatherBaseURL        string
+	WeatherForecastURL    string
+	GinMode               string
+	WeatherProvider       string
+	OpenMeteoBaseURL      string
+	OpenMeteoGeocodingURL string
+	CepUFMismatch         string
+	CepCacheMaxAge        time.Duration
+	CepProvider           string
+	CepOfflineIndex       string
+
+	// Coordinate lookups: accepted area as south,west,north,east and an
+	// optional municipalities CSV replacing the bundled one
+	GeoBoundingBox        [4]float64
+	GeoMunicipalitiesFile string
+
+	// Composite weather lookup (failover or consensus across providers)
+	WeatherStrategy         string
+	WeatherProviders        []string
+	WeatherProviderWeights  map[string]float64
+	WeatherProviderTimeout  time.Duration
+	WeatherProviderTimeouts map[string]time.Duration
+	WeatherOutlierThreshold float64
+
+	// gRPC API served next to the REST one
+	GrpcEnabled bool
+	GrpcPort    string
+
+	// GraphQL endpoint limits, checked before a query runs
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+	GraphQLMaxBatch      int
+
+	// Live temperature streams: how often a followed location is polled and
+	// how often an idle stream sends a heartbeat
+	StreamPollInterval      time.Duration
+	StreamHeartbeatInterval time.Duration
+
+	// Temperature threshold webhooks. An empty store path disables them
+	WebhookStorePath           string
+	WebhookEvalInterval        time.Duration
+	WebhookTimeout             time.Duration
+	WebhookMaxAttempts         int
+	WebhookMaxPerOwner         int
+	WebhookRetryBackoff        time.Duration
+	WebhookAllowPrivateTargets bool
+
+	// Observation history: every observation fetched is kept for
+	// HistoryRetention, at most HistoryMaxPerLocation per location. An empty
+	// store path disables it
+	HistoryStorePath      string
+	HistoryRetention      time.Duration
+	HistoryMaxPerLocation int
+	HistoryPruneInterval  time.Duration
+
+	// In-process caches of CEP addresses and city observations; zero
+	// disables a cache
+	CepCacheTTL     time.Duration
+	WeatherCacheTTL time.Duration
+
+	// Pre-warm jobs refreshing the caches on cron schedules, for a fixed
+	// list of CEPs and for the PrewarmTopN most requested ones
+	PrewarmCeps         []cep.CEP
+	PrewarmCepsSchedule string
+	PrewarmTopN         int
+	PrewarmTopSchedule  string
+	PrewarmLanguages    []i18n.Lang
+	PrewarmJitter       time.Duration
+	PrewarmConcurrency  int
+
+	// API keys, as name=key pairs and/or a JSON file with routes and quotas
+	// per key. Without keys the API is open. Zero quotas are unlimited
+	APIKeys                  map[string]string
+	APIKeysFile              string
+	APIKeyDailyQuota         int
+	APIKeyMonthlyQuota       int
+	APIKeyUsageFile          string
+	APIKeyUsageFlushInterval time.Duration
+
+	// Token bucket rate limits per route group, per client IP and per API
+	// key; groups left out are not limited. Buckets idle for
+	// RateLimitIdleTimeout are dropped. The client IP is taken from
+	// X-Forwarded-For only behind TrustedProxies
+	RateLimitPerIP       map[string]RateLimit
+	RateLimitPerKey      map[string]RateLimit
+	RateLimitIdleTimeout time.Duration
+	TrustedProxies       []string
+
+	// WeatherAPI monthly call quota, counted per billing period starting on
+	// WeatherAPIBillingDay at 00:00 UTC. Past WeatherAPIQuotaSoftPercent the
+	// calls degrade as WeatherAPIQuotaSoftMode says; at the quota WeatherAPI
+	// is no longer called. A zero quota disables the guard
+	WeatherAPIMonthlyQuota       int64
+	WeatherAPIQuotaSoftPercent   int
+	WeatherAPIQuotaSoftMode      string
+	WeatherAPIBillingDay         int
+	WeatherAPIQuotaFile          string
+	WeatherAPIQuotaFlushInterval time.Duration
+
+	// Bearer token authentication, next to API keys. Tokens are checked
+	// against the keys of the JWKS at JWTJWKSURL or the PEM key in
+	// JWTKeyFile; an empty URL and file disable it. JWTScopeRoutes maps
+	// token scopes to the routes they may call, and only tokens with
+	// JWTAdminScope may call the admin routes. Each token subject has the
+	// JWTDailyQuota and JWTMonthlyQuota quotas; 0 is unlimited
+	JWTJWKSURL             string
+	JWTKeyFile             string
+	JWTIssuer              string
+	JWTAudience            []string
+	JWTAlgorithms          []string
+	JWTLeeway              time.Duration
+	JWTJWKSRefreshInterval time.Duration
+	JWTScopeRoutes         map[string][]string
+	JWTAdminScope          string
+	JWTDailyQuota          int
+	JWTMonthlyQuota        int
+
+	// CORS for browser clients. CORSAllowedOrigins lists exact origins,
+	// wildcard subdomains such as https://*.example.com, or * for any
+	// origin; an empty list turns CORS off. Preflight answers are cached by
+	// browsers for CORSMaxAge
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSExposedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
 }
 
 var AppConfig *Config
@@ -32,8 +194,81 @@ func LoadConfig() (*Config, error) {
 	}
 
 	viper.SetDefault("VIA_CEP_BASE_URL", "https://viacep.com.br/ws/{cep}/json/")
+	viper.SetDefault("VIA_CEP_SEARCH_URL", "https://viacep.com.br/ws/{uf}/{city}/{street}/json/")
 	viper.SetDefault("WEATHER_BASE_URL", "http://api.weatherapi.com/v1/current.json")
-	viper.SetDefault("GIN_MODE", "debug") // debug, release, or test
+	viper.SetDefault("WEATHER_FORECAST_URL", "http://api.weatherapi.com/v1/forecast.json")
+	viper.SetDefault("GIN_MODE", "debug")              // debug, release, or test
+	viper.SetDefault("WEATHER_PROVIDER", "weatherapi") // weatherapi or openmeteo
+	viper.SetDefault("OPEN_METEO_BASE_URL", "https://api.open-meteo.com/v1/forecast")
+	viper.SetDefault("OPEN_METEO_GEOCODING_URL", "https://geocoding-api.open-meteo.com/v1/search")
+	viper.SetDefault("WEATHER_STRATEGY", "single") // single, failover or consensus
+	viper.SetDefault("WEATHER_PROVIDERS", "weatherapi,openmeteo")
+	viper.SetDefault("WEATHER_PROVIDER_TIMEOUT", "5s")
+	viper.SetDefault("WEATHER_OUTLIER_THRESHOLD", 3.0)
+	viper.SetDefault("CEP_UF_MISMATCH", CepUFMismatchWarn) // warn or reject
+	viper.SetDefault("CEP_CACHE_MAX_AGE", "24h")
+	viper.SetDefault("CEP_PROVIDER", "online")                        // online, offline or tiered
+	viper.SetDefault("GEO_BOUNDING_BOX", "-33.75,-73.99,5.27,-28.84") // Brazil, oceanic islands included
+	viper.SetDefault("GRPC_ENABLED", true)
+	viper.SetDefault("GRPC_PORT", "9090")
+	viper.SetDefault("GRAPHQL_MAX_DEPTH", 15) // the standard introspection query is 13 deep
+	viper.SetDefault("GRAPHQL_MAX_COMPLEXITY", 100)
+	viper.SetDefault("GRAPHQL_MAX_BATCH", 10)
+	viper.SetDefault("STREAM_POLL_INTERVAL", "30s")
+	viper.SetDefault("STREAM_HEARTBEAT_INTERVAL", "15s")
+	viper.SetDefault("WEBHOOK_STORE_PATH", "") // opt-in: each subscription polls the weather provider
+	viper.SetDefault("WEBHOOK_EVAL_INTERVAL", "5m")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 5)
+	viper.SetDefault("WEBHOOK_MAX_PER_OWNER", 20)
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF", "2s")
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", false)
+	viper.SetDefault("HISTORY_STORE_PATH", "data/history.db")
+	viper.SetDefault("HISTORY_RETENTION", "720h") // 30 days
+	viper.SetDefault("HISTORY_MAX_PER_LOCATION", 10000)
+	viper.SetDefault("HISTORY_PRUNE_INTERVAL", "1h")
+	viper.SetDefault("CEP_CACHE_TTL", "24h")
+	viper.SetDefault("WEATHER_CACHE_TTL", "5m")
+	viper.SetDefault("PREWARM_CEPS", "")
+	viper.SetDefault("PREWARM_CEPS_SCHEDULE", "*/10 * * * *")
+	viper.SetDefault("PREWARM_TOP_N", 0)
+	viper.SetDefault("PREWARM_TOP_SCHEDULE", "*/10 * * * *")
+	viper.SetDefault("PREWARM_LANGUAGES", "en")
+	viper.SetDefault("PREWARM_JITTER", "30s")
+	viper.SetDefault("PREWARM_CONCURRENCY", 4)
+	viper.SetDefault("API_KEYS", "")
+	viper.SetDefault("API_KEYS_FILE", "")
+	viper.SetDefault("API_KEY_DAILY_QUOTA", 1000)
+	viper.SetDefault("API_KEY_MONTHLY_QUOTA", 20000)
+	viper.SetDefault("API_KEY_USAGE_FILE", "")
+	viper.SetDefault("API_KEY_USAGE_FLUSH_INTERVAL", "1m")
+	viper.SetDefault("RATE_LIMIT_PER_IP", "api=10/s:20,graphql=5/s:10,stream=1/s:5,admin=1/s:5")
+	viper.SetDefault("RATE_LIMIT_PER_KEY", "api=20/s:40,graphql=10/s:20,stream=2/s:10")
+	viper.SetDefault("RATE_LIMIT_IDLE_TIMEOUT", "10m")
+	viper.SetDefault("TRUSTED_PROXIES", "")
+	viper.SetDefault("WEATHERAPI_MONTHLY_QUOTA", 1000000) // free plan
+	viper.SetDefault("WEATHERAPI_QUOTA_SOFT_PERCENT", 80)
+	viper.SetDefault("WEATHERAPI_QUOTA_SOFT_MODE", WeatherQuotaSoftFallback) // fallback or cache
+	viper.SetDefault("WEATHERAPI_BILLING_DAY", 1)
+	viper.SetDefault("WEATHERAPI_QUOTA_FILE", "data/weatherapi-quota.json")
+	viper.SetDefault("WEATHERAPI_QUOTA_FLUSH_INTERVAL", "1m")
+	viper.SetDefault("JWT_JWKS_URL", "")
+	viper.SetDefault("JWT_KEY_FILE", "")
+	viper.SetDefault("JWT_ISSUER", "")
+	viper.SetDefault("JWT_AUDIENCE", "")
+	viper.SetDefault("JWT_ALGORITHMS", "RS256,ES256")
+	viper.SetDefault("JWT_LEEWAY", "30s")
+	viper.SetDefault("JWT_JWKS_REFRESH_INTERVAL", "1h")
+	viper.SetDefault("JWT_SCOPE_ROUTES", "")
+	viper.SetDefault("JWT_ADMIN_SCOPE", "")
+	viper.SetDefault("JWT_DAILY_QUOTA", 1000)
+	viper.SetDefault("JWT_MONTHLY_QUOTA", 20000)
+	viper.SetDefault("CORS_ALLOWED_ORIGINS", "")
+	viper.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,DELETE")
+	viper.SetDefault("CORS_ALLOWED_HEADERS", "Accept,Accept-Language,Authorization,Content-Type,If-None-Match,X-API-Key")
+	viper.SetDefault("CORS_EXPOSED_HEADERS", "ETag,Retry-After,WWW-Authenticate,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset")
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", false)
+	viper.SetDefault("CORS_MAX_AGE", "10m")
 
 	// Try to read .env file, but don't fail if it doesn't exist
 	if err := viper.ReadInConfig(); err != nil {
@@ -45,15 +280,197 @@ func LoadConfig() (*Config, error) {
 	}
 
 	config := &Config{
-		Port:           port,
-		WeatherAPIKey:  viper.GetString("WEATHER_API_KEY"),
-		ViaCEPBaseURL:  viper.GetString("VIA_CEP_BASE_URL"),
-		WeatherBaseURL: viper.GetString("WEATHER_BASE_URL"),
-		GinMode:        viper.GetString("GIN_MODE"),
+		Port:                  port,
+		WeatherAPIKey:         viper.GetString("WEATHER_API_KEY"),
+		ViaCEPBaseURL:         viper.GetString("VIA_CEP_BASE_URL"),
+		ViaCEPSearchURL:       viper.GetString("VIA_CEP_SEARCH_URL"),
+		WeatherBaseURL:        viper.GetString("WEATHER_BASE_URL"),
+		WeatherForecastURL:    viper.GetString("WEATHER_FORECAST_URL"),
+		GinMode:               viper.GetString("GIN_MODE"),
+		WeatherProvider:       viper.GetString("WEATHER_PROVIDER"),
+		OpenMeteoBaseURL:      viper.GetString("OPEN_METEO_BASE_URL"),
+		OpenMeteoGeocodingURL: viper.GetString("OPEN_METEO_GEOCODING_URL"),
+		WeatherStrategy:       viper.GetString("WEATHER_STRATEGY"),
+		WeatherProviders:      parseList(viper.GetString("WEATHER_PROVIDERS")),
+		CepUFMismatch:         viper.GetString("CEP_UF_MISMATCH"),
+		CepProvider:           viper.GetString("CEP_PROVIDER"),
+		CepOfflineIndex:       viper.GetString("CEP_OFFLINE_INDEX"),
+		GeoMunicipalitiesFile: viper.GetString("GEO_MUNICIPALITIES_FILE"),
+		GrpcEnabled:           viper.GetBool("GRPC_ENABLED"),
+		GrpcPort:              viper.GetString("GRPC_PORT"),
+		GraphQLMaxDepth:       viper.GetInt("GRAPHQL_MAX_DEPTH"),
+		GraphQLMaxComplexity:  viper.GetInt("GRAPHQL_MAX_COMPLEXITY"),
+		GraphQLMaxBatch:       viper.GetInt("GRAPHQL_MAX_BATCH"),
+
+		WebhookStorePath:           viper.GetString("WEBHOOK_STORE_PATH"),
+		WebhookMaxAttempts:         viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookMaxPerOwner:         viper.GetInt("WEBHOOK_MAX_PER_OWNER"),
+		WebhookAllowPrivateTargets: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_TARGETS"),
+
+		HistoryStorePath:      viper.GetString("HISTORY_STORE_PATH"),
+		HistoryMaxPerLocation: viper.GetInt("HISTORY_MAX_PER_LOCATION"),
+
+		PrewarmCepsSchedule: viper.GetString("PREWARM_CEPS_SCHEDULE"),
+		PrewarmTopN:         viper.GetInt("PREWARM_TOP_N"),
+		PrewarmTopSchedule:  viper.GetString("PREWARM_TOP_SCHEDULE"),
+		PrewarmConcurrency:  viper.GetInt("PREWARM_CONCURRENCY"),
+
+		APIKeysFile:        viper.GetString("API_KEYS_FILE"),
+		APIKeyDailyQuota:   viper.GetInt("API_KEY_DAILY_QUOTA"),
+		APIKeyMonthlyQuota: viper.GetInt("API_KEY_MONTHLY_QUOTA"),
+		APIKeyUsageFile:    viper.GetString("API_KEY_USAGE_FILE"),
+
+		WeatherAPIMonthlyQuota:     viper.GetInt64("WEATHERAPI_MONTHLY_QUOTA"),
+		WeatherAPIQuotaSoftPercent: viper.GetInt("WEATHERAPI_QUOTA_SOFT_PERCENT"),
+		WeatherAPIQuotaSoftMode:    viper.GetString("WEATHERAPI_QUOTA_SOFT_MODE"),
+		WeatherAPIBillingDay:       viper.GetInt("WEATHERAPI_BILLING_DAY"),
+		WeatherAPIQuotaFile:        viper.GetString("WEATHERAPI_QUOTA_FILE"),
+
+		JWTJWKSURL:      viper.GetString("JWT_JWKS_URL"),
+		JWTKeyFile:      viper.GetString("JWT_KEY_FILE"),
+		JWTIssuer:       viper.GetString("JWT_ISSUER"),
+		JWTAudience:     parseList(viper.GetString("JWT_AUDIENCE")),
+		JWTAlgorithms:   parseList(viper.GetString("JWT_ALGORITHMS")),
+		JWTAdminScope:   viper.GetString("JWT_ADMIN_SCOPE"),
+		JWTDailyQuota:   viper.GetInt("JWT_DAILY_QUOTA"),
+		JWTMonthlyQuota: viper.GetInt("JWT_MONTHLY_QUOTA"),
+
+		CORSAllowedMethods:   parseList(strings.ToUpper(viper.GetString("CORS_ALLOWED_METHODS"))),
+		CORSAllowedHeaders:   parseList(viper.GetString("CORS_ALLOWED_HEADERS")),
+		CORSExposedHeaders:   parseList(viper.GetString("CORS_EXPOSED_HEADERS")),
+		CORSAllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
+	}
+
+	var err error
+	if config.WeatherProviderWeights, err = parseFloatMap(viper.GetString("WEATHER_PROVIDER_WEIGHTS")); err != nil {
+		return nil, fmt.Errorf("invalid WEATHER_PROVIDER_WEIGHTS: %w", err)
+	}
+	if config.WeatherProviderTimeout, err = time.ParseDuration(viper.GetString("WEATHER_PROVIDER_TIMEOUT")); err != nil {
+		return nil, fmt.Errorf("invalid WEATHER_PROVIDER_TIMEOUT: %w", err)
+	}
+	if config.WeatherProviderTimeouts, err = parseDurationMap(viper.GetString("WEATHER_PROVIDER_TIMEOUTS")); err != nil {
+		return nil, fmt.Errorf("invalid WEATHER_PROVIDER_TIMEOUTS: %w", err)
+	}
+	if config.WeatherOutlierThreshold, err = parseFloat(viper.GetString("WEATHER_OUTLIER_THRESHOLD")); err != nil {
+		return nil, fmt.Errorf("invalid WEATHER_OUTLIER_THRESHOLD: %w", err)
+	}
+	if threshold := config.WeatherOutlierThreshold; !(threshold > 0) || math.IsInf(threshold, 0) {
+		return nil, fmt.Errorf("invalid WEATHER_OUTLIER_THRESHOLD: %q (must be a positive number of degrees)", viper.GetString("WEATHER_OUTLIER_THRESHOLD"))
+	}
+	if config.APIKeys, err = parseKeyValues(viper.GetString("API_KEYS")); err != nil {
+		return nil, fmt.Errorf("invalid API_KEYS: %w", err)
+	}
+	for _, limits := range []struct {
+		name   string
+		target *map[string]RateLimit
+	}{
+		{"RATE_LIMIT_PER_IP", &config.RateLimitPerIP},
+		{"RATE_LIMIT_PER_KEY", &config.RateLimitPerKey},
+	} {
+		if *limits.target, err = parseRateLimits(viper.GetString(limits.name)); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", limits.name, err)
+		}
+	}
+	if config.JWTScopeRoutes, err = parseScopeRoutes(viper.GetString("JWT_SCOPE_ROUTES")); err != nil {
+		return nil, fmt.Errorf("invalid JWT_SCOPE_ROUTES: %w", err)
+	}
+	if config.CORSAllowedOrigins, err = parseOrigins(viper.GetString("CORS_ALLOWED_ORIGINS")); err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %w", err)
+	}
+	if config.TrustedProxies, err = parseProxies(viper.GetString("TRUSTED_PROXIES")); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	if config.CepCacheMaxAge, err = time.ParseDuration(viper.GetString("CEP_CACHE_MAX_AGE")); err != nil || config.CepCacheMaxAge < 0 {
+		return nil, fmt.Errorf("invalid CEP_CACHE_MAX_AGE: %q", viper.GetString("CEP_CACHE_MAX_AGE"))
+	}
+	if config.GeoBoundingBox, err = parseBoundingBox(viper.GetString("GEO_BOUNDING_BOX")); err != nil {
+		return nil, fmt.Errorf("invalid GEO_BOUNDING_BOX: %w", err)
+	}
+	if config.CepUFMismatch != CepUFMismatchWarn && config.CepUFMismatch != CepUFMismatchReject {
+		return nil, fmt.Errorf("invalid CEP_UF_MISMATCH: %q (use %s or %s)", config.CepUFMismatch, CepUFMismatchWarn, CepUFMismatchReject)
+	}
+	if config.StreamPollInterval, err = time.ParseDuration(viper.GetString("STREAM_POLL_INTERVAL")); err != nil || config.StreamPollInterval <= 0 {
+		return nil, fmt.Errorf("invalid STREAM_POLL_INTERVAL: %q", viper.GetString("STREAM_POLL_INTERVAL"))
+	}
+	if config.StreamHeartbeatInterval, err = time.ParseDuration(viper.GetString("STREAM_HEARTBEAT_INTERVAL")); err != nil || config.StreamHeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("invalid STREAM_HEARTBEAT_INTERVAL: %q", viper.GetString("STREAM_HEARTBEAT_INTERVAL"))
+	}
+	for _, ttl := range []struct {
+		name   string
+		target *time.Duration
+	}{
+		{"CEP_CACHE_TTL", &config.CepCacheTTL},
+		{"WEATHER_CACHE_TTL", &config.WeatherCacheTTL},
+		{"PREWARM_JITTER", &config.PrewarmJitter},
+		{"JWT_LEEWAY", &config.JWTLeeway},
+		{"CORS_MAX_AGE", &config.CORSMaxAge},
+	} {
+		if *ttl.target, err = time.ParseDuration(viper.GetString(ttl.name)); err != nil || *ttl.target < 0 {
+			return nil, fmt.Errorf("invalid %s: %q", ttl.name, viper.GetString(ttl.name))
+		}
+	}
+	for _, interval := range []struct {
+		name   string
+		target *time.Duration
+	}{
+		{"WEBHOOK_EVAL_INTERVAL", &config.WebhookEvalInterval},
+		{"WEBHOOK_TIMEOUT", &config.WebhookTimeout},
+		{"WEBHOOK_RETRY_BACKOFF", &config.WebhookRetryBackoff},
+		{"HISTORY_RETENTION", &config.HistoryRetention},
+		{"HISTORY_PRUNE_INTERVAL", &config.HistoryPruneInterval},
+		{"API_KEY_USAGE_FLUSH_INTERVAL", &config.APIKeyUsageFlushInterval},
+		{"RATE_LIMIT_IDLE_TIMEOUT", &config.RateLimitIdleTimeout},
+		{"WEATHERAPI_QUOTA_FLUSH_INTERVAL", &config.WeatherAPIQuotaFlushInterval},
+		{"JWT_JWKS_REFRESH_INTERVAL", &config.JWTJWKSRefreshInterval},
+	} {
+		if *interval.target, err = time.ParseDuration(viper.GetString(interval.name)); err != nil || *interval.target <= 0 {
+			return nil, fmt.Errorf("invalid %s: %q", interval.name, viper.GetString(interval.name))
+		}
+	}
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"GRAPHQL_MAX_DEPTH", config.GraphQLMaxDepth},
+		{"GRAPHQL_MAX_COMPLEXITY", config.GraphQLMaxComplexity},
+		{"GRAPHQL_MAX_BATCH", config.GraphQLMaxBatch},
+		{"WEBHOOK_MAX_ATTEMPTS", config.WebhookMaxAttempts},
+		{"WEBHOOK_MAX_PER_OWNER", config.WebhookMaxPerOwner},
+		{"HISTORY_MAX_PER_LOCATION", config.HistoryMaxPerLocation},
+		{"PREWARM_CONCURRENCY", config.PrewarmConcurrency},
+	} {
+		if limit.value <= 0 {
+			return nil, fmt.Errorf("invalid %s: %q (must be a positive integer)", limit.name, viper.GetString(limit.name))
+		}
+	}
+	for _, quota := range []struct {
+		name  string
+		value int
+	}{
+		{"API_KEY_DAILY_QUOTA", config.APIKeyDailyQuota},
+		{"API_KEY_MONTHLY_QUOTA", config.APIKeyMonthlyQuota},
+		{"JWT_DAILY_QUOTA", config.JWTDailyQuota},
+		{"JWT_MONTHLY_QUOTA", config.JWTMonthlyQuota},
+	} {
+		if quota.value < 0 {
+			return nil, fmt.Errorf("invalid %s: %q (must not be negative, 0 is unlimited)", quota.name, viper.GetString(quota.name))
+		}
+	}
+	if err := loadPrewarm(config); err != nil {
+		return nil, err
+	}
+	if err := checkWeatherAPIQuota(config); err != nil {
+		return nil, err
+	}
+	if err := checkJWT(config); err != nil {
+		return nil, err
+	}
+	if err := checkCORS(config); err != nil {
+		return nil, err
 	}
 
 	// Validate required fields
-	if config.WeatherAPIKey == "" {
+	if config.WeatherAPIKey == "" && config.WeatherProvider == "weatherapi" {
 		log.Println("Warning: WEATHER_API_KEY is not set")
 	}
 
@@ -61,6 +478,120 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// checkWeatherAPIQuota checks the quota guard settings. Serving expired
+// observations needs the weather cache, so the cache mode needs
+// WEATHER_CACHE_TTL
+func checkWeatherAPIQuota(config *Config) error {
+	switch {
+	case config.WeatherAPIMonthlyQuota < 0:
+		return fmt.Errorf("invalid WEATHERAPI_MONTHLY_QUOTA: %q (must not be negative, 0 disables the guard)", viper.GetString("WEATHERAPI_MONTHLY_QUOTA"))
+	case config.WeatherAPIQuotaSoftPercent < 1 || config.WeatherAPIQuotaSoftPercent > 100:
+		return fmt.Errorf("invalid WEATHERAPI_QUOTA_SOFT_PERCENT: %q (must be between 1 and 100)", viper.GetString("WEATHERAPI_QUOTA_SOFT_PERCENT"))
+	case config.WeatherAPIQuotaSoftMode != WeatherQuotaSoftFallback && config.WeatherAPIQuotaSoftMode != WeatherQuotaSoftCache:
+		return fmt.Errorf("invalid WEATHERAPI_QUOTA_SOFT_MODE: %q (use %s or %s)", config.WeatherAPIQuotaSoftMode, WeatherQuotaSoftFallback, WeatherQuotaSoftCache)
+	case config.WeatherAPIQuotaSoftMode == WeatherQuotaSoftCache && config.WeatherCacheTTL == 0:
+		return fmt.Errorf("invalid WEATHERAPI_QUOTA_SOFT_MODE: %s needs WEATHER_CACHE_TTL", WeatherQuotaSoftCache)
+	case config.WeatherAPIBillingDay < 1 || config.WeatherAPIBillingDay > 28:
+		return fmt.Errorf("invalid WEATHERAPI_BILLING_DAY: %q (must be between 1 and 28)", viper.GetString("WEATHERAPI_BILLING_DAY"))
+	}
+	return nil
+}
+
+// JWTSupportedAlgorithms are the signature algorithms JWT_ALGORITHMS may list
+var JWTSupportedAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// checkJWT checks the bearer token settings. Tokens come from a key source,
+// so the JWKS URL or the key file must be set, but not both, and the issuer
+// and the audience are required to tell them apart from other tokens of the
+// same provider
+func checkJWT(config *Config) error {
+	if config.JWTJWKSURL == "" && config.JWTKeyFile == "" {
+		return nil
+	}
+
+	switch {
+	case config.JWTJWKSURL != "" && config.JWTKeyFile != "":
+		return fmt.Errorf("invalid JWT_JWKS_URL: set JWT_JWKS_URL or JWT_KEY_FILE, not both")
+	case config.JWTIssuer == "":
+		return fmt.Errorf("invalid JWT_ISSUER: required with JWT_JWKS_URL or JWT_KEY_FILE")
+	case len(config.JWTAudience) == 0:
+		return fmt.Errorf("invalid JWT_AUDIENCE: required with JWT_JWKS_URL or JWT_KEY_FILE")
+	case len(config.JWTAlgorithms) == 0:
+		return fmt.Errorf("invalid JWT_ALGORITHMS: at least one algorithm is required")
+	}
+	for _, algorithm := range config.JWTAlgorithms {
+		if !slices.Contains(JWTSupportedAlgorithms, algorithm) {
+			return fmt.Errorf("invalid JWT_ALGORITHMS: %q (use %s)", algorithm, strings.Join(JWTSupportedAlgorithms, ", "))
+		}
+	}
+	return nil
+}
+
+// checkCORS checks the CORS settings. Browsers refuse credentialed answers
+// allowing any origin, so credentials need the origins listed
+func checkCORS(config *Config) error {
+	if len(config.CORSAllowedOrigins) == 0 {
+		return nil
+	}
+
+	switch {
+	case config.CORSAllowCredentials && slices.Contains(config.CORSAllowedOrigins, "*"):
+		return fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS: credentials can not be allowed for any origin (*)")
+	case len(config.CORSAllowedMethods) == 0:
+		return fmt.Errorf("invalid CORS_ALLOWED_METHODS: at least one method is required")
+	}
+	return nil
+}
+
+// loadPrewarm parses and checks the pre-warm job settings. Pre-warming
+// refreshes the CEP and weather caches, so it needs both TTLs
+func loadPrewarm(config *Config) error {
+	for _, value := range parseList(viper.GetString("PREWARM_CEPS")) {
+		code, err := cep.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid PREWARM_CEPS: %q: %w", value, err)
+		}
+		config.PrewarmCeps = append(config.PrewarmCeps, code)
+	}
+
+	if config.PrewarmTopN < 0 {
+		return fmt.Errorf("invalid PREWARM_TOP_N: %q (must not be negative)", viper.GetString("PREWARM_TOP_N"))
+	}
+
+	for _, schedule := range []struct {
+		name  string
+		value string
+	}{
+		{"PREWARM_CEPS_SCHEDULE", config.PrewarmCepsSchedule},
+		{"PREWARM_TOP_SCHEDULE", config.PrewarmTopSchedule},
+	} {
+		if _, err := cron.ParseStandard(schedule.value); err != nil {
+			return fmt.Errorf("invalid %s: %w", schedule.name, err)
+		}
+	}
+
+	for _, value := range parseList(viper.GetString("PREWARM_LANGUAGES")) {
+		lang := i18n.Lang(value)
+		if !slices.Contains(i18n.Supported(), lang) {
+			return fmt.Errorf("invalid PREWARM_LANGUAGES: %q (use %v)", value, i18n.Supported())
+		}
+		config.PrewarmLanguages = append(config.PrewarmLanguages, lang)
+	}
+	if len(config.PrewarmLanguages) == 0 {
+		return fmt.Errorf("invalid PREWARM_LANGUAGES: at least one language is needed")
+	}
+
+	if len(config.PrewarmCeps) > 0 || config.PrewarmTopN > 0 {
+		switch {
+		case config.CepCacheTTL == 0:
+			return fmt.Errorf("invalid PREWARM_CEPS or PREWARM_TOP_N: pre-warming needs CEP_CACHE_TTL")
+		case config.WeatherCacheTTL == 0:
+			return fmt.Errorf("invalid PREWARM_CEPS or PREWARM_TOP_N: pre-warming needs WEATHER_CACHE_TTL")
+		}
+	}
+	return nil
+}
+
 // GetConfig returns the current configuration
 func GetConfig() *Config {
 	if AppConfig == nil {
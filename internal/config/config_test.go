@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,8 +24,12 @@ func TestLoadConfig_WithDefaultValues(t *testing.T) {
 	os.Unsetenv("PORT")
 	os.Unsetenv("WEATHER_API_KEY")
 	os.Unsetenv("VIA_CEP_BASE_URL")
+	os.Unsetenv("VIA_CEP_SEARCH_URL")
 	os.Unsetenv("WEATHER_BASE_URL")
 	os.Unsetenv("GIN_MODE")
+	os.Unsetenv("WEATHER_PROVIDER")
+	os.Unsetenv("OPEN_METEO_BASE_URL")
+	os.Unsetenv("OPEN_METEO_GEOCODING_URL")
 
 	// act
 	config, err := LoadConfig()
@@ -33,11 +40,39 @@ func TestLoadConfig_WithDefaultValues(t *testing.T) {
 	assert.Equal(t, "8080", config.Port)
 	assert.Equal(t, "", config.WeatherAPIKey)
 	assert.Equal(t, "https://viacep.com.br/ws/{cep}/json/", config.ViaCEPBaseURL)
+	assert.Equal(t, "https://viacep.com.br/ws/{uf}/{city}/{street}/json/", config.ViaCEPSearchURL)
 	assert.Equal(t, "http://api.weatherapi.com/v1/current.json", config.WeatherBaseURL)
 	assert.Equal(t, "debug", config.GinMode)
+	assert.Equal(t, "weatherapi", config.WeatherProvider)
+	assert.Equal(t, "https://api.open-meteo.com/v1/forecast", config.OpenMeteoBaseURL)
+	assert.Equal(t, "https://geocoding-api.open-meteo.com/v1/search", config.OpenMeteoGeocodingURL)
 	assert.Equal(t, config, AppConfig)
 }
 
+func TestLoadConfig_WithOpenMeteoProvider(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	os.Setenv("WEATHER_PROVIDER", "openmeteo")
+	os.Setenv("OPEN_METEO_BASE_URL", "https://custom-open-meteo.com/v1/forecast")
+	os.Setenv("OPEN_METEO_GEOCODING_URL", "https://custom-open-meteo.com/v1/search")
+
+	defer func() {
+		os.Unsetenv("WEATHER_PROVIDER")
+		os.Unsetenv("OPEN_METEO_BASE_URL")
+		os.Unsetenv("OPEN_METEO_GEOCODING_URL")
+	}()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "openmeteo", config.WeatherProvider)
+	assert.Equal(t, "https://custom-open-meteo.com/v1/forecast", config.OpenMeteoBaseURL)
+	assert.Equal(t, "https://custom-open-meteo.com/v1/search", config.OpenMeteoGeocodingURL)
+}
+
 func TestLoadConfig_WithEnvironmentVariables(t *testing.T) {
 	// arrange
 	resetViperAndConfig()
@@ -287,3 +322,840 @@ func TestLoadConfig_ConfigNotNil(t *testing.T) {
 	assert.NotNil(t, config)
 	assert.IsType(t, &Config{}, config)
 }
+
+func TestLoadConfig_CompositeWeatherDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "single", config.WeatherStrategy)
+	assert.Equal(t, []string{"weatherapi", "openmeteo"}, config.WeatherProviders)
+	assert.Empty(t, config.WeatherProviderWeights)
+	assert.Equal(t, 5*time.Second, config.WeatherProviderTimeout)
+	assert.Empty(t, config.WeatherProviderTimeouts)
+	assert.Equal(t, 3.0, config.WeatherOutlierThreshold)
+}
+
+func TestLoadConfig_CompositeWeatherFromEnvironment(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	os.Setenv("WEATHER_STRATEGY", "consensus")
+	os.Setenv("WEATHER_PROVIDERS", "openmeteo, weatherapi")
+	os.Setenv("WEATHER_PROVIDER_WEIGHTS", "weatherapi=2,openmeteo=0.5")
+	os.Setenv("WEATHER_PROVIDER_TIMEOUT", "2s")
+	os.Setenv("WEATHER_PROVIDER_TIMEOUTS", "openmeteo=750ms")
+	os.Setenv("WEATHER_OUTLIER_THRESHOLD", "1.5")
+
+	defer func() {
+		os.Unsetenv("WEATHER_STRATEGY")
+		os.Unsetenv("WEATHER_PROVIDERS")
+		os.Unsetenv("WEATHER_PROVIDER_WEIGHTS")
+		os.Unsetenv("WEATHER_PROVIDER_TIMEOUT")
+		os.Unsetenv("WEATHER_PROVIDER_TIMEOUTS")
+		os.Unsetenv("WEATHER_OUTLIER_THRESHOLD")
+	}()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "consensus", config.WeatherStrategy)
+	assert.Equal(t, []string{"openmeteo", "weatherapi"}, config.WeatherProviders)
+	assert.Equal(t, map[string]float64{"weatherapi": 2, "openmeteo": 0.5}, config.WeatherProviderWeights)
+	assert.Equal(t, 2*time.Second, config.WeatherProviderTimeout)
+	assert.Equal(t, map[string]time.Duration{"openmeteo": 750 * time.Millisecond}, config.WeatherProviderTimeouts)
+	assert.Equal(t, 1.5, config.WeatherOutlierThreshold)
+}
+
+func TestLoadConfig_InvalidCompositeWeatherValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"Weight without value", "WEATHER_PROVIDER_WEIGHTS", "weatherapi"},
+		{"Weight not a number", "WEATHER_PROVIDER_WEIGHTS", "weatherapi=heavy"},
+		{"Invalid default timeout", "WEATHER_PROVIDER_TIMEOUT", "soon"},
+		{"Invalid provider timeout", "WEATHER_PROVIDER_TIMEOUTS", "openmeteo=5"},
+		{"Invalid outlier threshold", "WEATHER_OUTLIER_THRESHOLD", "far"},
+		{"Zero outlier threshold", "WEATHER_OUTLIER_THRESHOLD", "0"},
+		{"Negative outlier threshold", "WEATHER_OUTLIER_THRESHOLD", "-1"},
+		{"NaN outlier threshold", "WEATHER_OUTLIER_THRESHOLD", "NaN"},
+		{"Infinite outlier threshold", "WEATHER_OUTLIER_THRESHOLD", "+Inf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv(tt.key, tt.value)
+			defer os.Unsetenv(tt.key)
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.key)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestLoadConfig_CepUFMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"Default", "", CepUFMismatchWarn},
+		{"Warn", "warn", CepUFMismatchWarn},
+		{"Reject", "reject", CepUFMismatchReject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			if tt.value != "" {
+				os.Setenv("CEP_UF_MISMATCH", tt.value)
+				defer os.Unsetenv("CEP_UF_MISMATCH")
+			}
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, config.CepUFMismatch)
+		})
+	}
+}
+
+func TestLoadConfig_CepCacheMaxAge(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.CepCacheMaxAge)
+
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("CEP_CACHE_MAX_AGE", "90m")
+	defer os.Unsetenv("CEP_CACHE_MAX_AGE")
+
+	// act
+	config, err = LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, config.CepCacheMaxAge)
+}
+
+func TestLoadConfig_InvalidCepCacheMaxAge(t *testing.T) {
+	for _, value := range []string{"forever", "-1h"} {
+		t.Run(value, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("CEP_CACHE_MAX_AGE", value)
+			defer os.Unsetenv("CEP_CACHE_MAX_AGE")
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "CEP_CACHE_MAX_AGE")
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestLoadConfig_CepProvider(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "online", config.CepProvider)
+	assert.Equal(t, "", config.CepOfflineIndex)
+
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("CEP_PROVIDER", "tiered")
+	os.Setenv("CEP_OFFLINE_INDEX", "/data/ceps.idx")
+	defer func() {
+		os.Unsetenv("CEP_PROVIDER")
+		os.Unsetenv("CEP_OFFLINE_INDEX")
+	}()
+
+	// act
+	config, err = LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "tiered", config.CepProvider)
+	assert.Equal(t, "/data/ceps.idx", config.CepOfflineIndex)
+}
+
+func TestLoadConfig_GeoDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, [4]float64{-33.75, -73.99, 5.27, -28.84}, config.GeoBoundingBox)
+	assert.Equal(t, "", config.GeoMunicipalitiesFile)
+}
+
+func TestLoadConfig_GeoFromEnvironment(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("GEO_BOUNDING_BOX", " -25.5, -54, -19.8, -44 ")
+	os.Setenv("GEO_MUNICIPALITIES_FILE", "/data/municipios.csv")
+	defer func() {
+		os.Unsetenv("GEO_BOUNDING_BOX")
+		os.Unsetenv("GEO_MUNICIPALITIES_FILE")
+	}()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, [4]float64{-25.5, -54, -19.8, -44}, config.GeoBoundingBox)
+	assert.Equal(t, "/data/municipios.csv", config.GeoMunicipalitiesFile)
+}
+
+func TestLoadConfig_InvalidGeoBoundingBox(t *testing.T) {
+	tests := []string{
+		"-33.75,-73.99,5.27",
+		"south,-73.99,5.27,-28.84",
+		"5.27,-73.99,-33.75,-28.84",
+		"-33.75,-28.84,5.27,-73.99",
+		"-95,-73.99,5.27,-28.84",
+	}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("GEO_BOUNDING_BOX", value)
+			defer os.Unsetenv("GEO_BOUNDING_BOX")
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "GEO_BOUNDING_BOX")
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestLoadConfig_InvalidCepUFMismatch(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("CEP_UF_MISMATCH", "ignore")
+	defer os.Unsetenv("CEP_UF_MISMATCH")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CEP_UF_MISMATCH")
+	assert.Nil(t, config)
+}
+
+func TestLoadConfig_GrpcDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, config.GrpcEnabled)
+	assert.Equal(t, "9090", config.GrpcPort)
+	assert.Equal(t, "http://api.weatherapi.com/v1/forecast.json", config.WeatherForecastURL)
+}
+
+func TestLoadConfig_GrpcFromEnvironment(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("GRPC_ENABLED", "false")
+	os.Setenv("GRPC_PORT", "50051")
+	defer func() {
+		os.Unsetenv("GRPC_ENABLED")
+		os.Unsetenv("GRPC_PORT")
+	}()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.False(t, config.GrpcEnabled)
+	assert.Equal(t, "50051", config.GrpcPort)
+}
+
+func TestLoadConfig_GraphQLDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 15, config.GraphQLMaxDepth)
+	assert.Equal(t, 100, config.GraphQLMaxComplexity)
+	assert.Equal(t, 10, config.GraphQLMaxBatch)
+}
+
+func TestLoadConfig_InvalidGraphQLLimit(t *testing.T) {
+	for _, value := range []string{"0", "-1", "many"} {
+		t.Run(value, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("GRAPHQL_MAX_COMPLEXITY", value)
+			defer os.Unsetenv("GRAPHQL_MAX_COMPLEXITY")
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid GRAPHQL_MAX_COMPLEXITY")
+		})
+	}
+}
+
+func TestLoadConfig_StreamIntervals(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("STREAM_POLL_INTERVAL", "1m")
+	defer os.Unsetenv("STREAM_POLL_INTERVAL")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, config.StreamPollInterval)
+	assert.Equal(t, 15*time.Second, config.StreamHeartbeatInterval)
+}
+
+func TestLoadConfig_InvalidStreamInterval(t *testing.T) {
+	for _, value := range []string{"0s", "-5s", "often"} {
+		t.Run(value, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv("STREAM_HEARTBEAT_INTERVAL", value)
+			defer os.Unsetenv("STREAM_HEARTBEAT_INTERVAL")
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid STREAM_HEARTBEAT_INTERVAL")
+		})
+	}
+}
+
+func TestLoadConfig_WebhookDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, config.WebhookStorePath)
+	assert.Equal(t, 5*time.Minute, config.WebhookEvalInterval)
+	assert.Equal(t, 10*time.Second, config.WebhookTimeout)
+	assert.Equal(t, 5, config.WebhookMaxAttempts)
+	assert.Equal(t, 20, config.WebhookMaxPerOwner)
+	assert.Equal(t, 2*time.Second, config.WebhookRetryBackoff)
+	assert.False(t, config.WebhookAllowPrivateTargets)
+}
+
+func TestLoadConfig_InvalidWebhookSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"WEBHOOK_EVAL_INTERVAL", "0s"},
+		{"WEBHOOK_TIMEOUT", "soon"},
+		{"WEBHOOK_RETRY_BACKOFF", "-1s"},
+		{"WEBHOOK_MAX_ATTEMPTS", "0"},
+		{"WEBHOOK_MAX_PER_OWNER", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv(tt.name, tt.value)
+			defer os.Unsetenv(tt.name)
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid "+tt.name)
+		})
+	}
+}
+
+func TestLoadConfig_HistoryDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "data/history.db", config.HistoryStorePath)
+	assert.Equal(t, 30*24*time.Hour, config.HistoryRetention)
+	assert.Equal(t, 10000, config.HistoryMaxPerLocation)
+	assert.Equal(t, time.Hour, config.HistoryPruneInterval)
+}
+
+func TestLoadConfig_InvalidHistorySettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"HISTORY_RETENTION", "0s"},
+		{"HISTORY_PRUNE_INTERVAL", "hourly"},
+		{"HISTORY_MAX_PER_LOCATION", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv(tt.name, tt.value)
+			defer os.Unsetenv(tt.name)
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid "+tt.name)
+		})
+	}
+}
+
+func TestLoadConfig_CacheAndPrewarmDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.CepCacheTTL)
+	assert.Equal(t, 5*time.Minute, config.WeatherCacheTTL)
+	assert.Empty(t, config.PrewarmCeps)
+	assert.Equal(t, 0, config.PrewarmTopN)
+	assert.Equal(t, "*/10 * * * *", config.PrewarmCepsSchedule)
+	assert.Equal(t, []i18n.Lang{i18n.English}, config.PrewarmLanguages)
+	assert.Equal(t, 30*time.Second, config.PrewarmJitter)
+	assert.Equal(t, 4, config.PrewarmConcurrency)
+}
+
+func TestLoadConfig_Prewarm(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("PREWARM_CEPS", "01001-000, 20040020")
+	os.Setenv("PREWARM_TOP_SCHEDULE", "@every 15m")
+	os.Setenv("PREWARM_LANGUAGES", "pt-BR,es")
+	defer os.Unsetenv("PREWARM_CEPS")
+	defer os.Unsetenv("PREWARM_TOP_SCHEDULE")
+	defer os.Unsetenv("PREWARM_LANGUAGES")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []cep.CEP{cep.MustParse("01001000"), cep.MustParse("20040020")}, config.PrewarmCeps)
+	assert.Equal(t, "@every 15m", config.PrewarmTopSchedule)
+	assert.Equal(t, []i18n.Lang{i18n.Portuguese, i18n.Spanish}, config.PrewarmLanguages)
+}
+
+func TestLoadConfig_InvalidPrewarmSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"cep", map[string]string{"PREWARM_CEPS": "01001000,123"}, "invalid PREWARM_CEPS"},
+		{"schedule", map[string]string{"PREWARM_CEPS_SCHEDULE": "every day"}, "invalid PREWARM_CEPS_SCHEDULE"},
+		{"top n", map[string]string{"PREWARM_TOP_N": "-1"}, "invalid PREWARM_TOP_N"},
+		{"language", map[string]string{"PREWARM_LANGUAGES": "en,fr"}, "invalid PREWARM_LANGUAGES"},
+		{"concurrency", map[string]string{"PREWARM_CONCURRENCY": "0"}, "invalid PREWARM_CONCURRENCY"},
+		{"jitter", map[string]string{"PREWARM_JITTER": "-1s"}, "invalid PREWARM_JITTER"},
+		{"cache ttl", map[string]string{"WEATHER_CACHE_TTL": "soon"}, "invalid WEATHER_CACHE_TTL"},
+		{"without the cep cache", map[string]string{"PREWARM_TOP_N": "10", "CEP_CACHE_TTL": "0s"}, "pre-warming needs CEP_CACHE_TTL"},
+		{"without the weather cache", map[string]string{"PREWARM_CEPS": "01001000", "WEATHER_CACHE_TTL": "0s"}, "pre-warming needs WEATHER_CACHE_TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestLoadConfig_APIKeys(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("API_KEYS", "web=web-key-0123456789ab, mobile=mobile-key-0123456789")
+	os.Setenv("API_KEY_DAILY_QUOTA", "0")
+	defer os.Unsetenv("API_KEYS")
+	defer os.Unsetenv("API_KEY_DAILY_QUOTA")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"web": "web-key-0123456789ab", "mobile": "mobile-key-0123456789"}, config.APIKeys)
+	assert.Equal(t, 0, config.APIKeyDailyQuota)
+	assert.Equal(t, 20000, config.APIKeyMonthlyQuota)
+	assert.Equal(t, "", config.APIKeyUsageFile)
+	assert.Equal(t, time.Minute, config.APIKeyUsageFlushInterval)
+}
+
+func TestLoadConfig_InvalidAPIKeySettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"API_KEYS", "web"},
+		{"API_KEY_DAILY_QUOTA", "-1"},
+		{"API_KEY_MONTHLY_QUOTA", "-5"},
+		{"API_KEY_USAGE_FLUSH_INTERVAL", "0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv(tt.name, tt.value)
+			defer os.Unsetenv(tt.name)
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid "+tt.name)
+		})
+	}
+}
+
+func TestLoadConfig_RateLimitDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		RateLimitGroupAPI:     {Rate: 10, Burst: 20},
+		RateLimitGroupGraphQL: {Rate: 5, Burst: 10},
+		RateLimitGroupStream:  {Rate: 1, Burst: 5},
+		RateLimitGroupAdmin:   {Rate: 1, Burst: 5},
+	}, config.RateLimitPerIP)
+	assert.Equal(t, RateLimit{Rate: 20, Burst: 40}, config.RateLimitPerKey[RateLimitGroupAPI])
+	assert.Equal(t, 10*time.Minute, config.RateLimitIdleTimeout)
+	assert.Empty(t, config.TrustedProxies)
+}
+
+func TestLoadConfig_RateLimits(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("RATE_LIMIT_PER_IP", "api=30/m:10, graphql=3600/h:60")
+	os.Setenv("RATE_LIMIT_PER_KEY", "off")
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.10")
+	defer os.Unsetenv("RATE_LIMIT_PER_IP")
+	defer os.Unsetenv("RATE_LIMIT_PER_KEY")
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		RateLimitGroupAPI:     {Rate: 0.5, Burst: 10},
+		RateLimitGroupGraphQL: {Rate: 1, Burst: 60},
+	}, config.RateLimitPerIP)
+	assert.Empty(t, config.RateLimitPerKey)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.10"}, config.TrustedProxies)
+}
+
+func TestLoadConfig_InvalidRateLimitSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   string
+		value string
+	}{
+		{"unknown group", "RATE_LIMIT_PER_IP", "web=10/s:20"},
+		{"without burst", "RATE_LIMIT_PER_IP", "api=10/s"},
+		{"unknown unit", "RATE_LIMIT_PER_KEY", "api=10/d:20"},
+		{"zero rate", "RATE_LIMIT_PER_KEY", "api=0/s:20"},
+		{"zero burst", "RATE_LIMIT_PER_KEY", "api=10/s:0"},
+		{"NaN rate", "RATE_LIMIT_PER_KEY", "api=NaN/s:20"},
+		{"infinite rate", "RATE_LIMIT_PER_IP", "api=Inf/s:20"},
+		{"idle timeout", "RATE_LIMIT_IDLE_TIMEOUT", "0s"},
+		{"proxy", "TRUSTED_PROXIES", "10.0.0.0/33"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			os.Setenv(tt.env, tt.value)
+			defer os.Unsetenv(tt.env)
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, "invalid "+tt.env)
+		})
+	}
+}
+
+func TestLoadConfig_WeatherAPIQuotaDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000000), config.WeatherAPIMonthlyQuota)
+	assert.Equal(t, 80, config.WeatherAPIQuotaSoftPercent)
+	assert.Equal(t, WeatherQuotaSoftFallback, config.WeatherAPIQuotaSoftMode)
+	assert.Equal(t, 1, config.WeatherAPIBillingDay)
+	assert.Equal(t, "data/weatherapi-quota.json", config.WeatherAPIQuotaFile)
+	assert.Equal(t, time.Minute, config.WeatherAPIQuotaFlushInterval)
+}
+
+func TestLoadConfig_InvalidWeatherAPIQuotaSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"negative quota", map[string]string{"WEATHERAPI_MONTHLY_QUOTA": "-1"}, "invalid WEATHERAPI_MONTHLY_QUOTA"},
+		{"soft percent", map[string]string{"WEATHERAPI_QUOTA_SOFT_PERCENT": "120"}, "invalid WEATHERAPI_QUOTA_SOFT_PERCENT"},
+		{"soft mode", map[string]string{"WEATHERAPI_QUOTA_SOFT_MODE": "block"}, "invalid WEATHERAPI_QUOTA_SOFT_MODE"},
+		{"cache mode without the cache", map[string]string{"WEATHERAPI_QUOTA_SOFT_MODE": "cache", "WEATHER_CACHE_TTL": "0s"}, "cache needs WEATHER_CACHE_TTL"},
+		{"billing day", map[string]string{"WEATHERAPI_BILLING_DAY": "31"}, "invalid WEATHERAPI_BILLING_DAY"},
+		{"flush interval", map[string]string{"WEATHERAPI_QUOTA_FLUSH_INTERVAL": "0s"}, "invalid WEATHERAPI_QUOTA_FLUSH_INTERVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestLoadConfig_JWT(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("JWT_JWKS_URL", "https://auth.example.com/.well-known/jwks.json")
+	os.Setenv("JWT_ISSUER", "https://auth.example.com/")
+	os.Setenv("JWT_AUDIENCE", "lab-cloudrun, lab-cloudrun-web")
+	os.Setenv("JWT_SCOPE_ROUTES", "weather:read=/api/v1/temperature*|/graphql, cep:read=/api/v1/cep/*")
+	defer os.Unsetenv("JWT_JWKS_URL")
+	defer os.Unsetenv("JWT_ISSUER")
+	defer os.Unsetenv("JWT_AUDIENCE")
+	defer os.Unsetenv("JWT_SCOPE_ROUTES")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lab-cloudrun", "lab-cloudrun-web"}, config.JWTAudience)
+	assert.Equal(t, []string{"RS256", "ES256"}, config.JWTAlgorithms)
+	assert.Equal(t, 30*time.Second, config.JWTLeeway)
+	assert.Equal(t, time.Hour, config.JWTJWKSRefreshInterval)
+	assert.Equal(t, 1000, config.JWTDailyQuota)
+	assert.Equal(t, 20000, config.JWTMonthlyQuota)
+	assert.Equal(t, map[string][]string{
+		"weather:read": {"/api/v1/temperature*", "/graphql"},
+		"cep:read":     {"/api/v1/cep/*"},
+	}, config.JWTScopeRoutes)
+}
+
+func TestLoadConfig_InvalidJWTSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"url and file", map[string]string{"JWT_JWKS_URL": "https://auth.example.com/jwks", "JWT_KEY_FILE": "key.pem", "JWT_ISSUER": "iss", "JWT_AUDIENCE": "aud"}, "invalid JWT_JWKS_URL"},
+		{"without issuer", map[string]string{"JWT_KEY_FILE": "key.pem", "JWT_AUDIENCE": "aud"}, "invalid JWT_ISSUER"},
+		{"without audience", map[string]string{"JWT_KEY_FILE": "key.pem", "JWT_ISSUER": "iss"}, "invalid JWT_AUDIENCE"},
+		{"algorithm", map[string]string{"JWT_KEY_FILE": "key.pem", "JWT_ISSUER": "iss", "JWT_AUDIENCE": "aud", "JWT_ALGORITHMS": "HS256"}, "invalid JWT_ALGORITHMS"},
+		{"scope routes", map[string]string{"JWT_SCOPE_ROUTES": "weather:read=api/v1"}, "invalid JWT_SCOPE_ROUTES"},
+		{"leeway", map[string]string{"JWT_LEEWAY": "-1s"}, "invalid JWT_LEEWAY"},
+		{"refresh interval", map[string]string{"JWT_JWKS_REFRESH_INTERVAL": "0s"}, "invalid JWT_JWKS_REFRESH_INTERVAL"},
+		{"daily quota", map[string]string{"JWT_DAILY_QUOTA": "-1"}, "invalid JWT_DAILY_QUOTA"},
+		{"monthly quota", map[string]string{"JWT_MONTHLY_QUOTA": "-1"}, "invalid JWT_MONTHLY_QUOTA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestLoadConfig_CORSDefaults(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, config.CORSAllowedOrigins)
+	assert.Equal(t, []string{"GET", "POST", "DELETE"}, config.CORSAllowedMethods)
+	assert.Contains(t, config.CORSAllowedHeaders, "X-API-Key")
+	assert.Contains(t, config.CORSExposedHeaders, "RateLimit-Remaining")
+	assert.False(t, config.CORSAllowCredentials)
+	assert.Equal(t, 10*time.Minute, config.CORSMaxAge)
+}
+
+func TestLoadConfig_CORS(t *testing.T) {
+	// arrange
+	resetViperAndConfig()
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://App.Example.com/, https://*.example.com, http://localhost:3000")
+	os.Setenv("CORS_ALLOWED_METHODS", "get,post")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	os.Setenv("CORS_MAX_AGE", "1h")
+	defer os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	defer os.Unsetenv("CORS_ALLOWED_METHODS")
+	defer os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+	defer os.Unsetenv("CORS_MAX_AGE")
+
+	// act
+	config, err := LoadConfig()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com", "https://*.example.com", "http://localhost:3000"}, config.CORSAllowedOrigins)
+	assert.Equal(t, []string{"GET", "POST"}, config.CORSAllowedMethods)
+	assert.True(t, config.CORSAllowCredentials)
+	assert.Equal(t, time.Hour, config.CORSMaxAge)
+}
+
+func TestLoadConfig_InvalidCORSSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"origin without scheme", map[string]string{"CORS_ALLOWED_ORIGINS": "app.example.com"}, "invalid CORS_ALLOWED_ORIGINS"},
+		{"origin with path", map[string]string{"CORS_ALLOWED_ORIGINS": "https://app.example.com/web"}, "invalid CORS_ALLOWED_ORIGINS"},
+		{"wildcard inside the host", map[string]string{"CORS_ALLOWED_ORIGINS": "https://app.*.example.com"}, "invalid CORS_ALLOWED_ORIGINS"},
+		{"credentials for any origin", map[string]string{"CORS_ALLOWED_ORIGINS": "*", "CORS_ALLOW_CREDENTIALS": "true"}, "invalid CORS_ALLOW_CREDENTIALS"},
+		{"without methods", map[string]string{"CORS_ALLOWED_ORIGINS": "*", "CORS_ALLOWED_METHODS": " , "}, "invalid CORS_ALLOWED_METHODS"},
+		{"max age", map[string]string{"CORS_MAX_AGE": "-1m"}, "invalid CORS_MAX_AGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			resetViperAndConfig()
+			for name, value := range tt.env {
+				os.Setenv(name, value)
+				defer os.Unsetenv(name)
+			}
+
+			// act
+			config, err := LoadConfig()
+
+			// assert
+			assert.Nil(t, config)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseList splits a comma separated value, dropping blank entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseKeyValues splits a "key=value,key=value" list into a map
+func parseKeyValues(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, item := range parseList(value) {
+		key, val, ok := strings.Cut(item, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || key == "" || val == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", item)
+		}
+		pairs[key] = val
+	}
+	return pairs, nil
+}
+
+func parseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+func parseFloatMap(value string) (map[string]float64, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	floats := make(map[string]float64, len(pairs))
+	for key, val := range pairs {
+		f, err := parseFloat(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		floats[key] = f
+	}
+	return floats, nil
+}
+
+func parseDurationMap(value string) (map[string]time.Duration, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := make(map[string]time.Duration, len(pairs))
+	for key, val := range pairs {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		durations[key] = d
+	}
+	return durations, nil
+}
+
+// rateUnits are the periods a rate limit may be given per
+var rateUnits = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+// parseRateLimits reads "group=rate/unit:burst" pairs, such as
+// "api=10/s:20,graphql=30/m:10", for the known route groups. "off" turns
+// every limit off, as an empty variable falls back to the default
+func parseRateLimits(value string) (map[string]RateLimit, error) {
+	if strings.TrimSpace(value) == "off" {
+		return map[string]RateLimit{}, nil
+	}
+
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{RateLimitGroupAPI, RateLimitGroupGraphQL, RateLimitGroupStream, RateLimitGroupAdmin}
+	limits := make(map[string]RateLimit, len(pairs))
+	for group, val := range pairs {
+		if !slices.Contains(groups, group) {
+			return nil, fmt.Errorf("unknown group %q (use %s)", group, strings.Join(groups, ", "))
+		}
+
+		rate, burst, ok := strings.Cut(val, ":")
+		count, unit, hasUnit := strings.Cut(rate, "/")
+		n, countErr := strconv.ParseFloat(count, 64)
+		b, burstErr := strconv.Atoi(burst)
+		period, knownUnit := rateUnits[unit]
+		finite := !math.IsNaN(n) && !math.IsInf(n, 0)
+		if !ok || !hasUnit || !knownUnit || countErr != nil || burstErr != nil || !finite || n <= 0 || b <= 0 {
+			return nil, fmt.Errorf("%s: expected rate/unit:burst, such as 10/s:20, got %q", group, val)
+		}
+
+		limits[group] = RateLimit{Rate: n / period.Seconds(), Burst: b}
+	}
+	return limits, nil
+}
+
+// parseScopeRoutes reads "scope=route|route" pairs, such as
+// "weather:read=/api/v1/temperature*|/graphql,cep:read=/api/v1/cep/*"
+func parseScopeRoutes(value string) (map[string][]string, error) {
+	pairs, err := parseKeyValues(value)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make(map[string][]string, len(pairs))
+	for scope, val := range pairs {
+		for route := range strings.SplitSeq(val, "|") {
+			if route = strings.TrimSpace(route); !strings.HasPrefix(route, "/") {
+				return nil, fmt.Errorf("%s: routes must start with /, got %q", scope, route)
+			}
+			scopes[scope] = append(scopes[scope], route)
+		}
+	}
+	return scopes, nil
+}
+
+// parseOrigins reads a list of CORS origins: * for any origin, or a scheme
+// and host with an optional port, such as https://app.example.com, whose
+// host may start with *. to match any subdomain
+func parseOrigins(value string) ([]string, error) {
+	origins := parseList(strings.ToLower(value))
+	for i, origin := range origins {
+		if origin == "*" {
+			continue
+		}
+
+		origin = strings.TrimSuffix(origin, "/")
+		parsed, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil || strings.Contains(parsed.Host, "*") {
+			return nil, fmt.Errorf("expected *, scheme://host or scheme://*.domain, got %q", origins[i])
+		}
+		origins[i] = origin
+	}
+	return origins, nil
+}
+
+// parseProxies reads a list of IP addresses and CIDR ranges
+func parseProxies(value string) ([]string, error) {
+	proxies := parseList(value)
+	for _, proxy := range proxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR range", proxy)
+		}
+	}
+	return proxies, nil
+}
+
+// parseBoundingBox reads "south,west,north,east" in decimal degrees
+func parseBoundingBox(value string) ([4]float64, error) {
+	var box [4]float64
+
+	items := parseList(value)
+	if len(items) != 4 {
+		return box, fmt.Errorf("expected south,west,north,east, got %q", value)
+	}
+
+	for i, item := range items {
+		f, err := parseFloat(item)
+		if err != nil {
+			return box, err
+		}
+		box[i] = f
+	}
+
+	south, west, north, east := box[0], box[1], box[2], box[3]
+	if south < -90 || north > 90 || west < -180 || east > 180 || south >= north || west >= east {
+		return box, fmt.Errorf("%q is not a valid south,west,north,east box", value)
+	}
+
+	return box, nil
+}
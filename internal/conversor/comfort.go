@@ -0,0 +1,101 @@
+package conversor
+
+import (
+	"math"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Wind chill is only defined for cold air in moving wind
+const (
+	windChillMaxC   = 10.0
+	windChillMinKph = 4.8
+)
+
+// ConvertComfort derives the comfort indices of an observation. Indices that
+// need humidity are left out when the provider did not report it, and wind
+// chill is left out outside the range where it is defined
+func ConvertComfort(observation model.Observation) model.ComfortIndices {
+	var indices model.ComfortIndices
+
+	temp, humidity := observation.TemperatureC, observation.HumidityPct
+	if chill, ok := WindChillC(temp, observation.Wind.SpeedKph); ok {
+		indices.WindChillC = rounded(chill)
+	}
+
+	if humidity <= 0 {
+		return indices
+	}
+	humidity = math.Min(humidity, 100)
+
+	indices.HeatIndexC = rounded(HeatIndexC(temp, humidity))
+	indices.Humidex = rounded(Humidex(temp, humidity))
+	indices.DewPointC = rounded(DewPointC(temp, humidity))
+	indices.WBGTC = rounded(WBGTC(temp, humidity))
+
+	return indices
+}
+
+// HeatIndexC is the NWS heat index: Steadman's simple formula below 80°F and
+// the Rothfusz regression, with the NWS low and high humidity adjustments,
+// above it
+func HeatIndexC(tempC, humidityPct float64) float64 {
+	t, rh := tempC*1.8+32, humidityPct
+
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 < 80 {
+		return (hi - 32) / 1.8
+	}
+
+	hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+		0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+		0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+
+	switch {
+	case rh < 13 && t >= 80 && t <= 112:
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case rh > 85 && t >= 80 && t <= 87:
+		hi += (rh - 85) / 10 * ((87 - t) / 5)
+	}
+
+	return (hi - 32) / 1.8
+}
+
+// WindChillC is the Environment Canada / NWS wind chill index. The boolean is
+// false above 10°C or below 4.8 km/h, where the index is not defined
+func WindChillC(tempC, windKph float64) (float64, bool) {
+	if tempC > windChillMaxC || windKph < windChillMinKph {
+		return 0, false
+	}
+
+	v := math.Pow(windKph, 0.16)
+	return 13.12 + 0.6215*tempC - 11.37*v + 0.3965*tempC*v, true
+}
+
+// DewPointC uses the Magnus formula with the Alduchov and Eskridge constants
+func DewPointC(tempC, humidityPct float64) float64 {
+	const a, b = 17.625, 243.04
+
+	gamma := math.Log(humidityPct/100) + a*tempC/(b+tempC)
+	return b * gamma / (a - gamma)
+}
+
+// Humidex is the Environment Canada humidex, computed from the dew point
+func Humidex(tempC, humidityPct float64) float64 {
+	dewPointK := DewPointC(tempC, humidityPct) + 273.15
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/dewPointK))
+	return tempC + 0.5555*(vapourPressure-10)
+}
+
+// WBGTC approximates the wet bulb globe temperature from air temperature and
+// humidity alone, as the Australian Bureau of Meteorology does. It assumes
+// shade and light wind, so it underestimates WBGT in direct sun
+func WBGTC(tempC, humidityPct float64) float64 {
+	vapourPressure := humidityPct / 100 * 6.105 * math.Exp(17.27*tempC/(237.7+tempC))
+	return 0.567*tempC + 0.393*vapourPressure + 3.94
+}
+
+func rounded(value float64) *float64 {
+	value = roundToTwoDecimals(value)
+	return &value
+}
@@ -0,0 +1,171 @@
+package conversor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fahrenheitToCelsius(f float64) float64 {
+	return (f - 32) / 1.8
+}
+
+// Tabela de índice de calor do NWS (°F), valores arredondados para inteiro
+func TestHeatIndexC_NWSTable(t *testing.T) {
+	tests := []struct {
+		tempF, humidity, expectedF float64
+	}{
+		{80, 40, 80},
+		{90, 50, 95},
+		{86, 90, 105},
+		{100, 40, 109},
+		{96, 65, 121},
+		{110, 10, 104}, // ajuste de umidade baixa
+		{82, 95, 94},   // ajuste de umidade alta
+		{70, 50, 69},   // fórmula simples de Steadman
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fF_%.0f%%", tt.tempF, tt.humidity), func(t *testing.T) {
+			// Act
+			result := HeatIndexC(fahrenheitToCelsius(tt.tempF), tt.humidity)
+
+			// Assert
+			assert.InDelta(t, fahrenheitToCelsius(tt.expectedF), result, 0.5/1.8)
+		})
+	}
+}
+
+// Tabela de sensação térmica do Environment Canada (°C)
+func TestWindChillC_EnvironmentCanadaTable(t *testing.T) {
+	tests := []struct {
+		temp, wind, expected float64
+	}{
+		{0, 10, -3},
+		{5, 50, -1},
+		{-10, 20, -18},
+		{-20, 30, -33},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fC_%.0fkph", tt.temp, tt.wind), func(t *testing.T) {
+			// Act
+			result, ok := WindChillC(tt.temp, tt.wind)
+
+			// Assert
+			assert.True(t, ok)
+			assert.InDelta(t, tt.expected, result, 0.5)
+		})
+	}
+}
+
+func TestWindChillC_Undefined(t *testing.T) {
+	_, ok := WindChillC(15, 30)
+	assert.False(t, ok, "above 10°C")
+
+	_, ok = WindChillC(0, 3)
+	assert.False(t, ok, "below 4.8 km/h")
+}
+
+func TestDewPointC(t *testing.T) {
+	tests := []struct {
+		temp, humidity, expected float64
+	}{
+		{25, 60, 16.7},
+		{30, 80, 26.2},
+		{20, 50, 9.3},
+		{35, 20, 8.7},
+		{10, 100, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fC_%.0f%%", tt.temp, tt.humidity), func(t *testing.T) {
+			assert.InDelta(t, tt.expected, DewPointC(tt.temp, tt.humidity), 0.05)
+		})
+	}
+}
+
+// Tabela de humidex do Environment Canada
+func TestHumidex_EnvironmentCanadaTable(t *testing.T) {
+	tests := []struct {
+		temp, humidity, expected float64
+	}{
+		{25, 60, 30},
+		{30, 40, 34},
+		{30, 70, 41},
+		{35, 50, 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fC_%.0f%%", tt.temp, tt.humidity), func(t *testing.T) {
+			assert.InDelta(t, tt.expected, Humidex(tt.temp, tt.humidity), 0.5)
+		})
+	}
+}
+
+// Tabela de WBGT aproximado do Bureau of Meteorology australiano
+func TestWBGTC_BureauOfMeteorologyTable(t *testing.T) {
+	tests := []struct {
+		temp, humidity, expected float64
+	}{
+		{20, 50, 20},
+		{25, 80, 28},
+		{30, 50, 29},
+		{35, 40, 33},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.0fC_%.0f%%", tt.temp, tt.humidity), func(t *testing.T) {
+			assert.InDelta(t, tt.expected, WBGTC(tt.temp, tt.humidity), 0.5)
+		})
+	}
+}
+
+func TestConvertComfort_Hot(t *testing.T) {
+	// Arrange
+	observation := model.Observation{TemperatureC: 32.2, HumidityPct: 36, Wind: model.Wind{SpeedKph: 8.6}}
+
+	// Act
+	result := ConvertComfort(observation)
+
+	// Assert
+	assert.Nil(t, result.WindChillC)
+	require.NotNil(t, result.HeatIndexC)
+	require.NotNil(t, result.Humidex)
+	require.NotNil(t, result.DewPointC)
+	require.NotNil(t, result.WBGTC)
+	assert.Equal(t, 31.88, *result.HeatIndexC)
+	assert.InDelta(t, 15.3, *result.DewPointC, 0.1)
+	assert.Equal(t, roundToTwoDecimals(*result.WBGTC), *result.WBGTC)
+}
+
+func TestConvertComfort_Cold(t *testing.T) {
+	// Arrange
+	observation := model.Observation{TemperatureC: -10, HumidityPct: 70, Wind: model.Wind{SpeedKph: 20}}
+
+	// Act
+	result := ConvertComfort(observation)
+
+	// Assert
+	require.NotNil(t, result.WindChillC)
+	assert.Equal(t, -17.86, *result.WindChillC)
+	assert.NotNil(t, result.DewPointC)
+}
+
+func TestConvertComfort_MissingHumidity(t *testing.T) {
+	// Arrange
+	observation := model.Observation{TemperatureC: 5, Wind: model.Wind{SpeedKph: 30}}
+
+	// Act
+	result := ConvertComfort(observation)
+
+	// Assert
+	assert.NotNil(t, result.WindChillC)
+	assert.Nil(t, result.HeatIndexC)
+	assert.Nil(t, result.Humidex)
+	assert.Nil(t, result.DewPointC)
+	assert.Nil(t, result.WBGTC)
+}
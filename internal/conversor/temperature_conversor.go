@@ -6,13 +6,18 @@ import (
 	"github.com/alexduzi/labcloudrun/internal/model"
 )
 
-func ConvertWeatherResponse(weather model.WeatherResponse) model.TemperatureResponse {
-	kelvin := weather.Current.TempC + 273.15
-	fahrenheit := weather.Current.TempC*1.8 + 32
+var celsius, fahrenheit, kelvin = mustUnit("C"), mustUnit("F"), mustUnit("K")
+
+func ConvertObservation(observation model.Observation) model.TemperatureResponse {
+	return ConvertCelsius(observation.TemperatureC)
+}
+
+// ConvertCelsius expresses a Celsius temperature in Celsius, Fahrenheit and Kelvin
+func ConvertCelsius(tempC float64) model.TemperatureResponse {
 	return model.TemperatureResponse{
-		Celsius:    roundToTwoDecimals(weather.Current.TempC),
-		Fahrenheit: roundToTwoDecimals(fahrenheit),
-		Kelvin:     roundToTwoDecimals(kelvin),
+		Celsius:    roundToTwoDecimals(tempC),
+		Fahrenheit: roundToTwoDecimals(celsius.convert(tempC, fahrenheit)),
+		Kelvin:     roundToTwoDecimals(celsius.convert(tempC, kelvin)),
 	}
 }
 
@@ -7,13 +7,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestConvertWeatherResponse_ZeroCelsius(t *testing.T) {
+func TestConvertObservation_ZeroCelsius(t *testing.T) {
 	// Arrange
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 0
+	observation := model.Observation{TemperatureC: 0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, 0.0, result.Celsius)
@@ -21,13 +20,12 @@ func TestConvertWeatherResponse_ZeroCelsius(t *testing.T) {
 	assert.Equal(t, 273.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_PositiveCelsius(t *testing.T) {
+func TestConvertObservation_PositiveCelsius(t *testing.T) {
 	// Arrange
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 25.0
+	observation := model.Observation{TemperatureC: 25.0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, 25.0, result.Celsius)
@@ -35,13 +33,12 @@ func TestConvertWeatherResponse_PositiveCelsius(t *testing.T) {
 	assert.Equal(t, 298.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_NegativeCelsius(t *testing.T) {
+func TestConvertObservation_NegativeCelsius(t *testing.T) {
 	// Arrange
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = -10.0
+	observation := model.Observation{TemperatureC: -10.0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, -10.0, result.Celsius)
@@ -49,13 +46,12 @@ func TestConvertWeatherResponse_NegativeCelsius(t *testing.T) {
 	assert.Equal(t, 263.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_BoilingPointWater(t *testing.T) {
+func TestConvertObservation_BoilingPointWater(t *testing.T) {
 	// Arrange - 100°C (boiling point of water)
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 100.0
+	observation := model.Observation{TemperatureC: 100.0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, 100.0, result.Celsius)
@@ -63,13 +59,12 @@ func TestConvertWeatherResponse_BoilingPointWater(t *testing.T) {
 	assert.Equal(t, 373.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_AbsoluteZero(t *testing.T) {
+func TestConvertObservation_AbsoluteZero(t *testing.T) {
 	// Arrange - -273.15°C (absolute zero)
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = -273.15
+	observation := model.Observation{TemperatureC: -273.15}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, -273.15, result.Celsius)
@@ -77,13 +72,12 @@ func TestConvertWeatherResponse_AbsoluteZero(t *testing.T) {
 	assert.InDelta(t, 0.0, result.Kelvin, 0.01)
 }
 
-func TestConvertWeatherResponse_DecimalValues(t *testing.T) {
+func TestConvertObservation_DecimalValues(t *testing.T) {
 	// Arrange
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 28.5
+	observation := model.Observation{TemperatureC: 28.5}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, 28.5, result.Celsius)
@@ -91,13 +85,12 @@ func TestConvertWeatherResponse_DecimalValues(t *testing.T) {
 	assert.Equal(t, 301.65, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_TypicalSummerDay(t *testing.T) {
+func TestConvertObservation_TypicalSummerDay(t *testing.T) {
 	// Arrange - Typical hot summer day
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 35.0
+	observation := model.Observation{TemperatureC: 35.0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, 35.0, result.Celsius)
@@ -105,13 +98,12 @@ func TestConvertWeatherResponse_TypicalSummerDay(t *testing.T) {
 	assert.Equal(t, 308.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_TypicalWinterDay(t *testing.T) {
+func TestConvertObservation_TypicalWinterDay(t *testing.T) {
 	// Arrange - Typical cold winter day
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = -5.0
+	observation := model.Observation{TemperatureC: -5.0}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert
 	assert.Equal(t, -5.0, result.Celsius)
@@ -119,13 +111,12 @@ func TestConvertWeatherResponse_TypicalWinterDay(t *testing.T) {
 	assert.Equal(t, 268.15, result.Kelvin)
 }
 
-func TestConvertWeatherResponse_RoundingPrecision(t *testing.T) {
+func TestConvertObservation_RoundingPrecision(t *testing.T) {
 	// Arrange - Test case that previously had floating point precision issues
-	weather := model.WeatherResponse{}
-	weather.Current.TempC = 32.2
+	observation := model.Observation{TemperatureC: 32.2}
 
 	// Act
-	result := ConvertWeatherResponse(weather)
+	result := ConvertObservation(observation)
 
 	// Assert - All values should be rounded to exactly 2 decimal places
 	assert.Equal(t, 32.2, result.Celsius)
@@ -0,0 +1,264 @@
+package conversor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Quantity is the physical quantity a unit measures. Only units of the same
+// quantity convert into each other
+type Quantity string
+
+const (
+	Temperature   Quantity = "temperature"
+	Speed         Quantity = "speed"
+	Pressure      Quantity = "pressure"
+	Precipitation Quantity = "precipitation"
+)
+
+var (
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrIncompatibleUnits = errors.New("units measure different quantities")
+	ErrValueOutOfRange   = errors.New("value out of range")
+)
+
+// UnitError reports a unit LookupUnit does not know
+type UnitError struct {
+	Unit string
+}
+
+func (e *UnitError) Error() string {
+	return fmt.Sprintf("%v %q", ErrUnknownUnit, e.Unit)
+}
+
+func (e *UnitError) Unwrap() error {
+	return ErrUnknownUnit
+}
+
+// QuantityError reports a conversion between units of different quantities
+type QuantityError struct {
+	From, To Unit
+}
+
+func (e *QuantityError) Error() string {
+	return fmt.Sprintf("%v: %s is %s, %s is %s", ErrIncompatibleUnits, e.From.Symbol, e.From.Quantity, e.To.Symbol, e.To.Quantity)
+}
+
+func (e *QuantityError) Unwrap() error {
+	return ErrIncompatibleUnits
+}
+
+// RangeError reports a value that is not finite or outside Unit.Min and
+// Unit.Max
+type RangeError struct {
+	Value float64
+	Unit  Unit
+}
+
+// Finite reports whether the value was rejected for its range rather than
+// for being NaN or infinite
+func (e *RangeError) Finite() bool {
+	return !math.IsNaN(e.Value) && !math.IsInf(e.Value, 0)
+}
+
+// Below reports whether the value is under Unit.Min
+func (e *RangeError) Below() bool {
+	return e.Value < e.Unit.Min
+}
+
+func (e *RangeError) Error() string {
+	switch {
+	case !e.Finite():
+		return fmt.Sprintf("%v: %v is not a finite number", ErrValueOutOfRange, e.Value)
+	case e.Below():
+		return fmt.Sprintf("%v: %s must be at least %v %s", ErrValueOutOfRange, e.Unit.Quantity, e.Unit.Min, e.Unit.Symbol)
+	default:
+		return fmt.Sprintf("%v: %s must be at most %v %s", ErrValueOutOfRange, e.Unit.Quantity, e.Unit.Max, e.Unit.Symbol)
+	}
+}
+
+func (e *RangeError) Unwrap() error {
+	return ErrValueOutOfRange
+}
+
+// Unit converts values to and from the base unit of its quantity: kelvin,
+// metres per second, hectopascal and millimetre
+type Unit struct {
+	Symbol   string
+	Name     string
+	Quantity Quantity
+	// Min and Max bound the values that are physically possible, in this unit
+	Min, Max float64
+
+	toBase   func(float64) float64
+	fromBase func(float64) float64
+}
+
+// beaufortLimits are the upper wind speeds, in m/s, of forces 0 to 11 in the
+// WMO Beaufort scale; anything faster is force 12
+var beaufortLimits = []float64{0.2, 1.5, 3.3, 5.4, 7.9, 10.7, 13.8, 17.1, 20.7, 24.4, 28.4, 32.6}
+
+const (
+	kelvinOffset          = 273.15
+	metresPerMile         = 1609.344
+	metresPerNauticalMile = 1852
+	hPaPerInHg            = 33.8638866667
+	hPaPerMmHg            = 1.33322387415
+	millimetresPerIn      = 25.4
+)
+
+func scale(factor float64) (func(float64) float64, func(float64) float64) {
+	return func(v float64) float64 { return v * factor }, func(v float64) float64 { return v / factor }
+}
+
+func linear(factor, offset float64) (func(float64) float64, func(float64) float64) {
+	return func(v float64) float64 { return v*factor + offset }, func(v float64) float64 { return (v - offset) / factor }
+}
+
+func newUnit(symbol, name string, quantity Quantity, min, max float64, to, from func(float64) float64) Unit {
+	return Unit{Symbol: symbol, Name: name, Quantity: quantity, Min: min, Max: max, toBase: to, fromBase: from}
+}
+
+var units = func() []Unit {
+	inf := math.Inf(1)
+
+	celsiusTo, celsiusFrom := linear(1, kelvinOffset)
+	fahrenheitTo := func(v float64) float64 { return (v-32)/1.8 + kelvinOffset }
+	fahrenheitFrom := func(v float64) float64 { return (v-kelvinOffset)*1.8 + 32 }
+	kelvinTo, kelvinFrom := scale(1)
+	rankineTo, rankineFrom := scale(5.0 / 9)
+	reaumurTo, reaumurFrom := linear(1.25, kelvinOffset)
+
+	kphTo, kphFrom := scale(1 / 3.6)
+	mphTo, mphFrom := scale(metresPerMile / 3600)
+	mpsTo, mpsFrom := scale(1)
+	knotTo, knotFrom := scale(metresPerNauticalMile / 3600.0)
+
+	hPaTo, hPaFrom := scale(1)
+	inHgTo, inHgFrom := scale(hPaPerInHg)
+	mmHgTo, mmHgFrom := scale(hPaPerMmHg)
+
+	mmTo, mmFrom := scale(1)
+	inTo, inFrom := scale(millimetresPerIn)
+
+	return []Unit{
+		newUnit("C", "celsius", Temperature, -kelvinOffset, inf, celsiusTo, celsiusFrom),
+		newUnit("F", "fahrenheit", Temperature, -459.67, inf, fahrenheitTo, fahrenheitFrom),
+		newUnit("K", "kelvin", Temperature, 0, inf, kelvinTo, kelvinFrom),
+		newUnit("Ra", "rankine", Temperature, 0, inf, rankineTo, rankineFrom),
+		newUnit("Re", "reaumur", Temperature, -kelvinOffset*0.8, inf, reaumurTo, reaumurFrom),
+
+		newUnit("kph", "kilometres per hour", Speed, 0, inf, kphTo, kphFrom),
+		newUnit("mph", "miles per hour", Speed, 0, inf, mphTo, mphFrom),
+		newUnit("m/s", "metres per second", Speed, 0, inf, mpsTo, mpsFrom),
+		newUnit("kn", "knots", Speed, 0, inf, knotTo, knotFrom),
+		newUnit("bft", "beaufort", Speed, 0, 12, beaufortToSpeed, speedToBeaufort),
+
+		newUnit("mb", "millibar", Pressure, 0, inf, hPaTo, hPaFrom),
+		newUnit("hPa", "hectopascal", Pressure, 0, inf, hPaTo, hPaFrom),
+		newUnit("inHg", "inches of mercury", Pressure, 0, inf, inHgTo, inHgFrom),
+		newUnit("mmHg", "millimetres of mercury", Pressure, 0, inf, mmHgTo, mmHgFrom),
+
+		newUnit("mm", "millimetres", Precipitation, 0, inf, mmTo, mmFrom),
+		newUnit("in", "inches", Precipitation, 0, inf, inTo, inFrom),
+	}
+}()
+
+// unitAliases maps lower cased spellings accepted by LookupUnit to symbols
+var unitAliases = map[string]string{
+	"°c": "C", "°f": "F", "°ra": "Ra", "°r": "Ra", "°re": "Re", "°ré": "Re", "ré": "Re", "réaumur": "Re",
+	"km/h": "kph", "kmh": "kph", "mps": "m/s", "knot": "kn", "kt": "kn", "kts": "kn",
+	"millibars": "mb", "mbar": "mb", "inch": "in", "inches": "in",
+}
+
+var unitsByKey = func() map[string]Unit {
+	byKey := make(map[string]Unit)
+	for _, u := range units {
+		byKey[strings.ToLower(u.Symbol)] = u
+		byKey[u.Name] = u
+	}
+	for alias, symbol := range unitAliases {
+		byKey[alias] = byKey[strings.ToLower(symbol)]
+	}
+	return byKey
+}()
+
+// Units returns every supported unit, grouped by quantity
+func Units() []Unit {
+	return append([]Unit(nil), units...)
+}
+
+// LookupUnit finds a unit by symbol, name or common alias, ignoring case
+func LookupUnit(symbol string) (Unit, bool) {
+	u, ok := unitsByKey[strings.ToLower(strings.TrimSpace(symbol))]
+	return u, ok
+}
+
+// Convert converts value from one unit to another. Values that are not
+// physically possible in the source unit, such as temperatures below
+// absolute zero, are rejected
+func Convert(value float64, from, to string) (float64, error) {
+	source, ok := LookupUnit(from)
+	if !ok {
+		return 0, &UnitError{Unit: from}
+	}
+	target, ok := LookupUnit(to)
+	if !ok {
+		return 0, &UnitError{Unit: to}
+	}
+
+	return source.ConvertTo(value, target)
+}
+
+// Validate reports whether value is possible in the unit
+func (u Unit) Validate(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < u.Min || value > u.Max {
+		return &RangeError{Value: value, Unit: u}
+	}
+	return nil
+}
+
+// ConvertTo converts a value in u into target
+func (u Unit) ConvertTo(value float64, target Unit) (float64, error) {
+	if u.Quantity != target.Quantity {
+		return 0, &QuantityError{From: u, To: target}
+	}
+	if err := u.Validate(value); err != nil {
+		return 0, err
+	}
+
+	return u.convert(value, target), nil
+}
+
+// convert converts without validating value or the quantities
+func (u Unit) convert(value float64, target Unit) float64 {
+	if u.Symbol == target.Symbol {
+		return value
+	}
+	return target.fromBase(u.toBase(value))
+}
+
+func mustUnit(symbol string) Unit {
+	u, ok := LookupUnit(symbol)
+	if !ok {
+		panic("conversor: unknown unit " + symbol)
+	}
+	return u
+}
+
+// beaufortToSpeed uses the empirical relation v = 0.836 B^1.5 m/s
+func beaufortToSpeed(force float64) float64 {
+	return 0.836 * math.Pow(force, 1.5)
+}
+
+// speedToBeaufort returns the force whose WMO speed band contains v
+func speedToBeaufort(v float64) float64 {
+	for force, limit := range beaufortLimits {
+		if v < limit+0.05 {
+			return float64(force)
+		}
+	}
+	return float64(len(beaufortLimits))
+}
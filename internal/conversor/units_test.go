@@ -0,0 +1,225 @@
+package conversor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert_Temperature(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		expected float64
+	}{
+		{0, "C", "F", 32},
+		{100, "C", "K", 373.15},
+		{-40, "C", "F", -40},
+		{0, "K", "C", -273.15},
+		{0, "C", "Ra", 491.67},
+		{100, "C", "Re", 80},
+		{80, "Re", "F", 212},
+		{671.67, "Ra", "F", 212},
+		{98.6, "F", "C", 37},
+		{-459.67, "F", "K", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			// Act
+			result, err := Convert(tt.value, tt.from, tt.to)
+
+			// Assert
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+}
+
+func TestConvert_Speed(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		expected float64
+	}{
+		{36, "kph", "m/s", 10},
+		{100, "mph", "kph", 160.9344},
+		{10, "kn", "kph", 18.52},
+		{1, "m/s", "kn", 1.9438444924},
+		{0, "bft", "m/s", 0},
+		{12, "bft", "m/s", 34.7519},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			result, err := Convert(tt.value, tt.from, tt.to)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, 1e-4)
+		})
+	}
+}
+
+// Escala Beaufort da OMM: limites superiores de cada força em m/s
+func TestConvert_ToBeaufort(t *testing.T) {
+	tests := []struct {
+		speed float64
+		force float64
+	}{
+		{0, 0},
+		{0.2, 0},
+		{0.3, 1},
+		{3.3, 2},
+		{3.4, 3},
+		{10.7, 5},
+		{10.8, 6},
+		{24.4, 9},
+		{32.6, 11},
+		{32.7, 12},
+		{60, 12},
+	}
+
+	for _, tt := range tests {
+		result, err := Convert(tt.speed, "m/s", "bft")
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.force, result, "%v m/s", tt.speed)
+	}
+}
+
+func TestConvert_Pressure(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		expected float64
+	}{
+		{1013.25, "hPa", "mb", 1013.25},
+		{1013.25, "hPa", "inHg", 29.9212},
+		{1013.25, "mb", "mmHg", 760},
+		{29.92, "inHg", "hPa", 1013.2075},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			result, err := Convert(tt.value, tt.from, tt.to)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, 1e-3)
+		})
+	}
+}
+
+func TestConvert_Precipitation(t *testing.T) {
+	result, err := Convert(1, "in", "mm")
+	require.NoError(t, err)
+	assert.InDelta(t, 25.4, result, 1e-9)
+
+	result, err = Convert(12.7, "mm", "in")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, result, 1e-9)
+}
+
+func TestConvert_Aliases(t *testing.T) {
+	for _, alias := range []string{"celsius", "°C", "c", " C "} {
+		result, err := Convert(0, alias, "fahrenheit")
+		require.NoError(t, err, alias)
+		assert.Equal(t, 32.0, result)
+	}
+
+	for _, alias := range []string{"km/h", "KPH", "kmh"} {
+		u, ok := LookupUnit(alias)
+		assert.True(t, ok, alias)
+		assert.Equal(t, "kph", u.Symbol)
+	}
+}
+
+func TestConvert_BelowAbsoluteZero(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+	}{
+		{-273.16, "C"},
+		{-460, "F"},
+		{-0.01, "K"},
+		{-1, "Ra"},
+		{-219, "Re"},
+	}
+
+	for _, tt := range tests {
+		_, err := Convert(tt.value, tt.unit, "K")
+		assert.ErrorIs(t, err, ErrValueOutOfRange, "%v %s", tt.value, tt.unit)
+	}
+}
+
+func TestConvert_OutOfRange(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+	}{
+		{-1, "kph"},
+		{13, "bft"},
+		{-1, "hPa"},
+		{-0.1, "mm"},
+		{math.NaN(), "C"},
+		{math.Inf(1), "mb"},
+	}
+
+	for _, tt := range tests {
+		_, err := Convert(tt.value, tt.unit, tt.unit)
+		assert.ErrorIs(t, err, ErrValueOutOfRange, "%v %s", tt.value, tt.unit)
+	}
+}
+
+func TestConvert_UnknownUnit(t *testing.T) {
+	_, err := Convert(1, "parsec", "C")
+	assert.ErrorIs(t, err, ErrUnknownUnit)
+	assert.EqualError(t, err, `unknown unit "parsec"`)
+
+	_, err = Convert(1, "C", "")
+	assert.ErrorIs(t, err, ErrUnknownUnit)
+}
+
+func TestConvert_IncompatibleUnits(t *testing.T) {
+	_, err := Convert(10, "C", "mph")
+	assert.ErrorIs(t, err, ErrIncompatibleUnits)
+	assert.EqualError(t, err, "units measure different quantities: C is temperature, mph is speed")
+}
+
+func TestUnits_RoundTrip(t *testing.T) {
+	for _, from := range Units() {
+		if from.Symbol == "bft" {
+			continue
+		}
+		for _, to := range Units() {
+			if from.Quantity != to.Quantity || to.Symbol == "bft" {
+				continue
+			}
+
+			converted, err := from.ConvertTo(42, to)
+			require.NoError(t, err)
+			back, err := to.ConvertTo(converted, from)
+			require.NoError(t, err)
+			assert.InDelta(t, 42, back, 1e-9, "%s -> %s", from.Symbol, to.Symbol)
+		}
+	}
+}
+
+func TestConvert_ErrorDetails(t *testing.T) {
+	_, err := Convert(-300, "C", "F")
+
+	var rangeErr *RangeError
+	require.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, -300.0, rangeErr.Value)
+	assert.Equal(t, "C", rangeErr.Unit.Symbol)
+	assert.True(t, rangeErr.Finite())
+	assert.True(t, rangeErr.Below())
+	assert.EqualError(t, err, "value out of range: temperature must be at least -273.15 C")
+
+	_, err = Convert(1, "C", "furlong")
+
+	var unitErr *UnitError
+	require.ErrorAs(t, err, &unitErr)
+	assert.Equal(t, "furlong", unitErr.Unit)
+}
@@ -0,0 +1,48 @@
+// Package geo validates coordinates and finds the municipality nearest to a
+// point
+package geo
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// BrazilBoundingBox covers the mainland and the oceanic islands
+// (Fernando de Noronha, Trindade, Atol das Rocas)
+var BrazilBoundingBox = BoundingBox{South: -33.75, West: -73.99, North: 5.27, East: -28.84}
+
+// BoundingBox is a latitude/longitude rectangle in decimal degrees
+type BoundingBox struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// Contains reports whether the point lies inside the box, borders included
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	return lat >= b.South && lat <= b.North && lon >= b.West && lon <= b.East
+}
+
+// ValidCoordinates reports whether lat and lon are finite and within the
+// ranges of the WGS84 coordinate system
+func ValidCoordinates(lat, lon float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(lon) &&
+		lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// DistanceKm returns the great-circle distance between two points using the
+// haversine formula
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi := radians(lat2 - lat1)
+	dLambda := radians(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,66 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		expected               float64
+	}{
+		{"Same point", -23.5505, -46.6333, -23.5505, -46.6333, 0},
+		{"São Paulo to Rio de Janeiro", -23.5505, -46.6333, -22.9068, -43.1729, 361},
+		{"Brasília to Manaus", -15.7939, -47.8828, -3.1190, -60.0217, 1937},
+		{"One degree of latitude", 0, 0, 1, 0, 111.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, DistanceKm(tt.lat1, tt.lon1, tt.lat2, tt.lon2), 1)
+		})
+	}
+}
+
+func TestDistanceKm_IsSymmetric(t *testing.T) {
+	assert.InDelta(t,
+		DistanceKm(-30.0346, -51.2177, 2.8235, -60.6758),
+		DistanceKm(2.8235, -60.6758, -30.0346, -51.2177),
+		1e-9)
+}
+
+func TestBrazilBoundingBox_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		expected bool
+	}{
+		{"São Paulo", -23.5505, -46.6333, true},
+		{"Chuí (south)", -33.69, -53.46, true},
+		{"Monte Caburaí (north)", 5.27, -60.21, true},
+		{"Fernando de Noronha", -3.85, -32.42, true},
+		{"Ilha da Trindade", -20.51, -29.31, true},
+		{"Buenos Aires", -34.60, -58.38, false},
+		{"Lisbon", 38.72, -9.14, false},
+		{"Null Island", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BrazilBoundingBox.Contains(tt.lat, tt.lon))
+		})
+	}
+}
+
+func TestValidCoordinates(t *testing.T) {
+	assert.True(t, ValidCoordinates(-90, -180))
+	assert.True(t, ValidCoordinates(90, 180))
+	assert.False(t, ValidCoordinates(90.1, 0))
+	assert.False(t, ValidCoordinates(0, -180.1))
+	assert.False(t, ValidCoordinates(math.NaN(), 0))
+	assert.False(t, ValidCoordinates(0, math.Inf(1)))
+}
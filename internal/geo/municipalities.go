@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// municipalitiesCSV is a curated subset of IBGE's municipalities: every state
+// capital plus the largest cities of each state. Deployments that need full
+// coverage can point GEO_MUNICIPALITIES_FILE at a complete file in the same
+// format (ibge,name,uf,lat,lon,cep_prefix)
+//
+//go:embed municipalities.csv
+var municipalitiesCSV []byte
+
+// Municipality is a city with the coordinates of its seat and the 5-digit CEP
+// prefix of its central area
+type Municipality struct {
+	Ibge      string
+	Name      string
+	UF        string
+	Lat       float64
+	Lon       float64
+	CepPrefix string
+}
+
+// Dataset is an immutable list of municipalities
+type Dataset struct {
+	municipalities []Municipality
+}
+
+// Default returns the dataset bundled with the binary
+func Default() *Dataset {
+	dataset, err := ReadCSV(bytes.NewReader(municipalitiesCSV))
+	if err != nil {
+		panic(fmt.Sprintf("geo: invalid embedded municipalities: %v", err))
+	}
+	return dataset
+}
+
+// LoadFile reads a dataset from a CSV file
+func LoadFile(path string) (*Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dataset, err := ReadCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dataset, nil
+}
+
+// ReadCSV reads municipalities from CSV with the header
+// ibge,name,uf,lat,lon,cep_prefix
+func ReadCSV(r io.Reader) (*Dataset, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty municipalities file")
+	}
+
+	municipalities := make([]Municipality, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != 6 {
+			return nil, fmt.Errorf("line %d: expected 6 columns, got %d", i+2, len(record))
+		}
+
+		lat, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid lat: %w", i+2, err)
+		}
+		lon, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid lon: %w", i+2, err)
+		}
+		if !ValidCoordinates(lat, lon) {
+			return nil, fmt.Errorf("line %d: coordinates out of range", i+2)
+		}
+
+		municipalities = append(municipalities, Municipality{
+			Ibge:      record[0],
+			Name:      record[1],
+			UF:        strings.ToUpper(record[2]),
+			Lat:       lat,
+			Lon:       lon,
+			CepPrefix: record[5],
+		})
+	}
+
+	return &Dataset{municipalities: municipalities}, nil
+}
+
+// Len returns the number of municipalities in the dataset
+func (d *Dataset) Len() int {
+	return len(d.municipalities)
+}
+
+// All returns a copy of the municipalities in the dataset
+func (d *Dataset) All() []Municipality {
+	return append([]Municipality(nil), d.municipalities...)
+}
+
+// Nearest returns the municipality closest to the point and its distance in
+// km. The boolean is false only for an empty dataset
+func (d *Dataset) Nearest(lat, lon float64) (Municipality, float64, bool) {
+	var (
+		nearest  Municipality
+		distance float64
+		found    bool
+	)
+
+	for _, m := range d.municipalities {
+		if km := DistanceKm(lat, lon, m.Lat, m.Lon); !found || km < distance {
+			nearest, distance, found = m, km, true
+		}
+	}
+
+	return nearest, distance, found
+}
@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault_Dataset(t *testing.T) {
+	dataset := Default()
+
+	capitals := make(map[string]bool)
+	for _, m := range dataset.All() {
+		// todas as cidades ficam dentro do Brasil
+		assert.True(t, BrazilBoundingBox.Contains(m.Lat, m.Lon), "%s is outside Brazil", m.Name)
+
+		// o prefixo de CEP pertence à faixa da UF da cidade
+		code, err := cep.Parse(m.CepPrefix + "000")
+		if assert.NoError(t, err, m.Name) {
+			assert.Equal(t, m.UF, code.UF(), "%s has a CEP prefix from another UF", m.Name)
+		}
+
+		assert.Len(t, m.Ibge, 7, m.Name)
+		capitals[m.UF] = true
+	}
+
+	// ao menos uma cidade por UF
+	assert.Len(t, capitals, 27)
+}
+
+func TestDataset_Nearest(t *testing.T) {
+	dataset := Default()
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		expected string
+		maxKm    float64
+	}{
+		{"Avenida Paulista", -23.5614, -46.6559, "São Paulo", 5},
+		{"Copacabana", -22.9711, -43.1822, "Rio de Janeiro", 10},
+		{"Campinas center", -22.9056, -47.0608, "Campinas", 1},
+		{"Esplanada dos Ministérios", -15.7998, -47.8645, "Brasília", 3},
+		{"Balneário Camboriú", -26.9926, -48.6353, "Blumenau", 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, km, ok := dataset.Nearest(tt.lat, tt.lon)
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, m.Name)
+			assert.Less(t, km, tt.maxKm)
+		})
+	}
+}
+
+func TestDataset_NearestEmpty(t *testing.T) {
+	dataset, err := ReadCSV(strings.NewReader("ibge,name,uf,lat,lon,cep_prefix\n"))
+	require.NoError(t, err)
+
+	_, _, ok := dataset.Nearest(-23.55, -46.63)
+
+	assert.False(t, ok)
+	assert.Equal(t, 0, dataset.Len())
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{"Empty", "", "empty"},
+		{"Missing column", "header\n3550308,São Paulo,SP,-23.5,-46.6\n", "expected 6 columns"},
+		{"Invalid latitude", "header,,,,,\n3550308,São Paulo,SP,south,-46.6,01001\n", "invalid lat"},
+		{"Latitude out of range", "header,,,,,\n3550308,São Paulo,SP,-123.5,-46.6,01001\n", "out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataset, err := ReadCSV(strings.NewReader(tt.data))
+
+			assert.Nil(t, dataset)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "municipalities.csv")
+	data := "ibge,name,uf,lat,lon,cep_prefix\n3550308,São Paulo,sp,-23.5505,-46.6333,01001\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+
+	// act
+	dataset, err := LoadFile(path)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []Municipality{{Ibge: "3550308", Name: "São Paulo", UF: "SP", Lat: -23.5505, Lon: -46.6333, CepPrefix: "01001"}}, dataset.All())
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
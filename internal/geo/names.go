@@ -0,0 +1,129 @@
+package geo
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSuggestions bounds the candidates returned for an unknown name
+const maxSuggestions = 5
+
+// CityMatch is the outcome of looking a municipality up by name
+type CityMatch struct {
+	// Matches holds the municipalities whose name equals the query once
+	// accents, case and punctuation are ignored
+	Matches []Municipality
+	// Suggestions holds close names, best first, when nothing matched
+	Suggestions []Municipality
+}
+
+// NormalizeName folds a municipality name for comparison: accents are
+// removed, letters are lower cased and hyphens, apostrophes and repeated
+// spaces become a single space, so "Ji-Paraná" and "ji parana" are equal
+func NormalizeName(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+
+	folded = strings.Map(func(r rune) rune {
+		if r == '-' || r == '\'' || r == '’' {
+			return ' '
+		}
+		return unicode.ToLower(r)
+	}, folded)
+
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// FindCity looks name up among the municipalities of uf. When there is no
+// exact match, Suggestions lists names of uf within a small edit distance or
+// starting with the query, and failing that the same name in other UFs
+func (d *Dataset) FindCity(uf, name string) CityMatch {
+	uf = strings.ToUpper(uf)
+	query := NormalizeName(name)
+
+	var match CityMatch
+	for _, m := range d.municipalities {
+		if m.UF == uf && NormalizeName(m.Name) == query {
+			match.Matches = append(match.Matches, m)
+		}
+	}
+	if len(match.Matches) > 0 || query == "" {
+		return match
+	}
+
+	type candidate struct {
+		municipality Municipality
+		distance     int
+	}
+
+	var candidates []candidate
+	threshold := max(1, len([]rune(query))/3)
+	for _, m := range d.municipalities {
+		if m.UF != uf {
+			continue
+		}
+
+		normalized := NormalizeName(m.Name)
+		distance := levenshtein(query, normalized)
+		if distance <= threshold || (len(query) >= 3 && strings.HasPrefix(normalized, query)) {
+			candidates = append(candidates, candidate{m, distance})
+		}
+	}
+
+	// a known name under the wrong UF is the next best guess
+	if len(candidates) == 0 {
+		for _, m := range d.municipalities {
+			if NormalizeName(m.Name) == query {
+				candidates = append(candidates, candidate{m, 0})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].municipality.Name < candidates[j].municipality.Name
+	})
+
+	for i, c := range candidates {
+		if i == maxSuggestions {
+			break
+		}
+		match.Suggestions = append(match.Suggestions, c.municipality)
+	}
+
+	return match
+}
+
+// levenshtein returns the edit distance between a and b, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
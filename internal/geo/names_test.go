@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"São Paulo", "sao paulo"},
+		{"SÃO PAULO", "sao paulo"},
+		{"  sao   paulo ", "sao paulo"},
+		{"Ji-Paraná", "ji parana"},
+		{"Santa Bárbara d'Oeste", "santa barbara d oeste"},
+		{"Florianópolis", "florianopolis"},
+		{"Goiânia", "goiania"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeName(tt.name))
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("campinas", "campinas"))
+	assert.Equal(t, 1, levenshtein("campina", "campinas"))
+	assert.Equal(t, 2, levenshtein("canpinaz", "campinas"))
+	assert.Equal(t, 8, levenshtein("", "campinas"))
+	assert.Equal(t, 1, levenshtein("sao", "são"))
+}
+
+func TestDataset_FindCity(t *testing.T) {
+	dataset := Default()
+
+	// arrange / act
+	tests := []struct {
+		name        string
+		uf          string
+		city        string
+		matches     []string
+		suggestions []string
+	}{
+		{"exact", "SP", "Campinas", []string{"Campinas"}, nil},
+		{"accents and case", "sp", "SAO JOSE DO RIO PRETO", []string{"São José do Rio Preto"}, nil},
+		{"hyphen", "RO", "ji parana", []string{"Ji-Paraná"}, nil},
+		{"typo", "SP", "Campinaz", nil, []string{"Campinas"}},
+		{"prefix", "SP", "sao jose", nil, []string{"São José dos Campos", "São José do Rio Preto"}},
+		{"wrong uf", "RJ", "Campinas", nil, []string{"Campinas"}},
+		{"unknown", "SP", "Xique-Xique", nil, nil},
+		{"blank", "SP", "  ", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := dataset.FindCity(tt.uf, tt.city)
+
+			// assert
+			assert.Equal(t, tt.matches, names(match.Matches))
+			assert.Equal(t, tt.suggestions, names(match.Suggestions))
+		})
+	}
+}
+
+func TestDataset_FindCity_Ambiguous(t *testing.T) {
+	// arrange
+	dataset, err := ReadCSV(strings.NewReader(`ibge,name,uf,lat,lon,cep_prefix
+1111111,Bom Jesus,PI,-9.07,-44.36,64900
+2222222,Bom Jesús,PI,-9.10,-44.30,64901
+`))
+	require.NoError(t, err)
+
+	// act
+	match := dataset.FindCity("PI", "bom jesus")
+
+	// assert
+	assert.Len(t, match.Matches, 2)
+	assert.Empty(t, match.Suggestions)
+}
+
+func TestDataset_FindCity_LimitsSuggestions(t *testing.T) {
+	// arrange
+	var csv strings.Builder
+	csv.WriteString("ibge,name,uf,lat,lon,cep_prefix\n")
+	for _, name := range []string{"Santa Ana", "Santa Cruz", "Santa Fé", "Santa Helena", "Santa Inês", "Santa Luzia", "Santa Rita"} {
+		csv.WriteString("1111111," + name + ",PB,-7,-35,58000\n")
+	}
+	dataset, err := ReadCSV(strings.NewReader(csv.String()))
+	require.NoError(t, err)
+
+	// act
+	match := dataset.FindCity("PB", "Santa")
+
+	// assert
+	assert.Empty(t, match.Matches)
+	assert.Len(t, match.Suggestions, maxSuggestions)
+}
+
+func names(municipalities []Municipality) []string {
+	var result []string
+	for _, m := range municipalities {
+		result = append(result, m.Name)
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Error codes sent in the extensions of an error
+const (
+	CodeBadUserInput        = "BAD_USER_INPUT"
+	CodeNotFound            = "NOT_FOUND"
+	CodeUnsupported         = "UNSUPPORTED"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamQuota       = "UPSTREAM_QUOTA_EXHAUSTED"
+	CodeDeadlineExceeded    = "DEADLINE_EXCEEDED"
+	CodeCanceled            = "CANCELED"
+	CodeInternal            = "INTERNAL_SERVER_ERROR"
+	CodeQueryTooDeep        = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex     = "QUERY_TOO_COMPLEX"
+	CodeBatchTooLarge       = "BATCH_TOO_LARGE"
+	CodeBadRequest          = "BAD_REQUEST"
+)
+
+// errorMapping is the code and message key of a domain error
+type errorMapping struct {
+	target error
+	code   string
+	key    string
+}
+
+// errorMappings follow the gRPC status mapping, not the HTTP one, for
+// upstream failures: UPSTREAM_UNAVAILABLE and NOT_FOUND where HTTP answers 500
+var errorMappings = []errorMapping{
+	{hErrors.CepParamNotExists, CodeNotFound, "error.cep_not_found"},
+	{hErrors.CepCantFind, CodeNotFound, "error.cep_not_found"},
+	{hErrors.CepInvalid, CodeBadUserInput, "error.cep_invalid"},
+	{hErrors.CepUFMismatch, CodeBadUserInput, "error.cep_uf_mismatch"},
+	{hErrors.CoordinatesInvalid, CodeBadUserInput, "error.coordinates_invalid"},
+	{hErrors.CoordinatesOutOfBounds, CodeBadUserInput, "error.coordinates_out_of_bounds"},
+	{hErrors.ForecastDaysInvalid, CodeBadUserInput, "error.forecast_days_invalid"},
+	{cErrors.ForecastUnsupported, CodeUnsupported, "error.forecast_unsupported"},
+	{cErrors.WeatherQuotaExhausted, CodeUpstreamQuota, "error.weather_quota_exhausted"},
+	{cErrors.WeatherQuotaSoftLimited, CodeUpstreamQuota, "error.weather_quota_soft_limited"},
+	{cErrors.WeatherProvidersUnavailable, CodeUpstreamUnavailable, "error.upstream_unavailable"},
+	{cErrors.WeatherClientNotFound, CodeNotFound, "error.city_not_found"},
+	{cErrors.WeatherClientInternalError, CodeUpstreamUnavailable, "error.upstream_unavailable"},
+	{cErrors.WeatherClientUnexpectedError, CodeUpstreamUnavailable, "error.upstream_unavailable"},
+	{cErrors.CepClientBadRequest, CodeInternal, "error.internal"},
+	{cErrors.CepClientNotFound, CodeInternal, "error.internal"},
+	{cErrors.CepClientInternalError, CodeUpstreamUnavailable, "error.upstream_unavailable"},
+	{cErrors.CepClientUnexpectedError, CodeUpstreamUnavailable, "error.upstream_unavailable"},
+}
+
+// Error is a GraphQL error as sent to clients
+type Error struct {
+	Message    string          `json:"message" example:"can not find zipcode"`
+	Locations  []ErrorLocation `json:"locations,omitempty"`
+	Path       []any           `json:"path,omitempty"`
+	Extensions map[string]any  `json:"extensions,omitempty"`
+}
+
+// ErrorLocation points at the part of the query an error refers to
+type ErrorLocation struct {
+	Line   int `json:"line" example:"1"`
+	Column int `json:"column" example:"3"`
+}
+
+// NewError is an error with code in its extensions
+func NewError(code, message string) Error {
+	return Error{Message: message, Extensions: map[string]any{"code": code}}
+}
+
+// toErrors converts the errors of the executor. Errors raised by resolvers,
+// the ones with a path, get a code and a message in the language of ctx;
+// syntax and validation errors are passed on as reported
+func toErrors(ctx context.Context, formatted []gqlerrors.FormattedError) []Error {
+	if len(formatted) == 0 {
+		return nil
+	}
+
+	errs := make([]Error, 0, len(formatted))
+	for _, f := range formatted {
+		err := Error{Message: f.Message, Path: f.Path, Extensions: f.Extensions}
+		for _, l := range f.Locations {
+			err.Locations = append(err.Locations, ErrorLocation{Line: l.Line, Column: l.Column})
+		}
+
+		if len(f.Path) > 0 {
+			code, message := resolverError(ctx, originalError(f))
+			err.Message = message
+			err.Extensions = map[string]any{"code": code}
+		}
+
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// resolverError returns the code and localized message of a resolver error
+func resolverError(ctx context.Context, err error) (string, string) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded, err.Error()
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled, err.Error()
+	}
+
+	lang := i18n.FromContext(ctx)
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.target) {
+			return mapping.code, lang.Text(mapping.key)
+		}
+	}
+
+	return CodeInternal, lang.Text("error.internal")
+}
+
+// originalError unwraps the layers the executor adds around the error a
+// resolver returned
+func originalError(err error) error {
+	for {
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			if e.OriginalError() == nil {
+				return err
+			}
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			if e.OriginalError == nil {
+				return err
+			}
+			err = e.OriginalError
+		default:
+			return err
+		}
+	}
+}
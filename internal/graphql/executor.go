@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Request is a GraphQL operation as sent over HTTP
+type Request struct {
+	Query         string         `json:"query" example:"{ location(cep: \"01001000\") { address { city uf } current { temperature { celsius } } } }"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the result of a Request
+type Response struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Executor runs GraphQL requests on top of the service layer the REST
+// handlers use
+type Executor struct {
+	schema  graphql.Schema
+	service *service.WeatherService
+	limits  Limits
+}
+
+func NewExecutor(svc *service.WeatherService, limits Limits) *Executor {
+	schema, err := newSchema()
+	if err != nil {
+		// The schema is static, so this is a programming error
+		panic(err)
+	}
+
+	return &Executor{
+		schema:  schema,
+		service: svc,
+		limits:  limits,
+	}
+}
+
+// Execute runs a single request
+func (e *Executor) Execute(ctx context.Context, request Request) *Response {
+	return e.ExecuteBatch(ctx, []Request{request})[0]
+}
+
+// ExecuteBatch runs the requests in order. They share the loaders, so a
+// lookup made by one of them is not repeated by the others
+func (e *Executor) ExecuteBatch(ctx context.Context, requests []Request) []*Response {
+	ctx = withLoaders(ctx, newLoaders(ctx, e.service))
+
+	responses := make([]*Response, len(requests))
+	for i, request := range requests {
+		responses[i] = e.run(ctx, request)
+	}
+	return responses
+}
+
+func (e *Executor) run(ctx context.Context, request Request) *Response {
+	doc, err := parser.Parse(parser.ParseParams{Source: request.Query})
+	if err != nil {
+		return &Response{Errors: toErrors(ctx, gqlerrors.FormatErrors(err))}
+	}
+
+	if validation := graphql.ValidateDocument(&e.schema, doc, nil); !validation.IsValid {
+		return &Response{Errors: toErrors(ctx, validation.Errors)}
+	}
+
+	lang := i18n.FromContext(ctx)
+	measured := measure(doc, request.OperationName, request.Variables)
+	if e.limits.MaxDepth > 0 && measured.depth > e.limits.MaxDepth {
+		return &Response{Errors: []Error{NewError(CodeQueryTooDeep,
+			lang.Text("error.graphql_depth_exceeded", measured.depth, e.limits.MaxDepth))}}
+	}
+	if e.limits.MaxComplexity > 0 && measured.complexity > e.limits.MaxComplexity {
+		return &Response{Errors: []Error{NewError(CodeQueryTooComplex,
+			lang.Text("error.graphql_complexity_exceeded", measured.complexity, e.limits.MaxComplexity))}}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+
+	return &Response{Data: result.Data, Errors: toErrors(ctx, result.Errors)}
+}
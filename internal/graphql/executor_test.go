@@ -0,0 +1,344 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecutorTestSuite struct {
+	suite.Suite
+	cepClient     *client.CepClientStub
+	weatherClient *client.WeatherClientStub
+	executor      *Executor
+}
+
+func (s *ExecutorTestSuite) SetupTest() {
+	cfg := &config.Config{}
+	s.cepClient = client.NewCepClientStub(cfg)
+	s.weatherClient = client.NewWeatherClientStub(cfg)
+	s.executor = NewExecutor(service.NewWeatherService(cfg, s.cepClient, s.weatherClient, nil), Limits{
+		MaxDepth:      15,
+		MaxComplexity: 100,
+	})
+}
+
+// data encodes the data of response as JSON
+func (s *ExecutorTestSuite) data(response *Response) string {
+	data, err := json.Marshal(response.Data)
+	s.Require().NoError(err)
+	return string(data)
+}
+
+func (s *ExecutorTestSuite) TestExecute_Location() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+	s.weatherClient.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, 3).Return(model.GetForecastMock("São Paulo", 3), nil)
+
+	// act
+	response := s.executor.Execute(context.Background(), Request{Query: `{
+		location(cep: "01001000") {
+			cep
+			address { city uf state }
+			current { source temperature { celsius fahrenheit kelvin } condition { text } }
+			forecast { source days { date min { celsius } max { celsius kelvin } } }
+			alerts { event }
+		}
+	}`})
+
+	// assert
+	assert.Empty(s.T(), response.Errors)
+	assert.JSONEq(s.T(), `{"location": {
+		"cep": "01001-000",
+		"address": {"city": "São Paulo", "uf": "SP", "state": "São Paulo"},
+		"current": {
+			"source": "weatherapi",
+			"temperature": {"celsius": 32.2, "fahrenheit": 89.96, "kelvin": 305.35},
+			"condition": {"text": "Partly cloudy"}
+		},
+		"forecast": {"source": "openmeteo", "days": [
+			{"date": "2026-01-11", "min": {"celsius": 19.4}, "max": {"celsius": 29.8, "kelvin": 302.95}},
+			{"date": "2026-01-12", "min": {"celsius": 19.4}, "max": {"celsius": 30.8, "kelvin": 303.95}},
+			{"date": "2026-01-13", "min": {"celsius": 19.4}, "max": {"celsius": 31.8, "kelvin": 304.95}}
+		]},
+		"alerts": null
+	}}`, s.data(response))
+
+	// forecast and alerts share the forecast lookup
+	s.cepClient.AssertNumberOfCalls(s.T(), "GetCep", 1)
+	s.weatherClient.AssertNumberOfCalls(s.T(), "GetWeather", 1)
+	s.weatherClient.AssertNumberOfCalls(s.T(), "GetForecast", 1)
+}
+
+func (s *ExecutorTestSuite) TestExecute_Alerts() {
+	// arrange
+	expires := time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC)
+	forecast := model.GetForecastMock("São Paulo", 3)
+	forecast.Alerts = []model.Alert{{Event: "Heavy rain warning", Severity: "Moderate", Expires: &expires}}
+
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, 3).Return(forecast, nil)
+
+	// act
+	response := s.executor.Execute(context.Background(), Request{
+		Query: `{ location(cep: "01001-000") { alerts { event severity expires effective } } }`,
+	})
+
+	// assert
+	assert.Empty(s.T(), response.Errors)
+	assert.JSONEq(s.T(), `{"location": {"alerts": [
+		{"event": "Heavy rain warning", "severity": "Moderate", "expires": "2026-01-12T12:00:00Z", "effective": null}
+	]}}`, s.data(response))
+}
+
+func (s *ExecutorTestSuite) TestExecute_DeduplicatesLookups() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	response := s.executor.Execute(context.Background(), Request{Query: `{
+		a: location(cep: "01001000") { current { source } }
+		b: location(cep: "01001-000") { address { city } current { source } }
+		c: location(cep: "01310100") { current { source } }
+	}`})
+
+	// assert
+	assert.Empty(s.T(), response.Errors)
+	s.cepClient.AssertNumberOfCalls(s.T(), "GetCep", 2)
+	s.weatherClient.AssertNumberOfCalls(s.T(), "GetWeather", 1)
+}
+
+func (s *ExecutorTestSuite) TestExecuteBatch_SharesLookups() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	responses := s.executor.ExecuteBatch(context.Background(), []Request{
+		{Query: `{ location(cep: "01001000") { address { city } } }`},
+		{
+			Query:     `query Current($cep: String!) { location(cep: $cep) { current { temperature { celsius } } } }`,
+			Variables: map[string]any{"cep": "01001000"},
+		},
+	})
+
+	// assert
+	assert.Len(s.T(), responses, 2)
+	assert.JSONEq(s.T(), `{"location": {"address": {"city": "São Paulo"}}}`, s.data(responses[0]))
+	assert.JSONEq(s.T(), `{"location": {"current": {"temperature": {"celsius": 32.2}}}}`, s.data(responses[1]))
+	s.cepClient.AssertNumberOfCalls(s.T(), "GetCep", 1)
+}
+
+func (s *ExecutorTestSuite) TestExecute_ResolverErrors() {
+	erro := "true"
+	notFound := &model.ViacepResponse{Erro: &erro}
+
+	tests := []struct {
+		name    string
+		query   string
+		cep     *model.ViacepResponse
+		cepErr  error
+		code    string
+		message string
+	}{
+		{"invalid cep", `{ location(cep: "123") { cep } }`, nil, nil, CodeBadUserInput, "CEP inválido"},
+		{"cep not found", `{ location(cep: "01001000") { cep } }`, notFound, nil, CodeNotFound, "CEP não encontrado"},
+		{"cep upstream down", `{ location(cep: "01001000") { cep } }`, nil, cErrors.CepClientInternalError, CodeUpstreamUnavailable, "serviço externo indisponível"},
+		{"cep upstream bad request", `{ location(cep: "01001000") { cep } }`, nil, cErrors.CepClientBadRequest, CodeInternal, "erro interno do servidor"},
+		{"cep upstream not found", `{ location(cep: "01001000") { cep } }`, nil, cErrors.CepClientNotFound, CodeInternal, "erro interno do servidor"},
+		{"forecast days", `{ location(cep: "01001000") { forecast(days: 9) { source } } }`, model.GetViacepResponseMock("01001-000"), nil, CodeBadUserInput, "days deve estar entre 1 e 7"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// arrange
+			s.cepClient.ExpectedCalls = nil
+			s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(tt.cep, tt.cepErr)
+			ctx := i18n.WithLang(context.Background(), i18n.Portuguese)
+
+			// act
+			response := s.executor.Execute(ctx, Request{Query: tt.query})
+
+			// assert
+			s.Require().Len(response.Errors, 1)
+			assert.Equal(s.T(), tt.message, response.Errors[0].Message)
+			assert.Equal(s.T(), tt.code, response.Errors[0].Extensions["code"])
+			assert.NotEmpty(s.T(), response.Errors[0].Path)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestExecute_ForecastUnsupported() {
+	// arrange
+	cfg := &config.Config{}
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	executor := NewExecutor(service.NewWeatherService(cfg, s.cepClient, currentOnly{s.weatherClient}, nil), Limits{})
+
+	// act
+	response := executor.Execute(context.Background(), Request{
+		Query: `{ location(cep: "01001000") { address { city } alerts { event } } }`,
+	})
+
+	// assert
+	assert.JSONEq(s.T(), `{"location": {"address": {"city": "São Paulo"}, "alerts": null}}`, s.data(response))
+	s.Require().Len(response.Errors, 1)
+	assert.Equal(s.T(), CodeUnsupported, response.Errors[0].Extensions["code"])
+}
+
+func (s *ExecutorTestSuite) TestExecute_InvalidQuery() {
+	for _, query := range []string{`{ location(cep: "01001000") { cep `, `{ location { cep } }`, `{ weather }`} {
+		s.Run(query, func() {
+			// act
+			response := s.executor.Execute(context.Background(), Request{Query: query})
+
+			// assert
+			assert.Nil(s.T(), response.Data)
+			assert.NotEmpty(s.T(), response.Errors)
+			s.cepClient.AssertNotCalled(s.T(), "GetCep", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestExecute_Limits() {
+	tests := []struct {
+		name    string
+		query   string
+		code    string
+		message string
+	}{
+		{
+			name:    "too deep",
+			query:   `{ location(cep: "01001000") { forecast { days { max { celsius } } } } }`,
+			code:    CodeQueryTooDeep,
+			message: "query depth 5 exceeds the limit of 4",
+		},
+		{
+			name: "too complex",
+			query: `{
+				a: location(cep: "01001000") { forecast(days: 7) { days { date } } }
+				b: location(cep: "01310100") { forecast(days: 7) { days { date } } }
+			}`,
+			code:    CodeQueryTooComplex,
+			message: "query complexity 68 exceeds the limit of 50",
+		},
+	}
+
+	executor := NewExecutor(service.NewWeatherService(&config.Config{}, s.cepClient, s.weatherClient, nil), Limits{
+		MaxDepth:      4,
+		MaxComplexity: 50,
+	})
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			response := executor.Execute(context.Background(), Request{Query: tt.query})
+
+			// assert
+			assert.Nil(s.T(), response.Data)
+			s.Require().Len(response.Errors, 1)
+			assert.Equal(s.T(), tt.code, response.Errors[0].Extensions["code"])
+			assert.Equal(s.T(), tt.message, response.Errors[0].Message)
+			s.cepClient.AssertNotCalled(s.T(), "GetCep", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestExecute_Introspection() {
+	// act
+	response := s.executor.Execute(context.Background(), Request{Query: introspectionQuery})
+
+	// assert
+	assert.Empty(s.T(), response.Errors)
+	assert.Contains(s.T(), s.data(response), `"name":"Location"`)
+}
+
+// currentOnly hides the forecast support of a weather client
+type currentOnly struct {
+	client.WeatherClientInterface
+}
+
+func TestExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorTestSuite))
+}
+
+// introspectionQuery is the query GraphiQL and other tools send to load the
+// schema
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType { kind name }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,166 @@
+package graphql
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the queries the endpoint runs; zero disables a check
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// upstreamCost is the complexity of a field that calls an upstream API.
+// Other fields cost 1, and introspection fields nothing since they only read
+// the schema
+const upstreamCost = 10
+
+// upstreamFields are the fields resolved with an upstream lookup
+var upstreamFields = map[string]bool{
+	"location": true,
+	"current":  true,
+	"forecast": true,
+	"alerts":   true,
+}
+
+// maxMeasure caps depth and complexity, so fragments spread many times over
+// cannot overflow them
+const maxMeasure = math.MaxInt32
+
+// measurement is the depth and complexity of a selection set
+type measurement struct {
+	depth      int
+	complexity int
+}
+
+// measurer computes the depth and complexity of a validated document
+type measurer struct {
+	variables map[string]any
+	fragments map[string]*ast.FragmentDefinition
+	measured  map[string]measurement
+}
+
+// measure returns the depth and complexity of the operation named
+// operationName, or the largest of all operations when the name is empty.
+// A field in forecast's selection counts once per forecast day
+func measure(doc *ast.Document, operationName string, variables map[string]any) measurement {
+	m := &measurer{
+		variables: variables,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		measured:  make(map[string]measurement),
+	}
+
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var result measurement
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+
+		measured := m.selectionSet(operation.SelectionSet)
+		result.depth = max(result.depth, measured.depth)
+		result.complexity = max(result.complexity, measured.complexity)
+	}
+	return result
+}
+
+// selectionSet measures set relative to its parent field. Fragments are
+// measured once, as validation already rejected fragment cycles
+func (m *measurer) selectionSet(set *ast.SelectionSet) measurement {
+	var result measurement
+	if set == nil {
+		return result
+	}
+
+	for _, selection := range set.Selections {
+		var measured measurement
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			measured = m.field(s)
+		case *ast.InlineFragment:
+			measured = m.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			cached, ok := m.measured[name]
+			if !ok {
+				if fragment, found := m.fragments[name]; found {
+					cached = m.selectionSet(fragment.SelectionSet)
+				}
+				m.measured[name] = cached
+			}
+			measured = cached
+		}
+
+		result.depth = max(result.depth, measured.depth)
+		result.complexity = min(result.complexity+measured.complexity, maxMeasure)
+	}
+	return result
+}
+
+func (m *measurer) field(field *ast.Field) measurement {
+	name := field.Name.Value
+	children := m.selectionSet(field.SelectionSet)
+	result := measurement{depth: min(children.depth+1, maxMeasure)}
+
+	switch {
+	case strings.HasPrefix(name, "__"):
+		return result
+	case upstreamFields[name]:
+		result.complexity = upstreamCost
+	default:
+		result.complexity = 1
+	}
+
+	if name == "forecast" {
+		children.complexity = min(children.complexity*m.days(field), maxMeasure)
+	}
+	result.complexity = min(result.complexity+children.complexity, maxMeasure)
+	return result
+}
+
+// days reads forecast's days argument, clamped to the accepted range
+func (m *measurer) days(field *ast.Field) int {
+	days := service.DefaultForecastDays
+
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "days" {
+			continue
+		}
+
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if parsed, err := strconv.Atoi(value.Value); err == nil {
+				days = parsed
+			}
+		case *ast.Variable:
+			switch v := m.variables[value.Name.Value].(type) {
+			case float64:
+				days = int(v)
+			case int:
+				days = v
+			case json.Number:
+				if parsed, err := v.Int64(); err == nil {
+					days = int(parsed)
+				}
+			}
+		}
+	}
+
+	return min(max(days, service.MinForecastDays), service.MaxForecastDays)
+}
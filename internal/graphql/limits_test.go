@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		operation  string
+		variables  map[string]any
+		depth      int
+		complexity int
+	}{
+		{
+			name:       "address",
+			query:      `{ location(cep: "01001000") { cep address { city uf } } }`,
+			depth:      3,
+			complexity: 10 + 1 + 1 + 2,
+		},
+		{
+			name:       "forecast counts its selection once per day",
+			query:      `{ location(cep: "01001000") { forecast(days: 5) { days { date } } } }`,
+			depth:      4,
+			complexity: 10 + 10 + 5*2,
+		},
+		{
+			name:       "forecast defaults to 3 days",
+			query:      `{ location(cep: "01001000") { forecast { days { date } } } }`,
+			depth:      4,
+			complexity: 10 + 10 + 3*2,
+		},
+		{
+			name:       "forecast days from a variable",
+			query:      `query($days: Int) { location(cep: "01001000") { forecast(days: $days) { source } } }`,
+			variables:  map[string]any{"days": float64(7)},
+			depth:      3,
+			complexity: 10 + 10 + 7,
+		},
+		{
+			name:       "forecast days out of range are clamped",
+			query:      `{ location(cep: "01001000") { forecast(days: 90) { source } } }`,
+			depth:      3,
+			complexity: 10 + 10 + 7,
+		},
+		{
+			name: "fragments and aliases",
+			query: `{ a: location(cep: "01001000") { ...weather } b: location(cep: "01310100") { ...weather } }
+				fragment weather on Location { current { ... on Current { source } } alerts { event } }`,
+			depth:      3,
+			complexity: 2 * (10 + 10 + 1 + 10 + 1),
+		},
+		{
+			name:       "introspection costs nothing",
+			query:      `{ __schema { types { name fields { name } } } __typename }`,
+			depth:      4,
+			complexity: 0,
+		},
+		{
+			name:       "named operation",
+			query:      `query Small { location(cep: "01001000") { cep } } query Large { location(cep: "01001000") { current { source } } }`,
+			operation:  "Small",
+			depth:      2,
+			complexity: 11,
+		},
+		{
+			name:       "largest operation without a name",
+			query:      `query Small { location(cep: "01001000") { cep } } query Large { location(cep: "01001000") { current { source } } }`,
+			depth:      3,
+			complexity: 21,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			assert.NoError(t, err)
+
+			// act
+			measured := measure(doc, tt.operation, tt.variables)
+
+			// assert
+			assert.Equal(t, tt.depth, measured.depth)
+			assert.Equal(t, tt.complexity, measured.complexity)
+		})
+	}
+}
+
+func TestMeasure_RepeatedFragmentsSaturate(t *testing.T) {
+	// arrange
+	query := `{ ...f0 }
+		fragment f0 on Query { ...f1 ...f1 }`
+	for i := 1; i < 70; i++ {
+		query += "\nfragment f" + strconv.Itoa(i) + " on Query { ...f" + strconv.Itoa(i+1) + " ...f" + strconv.Itoa(i+1) + " }"
+	}
+	query += "\nfragment f70 on Query { location(cep: \"01001000\") { cep } }"
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	assert.NoError(t, err)
+
+	// act
+	measured := measure(doc, "", nil)
+
+	// assert
+	assert.Equal(t, 2, measured.depth)
+	assert.Equal(t, maxMeasure, measured.complexity)
+}
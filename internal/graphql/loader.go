@@ -0,0 +1,140 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+)
+
+// loader batches and memoizes the upstream lookups of one request. Load
+// registers a key and returns a thunk; the first thunk called fetches every
+// key registered so far concurrently. The executor resolves sibling fields
+// before calling their thunks, so the same field of several locations is
+// fetched in one round and a key requested twice is fetched once
+type loader[K comparable, V any] struct {
+	fetch func(K) (V, error)
+
+	mu      sync.Mutex
+	results map[K]*loaded[V]
+	pending []K
+}
+
+// loaded is the outcome of fetching one key, ready once done is closed
+type loaded[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(K) (V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		results: make(map[K]*loaded[V]),
+	}
+}
+
+// Load registers key and returns a thunk waiting for its value
+func (l *loader[K, V]) Load(key K) func() (V, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		result = &loaded[V]{done: make(chan struct{})}
+		l.results[key] = result
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.dispatch()
+		<-result.done
+		return result.value, result.err
+	}
+}
+
+// dispatch starts fetching the keys registered since the last dispatch
+func (l *loader[K, V]) dispatch() {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	results := make([]*loaded[V], len(pending))
+	for i, key := range pending {
+		results[i] = l.results[key]
+	}
+	l.mu.Unlock()
+
+	for i, key := range pending {
+		go func(key K, result *loaded[V]) {
+			result.value, result.err = l.fetch(key)
+			close(result.done)
+		}(key, results[i])
+	}
+}
+
+// cityKey identifies a city; cities of the same name in different states
+// are looked up apart
+type cityKey struct {
+	uf, city string
+}
+
+// forecastKey identifies a forecast request
+type forecastKey struct {
+	lat, lon float64
+	days     int
+}
+
+// loaders are the lookups of one HTTP request, shared by the operations of a
+// batch
+type loaders struct {
+	service      *service.WeatherService
+	addresses    *loader[cep.CEP, *model.ViacepResponse]
+	observations *loader[cityKey, *model.Observation]
+	forecasts    *loader[forecastKey, *model.Forecast]
+}
+
+func newLoaders(ctx context.Context, svc *service.WeatherService) *loaders {
+	return &loaders{
+		service: svc,
+		addresses: newLoader(func(code cep.CEP) (*model.ViacepResponse, error) {
+			_, cepModel, err := svc.ResolveCep(ctx, code.String())
+			return cepModel, err
+		}),
+		observations: newLoader(func(key cityKey) (*model.Observation, error) {
+			return svc.Observe(ctx, key.uf, key.city)
+		}),
+		forecasts: newLoader(func(key forecastKey) (*model.Forecast, error) {
+			return svc.Forecast(ctx, key.lat, key.lon, key.days)
+		}),
+	}
+}
+
+// forecast registers the forecast for the city of loc. The city is placed
+// with the municipality dataset when possible, so the forecast joins the
+// current batch; otherwise the coordinates come from its current weather
+func (l *loaders) forecast(loc *location, days int) func() (*model.Forecast, error) {
+	if lat, lon, ok := l.service.CityCoordinates(loc.address.Uf, loc.address.Localidade); ok {
+		return l.forecasts.Load(forecastKey{lat, lon, days})
+	}
+
+	observation := l.observations.Load(cityKey{loc.address.Uf, loc.address.Localidade})
+	return func() (*model.Forecast, error) {
+		current, err := observation()
+		if err != nil {
+			return nil, err
+		}
+		return l.forecasts.Load(forecastKey{current.Location.Lat, current.Location.Lon, days})()
+	}
+}
+
+// loadersKey is the context key of the request loaders
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
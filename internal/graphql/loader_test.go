@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader_BatchesPendingKeys(t *testing.T) {
+	// arrange
+	var (
+		mu                         sync.Mutex
+		calls, running, concurrent int
+	)
+	l := newLoader(func(key string) (string, error) {
+		mu.Lock()
+		calls++
+		running++
+		concurrent = max(concurrent, running)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return "value of " + key, nil
+	})
+
+	// act
+	first := l.Load("a")
+	second := l.Load("b")
+	repeated := l.Load("a")
+
+	a, errA := first()
+	b, errB := second()
+	again, errAgain := repeated()
+
+	// assert
+	assert.NoError(t, errA)
+	assert.NoError(t, errB)
+	assert.NoError(t, errAgain)
+	assert.Equal(t, "value of a", a)
+	assert.Equal(t, "value of b", b)
+	assert.Equal(t, "value of a", again)
+	assert.Equal(t, 2, calls, "a is fetched once")
+	assert.Equal(t, 2, concurrent, "a and b are fetched together")
+}
+
+func TestLoader_MemoizesErrors(t *testing.T) {
+	// arrange
+	var calls atomic.Int32
+	failure := errors.New("upstream down")
+	l := newLoader(func(key int) (string, error) {
+		calls.Add(1)
+		return "", failure
+	})
+
+	// act
+	_, first := l.Load(1)()
+	_, second := l.Load(1)()
+
+	// assert
+	assert.ErrorIs(t, first, failure)
+	assert.ErrorIs(t, second, failure)
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,241 @@
+package graphql
+
+import (
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/graphql-go/graphql"
+)
+
+// location is the source of the Location type: a CEP and its address
+type location struct {
+	cep     cep.CEP
+	address *model.ViacepResponse
+}
+
+// newSchema builds the schema. Fields that need an upstream lookup resolve
+// to thunks on the request loaders, so they are fetched in batches after
+// their siblings are registered
+func newSchema() (graphql.Schema, error) {
+	temperature := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Temperature",
+		Description: "A temperature in Celsius, Fahrenheit and Kelvin",
+		Fields: graphql.Fields{
+			"celsius":    &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"fahrenheit": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"kelvin":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	condition := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Condition",
+		Description: "Sky condition; code is the WeatherAPI condition code or the WMO weather code, depending on the source",
+		Fields: graphql.Fields{
+			"text":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"code":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"isDay": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+
+	wind := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Wind",
+		Fields: graphql.Fields{
+			"speedKph":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"directionDeg": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"gustKph":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	address := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Address",
+		Description: "Normalized address of a CEP",
+		Fields: graphql.Fields{
+			"cep":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"street":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"complement":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"neighborhood": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"city":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"uf":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"state":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"region":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"ibge":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"ddd":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	current := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Current",
+		Description: "Current weather at the city of the CEP",
+		Fields: graphql.Fields{
+			"source":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"observedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"temperature": &graphql.Field{
+				Type: graphql.NewNonNull(temperature),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return conversor.ConvertCelsius(p.Source.(*model.Observation).TemperatureC), nil
+				},
+			},
+			"humidityPct":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"wind":            &graphql.Field{Type: graphql.NewNonNull(wind)},
+			"pressureMb":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"precipitationMm": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"condition":       &graphql.Field{Type: graphql.NewNonNull(condition)},
+		},
+	})
+
+	forecastDay := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ForecastDay",
+		Description: "Forecast for one local date",
+		Fields: graphql.Fields{
+			"date": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"min": &graphql.Field{
+				Type: graphql.NewNonNull(temperature),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return conversor.ConvertCelsius(p.Source.(model.ForecastDay).MinC), nil
+				},
+			},
+			"max": &graphql.Field{
+				Type: graphql.NewNonNull(temperature),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return conversor.ConvertCelsius(p.Source.(model.ForecastDay).MaxC), nil
+				},
+			},
+			"precipitationMm": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"condition":       &graphql.Field{Type: graphql.NewNonNull(condition)},
+		},
+	})
+
+	forecast := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Forecast",
+		Fields: graphql.Fields{
+			"source": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"days":   &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(forecastDay)))},
+		},
+	})
+
+	alert := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Alert",
+		Description: "Weather warning in effect at the city of the CEP",
+		Fields: graphql.Fields{
+			"event":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"headline":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"severity":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"urgency":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"areas":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"instruction": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"effective":   &graphql.Field{Type: graphql.DateTime},
+			"expires":     &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	locationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Location",
+		Description: "A CEP with its address and the weather at its city",
+		Fields: graphql.Fields{
+			"cep": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "CEP formatted as 00000-000",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*location).cep.Formatted(), nil
+				},
+			},
+			"address": &graphql.Field{
+				Type: graphql.NewNonNull(address),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return service.AddressOf(p.Source.(*location).address), nil
+				},
+			},
+			"current": &graphql.Field{
+				Type: current,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					loc := p.Source.(*location)
+					return thunk(loadersFrom(p.Context).observations.Load(cityKey{loc.address.Uf, loc.address.Localidade})), nil
+				},
+			},
+			"forecast": &graphql.Field{
+				Type:        forecast,
+				Description: "Daily forecast, today first",
+				Args: graphql.FieldConfigArgument{
+					"days": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: service.DefaultForecastDays,
+						Description:  "Number of days, from 1 to 7",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					days, ok := p.Args["days"].(int)
+					if !ok {
+						days = service.DefaultForecastDays
+					}
+					if days < service.MinForecastDays || days > service.MaxForecastDays {
+						return nil, hErrors.ForecastDaysInvalid
+					}
+					return thunk(loadersFrom(p.Context).forecast(p.Source.(*location), days)), nil
+				},
+			},
+			"alerts": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(alert)),
+				Description: "Alerts in effect; null when the weather provider does not report alerts",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					// Alerts come with the forecast, so a forecast with the
+					// default length in the same query costs no extra call
+					load := loadersFrom(p.Context).forecast(p.Source.(*location), service.DefaultForecastDays)
+					return func() (any, error) {
+						forecast, err := load()
+						if err != nil || forecast.Alerts == nil {
+							return nil, err
+						}
+						return forecast.Alerts, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"location": &graphql.Field{
+				Type: locationType,
+				Args: graphql.FieldConfigArgument{
+					"cep": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "Brazilian postal code, with or without the hyphen",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					code, err := cep.Parse(p.Args["cep"].(string))
+					if err != nil {
+						return nil, hErrors.CepInvalid
+					}
+
+					load := loadersFrom(p.Context).addresses.Load(code)
+					return func() (any, error) {
+						cepModel, err := load()
+						if err != nil {
+							return nil, err
+						}
+						return &location{cep: code, address: cepModel}, nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// thunk adapts a loader thunk to the signature the executor expects,
+// keeping typed nil pointers out of the result
+func thunk[V any](load func() (V, error)) func() (any, error) {
+	return func() (any, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
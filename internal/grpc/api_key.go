@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata entries carrying the API key and the bearer token, as the
+// X-API-Key and Authorization headers do for HTTP requests
+const (
+	apiKeyKey        = "x-api-key"
+	authorizationKey = "authorization"
+)
+
+// openServices are callable without an API key or token, so probes and tooling keep
+// working when keys are configured
+var openServices = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+// authorize checks the bearer token or the API key of a call to method,
+// which is the route scopes and keys are matched against, counts units
+// requests against the key's quotas and returns the rate limit metadata to
+// send back. As over HTTP, calls that send an API key and no token are
+// checked against the keys
+func authorize(ctx context.Context, keys *apikey.Registry, tokens *jwtauth.Verifier, method string, units int) (metadata.MD, error) {
+	if keys == nil && tokens == nil {
+		return nil, nil
+	}
+	for _, prefix := range openServices {
+		if strings.HasPrefix(method, prefix) {
+			return nil, nil
+		}
+	}
+
+	secret := firstValue(ctx, apiKeyKey)
+	token := bearerToken(firstValue(ctx, authorizationKey))
+	if tokens != nil && (token != "" || keys == nil || secret == "") {
+		principal, err := tokens.Verify(ctx, token)
+		if err == nil {
+			err = tokens.Allow(principal, method)
+		}
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+
+		decision, err := tokens.Charge(principal, units)
+		return quotaHeader(decision), toStatus(ctx, err)
+	}
+
+	key, err := keys.Authenticate(secret)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	decision, err := keys.AllowN(key, method, units)
+	return quotaHeader(decision), toStatus(ctx, err)
+}
+
+// quotaHeader is the rate limit metadata of decision, nil without quotas
+func quotaHeader(decision apikey.Decision) metadata.MD {
+	var header metadata.MD
+	if decision.Limit > 0 {
+		header = metadata.Pairs(
+			"x-ratelimit-limit", strconv.Itoa(decision.Limit),
+			"x-ratelimit-remaining", strconv.Itoa(decision.Remaining),
+			"x-ratelimit-reset", strconv.FormatInt(decision.Reset.Unix(), 10),
+		)
+	}
+	return header
+}
+
+// firstValue returns the first value of the incoming metadata entry key
+func firstValue(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// bearerToken returns the token of a "Bearer <token>" authorization value
+func bearerToken(authorization string) string {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func unaryAPIKeyInterceptor(keys *apikey.Registry, tokens *jwtauth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		header, err := authorize(ctx, keys, tokens, info.FullMethod, 1)
+		if header != nil {
+			_ = grpc.SetHeader(ctx, header)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAPIKeyInterceptor checks the credentials before the handler runs,
+// but counts the call against the quotas once its request arrives, so a
+// batch is charged one request per CEP
+func streamAPIKeyInterceptor(keys *apikey.Registry, tokens *jwtauth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, err := authorize(stream.Context(), keys, tokens, info.FullMethod, 0); err != nil {
+			return err
+		}
+		return handler(srv, &meteredStream{ServerStream: stream, keys: keys, tokens: tokens, method: info.FullMethod})
+	}
+}
+
+// meteredStream charges the quotas when the first request of a stream is
+// received, by the number of lookups it asks for
+type meteredStream struct {
+	grpc.ServerStream
+	keys    *apikey.Registry
+	tokens  *jwtauth.Verifier
+	method  string
+	charged bool
+}
+
+func (s *meteredStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil || s.charged {
+		return err
+	}
+	s.charged = true
+
+	header, err := authorize(s.Context(), s.keys, s.tokens, s.method, requestUnits(m))
+	if header != nil {
+		_ = s.SetHeader(header)
+	}
+	return err
+}
+
+// requestUnits is how many requests a call counts as: one per CEP of a
+// batch, one otherwise
+func requestUnits(m any) int {
+	if batch, ok := m.(interface{ GetCeps() []string }); ok {
+		return max(len(batch.GetCeps()), 1)
+	}
+	return 1
+}
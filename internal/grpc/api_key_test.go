@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/grpc/weatherpb"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	forecastOnlyKey = "forecast-key-0123456789"
+	fullKey         = "full-key-0123456789abc"
+)
+
+type APIKeyInterceptorTestSuite struct {
+	suite.Suite
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client weatherpb.WeatherServiceClient
+}
+
+func (s *APIKeyInterceptorTestSuite) SetupTest() {
+	cfg := &config.Config{}
+	cepClient := client.NewCepClientStub(cfg)
+	cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	weatherClient := client.NewWeatherClientStub(cfg)
+	weatherClient.On("GetWeather", mock.Anything, mock.Anything).Return(model.GetObservationMock("São Paulo"), nil)
+
+	keys := apikey.NewRegistry([]apikey.Key{
+		{Name: "forecast", Secret: forecastOnlyKey, Routes: []string{"/weather.v1.WeatherService/GetForecast"}},
+		{Name: "full", Secret: fullKey, DailyQuota: 1},
+	})
+
+	listener := bufconn.Listen(1 << 20)
+	s.server = NewServer(service.NewWeatherService(cfg, cepClient, weatherClient, nil), keys, nil)
+	go func() { _ = s.server.Serve(listener) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+
+	s.conn = conn
+	s.client = weatherpb.NewWeatherServiceClient(conn)
+}
+
+func (s *APIKeyInterceptorTestSuite) TearDownTest() {
+	s.conn.Close()
+	s.server.Stop()
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyKey, key)
+}
+
+func (s *APIKeyInterceptorTestSuite) TestUnary() {
+	request := &weatherpb.GetTemperatureByCepRequest{Cep: "01001000"}
+
+	// act
+	_, missingErr := s.client.GetTemperatureByCep(context.Background(), request)
+	_, invalidErr := s.client.GetTemperatureByCep(withAPIKey("unknown-key-0123456789"), request)
+	_, forbiddenErr := s.client.GetTemperatureByCep(withAPIKey(forecastOnlyKey), request)
+
+	var header metadata.MD
+	_, err := s.client.GetTemperatureByCep(withAPIKey(fullKey), request, grpc.Header(&header))
+	_, exceededErr := s.client.GetTemperatureByCep(withAPIKey(fullKey), request)
+
+	// assert
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(missingErr))
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(invalidErr))
+	assert.Equal(s.T(), codes.PermissionDenied, status.Code(forbiddenErr))
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{"1"}, header.Get("x-ratelimit-limit"))
+	assert.Equal(s.T(), []string{"0"}, header.Get("x-ratelimit-remaining"))
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(exceededErr))
+	assert.Equal(s.T(), "api key quota exceeded", status.Convert(exceededErr).Message())
+}
+
+func (s *APIKeyInterceptorTestSuite) TestStream() {
+	// arrange
+	request := &weatherpb.BatchTemperaturesRequest{Ceps: []string{"01001000"}}
+
+	// act
+	denied, err := s.client.BatchTemperatures(context.Background(), request)
+	s.Require().NoError(err)
+	_, deniedErr := denied.Recv()
+
+	allowed, err := s.client.BatchTemperatures(withAPIKey(fullKey), request)
+	s.Require().NoError(err)
+	_, allowedErr := allowed.Recv()
+	_, endErr := allowed.Recv()
+
+	// assert
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(deniedErr))
+	assert.NoError(s.T(), allowedErr)
+	assert.Equal(s.T(), io.EOF, endErr)
+}
+
+func (s *APIKeyInterceptorTestSuite) TestStream_ChargesEachCep() {
+	// arrange
+	request := &weatherpb.BatchTemperaturesRequest{Ceps: []string{"01001000", "01310100"}}
+
+	// act
+	exceeded, err := s.client.BatchTemperatures(withAPIKey(fullKey), request)
+	s.Require().NoError(err)
+	_, exceededErr := exceeded.Recv()
+
+	allowed, err := s.client.BatchTemperatures(withAPIKey(fullKey), &weatherpb.BatchTemperaturesRequest{Ceps: request.Ceps[:1]})
+	s.Require().NoError(err)
+	_, allowedErr := allowed.Recv()
+	header, headerErr := allowed.Header()
+
+	// assert
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(exceededErr), "two CEPs do not fit in a daily quota of one")
+	assert.NoError(s.T(), allowedErr)
+	s.Require().NoError(headerErr)
+	assert.Equal(s.T(), []string{"0"}, header.Get("x-ratelimit-remaining"))
+}
+
+func (s *APIKeyInterceptorTestSuite) TestHealthIsOpen() {
+	// act
+	response, err := healthpb.NewHealthClient(s.conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	// assert
+	s.Require().NoError(err)
+	assert.Equal(s.T(), healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+}
+
+func TestAPIKeyInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyInterceptorTestSuite))
+}
+
+func TestBearerToken(t *testing.T) {
+	// arrange
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	tokens, err := jwtauth.Load(&config.Config{
+		JWTKeyFile:     keyFile,
+		JWTIssuer:      "https://auth.example.com/",
+		JWTAudience:    []string{"lab-cloudrun"},
+		JWTAlgorithms:  []string{"ES256"},
+		JWTScopeRoutes: map[string][]string{"forecast": {"/weather.v1.WeatherService/GetForecast"}},
+	})
+	require.NoError(t, err)
+
+	sign := func(scope string) context.Context {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"iss":   "https://auth.example.com/",
+			"aud":   "lab-cloudrun",
+			"sub":   "web-app",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": scope,
+		}).SignedString(key)
+		require.NoError(t, err)
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationKey, "Bearer "+token))
+	}
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "full", Secret: fullKey}})
+	method := "/weather.v1.WeatherService/GetForecast"
+
+	// act
+	_, allowedErr := authorize(sign("forecast"), keys, tokens, method, 1)
+	_, forbiddenErr := authorize(sign("other"), keys, tokens, method, 1)
+	_, missingErr := authorize(context.Background(), keys, tokens, method, 1)
+	_, apiKeyErr := authorize(metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyKey, fullKey)), keys, tokens, method, 1)
+
+	// assert
+	assert.NoError(t, allowedErr)
+	assert.Equal(t, codes.PermissionDenied, status.Code(forbiddenErr))
+	assert.Equal(t, codes.Unauthenticated, status.Code(missingErr))
+	assert.NoError(t, apiKeyErr)
+}
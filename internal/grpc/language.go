@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// languageKey is the metadata entry carrying the Accept-Language value
+const languageKey = "accept-language"
+
+// withLanguage negotiates the response language from the incoming metadata,
+// as LanguageMiddleware does for HTTP requests
+func withLanguage(ctx context.Context) context.Context {
+	var accept string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(languageKey); len(values) > 0 {
+			accept = values[0]
+		}
+	}
+	return i18n.WithLang(ctx, i18n.Negotiate(accept))
+}
+
+func unaryLanguageInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withLanguage(ctx), req)
+}
+
+func streamLanguageInterceptor(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &languageStream{ServerStream: stream, ctx: withLanguage(stream.Context())})
+}
+
+// languageStream replaces the context of a server stream
+type languageStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *languageStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,215 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/grpc/weatherpb"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BatchTemperatures limits
+const (
+	maxBatchSize     = 50
+	batchConcurrency = 4
+)
+
+// WeatherServer implements weatherpb.WeatherServiceServer on top of the
+// service layer the REST handlers use
+type WeatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	service *service.WeatherService
+}
+
+func NewWeatherServer(svc *service.WeatherService) *WeatherServer {
+	return &WeatherServer{service: svc}
+}
+
+// NewServer builds a gRPC server with WeatherService, the standard health
+// service and server reflection registered. With keys, WeatherService calls
+// require an API key in the x-api-key metadata; with tokens, a bearer token
+// in the authorization metadata is accepted too
+func NewServer(svc *service.WeatherService, keys *apikey.Registry, tokens *jwtauth.Verifier) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLanguageInterceptor, unaryAPIKeyInterceptor(keys, tokens)),
+		grpc.ChainStreamInterceptor(streamLanguageInterceptor, streamAPIKeyInterceptor(keys, tokens)),
+	)
+
+	weatherpb.RegisterWeatherServiceServer(server, NewWeatherServer(svc))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(weatherpb.WeatherService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+func (s *WeatherServer) GetTemperatureByCep(ctx context.Context, req *weatherpb.GetTemperatureByCepRequest) (*weatherpb.GetTemperatureByCepResponse, error) {
+	result, err := s.service.TemperatureByCep(ctx, req.GetCep())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return toTemperatureResponse(result), nil
+}
+
+func (s *WeatherServer) GetForecast(ctx context.Context, req *weatherpb.GetForecastRequest) (*weatherpb.GetForecastResponse, error) {
+	days := int(req.GetDays())
+	if days == 0 {
+		days = service.DefaultForecastDays
+	}
+
+	switch location := req.GetLocation().(type) {
+	case *weatherpb.GetForecastRequest_Cep:
+		result, err := s.service.ForecastByCep(ctx, location.Cep, days)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+
+		response := toForecastResponse(result.Forecast)
+		response.Location.City = result.Address.Localidade
+		response.Location.Uf = result.Address.Uf
+		return response, nil
+
+	case *weatherpb.GetForecastRequest_Coordinates:
+		forecast, err := s.service.Forecast(ctx, location.Coordinates.GetLat(), location.Coordinates.GetLon(), days)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		return toForecastResponse(forecast), nil
+	}
+
+	return nil, status.Error(codes.InvalidArgument, "location must be a cep or coordinates")
+}
+
+func (s *WeatherServer) BatchTemperatures(req *weatherpb.BatchTemperaturesRequest, stream grpc.ServerStreamingServer[weatherpb.BatchTemperaturesResponse]) error {
+	ceps := req.GetCeps()
+	if len(ceps) == 0 || len(ceps) > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "ceps must have 1 to %d entries", maxBatchSize)
+	}
+
+	ctx := stream.Context()
+	jobs := make(chan string)
+	results := make(chan *weatherpb.BatchTemperaturesResponse)
+
+	var wg sync.WaitGroup
+	for range min(batchConcurrency, len(ceps)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for raw := range jobs {
+				select {
+				case results <- s.batchItem(ctx, raw):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, raw := range ceps {
+			select {
+			case jobs <- raw:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+
+	return toStatus(ctx, ctx.Err())
+}
+
+// batchItem looks up a single CEP of a batch, carrying a failure as the
+// status the unary call would have returned
+func (s *WeatherServer) batchItem(ctx context.Context, raw string) *weatherpb.BatchTemperaturesResponse {
+	result, err := s.service.TemperatureByCep(ctx, raw)
+	if err != nil {
+		st := status.Convert(toStatus(ctx, err))
+		return &weatherpb.BatchTemperaturesResponse{
+			Cep: raw,
+			Result: &weatherpb.BatchTemperaturesResponse_Error{Error: &weatherpb.Error{
+				Code:    int32(st.Code()),
+				Message: st.Message(),
+			}},
+		}
+	}
+
+	return &weatherpb.BatchTemperaturesResponse{
+		Cep:    raw,
+		Result: &weatherpb.BatchTemperaturesResponse_Temperature{Temperature: toTemperatureResponse(result)},
+	}
+}
+
+func toTemperature(temp model.TemperatureResponse) *weatherpb.Temperature {
+	return &weatherpb.Temperature{
+		Celsius:    temp.Celsius,
+		Fahrenheit: temp.Fahrenheit,
+		Kelvin:     temp.Kelvin,
+	}
+}
+
+func toTemperatureResponse(result *service.CepTemperature) *weatherpb.GetTemperatureByCepResponse {
+	observation := result.Observation
+
+	return &weatherpb.GetTemperatureByCepResponse{
+		Cep: result.Cep.Formatted(),
+		Location: &weatherpb.Location{
+			City:        result.Address.Localidade,
+			Uf:          result.Address.Uf,
+			Coordinates: &weatherpb.Coordinates{Lat: observation.Location.Lat, Lon: observation.Location.Lon},
+			Timezone:    observation.Location.Timezone,
+		},
+		Temperature: toTemperature(result.Temperature),
+		Source:      observation.Source,
+		ObservedAt:  timestamppb.New(observation.ObservedAt),
+	}
+}
+
+func toForecastResponse(forecast *model.Forecast) *weatherpb.GetForecastResponse {
+	response := &weatherpb.GetForecastResponse{
+		Location: &weatherpb.Location{
+			City:        forecast.Location.Name,
+			Coordinates: &weatherpb.Coordinates{Lat: forecast.Location.Lat, Lon: forecast.Location.Lon},
+			Timezone:    forecast.Location.Timezone,
+		},
+		Source: forecast.Source,
+	}
+
+	for _, day := range forecast.Days {
+		response.Days = append(response.Days, &weatherpb.ForecastDay{
+			Date:            day.Date,
+			Min:             toTemperature(conversor.ConvertCelsius(day.MinC)),
+			Max:             toTemperature(conversor.ConvertCelsius(day.MaxC)),
+			PrecipitationMm: day.PrecipitationMm,
+			Condition:       day.Condition.Text,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,304 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/grpc/weatherpb"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type WeatherServerTestSuite struct {
+	suite.Suite
+	cepClient     *client.CepClientStub
+	weatherClient *client.WeatherClientStub
+	server        *grpc.Server
+	conn          *grpc.ClientConn
+	client        weatherpb.WeatherServiceClient
+}
+
+func (s *WeatherServerTestSuite) SetupTest() {
+	cfg := &config.Config{}
+	s.cepClient = client.NewCepClientStub(cfg)
+	s.weatherClient = client.NewWeatherClientStub(cfg)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(service.NewWeatherService(cfg, s.cepClient, s.weatherClient, nil), nil, nil)
+	go func() { _ = server.Serve(listener) }()
+	s.server = server
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+
+	s.conn = conn
+	s.client = weatherpb.NewWeatherServiceClient(conn)
+}
+
+func (s *WeatherServerTestSuite) TearDownTest() {
+	s.conn.Close()
+	s.server.Stop()
+}
+
+func (s *WeatherServerTestSuite) TestGetTemperatureByCep_Success() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	response, err := s.client.GetTemperatureByCep(context.Background(), &weatherpb.GetTemperatureByCepRequest{Cep: "01001000"})
+
+	// assert
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "01001-000", response.GetCep())
+	assert.Equal(s.T(), "São Paulo", response.GetLocation().GetCity())
+	assert.Equal(s.T(), "SP", response.GetLocation().GetUf())
+	assert.Equal(s.T(), 32.2, response.GetTemperature().GetCelsius())
+	assert.Equal(s.T(), 89.96, response.GetTemperature().GetFahrenheit())
+	assert.Equal(s.T(), 305.35, response.GetTemperature().GetKelvin())
+	assert.Equal(s.T(), "weatherapi", response.GetSource())
+	assert.Equal(s.T(), int64(1768066200), response.GetObservedAt().GetSeconds())
+}
+
+func (s *WeatherServerTestSuite) TestGetTemperatureByCep_Errors() {
+	tests := []struct {
+		name    string
+		cep     string
+		cepErr  error
+		erro    bool
+		code    codes.Code
+		message string
+	}{
+		{name: "invalid cep", cep: "123", code: codes.InvalidArgument, message: "invalid zipcode"},
+		{name: "unknown cep", cep: "99999999", erro: true, code: codes.NotFound, message: "can not find zipcode"},
+		{name: "cep api down", cep: "01001000", cepErr: cErrors.CepClientInternalError, code: codes.Unavailable, message: "upstream service unavailable"},
+		{name: "cep api bad request", cep: "01001000", cepErr: cErrors.CepClientBadRequest, code: codes.Internal, message: "internal server error"},
+		{name: "cep api not found", cep: "01001000", cepErr: cErrors.CepClientNotFound, code: codes.Internal, message: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// arrange
+			s.cepClient.ExpectedCalls = nil
+
+			viacep := model.GetViacepResponseMock("01001-000")
+			if tt.erro {
+				erro := "true"
+				viacep.Erro = &erro
+			}
+			if tt.cepErr != nil {
+				s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(nil, tt.cepErr)
+			} else {
+				s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(viacep, nil)
+			}
+
+			// act
+			_, err := s.client.GetTemperatureByCep(context.Background(), &weatherpb.GetTemperatureByCepRequest{Cep: tt.cep})
+
+			// assert
+			assert.Equal(s.T(), tt.code, status.Code(err))
+			assert.Equal(s.T(), tt.message, status.Convert(err).Message())
+		})
+	}
+}
+
+func (s *WeatherServerTestSuite) TestGetTemperatureByCep_LocalizedError() {
+	// arrange
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "pt-BR")
+
+	// act
+	_, err := s.client.GetTemperatureByCep(ctx, &weatherpb.GetTemperatureByCepRequest{Cep: "123"})
+
+	// assert
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	assert.Equal(s.T(), "CEP inválido", status.Convert(err).Message())
+}
+
+func (s *WeatherServerTestSuite) TestGetForecast_ByCoordinates() {
+	// arrange
+	s.weatherClient.On("GetForecast", mock.Anything, -23.55, -46.63, 3).Return(model.GetForecastMock("São Paulo", 3), nil)
+
+	// act
+	response, err := s.client.GetForecast(context.Background(), &weatherpb.GetForecastRequest{
+		Location: &weatherpb.GetForecastRequest_Coordinates{Coordinates: &weatherpb.Coordinates{Lat: -23.55, Lon: -46.63}},
+	})
+
+	// assert
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "openmeteo", response.GetSource())
+	assert.Len(s.T(), response.GetDays(), 3)
+	assert.Equal(s.T(), "2026-01-13", response.GetDays()[2].GetDate())
+	assert.Equal(s.T(), 19.4, response.GetDays()[2].GetMin().GetCelsius())
+	assert.Equal(s.T(), 31.8, response.GetDays()[2].GetMax().GetCelsius())
+	assert.Equal(s.T(), 304.95, response.GetDays()[2].GetMax().GetKelvin())
+}
+
+func (s *WeatherServerTestSuite) TestGetForecast_ByCep() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, 5).Return(model.GetForecastMock("", 5), nil)
+
+	// act
+	response, err := s.client.GetForecast(context.Background(), &weatherpb.GetForecastRequest{
+		Location: &weatherpb.GetForecastRequest_Cep{Cep: "01001-000"},
+		Days:     5,
+	})
+
+	// assert
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "São Paulo", response.GetLocation().GetCity())
+	assert.Equal(s.T(), "SP", response.GetLocation().GetUf())
+	assert.Len(s.T(), response.GetDays(), 5)
+
+	// São Paulo está na tabela de municípios, então a previsão não depende do GetWeather
+	s.weatherClient.AssertNotCalled(s.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (s *WeatherServerTestSuite) TestGetForecast_InvalidRequests() {
+	tests := []struct {
+		name    string
+		request *weatherpb.GetForecastRequest
+	}{
+		{"no location", &weatherpb.GetForecastRequest{}},
+		{"too many days", &weatherpb.GetForecastRequest{
+			Location: &weatherpb.GetForecastRequest_Coordinates{Coordinates: &weatherpb.Coordinates{Lat: -23.55, Lon: -46.63}},
+			Days:     8,
+		}},
+		{"invalid coordinates", &weatherpb.GetForecastRequest{
+			Location: &weatherpb.GetForecastRequest_Coordinates{Coordinates: &weatherpb.Coordinates{Lat: 95, Lon: -46.63}},
+		}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			_, err := s.client.GetForecast(context.Background(), tt.request)
+
+			// assert
+			assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func (s *WeatherServerTestSuite) TestGetForecast_Unsupported() {
+	// arrange
+	s.weatherClient.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, cErrors.ForecastUnsupported)
+
+	// act
+	_, err := s.client.GetForecast(context.Background(), &weatherpb.GetForecastRequest{
+		Location: &weatherpb.GetForecastRequest_Coordinates{Coordinates: &weatherpb.Coordinates{Lat: -23.55, Lon: -46.63}},
+	})
+
+	// assert
+	assert.Equal(s.T(), codes.Unimplemented, status.Code(err))
+}
+
+func (s *WeatherServerTestSuite) TestBatchTemperatures_StreamsEveryCep() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+	ceps := []string{"01001000", "01310-100", "invalid", "04538133", "20040002"}
+
+	// act
+	stream, err := s.client.BatchTemperatures(context.Background(), &weatherpb.BatchTemperaturesRequest{Ceps: ceps})
+	s.Require().NoError(err)
+
+	var received []*weatherpb.BatchTemperaturesResponse
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		received = append(received, response)
+	}
+
+	// assert
+	s.Require().Len(received, len(ceps))
+	sort.Slice(received, func(i, j int) bool { return received[i].GetCep() < received[j].GetCep() })
+
+	assert.Equal(s.T(), "invalid", received[4].GetCep())
+	assert.Equal(s.T(), int32(codes.InvalidArgument), received[4].GetError().GetCode())
+	assert.Equal(s.T(), "invalid zipcode", received[4].GetError().GetMessage())
+
+	// 20040002 é do RJ, mas o stub do ViaCEP devolve SP: só gera aviso com CEP_UF_MISMATCH=warn
+	for _, response := range received[:4] {
+		assert.Equal(s.T(), 32.2, response.GetTemperature().GetTemperature().GetCelsius(), response.GetCep())
+	}
+}
+
+func (s *WeatherServerTestSuite) TestBatchTemperatures_Limits() {
+	for _, size := range []int{0, maxBatchSize + 1} {
+		// arrange
+		ceps := make([]string, size)
+
+		// act
+		stream, err := s.client.BatchTemperatures(context.Background(), &weatherpb.BatchTemperaturesRequest{Ceps: ceps})
+		s.Require().NoError(err)
+		_, err = stream.Recv()
+
+		// assert
+		assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func (s *WeatherServerTestSuite) TestHealth() {
+	// arrange
+	health := healthpb.NewHealthClient(s.conn)
+
+	for _, service := range []string{"", "weather.v1.WeatherService"} {
+		// act
+		response, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+
+		// assert
+		s.Require().NoError(err)
+		assert.Equal(s.T(), healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+	}
+}
+
+func (s *WeatherServerTestSuite) TestReflection_ListsServices() {
+	// arrange
+	stream, err := reflectionpb.NewServerReflectionClient(s.conn).ServerReflectionInfo(context.Background())
+	s.Require().NoError(err)
+
+	// act
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	s.Require().NoError(err)
+	response, err := stream.Recv()
+	s.Require().NoError(err)
+
+	// assert
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(s.T(), services, "weather.v1.WeatherService")
+	assert.Contains(s.T(), services, "grpc.health.v1.Health")
+}
+
+func TestWeatherServerTestSuite(t *testing.T) {
+	suite.Run(t, new(WeatherServerTestSuite))
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusMapping is the gRPC code and message key of a domain error
+type statusMapping struct {
+	target error
+	code   codes.Code
+	key    string
+}
+
+// statusMappings follow the HTTP statuses chosen by ErrorHandlerMiddleware
+// for the caller's errors. They part ways on upstream failures, which HTTP
+// answers with 500: here they become Unavailable, so clients know they may
+// retry, and a city the weather provider does not know becomes NotFound
+var statusMappings = []statusMapping{
+	{hErrors.CepParamNotExists, codes.NotFound, "error.cep_not_found"},
+	{hErrors.CepCantFind, codes.NotFound, "error.cep_not_found"},
+	{hErrors.CepInvalid, codes.InvalidArgument, "error.cep_invalid"},
+	{hErrors.CepUFMismatch, codes.InvalidArgument, "error.cep_uf_mismatch"},
+	{hErrors.CoordinatesInvalid, codes.InvalidArgument, "error.coordinates_invalid"},
+	{hErrors.CoordinatesOutOfBounds, codes.InvalidArgument, "error.coordinates_out_of_bounds"},
+	{hErrors.ForecastDaysInvalid, codes.InvalidArgument, "error.forecast_days_invalid"},
+	{cErrors.ForecastUnsupported, codes.Unimplemented, "error.forecast_unsupported"},
+	{cErrors.WeatherQuotaExhausted, codes.Unavailable, "error.weather_quota_exhausted"},
+	{cErrors.WeatherQuotaSoftLimited, codes.Unavailable, "error.weather_quota_soft_limited"},
+	{cErrors.WeatherProvidersUnavailable, codes.Unavailable, "error.upstream_unavailable"},
+	{cErrors.WeatherClientNotFound, codes.NotFound, "error.city_not_found"},
+	{cErrors.WeatherClientInternalError, codes.Unavailable, "error.upstream_unavailable"},
+	{cErrors.WeatherClientUnexpectedError, codes.Unavailable, "error.upstream_unavailable"},
+	{cErrors.CepClientBadRequest, codes.Internal, "error.internal"},
+	{cErrors.CepClientNotFound, codes.Internal, "error.internal"},
+	{cErrors.CepClientInternalError, codes.Unavailable, "error.upstream_unavailable"},
+	{cErrors.CepClientUnexpectedError, codes.Unavailable, "error.upstream_unavailable"},
+	{apikey.ErrMissing, codes.Unauthenticated, "error.api_key_missing"},
+	{apikey.ErrInvalid, codes.Unauthenticated, "error.api_key_invalid"},
+	{apikey.ErrForbidden, codes.PermissionDenied, "error.api_key_forbidden"},
+	{apikey.ErrQuotaExceeded, codes.ResourceExhausted, "error.api_key_quota_exceeded"},
+	{jwtauth.ErrMissing, codes.Unauthenticated, "error.bearer_token_missing"},
+	{jwtauth.ErrExpired, codes.Unauthenticated, "error.bearer_token_expired"},
+	{jwtauth.ErrInvalid, codes.Unauthenticated, "error.bearer_token_invalid"},
+	{jwtauth.ErrForbidden, codes.PermissionDenied, "error.bearer_token_forbidden"},
+	{jwtauth.ErrQuotaExceeded, codes.ResourceExhausted, "error.bearer_token_quota_exceeded"},
+}
+
+// toStatus converts a service error into a gRPC status error with a message
+// in the language negotiated for ctx
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	lang := i18n.FromContext(ctx)
+	for _, mapping := range statusMappings {
+		if errors.Is(err, mapping.target) {
+			return status.Error(mapping.code, lang.Text(mapping.key))
+		}
+	}
+
+	return status.Error(codes.Internal, lang.Text("error.internal"))
+}
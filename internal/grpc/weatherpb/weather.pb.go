@@ -0,0 +1,889 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.0
+// source: weather/v1/weather.proto
+
+package weatherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Temperature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Celsius       float64                `protobuf:"fixed64,1,opt,name=celsius,proto3" json:"celsius,omitempty"`
+	Fahrenheit    float64                `protobuf:"fixed64,2,opt,name=fahrenheit,proto3" json:"fahrenheit,omitempty"`
+	Kelvin        float64                `protobuf:"fixed64,3,opt,name=kelvin,proto3" json:"kelvin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Temperature) Reset() {
+	*x = Temperature{}
+	mi := &file_weather_v1_weather_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Temperature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Temperature) ProtoMessage() {}
+
+func (x *Temperature) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Temperature.ProtoReflect.Descriptor instead.
+func (*Temperature) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Temperature) GetCelsius() float64 {
+	if x != nil {
+		return x.Celsius
+	}
+	return 0
+}
+
+func (x *Temperature) GetFahrenheit() float64 {
+	if x != nil {
+		return x.Fahrenheit
+	}
+	return 0
+}
+
+func (x *Temperature) GetKelvin() float64 {
+	if x != nil {
+		return x.Kelvin
+	}
+	return 0
+}
+
+type Coordinates struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Coordinates) Reset() {
+	*x = Coordinates{}
+	mi := &file_weather_v1_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinates) ProtoMessage() {}
+
+func (x *Coordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinates.ProtoReflect.Descriptor instead.
+func (*Coordinates) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Coordinates) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Coordinates) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+type Location struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// City and uf are filled when the lookup started from a CEP.
+	City          string       `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Uf            string       `protobuf:"bytes,2,opt,name=uf,proto3" json:"uf,omitempty"`
+	Coordinates   *Coordinates `protobuf:"bytes,3,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+	Timezone      string       `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_weather_v1_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Location) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Location) GetUf() string {
+	if x != nil {
+		return x.Uf
+	}
+	return ""
+}
+
+func (x *Location) GetCoordinates() *Coordinates {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *Location) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type GetTemperatureByCepRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Accepts 00000000, 00000-000 or 00.000-000.
+	Cep           string `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemperatureByCepRequest) Reset() {
+	*x = GetTemperatureByCepRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemperatureByCepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemperatureByCepRequest) ProtoMessage() {}
+
+func (x *GetTemperatureByCepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemperatureByCepRequest.ProtoReflect.Descriptor instead.
+func (*GetTemperatureByCepRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTemperatureByCepRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type GetTemperatureByCepResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Normalized as 00000-000.
+	Cep         string       `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	Location    *Location    `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Temperature *Temperature `protobuf:"bytes,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Weather provider that answered, or the composite strategy.
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	ObservedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemperatureByCepResponse) Reset() {
+	*x = GetTemperatureByCepResponse{}
+	mi := &file_weather_v1_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemperatureByCepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemperatureByCepResponse) ProtoMessage() {}
+
+func (x *GetTemperatureByCepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemperatureByCepResponse.ProtoReflect.Descriptor instead.
+func (*GetTemperatureByCepResponse) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTemperatureByCepResponse) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *GetTemperatureByCepResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetTemperatureByCepResponse) GetTemperature() *Temperature {
+	if x != nil {
+		return x.Temperature
+	}
+	return nil
+}
+
+func (x *GetTemperatureByCepResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetTemperatureByCepResponse) GetObservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return nil
+}
+
+type GetForecastRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Location:
+	//
+	//	*GetForecastRequest_Cep
+	//	*GetForecastRequest_Coordinates
+	Location isGetForecastRequest_Location `protobuf_oneof:"location"`
+	// Number of days, 1 to 7. Zero means 3.
+	Days          int32 `protobuf:"varint,3,opt,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetForecastRequest) Reset() {
+	*x = GetForecastRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetForecastRequest) ProtoMessage() {}
+
+func (x *GetForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetForecastRequest.ProtoReflect.Descriptor instead.
+func (*GetForecastRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetForecastRequest) GetLocation() isGetForecastRequest_Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetForecastRequest) GetCep() string {
+	if x != nil {
+		if x, ok := x.Location.(*GetForecastRequest_Cep); ok {
+			return x.Cep
+		}
+	}
+	return ""
+}
+
+func (x *GetForecastRequest) GetCoordinates() *Coordinates {
+	if x != nil {
+		if x, ok := x.Location.(*GetForecastRequest_Coordinates); ok {
+			return x.Coordinates
+		}
+	}
+	return nil
+}
+
+func (x *GetForecastRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type isGetForecastRequest_Location interface {
+	isGetForecastRequest_Location()
+}
+
+type GetForecastRequest_Cep struct {
+	Cep string `protobuf:"bytes,1,opt,name=cep,proto3,oneof"`
+}
+
+type GetForecastRequest_Coordinates struct {
+	Coordinates *Coordinates `protobuf:"bytes,2,opt,name=coordinates,proto3,oneof"`
+}
+
+func (*GetForecastRequest_Cep) isGetForecastRequest_Location() {}
+
+func (*GetForecastRequest_Coordinates) isGetForecastRequest_Location() {}
+
+type ForecastDay struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Local date as YYYY-MM-DD.
+	Date            string       `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Min             *Temperature `protobuf:"bytes,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max             *Temperature `protobuf:"bytes,3,opt,name=max,proto3" json:"max,omitempty"`
+	PrecipitationMm float64      `protobuf:"fixed64,4,opt,name=precipitation_mm,json=precipitationMm,proto3" json:"precipitation_mm,omitempty"`
+	Condition       string       `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ForecastDay) Reset() {
+	*x = ForecastDay{}
+	mi := &file_weather_v1_weather_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastDay) ProtoMessage() {}
+
+func (x *ForecastDay) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastDay.ProtoReflect.Descriptor instead.
+func (*ForecastDay) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ForecastDay) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ForecastDay) GetMin() *Temperature {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *ForecastDay) GetMax() *Temperature {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+func (x *ForecastDay) GetPrecipitationMm() float64 {
+	if x != nil {
+		return x.PrecipitationMm
+	}
+	return 0
+}
+
+func (x *ForecastDay) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+type GetForecastResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Days          []*ForecastDay         `protobuf:"bytes,3,rep,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetForecastResponse) Reset() {
+	*x = GetForecastResponse{}
+	mi := &file_weather_v1_weather_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetForecastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetForecastResponse) ProtoMessage() {}
+
+func (x *GetForecastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetForecastResponse.ProtoReflect.Descriptor instead.
+func (*GetForecastResponse) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetForecastResponse) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetForecastResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetForecastResponse) GetDays() []*ForecastDay {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type BatchTemperaturesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Up to 50 CEPs.
+	Ceps          []string `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchTemperaturesRequest) Reset() {
+	*x = BatchTemperaturesRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTemperaturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTemperaturesRequest) ProtoMessage() {}
+
+func (x *BatchTemperaturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTemperaturesRequest.ProtoReflect.Descriptor instead.
+func (*BatchTemperaturesRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchTemperaturesRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+// Error carries the google.rpc.Code and message a single CEP lookup
+// would have failed with.
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_weather_v1_weather_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchTemperaturesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The CEP as sent in the request.
+	Cep string `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchTemperaturesResponse_Temperature
+	//	*BatchTemperaturesResponse_Error
+	Result        isBatchTemperaturesResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchTemperaturesResponse) Reset() {
+	*x = BatchTemperaturesResponse{}
+	mi := &file_weather_v1_weather_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTemperaturesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTemperaturesResponse) ProtoMessage() {}
+
+func (x *BatchTemperaturesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTemperaturesResponse.ProtoReflect.Descriptor instead.
+func (*BatchTemperaturesResponse) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchTemperaturesResponse) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *BatchTemperaturesResponse) GetResult() isBatchTemperaturesResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchTemperaturesResponse) GetTemperature() *GetTemperatureByCepResponse {
+	if x != nil {
+		if x, ok := x.Result.(*BatchTemperaturesResponse_Temperature); ok {
+			return x.Temperature
+		}
+	}
+	return nil
+}
+
+func (x *BatchTemperaturesResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*BatchTemperaturesResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchTemperaturesResponse_Result interface {
+	isBatchTemperaturesResponse_Result()
+}
+
+type BatchTemperaturesResponse_Temperature struct {
+	Temperature *GetTemperatureByCepResponse `protobuf:"bytes,2,opt,name=temperature,proto3,oneof"`
+}
+
+type BatchTemperaturesResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchTemperaturesResponse_Temperature) isBatchTemperaturesResponse_Result() {}
+
+func (*BatchTemperaturesResponse_Error) isBatchTemperaturesResponse_Result() {}
+
+var File_weather_v1_weather_proto protoreflect.FileDescriptor
+
+const file_weather_v1_weather_proto_rawDesc = "" +
+	"\n" +
+	"\x18weather/v1/weather.proto\x12\n" +
+	"weather.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"_\n" +
+	"\vTemperature\x12\x18\n" +
+	"\acelsius\x18\x01 \x01(\x01R\acelsius\x12\x1e\n" +
+	"\n" +
+	"fahrenheit\x18\x02 \x01(\x01R\n" +
+	"fahrenheit\x12\x16\n" +
+	"\x06kelvin\x18\x03 \x01(\x01R\x06kelvin\"1\n" +
+	"\vCoordinates\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\"\x85\x01\n" +
+	"\bLocation\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12\x0e\n" +
+	"\x02uf\x18\x02 \x01(\tR\x02uf\x129\n" +
+	"\vcoordinates\x18\x03 \x01(\v2\x17.weather.v1.CoordinatesR\vcoordinates\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\".\n" +
+	"\x1aGetTemperatureByCepRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\"\xf1\x01\n" +
+	"\x1bGetTemperatureByCepResponse\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x120\n" +
+	"\blocation\x18\x02 \x01(\v2\x14.weather.v1.LocationR\blocation\x129\n" +
+	"\vtemperature\x18\x03 \x01(\v2\x17.weather.v1.TemperatureR\vtemperature\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12;\n" +
+	"\vobserved_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"observedAt\"\x85\x01\n" +
+	"\x12GetForecastRequest\x12\x12\n" +
+	"\x03cep\x18\x01 \x01(\tH\x00R\x03cep\x12;\n" +
+	"\vcoordinates\x18\x02 \x01(\v2\x17.weather.v1.CoordinatesH\x00R\vcoordinates\x12\x12\n" +
+	"\x04days\x18\x03 \x01(\x05R\x04daysB\n" +
+	"\n" +
+	"\blocation\"\xc0\x01\n" +
+	"\vForecastDay\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12)\n" +
+	"\x03min\x18\x02 \x01(\v2\x17.weather.v1.TemperatureR\x03min\x12)\n" +
+	"\x03max\x18\x03 \x01(\v2\x17.weather.v1.TemperatureR\x03max\x12)\n" +
+	"\x10precipitation_mm\x18\x04 \x01(\x01R\x0fprecipitationMm\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\"\x8c\x01\n" +
+	"\x13GetForecastResponse\x120\n" +
+	"\blocation\x18\x01 \x01(\v2\x14.weather.v1.LocationR\blocation\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12+\n" +
+	"\x04days\x18\x03 \x03(\v2\x17.weather.v1.ForecastDayR\x04days\".\n" +
+	"\x18BatchTemperaturesRequest\x12\x12\n" +
+	"\x04ceps\x18\x01 \x03(\tR\x04ceps\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xaf\x01\n" +
+	"\x19BatchTemperaturesResponse\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12K\n" +
+	"\vtemperature\x18\x02 \x01(\v2'.weather.v1.GetTemperatureByCepResponseH\x00R\vtemperature\x12)\n" +
+	"\x05error\x18\x03 \x01(\v2\x11.weather.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result2\xac\x02\n" +
+	"\x0eWeatherService\x12f\n" +
+	"\x13GetTemperatureByCep\x12&.weather.v1.GetTemperatureByCepRequest\x1a'.weather.v1.GetTemperatureByCepResponse\x12N\n" +
+	"\vGetForecast\x12\x1e.weather.v1.GetForecastRequest\x1a\x1f.weather.v1.GetForecastResponse\x12b\n" +
+	"\x11BatchTemperatures\x12$.weather.v1.BatchTemperaturesRequest\x1a%.weather.v1.BatchTemperaturesResponse0\x01BCZAgithub.com/alexduzi/labcloudrun/internal/grpc/weatherpb;weatherpbb\x06proto3"
+
+var (
+	file_weather_v1_weather_proto_rawDescOnce sync.Once
+	file_weather_v1_weather_proto_rawDescData []byte
+)
+
+func file_weather_v1_weather_proto_rawDescGZIP() []byte {
+	file_weather_v1_weather_proto_rawDescOnce.Do(func() {
+		file_weather_v1_weather_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_weather_v1_weather_proto_rawDesc), len(file_weather_v1_weather_proto_rawDesc)))
+	})
+	return file_weather_v1_weather_proto_rawDescData
+}
+
+var file_weather_v1_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_weather_v1_weather_proto_goTypes = []any{
+	(*Temperature)(nil),                 // 0: weather.v1.Temperature
+	(*Coordinates)(nil),                 // 1: weather.v1.Coordinates
+	(*Location)(nil),                    // 2: weather.v1.Location
+	(*GetTemperatureByCepRequest)(nil),  // 3: weather.v1.GetTemperatureByCepRequest
+	(*GetTemperatureByCepResponse)(nil), // 4: weather.v1.GetTemperatureByCepResponse
+	(*GetForecastRequest)(nil),          // 5: weather.v1.GetForecastRequest
+	(*ForecastDay)(nil),                 // 6: weather.v1.ForecastDay
+	(*GetForecastResponse)(nil),         // 7: weather.v1.GetForecastResponse
+	(*BatchTemperaturesRequest)(nil),    // 8: weather.v1.BatchTemperaturesRequest
+	(*Error)(nil),                       // 9: weather.v1.Error
+	(*BatchTemperaturesResponse)(nil),   // 10: weather.v1.BatchTemperaturesResponse
+	(*timestamppb.Timestamp)(nil),       // 11: google.protobuf.Timestamp
+}
+var file_weather_v1_weather_proto_depIdxs = []int32{
+	1,  // 0: weather.v1.Location.coordinates:type_name -> weather.v1.Coordinates
+	2,  // 1: weather.v1.GetTemperatureByCepResponse.location:type_name -> weather.v1.Location
+	0,  // 2: weather.v1.GetTemperatureByCepResponse.temperature:type_name -> weather.v1.Temperature
+	11, // 3: weather.v1.GetTemperatureByCepResponse.observed_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: weather.v1.GetForecastRequest.coordinates:type_name -> weather.v1.Coordinates
+	0,  // 5: weather.v1.ForecastDay.min:type_name -> weather.v1.Temperature
+	0,  // 6: weather.v1.ForecastDay.max:type_name -> weather.v1.Temperature
+	2,  // 7: weather.v1.GetForecastResponse.location:type_name -> weather.v1.Location
+	6,  // 8: weather.v1.GetForecastResponse.days:type_name -> weather.v1.ForecastDay
+	4,  // 9: weather.v1.BatchTemperaturesResponse.temperature:type_name -> weather.v1.GetTemperatureByCepResponse
+	9,  // 10: weather.v1.BatchTemperaturesResponse.error:type_name -> weather.v1.Error
+	3,  // 11: weather.v1.WeatherService.GetTemperatureByCep:input_type -> weather.v1.GetTemperatureByCepRequest
+	5,  // 12: weather.v1.WeatherService.GetForecast:input_type -> weather.v1.GetForecastRequest
+	8,  // 13: weather.v1.WeatherService.BatchTemperatures:input_type -> weather.v1.BatchTemperaturesRequest
+	4,  // 14: weather.v1.WeatherService.GetTemperatureByCep:output_type -> weather.v1.GetTemperatureByCepResponse
+	7,  // 15: weather.v1.WeatherService.GetForecast:output_type -> weather.v1.GetForecastResponse
+	10, // 16: weather.v1.WeatherService.BatchTemperatures:output_type -> weather.v1.BatchTemperaturesResponse
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_weather_v1_weather_proto_init() }
+func file_weather_v1_weather_proto_init() {
+	if File_weather_v1_weather_proto != nil {
+		return
+	}
+	file_weather_v1_weather_proto_msgTypes[5].OneofWrappers = []any{
+		(*GetForecastRequest_Cep)(nil),
+		(*GetForecastRequest_Coordinates)(nil),
+	}
+	file_weather_v1_weather_proto_msgTypes[10].OneofWrappers = []any{
+		(*BatchTemperaturesResponse_Temperature)(nil),
+		(*BatchTemperaturesResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_weather_v1_weather_proto_rawDesc), len(file_weather_v1_weather_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_v1_weather_proto_goTypes,
+		DependencyIndexes: file_weather_v1_weather_proto_depIdxs,
+		MessageInfos:      file_weather_v1_weather_proto_msgTypes,
+	}.Build()
+	File_weather_v1_weather_proto = out.File
+	file_weather_v1_weather_proto_goTypes = nil
+	file_weather_v1_weather_proto_depIdxs = nil
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.33.0
+// source: weather/v1/weather.proto
+
+package weatherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WeatherService_GetTemperatureByCep_FullMethodName = "/weather.v1.WeatherService/GetTemperatureByCep"
+	WeatherService_GetForecast_FullMethodName         = "/weather.v1.WeatherService/GetForecast"
+	WeatherService_BatchTemperatures_FullMethodName   = "/weather.v1.WeatherService/BatchTemperatures"
+)
+
+// WeatherServiceClient is the client API for WeatherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WeatherService exposes the same lookups as the REST API under /api/v1.
+// Send an "accept-language" metadata entry (en, pt-BR or es) to localize
+// error messages and condition descriptions.
+type WeatherServiceClient interface {
+	// GetTemperatureByCep returns the current temperature at the city of a CEP.
+	GetTemperatureByCep(ctx context.Context, in *GetTemperatureByCepRequest, opts ...grpc.CallOption) (*GetTemperatureByCepResponse, error)
+	// GetForecast returns a daily forecast for a CEP or a pair of coordinates.
+	GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*GetForecastResponse, error)
+	// BatchTemperatures looks up several CEPs and streams one message per CEP
+	// as soon as it is ready, so results may arrive out of order.
+	BatchTemperatures(ctx context.Context, in *BatchTemperaturesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchTemperaturesResponse], error)
+}
+
+type weatherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherServiceClient(cc grpc.ClientConnInterface) WeatherServiceClient {
+	return &weatherServiceClient{cc}
+}
+
+func (c *weatherServiceClient) GetTemperatureByCep(ctx context.Context, in *GetTemperatureByCepRequest, opts ...grpc.CallOption) (*GetTemperatureByCepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTemperatureByCepResponse)
+	err := c.cc.Invoke(ctx, WeatherService_GetTemperatureByCep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*GetForecastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetForecastResponse)
+	err := c.cc.Invoke(ctx, WeatherService_GetForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) BatchTemperatures(ctx context.Context, in *BatchTemperaturesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchTemperaturesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherService_ServiceDesc.Streams[0], WeatherService_BatchTemperatures_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchTemperaturesRequest, BatchTemperaturesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_BatchTemperaturesClient = grpc.ServerStreamingClient[BatchTemperaturesResponse]
+
+// WeatherServiceServer is the server API for WeatherService service.
+// All implementations must embed UnimplementedWeatherServiceServer
+// for forward compatibility.
+//
+// WeatherService exposes the same lookups as the REST API under /api/v1.
+// Send an "accept-language" metadata entry (en, pt-BR or es) to localize
+// error messages and condition descriptions.
+type WeatherServiceServer interface {
+	// GetTemperatureByCep returns the current temperature at the city of a CEP.
+	GetTemperatureByCep(context.Context, *GetTemperatureByCepRequest) (*GetTemperatureByCepResponse, error)
+	// GetForecast returns a daily forecast for a CEP or a pair of coordinates.
+	GetForecast(context.Context, *GetForecastRequest) (*GetForecastResponse, error)
+	// BatchTemperatures looks up several CEPs and streams one message per CEP
+	// as soon as it is ready, so results may arrive out of order.
+	BatchTemperatures(*BatchTemperaturesRequest, grpc.ServerStreamingServer[BatchTemperaturesResponse]) error
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+// UnimplementedWeatherServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWeatherServiceServer struct{}
+
+func (UnimplementedWeatherServiceServer) GetTemperatureByCep(context.Context, *GetTemperatureByCepRequest) (*GetTemperatureByCepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemperatureByCep not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetForecast(context.Context, *GetForecastRequest) (*GetForecastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetForecast not implemented")
+}
+func (UnimplementedWeatherServiceServer) BatchTemperatures(*BatchTemperaturesRequest, grpc.ServerStreamingServer[BatchTemperaturesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchTemperatures not implemented")
+}
+func (UnimplementedWeatherServiceServer) mustEmbedUnimplementedWeatherServiceServer() {}
+func (UnimplementedWeatherServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeWeatherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherServiceServer will
+// result in compilation errors.
+type UnsafeWeatherServiceServer interface {
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+func RegisterWeatherServiceServer(s grpc.ServiceRegistrar, srv WeatherServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWeatherServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WeatherService_ServiceDesc, srv)
+}
+
+func _WeatherService_GetTemperatureByCep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemperatureByCepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetTemperatureByCep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetTemperatureByCep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetTemperatureByCep(ctx, req.(*GetTemperatureByCepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetForecast(ctx, req.(*GetForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_BatchTemperatures_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchTemperaturesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherServiceServer).BatchTemperatures(m, &grpc.GenericServerStream[BatchTemperaturesRequest, BatchTemperaturesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_BatchTemperaturesServer = grpc.ServerStreamingServer[BatchTemperaturesResponse]
+
+// WeatherService_ServiceDesc is the grpc.ServiceDesc for WeatherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WeatherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "weather.v1.WeatherService",
+	HandlerType: (*WeatherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTemperatureByCep",
+			Handler:    _WeatherService_GetTemperatureByCep_Handler,
+		},
+		{
+			MethodName: "GetForecast",
+			Handler:    _WeatherService_GetForecast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchTemperatures",
+			Handler:       _WeatherService_BatchTemperatures_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "weather/v1/weather.proto",
+}
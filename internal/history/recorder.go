@@ -0,0 +1,190 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Recorder keeps the observations fetched through the weather clients it
+// wraps and prunes them every interval, past the retention
+type Recorder struct {
+	store          *Store
+	municipalities *geo.Dataset
+	retention      time.Duration
+	maxPerLocation int
+	interval       time.Duration
+	now            func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRecorder records into store. Coordinate lookups are recorded under the
+// nearest municipality of municipalities, when it is not nil
+func NewRecorder(cfg *config.Config, store *Store, municipalities *geo.Dataset) *Recorder {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Recorder{
+		store:          store,
+		municipalities: municipalities,
+		retention:      cfg.HistoryRetention,
+		maxPerLocation: cfg.HistoryMaxPerLocation,
+		interval:       cfg.HistoryPruneInterval,
+		now:            time.Now,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Wrap returns a weather client that records every observation next returns
+func (r *Recorder) Wrap(next client.WeatherClientInterface) client.WeatherClientInterface {
+	return &recordingClient{next: next, recorder: r}
+}
+
+// Query returns the observations recorded for city, in the state uf,
+// between from and to, oldest first. City names are compared without
+// accents or case
+func (r *Recorder) Query(uf, city string, from, to time.Time) ([]model.RecordedObservation, error) {
+	return r.store.Query(locationKey(uf, city), from, to)
+}
+
+// locationKey keeps cities of the same name in different states apart.
+// Locations without a UF, as the coordinates no municipality is near, go by
+// name alone
+func locationKey(uf, location string) string {
+	if uf == "" {
+		return geo.NormalizeName(location)
+	}
+	return strings.ToUpper(uf) + "/" + geo.NormalizeName(location)
+}
+
+// Start prunes the history now and then every interval, until Close
+func (r *Recorder) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.prune()
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops pruning and closes the store
+func (r *Recorder) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return r.store.Close()
+}
+
+func (r *Recorder) prune() {
+	removed, err := r.store.Prune(r.now().Add(-r.retention), r.maxPerLocation)
+	if err != nil {
+		slog.Error("Failed to prune observation history", "error", err)
+		return
+	}
+	if removed > 0 {
+		slog.Info("Observation history pruned", "removed", removed)
+	}
+}
+
+// record keeps an observation of location, in the state uf. An observation
+// without a usable time, as a provider time that did not parse, is recorded
+// at the time it was fetched. Failures are logged, never returned: the
+// history must not break the lookup that fed it
+func (r *Recorder) record(uf, location string, observation *model.Observation) {
+	fetchedAt := r.now().UTC()
+	observedAt := observation.ObservedAt.UTC()
+	if observedAt.Before(epoch) {
+		observedAt = fetchedAt
+	}
+
+	err := r.store.Put(locationKey(uf, location), model.RecordedObservation{
+		Provider:        observation.Source,
+		ObservedAt:      observedAt,
+		FetchedAt:       fetchedAt,
+		Location:        observation.Location,
+		TemperatureC:    observation.TemperatureC,
+		HumidityPct:     observation.HumidityPct,
+		Wind:            observation.Wind,
+		PressureMb:      observation.PressureMb,
+		PrecipitationMm: observation.PrecipitationMm,
+		Condition:       observation.Condition,
+	})
+	if err != nil {
+		slog.Error("Failed to record observation", "location", location, "uf", uf, "error", err)
+	}
+}
+
+// recordingClient is a weather client that records what it returns. City
+// lookups are recorded under the state and city asked for, coordinate
+// lookups under the nearest municipality or, without one, under the name the
+// provider reports, or the coordinates when it has none
+type recordingClient struct {
+	next     client.WeatherClientInterface
+	recorder *Recorder
+}
+
+func (c *recordingClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	return c.GetCityWeather(ctx, "", city)
+}
+
+func (c *recordingClient) GetCityWeather(ctx context.Context, uf, city string) (*model.Observation, error) {
+	observation, err := client.GetCityWeather(ctx, c.next, uf, city)
+	if err != nil {
+		return nil, err
+	}
+
+	c.recorder.record(uf, city, observation)
+	return observation, nil
+}
+
+func (c *recordingClient) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*model.Observation, error) {
+	observation, err := c.next.GetWeatherByCoordinates(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.recorder.municipalities != nil {
+		if m, _, ok := c.recorder.municipalities.Nearest(lat, lon); ok {
+			c.recorder.record(m.UF, m.Name, observation)
+			return observation, nil
+		}
+	}
+
+	location := observation.Location.Name
+	if location == "" {
+		location = fmt.Sprintf("%.4f,%.4f", lat, lon)
+	}
+	c.recorder.record("", location, observation)
+	return observation, nil
+}
+
+// GetForecast passes forecasts through; they are not recorded
+func (c *recordingClient) GetForecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	forecaster, ok := c.next.(client.ForecastClientInterface)
+	if !ok {
+		return nil, cErrors.ForecastUnsupported
+	}
+	return forecaster.GetForecast(ctx, lat, lon, days)
+}
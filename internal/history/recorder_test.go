@@ -0,0 +1,197 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// cityOnlyClient is a weather client without forecasts
+type cityOnlyClient struct {
+	client.WeatherClientInterface
+}
+
+type RecorderTestSuite struct {
+	suite.Suite
+	cfg         *config.Config
+	weatherStub *client.WeatherClientStub
+	recorder    *Recorder
+	client      client.WeatherClientInterface
+}
+
+func (s *RecorderTestSuite) SetupTest() {
+	s.cfg = &config.Config{
+		HistoryRetention:      24 * time.Hour,
+		HistoryMaxPerLocation: 100,
+		HistoryPruneInterval:  time.Hour,
+	}
+	store, err := OpenStore(filepath.Join(s.T().TempDir(), "history.db"))
+	require.NoError(s.T(), err)
+
+	s.weatherStub = client.NewWeatherClientStub(s.cfg)
+	s.recorder = NewRecorder(s.cfg, store, nil)
+	s.recorder.now = func() time.Time { return base.Add(time.Hour) }
+	s.client = s.recorder.Wrap(s.weatherStub)
+}
+
+func (s *RecorderTestSuite) TearDownTest() {
+	_ = s.recorder.Close()
+}
+
+func (s *RecorderTestSuite) observation(name string, minutes int) *model.Observation {
+	observation := model.GetObservationMock(name)
+	observation.ObservedAt = base.Add(time.Duration(minutes) * time.Minute)
+	return observation
+}
+
+func (s *RecorderTestSuite) TestGetWeather_RecordsUnderTheCityAsked() {
+	// arrange
+	s.weatherStub.On("GetWeather", mock.Anything, "São Paulo").Return(s.observation("Sao Paulo", 0), nil)
+
+	// act
+	observation, err := client.GetCityWeather(context.Background(), s.client, "SP", "São Paulo")
+	recorded, queryErr := s.recorder.Query("sp", "SAO PAULO", base, base)
+
+	// assert
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), queryErr)
+	require.Len(s.T(), recorded, 1)
+	assert.Equal(s.T(), observation.Source, recorded[0].Provider)
+	assert.Equal(s.T(), observation.TemperatureC, recorded[0].TemperatureC)
+	assert.Equal(s.T(), observation.Location, recorded[0].Location)
+	assert.Equal(s.T(), base, recorded[0].ObservedAt)
+	assert.Equal(s.T(), base.Add(time.Hour), recorded[0].FetchedAt)
+}
+
+func (s *RecorderTestSuite) TestGetWeather_KeepsStatesApart() {
+	// arrange
+	s.weatherStub.On("GetWeather", mock.Anything, "Bom Jesus").Return(s.observation("Bom Jesus", 0), nil)
+
+	// act
+	_, err := client.GetCityWeather(context.Background(), s.client, "PI", "Bom Jesus")
+	inPiaui, piauiErr := s.recorder.Query("PI", "Bom Jesus", base, base)
+	inBahia, bahiaErr := s.recorder.Query("BA", "Bom Jesus", base, base)
+
+	// assert
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), piauiErr)
+	require.NoError(s.T(), bahiaErr)
+	assert.Len(s.T(), inPiaui, 1)
+	assert.Empty(s.T(), inBahia)
+}
+
+func (s *RecorderTestSuite) TestGetWeatherByCoordinates_RecordsUnderTheReportedName() {
+	// arrange
+	s.weatherStub.On("GetWeatherByCoordinates", mock.Anything, -22.9, -47.06).Return(s.observation("Campinas", 0), nil)
+
+	// act
+	_, err := s.client.GetWeatherByCoordinates(context.Background(), -22.9, -47.06)
+	recorded, queryErr := s.recorder.Query("", "Campinas", base, base)
+
+	// assert
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), queryErr)
+	assert.Len(s.T(), recorded, 1)
+}
+
+func (s *RecorderTestSuite) TestGetWeatherByCoordinates_RecordsUnderTheNearestMunicipality() {
+	// arrange
+	municipalities, err := geo.ReadCSV(strings.NewReader("ibge,name,uf,lat,lon,cep_prefix\n" +
+		"3509502,Campinas,SP,-22.9053,-47.0659,13000\n" +
+		"3304557,Rio de Janeiro,RJ,-22.9068,-43.1729,20000\n"))
+	require.NoError(s.T(), err)
+	s.recorder.municipalities = municipalities
+	s.weatherStub.On("GetWeatherByCoordinates", mock.Anything, -22.9, -47.06).Return(s.observation("Campinas Airport", 0), nil)
+
+	// act
+	_, lookupErr := s.client.GetWeatherByCoordinates(context.Background(), -22.9, -47.06)
+	recorded, queryErr := s.recorder.Query("SP", "Campinas", base, base)
+
+	// assert
+	require.NoError(s.T(), lookupErr)
+	require.NoError(s.T(), queryErr)
+	assert.Len(s.T(), recorded, 1)
+}
+
+func (s *RecorderTestSuite) TestGetWeather_WithoutObservationTimeRecordsTheFetchTime() {
+	// arrange
+	observation := s.observation("Sao Paulo", 0)
+	observation.ObservedAt = time.Time{}
+	s.weatherStub.On("GetWeather", mock.Anything, "São Paulo").Return(observation, nil)
+
+	// act
+	_, err := client.GetCityWeather(context.Background(), s.client, "SP", "São Paulo")
+	recorded, queryErr := s.recorder.Query("SP", "São Paulo", base.Add(time.Hour), base.Add(time.Hour))
+
+	// assert
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), queryErr)
+	require.Len(s.T(), recorded, 1)
+	assert.Equal(s.T(), base.Add(time.Hour), recorded[0].ObservedAt)
+}
+
+func (s *RecorderTestSuite) TestGetWeather_FailuresAreNotRecorded() {
+	// arrange
+	upstreamErr := errors.New("upstream down")
+	s.weatherStub.On("GetWeather", mock.Anything, "São Paulo").Return(nil, upstreamErr)
+
+	// act
+	_, err := s.client.GetWeather(context.Background(), "São Paulo")
+	recorded, queryErr := s.recorder.Query("", "São Paulo", base.Add(-time.Hour), base.Add(time.Hour))
+
+	// assert
+	assert.ErrorIs(s.T(), err, upstreamErr)
+	require.NoError(s.T(), queryErr)
+	assert.Empty(s.T(), recorded)
+}
+
+func (s *RecorderTestSuite) TestGetForecast() {
+	// arrange
+	forecast := model.GetForecastMock("São Paulo", 3)
+	s.weatherStub.On("GetForecast", mock.Anything, -23.5, -46.6, 3).Return(forecast, nil)
+	withoutForecasts := s.recorder.Wrap(cityOnlyClient{s.weatherStub})
+
+	// act
+	got, err := s.client.(client.ForecastClientInterface).GetForecast(context.Background(), -23.5, -46.6, 3)
+	_, unsupportedErr := withoutForecasts.(client.ForecastClientInterface).GetForecast(context.Background(), -23.5, -46.6, 3)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Same(s.T(), forecast, got)
+	assert.ErrorIs(s.T(), unsupportedErr, cErrors.ForecastUnsupported)
+}
+
+func (s *RecorderTestSuite) TestStart_PrunesPastTheRetention() {
+	// arrange
+	s.recorder.now = func() time.Time { return base.Add(24*time.Hour + 30*time.Minute) }
+	s.weatherStub.On("GetWeather", mock.Anything, "São Paulo").Return(s.observation("São Paulo", 0), nil).Once()
+	s.weatherStub.On("GetWeather", mock.Anything, "São Paulo").Return(s.observation("São Paulo", 60), nil).Once()
+	_, _ = s.client.GetWeather(context.Background(), "São Paulo")
+	_, _ = s.client.GetWeather(context.Background(), "São Paulo")
+
+	// act
+	s.recorder.Start()
+
+	// assert
+	assert.Eventually(s.T(), func() bool {
+		recorded, err := s.recorder.Query("", "São Paulo", base, base.Add(time.Hour))
+		return err == nil && len(recorded) == 1 && recorded[0].ObservedAt.Equal(base.Add(time.Hour))
+	}, time.Second, 5*time.Millisecond, "the first prune runs on start")
+}
+
+func TestRecorderTestSuite(t *testing.T) {
+	suite.Run(t, new(RecorderTestSuite))
+}
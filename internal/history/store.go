@@ -0,0 +1,170 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+var observationsBucket = []byte("observations")
+
+// Store persists recorded observations in a bbolt file. Keys are the
+// location, a zero byte, the observation time and the provider, so the
+// observations of a location sort by time and an observation fetched twice
+// is kept once
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens, or creates, the store at path. It fails if another
+// process holds the file
+func OpenStore(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open history store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(observationsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// locationPrefix starts the keys of every observation of location
+func locationPrefix(location string) []byte {
+	return append([]byte(location), 0)
+}
+
+// epoch is the earliest time a key holds: the time is stored as unsigned
+// nanoseconds since it
+var epoch = time.Unix(0, 0)
+
+// timeKey is the prefix of the observations of location taken at t
+func timeKey(location string, t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(locationPrefix(location), uint64(t.UnixNano()))
+}
+
+// keyTime reads the observation time back from a key
+func keyTime(key []byte) time.Time {
+	i := bytes.IndexByte(key, 0)
+	if i < 0 || len(key) < i+9 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[i+1:i+9])))
+}
+
+// Put records an observation of location, replacing the one with the same
+// time and provider
+func (s *Store) Put(location string, observation model.RecordedObservation) error {
+	data, err := json.Marshal(observation)
+	if err != nil {
+		return err
+	}
+	key := append(timeKey(location, observation.ObservedAt), observation.Provider...)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(observationsBucket).Put(key, data)
+	})
+}
+
+// Query returns the observations of location taken between from and to,
+// both included, oldest first
+func (s *Store) Query(location string, from, to time.Time) ([]model.RecordedObservation, error) {
+	observations := []model.RecordedObservation{}
+	prefix := locationPrefix(location)
+
+	// An earlier from would wrap around to the end of the keys
+	if from.Before(epoch) {
+		from = epoch
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(observationsBucket).Cursor()
+		for k, data := cursor.Seek(timeKey(location, from)); k != nil && bytes.HasPrefix(k, prefix); k, data = cursor.Next() {
+			if keyTime(k).After(to) {
+				break
+			}
+
+			var observation model.RecordedObservation
+			if err := json.Unmarshal(data, &observation); err != nil {
+				return err
+			}
+			observations = append(observations, observation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
+
+// Prune removes the observations taken before cutoff and, past
+// maxPerLocation observations of a location, its oldest ones. It returns how
+// many were removed
+func (s *Store) Prune(cutoff time.Time, maxPerLocation int) (int, error) {
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(observationsBucket)
+
+		var expired [][]byte
+		var location []byte
+		var kept [][]byte
+
+		// excess drops the oldest kept keys of the location just scanned
+		excess := func() {
+			if len(kept) > maxPerLocation {
+				expired = append(expired, kept[:len(kept)-maxPerLocation]...)
+			}
+			kept = kept[:0]
+		}
+
+		cursor := bucket.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			prefix := k[:bytes.IndexByte(k, 0)+1]
+			if !bytes.Equal(prefix, location) {
+				excess()
+				location = bytes.Clone(prefix)
+			}
+
+			if keyTime(k).Before(cutoff) {
+				expired = append(expired, bytes.Clone(k))
+				continue
+			}
+			kept = append(kept, bytes.Clone(k))
+		}
+		excess()
+
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		removed = len(expired)
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,148 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var base = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+type StoreTestSuite struct {
+	suite.Suite
+	path  string
+	store *Store
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "history.db")
+
+	var err error
+	s.store, err = OpenStore(s.path)
+	require.NoError(s.T(), err)
+}
+
+func (s *StoreTestSuite) TearDownTest() {
+	_ = s.store.Close()
+}
+
+// put records an observation of location taken minutes after base
+func (s *StoreTestSuite) put(location, provider string, minutes int, tempC float64) {
+	require.NoError(s.T(), s.store.Put(location, model.RecordedObservation{
+		Provider:     provider,
+		ObservedAt:   base.Add(time.Duration(minutes) * time.Minute),
+		TemperatureC: tempC,
+	}))
+}
+
+func temperatures(observations []model.RecordedObservation) []float64 {
+	temps := make([]float64, 0, len(observations))
+	for _, observation := range observations {
+		temps = append(temps, observation.TemperatureC)
+	}
+	return temps
+}
+
+func (s *StoreTestSuite) TestQuery() {
+	// arrange
+	s.put("sao paulo", "weatherapi", 30, 23)
+	s.put("sao paulo", "weatherapi", 0, 21)
+	s.put("sao paulo", "weatherapi", 15, 22)
+	s.put("sao paulo", "weatherapi", 45, 24)
+	s.put("sao paulo x", "weatherapi", 15, 99)
+	s.put("campinas", "weatherapi", 15, 30)
+
+	// act
+	observations, err := s.store.Query("sao paulo", base.Add(15*time.Minute), base.Add(30*time.Minute))
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []float64{22, 23}, temperatures(observations), "bounds included, oldest first, other locations left out")
+}
+
+func (s *StoreTestSuite) TestQuery_FromBeforeTheEpoch() {
+	// arrange
+	s.put("sao paulo", "weatherapi", 0, 21)
+
+	// act
+	observations, err := s.store.Query("sao paulo", time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), base.Add(time.Hour))
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []float64{21}, temperatures(observations))
+}
+
+func (s *StoreTestSuite) TestQuery_Empty() {
+	// act
+	observations, err := s.store.Query("sao paulo", base, base.Add(time.Hour))
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.NotNil(s.T(), observations)
+	assert.Empty(s.T(), observations)
+}
+
+func (s *StoreTestSuite) TestPut_KeepsOneObservationPerTimeAndProvider() {
+	// arrange
+	s.put("sao paulo", "weatherapi", 0, 21)
+	s.put("sao paulo", "openmeteo", 0, 20.5)
+
+	// act
+	s.put("sao paulo", "weatherapi", 0, 21.2)
+	observations, err := s.store.Query("sao paulo", base, base)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []float64{20.5, 21.2}, temperatures(observations))
+}
+
+func (s *StoreTestSuite) TestPrune() {
+	// arrange
+	for minute := range 5 {
+		s.put("sao paulo", "weatherapi", minute, float64(minute))
+		s.put("campinas", "weatherapi", minute, float64(10+minute))
+	}
+	s.put("ubatuba", "weatherapi", 4, 20)
+
+	// act
+	removed, err := s.store.Prune(base.Add(time.Minute), 2)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 6, removed)
+
+	for location, expected := range map[string][]float64{
+		"sao paulo": {3, 4},
+		"campinas":  {13, 14},
+		"ubatuba":   {20},
+	} {
+		observations, err := s.store.Query(location, base, base.Add(time.Hour))
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), expected, temperatures(observations), location)
+	}
+}
+
+func (s *StoreTestSuite) TestPersistsAcrossRestarts() {
+	// arrange
+	s.put("sao paulo", "weatherapi", 0, 21)
+	require.NoError(s.T(), s.store.Close())
+
+	// act
+	var err error
+	s.store, err = OpenStore(s.path)
+	require.NoError(s.T(), err)
+	observations, err := s.store.Query("sao paulo", base, base)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []float64{21}, temperatures(observations))
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"net/http"
+
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/gin-gonic/gin"
+)
+
+// GetAPIKeyUsage godoc
+// @Summary Get the usage of the API keys
+// @Description List the API keys, in configuration order, with their routes, quotas and the requests counted in the current UTC day and month. Secrets are not included. Requires an admin key.
+// @Tags admin
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} model.APIKeyUsage
+// @Failure 401 {object} model.ErrorResponse "api key required or invalid"
+// @Failure 403 {object} model.ErrorResponse "api key not allowed on this route"
+// @Failure 501 {object} model.ErrorResponse "api keys are disabled"
+// @Router /admin/api-keys/usage [get]
+func (h *HttpHandler) GetAPIKeyUsage(c *gin.Context) {
+	if h.apiKeys == nil {
+		_ = c.Error(hErrors.APIKeysDisabled)
+		return
+	}
+
+	render.Render(c, http.StatusOK, h.apiKeys.Usage())
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testPartnerKey = "partner-key-0123456789"
+	testOpsKey     = "ops-key-0123456789abc"
+)
+
+type APIKeysTestSuite struct {
+	suite.Suite
+	cfg    *config.Config
+	router *gin.Engine
+}
+
+func (s *APIKeysTestSuite) SetupTest() {
+	s.cfg = &config.Config{GinMode: gin.TestMode}
+	keys := apikey.NewRegistry([]apikey.Key{
+		{Name: "partner", Secret: testPartnerKey, Routes: []string{"/api/v1/convert"}, DailyQuota: 10, MonthlyQuota: 100},
+		{Name: "ops", Secret: testOpsKey, Admin: true},
+	})
+
+	handler := NewHttpHandler(s.cfg, client.NewCepClientStub(s.cfg), client.NewWeatherClientStub(s.cfg), WithAPIKeys(keys))
+	s.router = handler.SetupRouter()
+}
+
+func (s *APIKeysTestSuite) serve(method, path, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *APIKeysTestSuite) TestGetAPIKeyUsage() {
+	// arrange
+	convert := s.serve(http.MethodPost, "/api/v1/convert", testPartnerKey, `{"value": 100, "from": "C", "to": ["F"]}`)
+	require.Equal(s.T(), http.StatusOK, convert.Code)
+	assert.Equal(s.T(), "9", convert.Header().Get("X-RateLimit-Remaining"))
+
+	// act
+	w := s.serve(http.MethodGet, "/admin/api-keys/usage", testOpsKey, "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var usage []model.APIKeyUsage
+	require.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &usage))
+	require.Len(s.T(), usage, 2)
+	assert.Equal(s.T(), "partner", usage[0].Name)
+	assert.Equal(s.T(), 1, usage[0].DailyUsed)
+	assert.Equal(s.T(), 1, usage[0].MonthlyUsed)
+	assert.Equal(s.T(), "ops", usage[1].Name)
+	assert.Zero(s.T(), usage[1].Total)
+	assert.NotContains(s.T(), w.Body.String(), testPartnerKey)
+}
+
+func (s *APIKeysTestSuite) TestGetAPIKeyUsage_RequiresAdmin() {
+	// act
+	w := s.serve(http.MethodGet, "/admin/api-keys/usage", testPartnerKey, "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusForbidden, w.Code)
+}
+
+func (s *APIKeysTestSuite) TestOpenRoutes() {
+	// act
+	health := s.serve(http.MethodGet, "/health", "", "")
+	temperature := s.serve(http.MethodGet, "/api/v1/temperature/01001000", "", "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, health.Code)
+	assert.Equal(s.T(), http.StatusUnauthorized, temperature.Code)
+}
+
+func TestAPIKeysTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeysTestSuite))
+}
+
+func TestGetAPIKeyUsage_Disabled(t *testing.T) {
+	// arrange
+	cfg := &config.Config{GinMode: gin.TestMode}
+	handler := NewHttpHandler(cfg, client.NewCepClientStub(cfg), client.NewWeatherClientStub(cfg))
+	router := handler.SetupRouter()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/api-keys/usage", nil)
+
+	// act
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
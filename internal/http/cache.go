@@ -0,0 +1,57 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/gin-gonic/gin"
+)
+
+// cachedRender writes body in the negotiated format with Cache-Control and a
+// strong ETag, and answers 304 Not Modified when the client already holds the
+// same version. The ETag is computed over the encoded bytes, so every format
+// gets its own. With API keys or bearer tokens configured the response is
+// private, so shared caches do not serve it to callers without credentials
+func (h *HttpHandler) cachedRender(c *gin.Context, maxAge time.Duration, body any) {
+	format := render.FormatOf(c)
+
+	data, err := render.Encode(format, body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	scope := "public"
+	if h.apiKeys != nil || h.tokens != nil {
+		scope = "private"
+	}
+
+	c.Header("Cache-Control", scope+", max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	c.Header("ETag", etag)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+// etagMatches implements the weak comparison If-None-Match requires
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
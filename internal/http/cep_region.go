@@ -0,0 +1,46 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCepRegion godoc
+// @Summary Get UF and region by CEP
+// @Description Resolve the UF and region that own a Brazilian postal code (CEP) range, without calling ViaCEP
+// @Tags cep
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param cep path string true "Brazilian postal code (CEP)" example(01310100)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.CepRegionResponse
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/cep/{cep}/region [get]
+func (h *HttpHandler) GetCepRegion(c *gin.Context) {
+	rawCep, _ := c.Params.Get("cep")
+
+	code, err := cep.Parse(rawCep)
+	if err != nil {
+		slog.Error("Invalid CEP", "cep", rawCep, "error", err)
+		_ = c.Error(hErrors.CepInvalid)
+		return
+	}
+
+	state, _ := cep.Lookup(code)
+
+	render.Render(c, http.StatusOK, model.CepRegionResponse{
+		Cep:    code.Formatted(),
+		UF:     state.UF,
+		State:  state.Name,
+		Region: state.Region,
+	})
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCepRegionRouter() (*gin.Engine, *client.CepClientStub) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+
+	cfg := &config.Config{}
+	cepClient := client.NewCepClientStub(cfg)
+	handler := NewHttpHandler(cfg, cepClient, client.NewWeatherClientStub(cfg))
+
+	r.GET("/:cep/region", handler.GetCepRegion)
+
+	return r, cepClient
+}
+
+func TestGetCepRegion_Success(t *testing.T) {
+	tests := []struct {
+		cep      string
+		expected model.CepRegionResponse
+	}{
+		{"01310100", model.CepRegionResponse{Cep: "01310-100", UF: "SP", State: "São Paulo", Region: "Sudeste"}},
+		{"20040-020", model.CepRegionResponse{Cep: "20040-020", UF: "RJ", State: "Rio de Janeiro", Region: "Sudeste"}},
+		{"69.005-010", model.CepRegionResponse{Cep: "69005-010", UF: "AM", State: "Amazonas", Region: "Norte"}},
+		{"70040-010", model.CepRegionResponse{Cep: "70040-010", UF: "DF", State: "Distrito Federal", Region: "Centro-Oeste"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			// arrange
+			router, cepClient := setupCepRegionRouter()
+
+			// act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/"+tt.cep+"/region", nil)
+			router.ServeHTTP(w, req)
+
+			// assert
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response model.CepRegionResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, response)
+
+			// a consulta é feita sem chamar o ViaCEP
+			cepClient.AssertNotCalled(t, "GetCep", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetCepRegion_InvalidCep(t *testing.T) {
+	for _, value := range []string{"123", "00000-000", "abcdefgh"} {
+		t.Run(value, func(t *testing.T) {
+			// arrange
+			router, _ := setupCepRegionRouter()
+
+			// act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/"+value+"/region", nil)
+			router.ServeHTTP(w, req)
+
+			// assert
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "invalid zipcode", response.Message)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// convertDecimals is the precision of converted values
+const convertDecimals = 4
+
+// Convert godoc
+// @Summary Convert units
+// @Description Convert a value into one or more units of the same quantity.
+// @Description temperature: C, F, K, Ra (Rankine), Re (Réaumur); speed: kph, mph, m/s, kn, bft (Beaufort force 0-12); pressure: mb, hPa, inHg, mmHg; precipitation: mm, in.
+// @Description Units are case insensitive and accept names and common spellings (celsius, km/h, knots). Values that are physically impossible, such as temperatures below absolute zero or negative speeds, are rejected.
+// @Tags conversion
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param request body model.ConvertRequest true "Value, source unit and target units"
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.ConvertResponse "Value in each target unit"
+// @Failure 400 {object} model.ErrorResponse "malformed body"
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "unknown unit, incompatible units or value out of range"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/convert [post]
+func (h *HttpHandler) Convert(c *gin.Context) {
+	var request model.ConvertRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Invalid convert request", "error", err)
+		_ = c.Error(hErrors.ConvertRequestInvalid)
+		return
+	}
+
+	source, ok := conversor.LookupUnit(request.From)
+	if !ok {
+		_ = c.Error(&conversor.UnitError{Unit: request.From})
+		return
+	}
+
+	response := model.ConvertResponse{
+		Quantity: string(source.Quantity),
+		Value:    *request.Value,
+		From:     source.Symbol,
+		Results:  make([]model.ConvertedValue, 0, len(request.To)),
+	}
+
+	for _, to := range request.To {
+		target, ok := conversor.LookupUnit(to)
+		if !ok {
+			_ = c.Error(&conversor.UnitError{Unit: to})
+			return
+		}
+
+		value, err := source.ConvertTo(*request.Value, target)
+		if err != nil {
+			slog.Error("Failed to convert", "value", *request.Value, "from", source.Symbol, "to", target.Symbol, "error", err)
+			_ = c.Error(err)
+			return
+		}
+
+		response.Results = append(response.Results, model.ConvertedValue{
+			Unit:  target.Symbol,
+			Value: roundTo(value, convertDecimals),
+		})
+	}
+
+	render.Render(c, http.StatusOK, response)
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
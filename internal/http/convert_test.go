@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConvertTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+func (s *ConvertTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{GinMode: "test"}
+	handler := NewHttpHandler(cfg, client.NewCepClientStub(cfg), client.NewWeatherClientStub(cfg))
+
+	s.router = gin.New()
+	s.router.Use(middleware.ErrorHandlerMiddleware())
+	s.router.POST("/convert", handler.Convert)
+}
+
+func (s *ConvertTestSuite) post(body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/convert", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *ConvertTestSuite) TestConvert_Temperature() {
+	// act
+	w := s.post(`{"value": -40, "from": "celsius", "to": ["F", "K", "Ra", "Re"]}`)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.ConvertResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ConvertResponse{
+		Quantity: "temperature",
+		Value:    -40,
+		From:     "C",
+		Results: []model.ConvertedValue{
+			{Unit: "F", Value: -40},
+			{Unit: "K", Value: 233.15},
+			{Unit: "Ra", Value: 419.67},
+			{Unit: "Re", Value: -32},
+		},
+	}, response)
+}
+
+func (s *ConvertTestSuite) TestConvert_SpeedAndPressure() {
+	tests := []struct {
+		body     string
+		unit     string
+		expected float64
+	}{
+		{`{"value": 100, "from": "km/h", "to": ["mph"]}`, "mph", 62.1371},
+		{`{"value": 20, "from": "kn", "to": ["bft"]}`, "bft", 5},
+		{`{"value": 1013.25, "from": "hPa", "to": ["inHg"]}`, "inHg", 29.9213},
+		{`{"value": 0, "from": "mm", "to": ["in"]}`, "in", 0},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.body, func() {
+			w := s.post(tt.body)
+
+			assert.Equal(s.T(), http.StatusOK, w.Code)
+
+			var response model.ConvertResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), []model.ConvertedValue{{Unit: tt.unit, Value: tt.expected}}, response.Results)
+		})
+	}
+}
+
+func (s *ConvertTestSuite) TestConvert_BelowAbsoluteZero() {
+	// act
+	w := s.post(`{"value": -300, "from": "C", "to": ["F"]}`)
+
+	// assert
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "value out of range: temperature must be at least -273.15 C", response.Message)
+}
+
+func (s *ConvertTestSuite) TestConvert_UnprocessableUnits() {
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"value": 1, "from": "parsec", "to": ["C"]}`, `unknown unit "parsec"`},
+		{`{"value": 1, "from": "C", "to": ["F", "lightyear"]}`, `unknown unit "lightyear"`},
+		{`{"value": 1, "from": "C", "to": ["mph"]}`, "units measure different quantities: C is temperature, mph is speed"},
+		{`{"value": -5, "from": "kph", "to": ["mph"]}`, "value out of range: speed must be at least 0 kph"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.message, func() {
+			w := s.post(tt.body)
+
+			assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tt.message, response.Message)
+		})
+	}
+}
+
+func (s *ConvertTestSuite) TestConvert_InvalidBody() {
+	bodies := []string{
+		``,
+		`not json`,
+		`{"from": "C", "to": ["F"]}`,
+		`{"value": 1, "to": ["F"]}`,
+		`{"value": 1, "from": "C"}`,
+		`{"value": 1, "from": "C", "to": []}`,
+		`{"value": "hot", "from": "C", "to": ["F"]}`,
+	}
+
+	for _, body := range bodies {
+		s.Run(body, func() {
+			w := s.post(body)
+
+			assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestConvertTestSuite(t *testing.T) {
+	suite.Run(t, new(ConvertTestSuite))
+}
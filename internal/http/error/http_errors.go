@@ -6,4 +6,25 @@ var (
 	CepParamNotExists = errors.New("cep parameter can not be blank")
 	CepInvalid        = errors.New("invalid zipcode")
 	CepCantFind       = errors.New("can not find zipcode")
+	CepUFMismatch     = errors.New("zipcode does not match its state")
+
+	AddressUFInvalid      = errors.New("invalid uf")
+	AddressCityTooShort   = errors.New("city must have at least 3 characters")
+	AddressStreetTooShort = errors.New("street must have at least 3 characters")
+	PaginationInvalid     = errors.New("page must be at least 1 and page_size between 1 and 50")
+
+	CoordinatesInvalid     = errors.New("invalid coordinates")
+	CoordinatesOutOfBounds = errors.New("coordinates outside the supported area")
+
+	ForecastDaysInvalid = errors.New("days must be between 1 and 7")
+
+	ConvertRequestInvalid = errors.New("body must have a numeric value, a from unit and 1 to 20 to units")
+
+	SubscriptionRequestInvalid = errors.New("body must have a cep, a condition, a numeric threshold and a url")
+	SubscriptionsDisabled      = errors.New("webhook subscriptions are disabled")
+
+	ObservationRangeInvalid = errors.New("from and to must be RFC 3339 times, with from not after to")
+	HistoryDisabled         = errors.New("observation history is disabled")
+
+	APIKeysDisabled = errors.New("api keys are disabled")
 )
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+const (
+	expandProviders          = "providers"
+	expandTemperatureSection = "temperature"
+	expandMunicipality       = "municipality"
+	expandComfort            = "comfort"
+	expandCondition          = "condition"
+)
+
+// parseExpand reads the comma separated sections requested through ?expand=
+func parseExpand(value string) map[string]bool {
+	sections := make(map[string]bool)
+	for _, section := range strings.Split(value, ",") {
+		if section = strings.ToLower(strings.TrimSpace(section)); section != "" {
+			sections[section] = true
+		}
+	}
+	return sections
+}
+
+func expandTemperature(temp model.TemperatureResponse, observation model.Observation, sections map[string]bool) model.ExpandedTemperatureResponse {
+	response := model.ExpandedTemperatureResponse{
+		TemperatureResponse: temp,
+		Source:              observation.Source,
+	}
+
+	if sections[expandProviders] {
+		response.Providers = providerDetails(observation)
+	}
+
+	if sections[expandCondition] {
+		condition := observation.Condition
+		response.Condition = &condition
+	}
+
+	if sections[expandComfort] {
+		comfort := conversor.ConvertComfort(observation)
+		response.Comfort = &comfort
+	}
+
+	return response
+}
+
+// providerDetails reports the composite client's per-provider breakdown, or
+// a single contribution when the observation came straight from one provider
+func providerDetails(observation model.Observation) *model.Consensus {
+	if observation.Consensus != nil {
+		return observation.Consensus
+	}
+
+	temperature := observation.TemperatureC
+	return &model.Consensus{
+		Strategy: client.StrategySingle,
+		Contributions: []model.ProviderContribution{
+			{Provider: observation.Source, TemperatureC: &temperature, Weight: 1},
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCep godoc
+// @Summary Get address by CEP
+// @Description Validate a Brazilian postal code (CEP) and return its normalized address, without looking up the weather.
+// @Description Responses carry Cache-Control and ETag headers; send If-None-Match to get 304 Not Modified.
+// @Tags cep
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param cep path string true "Brazilian postal code (CEP)" example(01310100)
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.Address
+// @Success 304 "Not modified"
+// @Header 200 {string} ETag "Version of the address"
+// @Header 200 {string} Cache-Control "public, max-age=<CEP_CACHE_MAX_AGE>"
+// @Failure 404 {object} model.ErrorResponse "can not find zipcode"
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/cep/{cep} [get]
+func (h *HttpHandler) GetCep(c *gin.Context) {
+	rawCep, _ := c.Params.Get("cep")
+
+	_, cepModel, err := h.service.ResolveCep(c.Request.Context(), rawCep)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.cachedRender(c, h.config.CepCacheMaxAge, service.AddressOf(cepModel))
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetCepTestSuite struct {
+	suite.Suite
+	config            *config.Config
+	router            *gin.Engine
+	cepClientStub     *client.CepClientStub
+	weatherClientStub *client.WeatherClientStub
+}
+
+func (s *GetCepTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.config = &config.Config{GinMode: "test", CepCacheMaxAge: time.Hour}
+	s.cepClientStub = client.NewCepClientStub(s.config)
+	s.weatherClientStub = client.NewWeatherClientStub(s.config)
+
+	handler := NewHttpHandler(s.config, s.cepClientStub, s.weatherClientStub)
+
+	s.router = gin.New()
+	s.router.Use(middleware.ErrorHandlerMiddleware())
+	s.router.GET("/:cep", handler.GetCep)
+}
+
+func (s *GetCepTestSuite) get(path string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *GetCepTestSuite) TestGetCep_Success() {
+	// arrange
+	zipcode := "01001-000"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(model.GetViacepResponseMock(zipcode), nil)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Equal(s.T(), "public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(s.T(), w.Header().Get("ETag"))
+
+	var response model.Address
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.Address{
+		Cep:          "01001-000",
+		Street:       "Praça da Sé",
+		Complement:   "lado ímpar",
+		Neighborhood: "Sé",
+		City:         "São Paulo",
+		UF:           "SP",
+		State:        "São Paulo",
+		Region:       "Sudeste",
+		Ibge:         "3550308",
+		Ddd:          "11",
+	}, response)
+
+	// nenhuma consulta de clima é feita
+	s.weatherClientStub.AssertNotCalled(s.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (s *GetCepTestSuite) TestGetCep_PrivateWithAuthentication() {
+	// arrange
+	zipcode := "01001-000"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(model.GetViacepResponseMock(zipcode), nil)
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "web", Secret: "web-key-0123456789abc"}})
+	handler := NewHttpHandler(s.config, s.cepClientStub, s.weatherClientStub, WithAPIKeys(keys))
+	s.router = gin.New()
+	s.router.Use(middleware.ErrorHandlerMiddleware())
+	s.router.GET("/:cep", handler.GetCep)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Equal(s.T(), "private, max-age=3600", w.Header().Get("Cache-Control"))
+}
+
+func (s *GetCepTestSuite) TestGetCep_FillsStateAndRegionFromUF() {
+	// arrange
+	zipcode := "20040-020"
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	cepResponse.Localidade = "Rio de Janeiro"
+	cepResponse.Uf = "RJ"
+	cepResponse.Estado = ""
+	cepResponse.Regiao = ""
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(cepResponse, nil)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.Address
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Rio de Janeiro", response.State)
+	assert.Equal(s.T(), "Sudeste", response.Region)
+}
+
+func (s *GetCepTestSuite) TestGetCep_IfNoneMatch() {
+	// arrange
+	zipcode := "01001-000"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(model.GetViacepResponseMock(zipcode), nil)
+
+	first := s.get("/"+zipcode, nil)
+	etag := first.Header().Get("ETag")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    int
+	}{
+		{"Same ETag", etag, http.StatusNotModified},
+		{"Weak ETag", "W/" + etag, http.StatusNotModified},
+		{"ETag in list", `"other", ` + etag, http.StatusNotModified},
+		{"Wildcard", "*", http.StatusNotModified},
+		{"Different ETag", `"other"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := s.get("/"+zipcode, map[string]string{"If-None-Match": tt.ifNoneMatch})
+
+			// assert
+			assert.Equal(s.T(), tt.expected, w.Code)
+			assert.Equal(s.T(), etag, w.Header().Get("ETag"))
+			if tt.expected == http.StatusNotModified {
+				assert.Empty(s.T(), w.Body.String())
+			}
+		})
+	}
+}
+
+func (s *GetCepTestSuite) TestGetCep_ETagIsStableAcrossFormats() {
+	// arrange
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse("01001000")).Return(model.GetViacepResponseMock("01001-000"), nil)
+
+	// act
+	plain := s.get("/01001000", nil)
+	formatted := s.get("/01.001-000", nil)
+
+	// assert
+	assert.Equal(s.T(), plain.Header().Get("ETag"), formatted.Header().Get("ETag"))
+}
+
+func (s *GetCepTestSuite) TestGetCep_InvalidZipCode() {
+	for _, value := range []string{"123", "00000-000", "abcdefgh"} {
+		s.Run(value, func() {
+			// act
+			w := s.get("/"+value, nil)
+
+			// assert
+			assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+			assert.Empty(s.T(), w.Header().Get("ETag"))
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), "invalid zipcode", response.Message)
+		})
+	}
+
+	s.cepClientStub.AssertNotCalled(s.T(), "GetCep", mock.Anything, mock.Anything)
+}
+
+func (s *GetCepTestSuite) TestGetCep_NotFound() {
+	// arrange
+	zipcode := "99999-999"
+	erro := "true"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(&model.ViacepResponse{Erro: &erro}, nil)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "can not find zipcode", response.Message)
+}
+
+func (s *GetCepTestSuite) TestGetCep_UFMismatchRejected() {
+	// arrange
+	s.config.CepUFMismatch = config.CepUFMismatchReject
+
+	zipcode := "01001-000"
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	cepResponse.Uf = "MG"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(cepResponse, nil)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+}
+
+func (s *GetCepTestSuite) TestGetCep_CepClientError() {
+	// arrange
+	zipcode := "01001-000"
+	s.cepClientStub.On("GetCep", mock.Anything, cep.MustParse(zipcode)).Return(nil, cErrors.CepClientInternalError)
+
+	// act
+	w := s.get("/"+zipcode, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+	assert.Empty(s.T(), w.Header().Get("Cache-Control"))
+}
+
+func TestGetCepTestSuite(t *testing.T) {
+	suite.Run(t, new(GetCepTestSuite))
+}
@@ -4,8 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 
-	"github.com/alexduzi/labcloudrun/internal/conversor"
 	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,45 +17,39 @@ func (h *HttpHandler) GetTemperatureWithoutCep(c *gin.Context) {
 
 // GetTemperatureByCep godoc
 // @Summary Get Temperature by CEP
-// @Description Get temperature information by Brazilian postal code (CEP)
+// @Description Get temperature information by Brazilian postal code (CEP).
+// @Description With ?expand=providers the response also carries the source and each weather provider's contribution (see model.ExpandedTemperatureResponse).
+// @Description With ?expand=comfort it carries heat index, wind chill, humidex, dew point and an approximate WBGT computed from temperature, humidity and wind.
+// @Description With ?expand=condition it carries the sky condition, described in the negotiated language.
 // @Tags weather
 // @Accept json
-// @Produce json
+// @Produce json,application/xml,text/csv,application/msgpack
 // @Param cep path string true "Brazilian postal code (CEP)" example(01310100)
+// @Param expand query string false "Comma separated extra sections to include (providers, comfort, condition)" example(providers)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
 // @Success 200 {object} model.TemperatureResponse "Temperature in Celsius, Fahrenheit and Kelvin"
 // @Failure 404 {object} model.ErrorResponse "can not find zipcode"
-// @Failure 422 {object} model.ErrorResponse "invalid zipcode"
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/v1/temperature/{cep} [get]
 func (h *HttpHandler) GetTemperatureByCep(c *gin.Context) {
-	cep, _ := c.Params.Get("cep")
+	rawCep, _ := c.Params.Get("cep")
 
-	if !h.cepRegex.MatchString(cep) {
-		slog.Error("Invalid CEP format", "cep", cep)
-		_ = c.Error(hErrors.CepInvalid)
-		return
-	}
-
-	cepModel, err := h.cepApiClient.GetCep(c.Request.Context(), cep)
+	result, err := h.service.TemperatureByCep(c.Request.Context(), rawCep)
 	if err != nil {
-		slog.Error("Failed to get CEP information", "cep", cep, "error", err)
 		_ = c.Error(err)
 		return
 	}
 
-	if cepModel.Erro != nil {
-		slog.Error("CEP not found", "cep", cep)
-		_ = c.Error(hErrors.CepCantFind)
-		return
-	}
+	temp := result.Temperature
 
-	weatherModel, err := h.weatherApiClient.GetWeather(c.Request.Context(), cepModel.Localidade)
-	if err != nil {
-		slog.Error("Failed to get weather information", "location", cepModel.Localidade, "cep", cep, "error", err)
-		_ = c.Error(err)
+	sections := parseExpand(c.Query("expand"))
+	if len(sections) == 0 {
+		render.Render(c, http.StatusOK, temp)
 		return
 	}
 
-	temp := conversor.ConvertWeatherResponse(*weatherModel)
-
-	c.JSON(http.StatusOK, temp)
+	render.Render(c, http.StatusOK, expandTemperature(temp, *result.Observation, sections))
 }
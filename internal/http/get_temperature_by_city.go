@@ -0,0 +1,95 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemperatureByCity godoc
+// @Summary Get Temperature by city
+// @Description Get temperature information by municipality name and UF. Names are matched ignoring accents, case and hyphens ("sao jose dos campos" finds "São José dos Campos").
+// @Description An unknown name answers 404 with suggestions pointing at this route.
+// @Description ?expand= accepts providers, comfort, condition and municipality, as in the coordinates route.
+// @Tags weather
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param uf path string true "Brazilian state (UF)" example(SP)
+// @Param city path string true "Municipality name" example(Campinas)
+// @Param expand query string false "Comma separated extra sections to include (providers, comfort, condition, municipality)" example(municipality)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.TemperatureResponse "Temperature in Celsius, Fahrenheit and Kelvin"
+// @Failure 404 {object} model.CityLookupResponse "can not find city"
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid uf"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/temperature/city/{uf}/{city} [get]
+func (h *HttpHandler) GetTemperatureByCity(c *gin.Context) {
+	uf := strings.ToUpper(strings.TrimSpace(c.Param("uf")))
+	city := c.Param("city")
+
+	if _, ok := cep.StateOf(uf); !ok {
+		slog.Error("Invalid UF", "uf", uf, "city", city)
+		_ = c.Error(hErrors.AddressUFInvalid)
+		return
+	}
+
+	lang := i18n.FromContext(c.Request.Context())
+	match := h.municipalities.FindCity(uf, city)
+	if len(match.Matches) == 0 {
+		slog.Error("City not found", "uf", uf, "city", city, "suggestions", len(match.Suggestions))
+		render.Render(c, http.StatusNotFound, cityLookupResponse(lang.Text("error.city_not_found"), match.Suggestions))
+		return
+	}
+
+	municipality := match.Matches[0]
+
+	// the history records coordinate lookups under the nearest municipality,
+	// which for its own coordinates is this one, so it lands under its UF
+	weatherModel, err := h.weatherApiClient.GetWeatherByCoordinates(c.Request.Context(), municipality.Lat, municipality.Lon)
+	if err != nil {
+		slog.Error("Failed to get weather information", "uf", uf, "city", municipality.Name, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	temp := conversor.ConvertObservation(*weatherModel)
+
+	sections := parseExpand(c.Query("expand"))
+	if len(sections) == 0 {
+		render.Render(c, http.StatusOK, temp)
+		return
+	}
+
+	response := expandTemperature(temp, *weatherModel, sections)
+	if sections[expandMunicipality] {
+		response.Municipality = toMunicipality(municipality, 0)
+	}
+
+	render.Render(c, http.StatusOK, response)
+}
+
+func cityLookupResponse(message string, municipalities []geo.Municipality) model.CityLookupResponse {
+	suggestions := make([]model.CitySuggestion, 0, len(municipalities))
+	for _, m := range municipalities {
+		suggestions = append(suggestions, model.CitySuggestion{
+			Name: m.Name,
+			UF:   m.UF,
+			Ibge: m.Ibge,
+			URL:  "/api/v1/temperature/city/" + m.UF + "/" + url.PathEscape(m.Name),
+		})
+	}
+
+	return model.CityLookupResponse{Message: message, Suggestions: suggestions}
+}
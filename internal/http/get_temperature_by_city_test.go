@@ -0,0 +1,194 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetTemperatureByCityTestSuite struct {
+	suite.Suite
+	config            *config.Config
+	router            *gin.Engine
+	weatherClientStub *client.WeatherClientStub
+}
+
+func (s *GetTemperatureByCityTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.config = &config.Config{GinMode: "test"}
+	s.weatherClientStub = client.NewWeatherClientStub(s.config)
+	s.router = s.newRouter()
+}
+
+func (s *GetTemperatureByCityTestSuite) newRouter(opts ...HandlerOption) *gin.Engine {
+	handler := NewHttpHandler(s.config, client.NewCepClientStub(s.config), s.weatherClientStub, opts...)
+
+	router := gin.New()
+	router.Use(middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
+	router.GET("/city/:uf/:city", handler.GetTemperatureByCity)
+	return router
+}
+
+func (s *GetTemperatureByCityTestSuite) get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *GetTemperatureByCityTestSuite) TestSuccess() {
+	paths := []string{
+		"/city/SP/Campinas",
+		"/city/sp/campinas",
+		"/city/SP/CAMPINAS",
+	}
+
+	for _, path := range paths {
+		s.Run(path, func() {
+			// arrange
+			s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -22.9099, -47.0626).
+				Return(model.GetObservationMock("Campinas"), nil).Once()
+
+			// act
+			w := s.get(s.router, path)
+
+			// assert
+			assert.Equal(s.T(), http.StatusOK, w.Code)
+
+			var response model.TemperatureResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), 32.2, response.Celsius)
+		})
+	}
+
+	s.weatherClientStub.AssertExpectations(s.T())
+}
+
+func (s *GetTemperatureByCityTestSuite) TestAccentInsensitive() {
+	// arrange
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything).
+		Return(model.GetObservationMock("São José dos Campos"), nil)
+
+	// act
+	w := s.get(s.router, "/city/SP/sao%20jose%20dos%20campos?expand=municipality")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	require.NotNil(s.T(), response.Municipality)
+	assert.Equal(s.T(), "São José dos Campos", response.Municipality.Name)
+	assert.Equal(s.T(), "SP", response.Municipality.UF)
+	assert.Equal(s.T(), 0.0, response.Municipality.DistanceKm)
+}
+
+func (s *GetTemperatureByCityTestSuite) TestNotFound_WithSuggestions() {
+	// act
+	w := s.get(s.router, "/city/SP/Campinaz")
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	var response model.CityLookupResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "can not find city", response.Message)
+	require.Len(s.T(), response.Suggestions, 1)
+	assert.Equal(s.T(), model.CitySuggestion{
+		Name: "Campinas",
+		UF:   "SP",
+		Ibge: "3509502",
+		URL:  "/api/v1/temperature/city/SP/Campinas",
+	}, response.Suggestions[0])
+
+	s.weatherClientStub.AssertNotCalled(s.T(), "GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *GetTemperatureByCityTestSuite) TestNotFound_WrongUF() {
+	// act
+	w := s.get(s.router, "/city/RJ/Ribeirao%20Preto")
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	var response model.CityLookupResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	require.Len(s.T(), response.Suggestions, 1)
+	assert.Equal(s.T(), "SP", response.Suggestions[0].UF)
+	assert.Equal(s.T(), "/api/v1/temperature/city/SP/Ribeir%C3%A3o%20Preto", response.Suggestions[0].URL)
+}
+
+func (s *GetTemperatureByCityTestSuite) TestNotFound_Localized() {
+	// arrange
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/city/SP/Campinaz", nil)
+	req.Header.Set("Accept-Language", "pt-BR")
+
+	// act
+	s.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+
+	var response model.CityLookupResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "cidade não encontrada", response.Message)
+	assert.Len(s.T(), response.Suggestions, 1)
+}
+
+func (s *GetTemperatureByCityTestSuite) TestNotFound_NoSuggestions() {
+	// act
+	w := s.get(s.router, "/city/BA/Xique-Xique")
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotFound, w.Code)
+	assert.JSONEq(s.T(), `{"message":"can not find city","suggestions":[]}`, w.Body.String())
+}
+
+func (s *GetTemperatureByCityTestSuite) TestInvalidUF() {
+	// act
+	w := s.get(s.router, "/city/XX/Campinas")
+
+	// assert
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "invalid uf", response.Message)
+}
+
+func (s *GetTemperatureByCityTestSuite) TestWeatherError() {
+	// arrange
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, cErrors.WeatherClientInternalError)
+
+	// act
+	w := s.get(s.router, "/city/DF/Brasilia")
+
+	// assert
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
+func TestGetTemperatureByCityTestSuite(t *testing.T) {
+	suite.Run(t, new(GetTemperatureByCityTestSuite))
+}
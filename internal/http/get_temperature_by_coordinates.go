@@ -0,0 +1,98 @@
+package http
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemperatureByCoordinates godoc
+// @Summary Get Temperature by coordinates
+// @Description Get temperature information by latitude and longitude. Coordinates must be inside the configured area (GEO_BOUNDING_BOX, Brazil by default).
+// @Description With ?expand=municipality the response also carries the nearest municipality and its CEP prefix; ?expand=providers, comfort and condition work as in the CEP route.
+// @Tags weather
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param lat query number true "Latitude in decimal degrees" example(-23.5614)
+// @Param lon query number true "Longitude in decimal degrees" example(-46.6559)
+// @Param expand query string false "Comma separated extra sections to include (providers, comfort, condition, municipality)" example(municipality)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.TemperatureResponse "Temperature in Celsius, Fahrenheit and Kelvin"
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid coordinates, or coordinates outside the supported area"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/temperature [get]
+func (h *HttpHandler) GetTemperatureByCoordinates(c *gin.Context) {
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(c.Query("lon"), 64)
+	if latErr != nil || lonErr != nil || !geo.ValidCoordinates(lat, lon) {
+		slog.Error("Invalid coordinates", "lat", c.Query("lat"), "lon", c.Query("lon"))
+		_ = c.Error(hErrors.CoordinatesInvalid)
+		return
+	}
+
+	if !h.boundingBox().Contains(lat, lon) {
+		slog.Error("Coordinates outside the supported area", "lat", lat, "lon", lon)
+		_ = c.Error(hErrors.CoordinatesOutOfBounds)
+		return
+	}
+
+	weatherModel, err := h.weatherApiClient.GetWeatherByCoordinates(c.Request.Context(), lat, lon)
+	if err != nil {
+		slog.Error("Failed to get weather information", "lat", lat, "lon", lon, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	temp := conversor.ConvertObservation(*weatherModel)
+
+	sections := parseExpand(c.Query("expand"))
+	if len(sections) == 0 {
+		render.Render(c, http.StatusOK, temp)
+		return
+	}
+
+	response := expandTemperature(temp, *weatherModel, sections)
+	if sections[expandMunicipality] {
+		response.Municipality = h.nearestMunicipality(lat, lon)
+	}
+
+	render.Render(c, http.StatusOK, response)
+}
+
+// boundingBox returns GEO_BOUNDING_BOX, or Brazil's box when it is not set
+func (h *HttpHandler) boundingBox() geo.BoundingBox {
+	box := h.config.GeoBoundingBox
+	if box == [4]float64{} {
+		return geo.BrazilBoundingBox
+	}
+	return geo.BoundingBox{South: box[0], West: box[1], North: box[2], East: box[3]}
+}
+
+func (h *HttpHandler) nearestMunicipality(lat, lon float64) *model.Municipality {
+	m, km, ok := h.municipalities.Nearest(lat, lon)
+	if !ok {
+		return nil
+	}
+
+	return toMunicipality(m, km)
+}
+
+func toMunicipality(m geo.Municipality, km float64) *model.Municipality {
+	return &model.Municipality{
+		Ibge:       m.Ibge,
+		Name:       m.Name,
+		UF:         m.UF,
+		CepPrefix:  m.CepPrefix,
+		DistanceKm: math.Round(km*10) / 10,
+	}
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetTemperatureByCoordinatesTestSuite struct {
+	suite.Suite
+	config            *config.Config
+	router            *gin.Engine
+	weatherClientStub *client.WeatherClientStub
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.config = &config.Config{GinMode: "test"}
+	s.weatherClientStub = client.NewWeatherClientStub(s.config)
+	s.router = s.newRouter()
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) newRouter(opts ...HandlerOption) *gin.Engine {
+	handler := NewHttpHandler(s.config, client.NewCepClientStub(s.config), s.weatherClientStub, opts...)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandlerMiddleware())
+	router.GET("/temperature", handler.GetTemperatureByCoordinates)
+	return router
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) get(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/temperature?"+query, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestSuccess() {
+	// arrange
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -23.5614, -46.6559).
+		Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	w := s.get(s.router, "lat=-23.5614&lon=-46.6559")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.TemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 32.2, response.Celsius)
+	assert.NotContains(s.T(), w.Body.String(), "municipality")
+
+	s.weatherClientStub.AssertExpectations(s.T())
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestExpandMunicipality() {
+	// arrange
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -23.5614, -46.6559).
+		Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	w := s.get(s.router, "lat=-23.5614&lon=-46.6559&expand=municipality")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 32.2, response.Celsius)
+	assert.Nil(s.T(), response.Providers)
+	require.NotNil(s.T(), response.Municipality)
+	assert.Equal(s.T(), "3550308", response.Municipality.Ibge)
+	assert.Equal(s.T(), "SP", response.Municipality.UF)
+	assert.Equal(s.T(), "01001", response.Municipality.CepPrefix)
+	assert.Less(s.T(), response.Municipality.DistanceKm, 10.0)
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestExpandMunicipality_CustomDataset() {
+	// arrange
+	dataset, err := geo.ReadCSV(strings.NewReader("ibge,name,uf,lat,lon,cep_prefix\n4314902,Porto Alegre,RS,-30.0346,-51.2177,90010\n"))
+	require.NoError(s.T(), err)
+	router := s.newRouter(WithMunicipalities(dataset))
+
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -23.5614, -46.6559).
+		Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	w := s.get(router, "lat=-23.5614&lon=-46.6559&expand=municipality")
+
+	// assert
+	var response model.ExpandedTemperatureResponse
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	require.NotNil(s.T(), response.Municipality)
+	assert.Equal(s.T(), "Porto Alegre", response.Municipality.Name)
+	assert.Greater(s.T(), response.Municipality.DistanceKm, 800.0)
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestInvalidCoordinates() {
+	queries := []string{
+		"",
+		"lat=-23.5",
+		"lon=-46.6",
+		"lat=abc&lon=-46.6",
+		"lat=-95&lon=-46.6",
+		"lat=-23.5&lon=200",
+		"lat=NaN&lon=-46.6",
+	}
+
+	for _, query := range queries {
+		s.Run(query, func() {
+			// act
+			w := s.get(s.router, query)
+
+			// assert
+			assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), "invalid coordinates", response.Message)
+		})
+	}
+
+	s.weatherClientStub.AssertNotCalled(s.T(), "GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestOutsideBrazil() {
+	// act
+	w := s.get(s.router, "lat=40.7128&lon=-74.0060")
+
+	// assert
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "coordinates outside the supported area", response.Message)
+
+	s.weatherClientStub.AssertNotCalled(s.T(), "GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestConfiguredBoundingBox() {
+	// arrange: a box around New York only
+	s.config.GeoBoundingBox = [4]float64{40, -75, 41.5, -73}
+	router := s.newRouter()
+
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, 40.7128, -74.006).
+		Return(model.GetObservationMock("New York"), nil)
+
+	// act
+	inside := s.get(router, "lat=40.7128&lon=-74.006")
+	outside := s.get(router, "lat=-23.5614&lon=-46.6559")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, inside.Code)
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, outside.Code)
+}
+
+func (s *GetTemperatureByCoordinatesTestSuite) TestWeatherError() {
+	// arrange
+	s.weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -15.7939, -47.8828).
+		Return(nil, cErrors.WeatherClientBadRequest)
+
+	// act
+	w := s.get(s.router, "lat=-15.7939&lon=-47.8828")
+
+	// assert
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
+func TestGetTemperatureByCoordinatesTestSuite(t *testing.T) {
+	suite.Run(t, new(GetTemperatureByCoordinatesTestSuite))
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/alexduzi/labcloudrun/internal/cep"
 	"github.com/alexduzi/labcloudrun/internal/client"
 	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
 	"github.com/alexduzi/labcloudrun/internal/config"
@@ -14,6 +15,7 @@ import (
 	"github.com/alexduzi/labcloudrun/internal/model"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -54,11 +56,11 @@ func (h *HttpHandlerTestSuite) SetupTest() {
 
 func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_Success() {
 	// arrange
-	cep := "01001-000"
+	zipcode := "01001-000"
 	city := "São Paulo"
 
-	cepResponse := model.GetViacepResponseMock(cep)
-	weatherResponse := model.GetWeatherResponseMock(city)
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	weatherResponse := model.GetObservationMock(city)
 
 	expectedTempC := 32.2
 	expectedTempF := 89.96  // 32.2 * 1.8 + 32
@@ -66,13 +68,13 @@ func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_Success() {
 
 	ctx := context.Background()
 
-	h.cepClientStub.On("GetCep", ctx, cep).Return(cepResponse, nil)
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
 
 	h.weatherClientStub.On("GetWeather", ctx, city).Return(weatherResponse, nil)
 
 	// act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+cep, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
 	h.router.ServeHTTP(w, req)
 
 	// assert
@@ -90,21 +92,160 @@ func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_Success() {
 	h.weatherClientStub.AssertExpectations(h.Suite.T())
 }
 
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_ExpandProviders() {
+	// arrange
+	zipcode := "01001-000"
+	city := "São Paulo"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	weatherResponse := model.GetObservationMock(city)
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(weatherResponse, nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode+"?expand=providers", nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Equal(h.Suite.T(), 32.2, response.Celsius)
+	assert.Equal(h.Suite.T(), "weatherapi", response.Source)
+	assert.NotNil(h.Suite.T(), response.Providers)
+	assert.Equal(h.Suite.T(), "single", response.Providers.Strategy)
+	assert.Len(h.Suite.T(), response.Providers.Contributions, 1)
+	assert.Equal(h.Suite.T(), "weatherapi", response.Providers.Contributions[0].Provider)
+	assert.Equal(h.Suite.T(), 32.2, *response.Providers.Contributions[0].TemperatureC)
+
+	h.cepClientStub.AssertExpectations(h.Suite.T())
+	h.weatherClientStub.AssertExpectations(h.Suite.T())
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_ExpandProvidersConsensus() {
+	// arrange
+	zipcode := "01001-000"
+	city := "São Paulo"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	weatherResponse := model.GetObservationMock(city)
+	weatherResponse.Source = "consensus"
+	weatherResponse.Consensus = &model.Consensus{
+		Strategy: "consensus",
+		SpreadC:  0.8,
+		Contributions: []model.ProviderContribution{
+			{Provider: "weatherapi", Weight: 1},
+			{Provider: "openmeteo", Weight: 1},
+		},
+	}
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(weatherResponse, nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode+"?expand=providers", nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Equal(h.Suite.T(), "consensus", response.Source)
+	assert.Equal(h.Suite.T(), 0.8, response.Providers.SpreadC)
+	assert.Len(h.Suite.T(), response.Providers.Contributions, 2)
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_ExpandComfort() {
+	// arrange
+	zipcode := "01001-000"
+	city := "São Paulo"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	weatherResponse := model.GetObservationMock(city)
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(weatherResponse, nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode+"?expand=comfort", nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Nil(h.Suite.T(), response.Providers)
+	assert.NotNil(h.Suite.T(), response.Comfort)
+	assert.Nil(h.Suite.T(), response.Comfort.WindChillC)
+	assert.Equal(h.Suite.T(), 31.88, *response.Comfort.HeatIndexC)
+	assert.Equal(h.Suite.T(), 15.24, *response.Comfort.DewPointC)
+	assert.NotNil(h.Suite.T(), response.Comfort.Humidex)
+	assert.NotNil(h.Suite.T(), response.Comfort.WBGTC)
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_ExpandCondition() {
+	// arrange
+	zipcode := "01001-000"
+	city := "São Paulo"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	weatherResponse := model.GetObservationMock(city)
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(weatherResponse, nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode+"?expand=condition", nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	var response model.ExpandedTemperatureResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Nil(h.Suite.T(), response.Comfort)
+	assert.Equal(h.Suite.T(), &weatherResponse.Condition, response.Condition)
+}
+
 func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_CepNotFound() {
 	// arrange
-	cep := "11001-000"
+	zipcode := "11001-000"
 
-	cepResponse := model.GetViacepResponseMock(cep)
+	cepResponse := model.GetViacepResponseMock(zipcode)
 	erro := "true"
 	cepResponse.Erro = &erro
 
 	ctx := context.Background()
 
-	h.cepClientStub.On("GetCep", ctx, cep).Return(cepResponse, nil)
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
 
 	// act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+cep, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
 	h.router.ServeHTTP(w, req)
 
 	// assert
@@ -121,13 +262,13 @@ func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_CepNotFound()
 
 func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_InvalidZipCode() {
 	// arrange
-	cep := "111111111111111111111111111"
+	zipcode := "111111111111111111111111111"
 
 	ctx := context.Background()
 
 	// act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+cep, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
 	h.router.ServeHTTP(w, req)
 
 	// assert
@@ -140,19 +281,115 @@ func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_InvalidZipCod
 	assert.Equal(h.Suite.T(), "invalid zipcode", response.Message)
 }
 
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_ImpossibleZipCode() {
+	// arrange
+	ctx := context.Background()
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/00000-000", nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Equal(h.Suite.T(), "invalid zipcode", response.Message)
+	h.cepClientStub.AssertNotCalled(h.Suite.T(), "GetCep", mock.Anything, mock.Anything)
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_FormattedWithDot() {
+	// arrange
+	zipcode := "01.001-000"
+	city := "São Paulo"
+
+	ctx := context.Background()
+
+	cepResponse := model.GetViacepResponseMock("01001-000")
+	h.cepClientStub.On("GetCep", ctx, cep.CEP("01001000")).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(model.GetObservationMock(city), nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	h.cepClientStub.AssertExpectations(h.Suite.T())
+	h.weatherClientStub.AssertExpectations(h.Suite.T())
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_UFMismatchWarns() {
+	// arrange
+	zipcode := "01001-000"
+	city := "São Paulo"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	cepResponse.Uf = "RJ"
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+	h.weatherClientStub.On("GetWeather", ctx, city).Return(model.GetObservationMock(city), nil)
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+	h.weatherClientStub.AssertExpectations(h.Suite.T())
+}
+
+func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_UFMismatchRejected() {
+	// arrange
+	zipcode := "01001-000"
+
+	cepResponse := model.GetViacepResponseMock(zipcode)
+	cepResponse.Uf = "RJ"
+
+	ctx := context.Background()
+
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
+
+	cfg := &config.Config{CepUFMismatch: config.CepUFMismatchReject}
+	router := setupTestRouter(NewHttpHandler(cfg, h.cepClientStub, h.weatherClientStub))
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Equal(h.Suite.T(), "zipcode does not match its state", response.Message)
+	h.weatherClientStub.AssertNotCalled(h.Suite.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
 func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_CepClientError() {
 	// arrange
-	cep := "01001-000"
+	zipcode := "01001-000"
 
 	cepClientError := cErrors.NewCepClientHTTPError(500)
 
 	ctx := context.Background()
 
-	h.cepClientStub.On("GetCep", ctx, cep).Return(nil, cepClientError)
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(nil, cepClientError)
 
 	// act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+cep, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
 	h.router.ServeHTTP(w, req)
 
 	// assert
@@ -169,20 +406,20 @@ func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_CepClientErro
 
 func (h *HttpHandlerTestSuite) TestHttpHandler_GetTemperatureByCep_WeatherClientError() {
 	// arrange
-	cep := "01001-000"
+	zipcode := "01001-000"
 	city := "São Paulo"
 
-	cepResponse := model.GetViacepResponseMock(cep)
+	cepResponse := model.GetViacepResponseMock(zipcode)
 	weatherClientError := cErrors.NewWeatherClientHTTPError(500)
 
 	ctx := context.Background()
 
-	h.cepClientStub.On("GetCep", ctx, cep).Return(cepResponse, nil)
+	h.cepClientStub.On("GetCep", ctx, cep.MustParse(zipcode)).Return(cepResponse, nil)
 	h.weatherClientStub.On("GetWeather", ctx, city).Return(nil, weatherClientError)
 
 	// act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+cep, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/"+zipcode, nil)
 	h.router.ServeHTTP(w, req)
 
 	// assert
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/graphql"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// maxGraphQLBody is the largest GraphQL request body read, in bytes
+const maxGraphQLBody = 1 << 20
+
+// GraphQL godoc
+// @Summary GraphQL endpoint
+// @Description Query the address, current weather, daily forecast and alerts of CEPs in one round trip, for example `{ location(cep: "01001000") { address { city uf } current { temperature { celsius } } forecast(days: 3) { days { date max { celsius } } } alerts { event severity } } }`.
+// @Description Send a JSON array of requests to run them as a batch: the response is an array in the same order, and upstream lookups are shared by the whole batch. GET accepts query, operationName and variables (JSON) as query parameters.
+// @Description Queries deeper than GRAPHQL_MAX_DEPTH or more complex than GRAPHQL_MAX_COMPLEXITY are rejected before running: location, current, forecast and alerts cost 10, other fields 1, and forecast's selection counts once per day.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "GraphQL request, or a JSON array of them"
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} graphql.Response "Result, or an array of results for a batch"
+// @Failure 400 {object} graphql.Response "malformed request or batch larger than GRAPHQL_MAX_BATCH"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /graphql [post]
+func (h *HttpHandler) GraphQL(c *gin.Context) {
+	lang := i18n.FromContext(c.Request.Context())
+
+	requests, batch, err := readGraphQLRequests(c)
+	if err != nil {
+		slog.Error("Invalid GraphQL request", "error", err)
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []graphql.Error{
+			graphql.NewError(graphql.CodeBadRequest, lang.Text("error.graphql_request_invalid")),
+		}})
+		return
+	}
+
+	if limit := h.config.GraphQLMaxBatch; limit > 0 && len(requests) > limit {
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []graphql.Error{
+			graphql.NewError(graphql.CodeBatchTooLarge, lang.Text("error.graphql_batch_too_large", len(requests), limit)),
+		}})
+		return
+	}
+
+	responses := h.graphqlExecutor.ExecuteBatch(c.Request.Context(), requests)
+	if batch {
+		c.JSON(http.StatusOK, responses)
+		return
+	}
+	c.JSON(http.StatusOK, responses[0])
+}
+
+// errGraphQLQueryMissing is returned for a request without a query
+var errGraphQLQueryMissing = errors.New("graphql request has no query")
+
+// readGraphQLRequests reads a request from the query string of a GET, or a
+// request or a batch of them from the JSON body of a POST
+func readGraphQLRequests(c *gin.Context) ([]graphql.Request, bool, error) {
+	if c.Request.Method == http.MethodGet {
+		request := graphql.Request{
+			Query:         c.Query("query"),
+			OperationName: c.Query("operationName"),
+		}
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return nil, false, err
+			}
+		}
+		if strings.TrimSpace(request.Query) == "" {
+			return nil, false, errGraphQLQueryMissing
+		}
+		return []graphql.Request{request}, false, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLBody))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var requests []graphql.Request
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		var request graphql.Request
+		err = json.Unmarshal(body, &request)
+		requests = []graphql.Request{request}
+	}
+	if err != nil {
+		return nil, batch, err
+	}
+
+	if len(requests) == 0 {
+		return nil, batch, errGraphQLQueryMissing
+	}
+	for _, request := range requests {
+		if strings.TrimSpace(request.Query) == "" {
+			return nil, batch, errGraphQLQueryMissing
+		}
+	}
+	return requests, batch, nil
+}
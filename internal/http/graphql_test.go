@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GraphQLTestSuite struct {
+	suite.Suite
+	cepClient *client.CepClientStub
+	router    *gin.Engine
+}
+
+func (s *GraphQLTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		GinMode:              "test",
+		GraphQLMaxDepth:      15,
+		GraphQLMaxComplexity: 100,
+		GraphQLMaxBatch:      2,
+	}
+	s.cepClient = client.NewCepClientStub(cfg)
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	handler := NewHttpHandler(cfg, s.cepClient, client.NewWeatherClientStub(cfg))
+
+	s.router = gin.New()
+	s.router.Use(middleware.LanguageMiddleware())
+	s.router.GET("/graphql", handler.GraphQL)
+	s.router.POST("/graphql", handler.GraphQL)
+}
+
+func (s *GraphQLTestSuite) post(body string, headers ...string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *GraphQLTestSuite) TestGraphQL_Post() {
+	// act
+	w := s.post(`{"query": "query City($cep: String!) { location(cep: $cep) { cep address { city } } }", "variables": {"cep": "01001000"}}`)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `{"data": {"location": {"cep": "01001-000", "address": {"city": "São Paulo"}}}}`, w.Body.String())
+}
+
+func (s *GraphQLTestSuite) TestGraphQL_Get() {
+	// arrange
+	query := url.Values{
+		"query":     {`query City($cep: String!) { location(cep: $cep) { address { uf } } }`},
+		"variables": {`{"cep": "01001000"}`},
+	}
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+	s.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `{"data": {"location": {"address": {"uf": "SP"}}}}`, w.Body.String())
+}
+
+func (s *GraphQLTestSuite) TestGraphQL_Batch() {
+	// act
+	w := s.post(`[
+		{"query": "{ location(cep: \"01001000\") { cep } }"},
+		{"query": "{ location(cep: \"01001-000\") { address { city } } }"}
+	]`)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `[
+		{"data": {"location": {"cep": "01001-000"}}},
+		{"data": {"location": {"address": {"city": "São Paulo"}}}}
+	]`, w.Body.String())
+	s.cepClient.AssertNumberOfCalls(s.T(), "GetCep", 1)
+}
+
+func (s *GraphQLTestSuite) TestGraphQL_FieldErrorIsOK() {
+	// act
+	w := s.post(`{"query": "{ location(cep: \"123\") { cep } }"}`, "Accept-Language", "es")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `{
+		"data": {"location": null},
+		"errors": [{
+			"message": "código postal inválido",
+			"locations": [{"line": 1, "column": 3}],
+			"path": ["location"],
+			"extensions": {"code": "BAD_USER_INPUT"}
+		}]
+	}`, w.Body.String())
+}
+
+func (s *GraphQLTestSuite) TestGraphQL_BadRequest() {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"malformed json", `{"query": `, `{"errors": [{"message": "request must have a query, or be a non-empty list of requests with one", "extensions": {"code": "BAD_REQUEST"}}]}`},
+		{"missing query", `{"variables": {}}`, `{"errors": [{"message": "request must have a query, or be a non-empty list of requests with one", "extensions": {"code": "BAD_REQUEST"}}]}`},
+		{"empty batch", `[]`, `{"errors": [{"message": "request must have a query, or be a non-empty list of requests with one", "extensions": {"code": "BAD_REQUEST"}}]}`},
+		{"batch too large", `[{"query": "{ __typename }"}, {"query": "{ __typename }"}, {"query": "{ __typename }"}]`, `{"errors": [{"message": "batch of 3 operations exceeds the limit of 2", "extensions": {"code": "BATCH_TOO_LARGE"}}]}`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := s.post(tt.body)
+
+			// assert
+			assert.Equal(s.T(), http.StatusBadRequest, w.Code)
+			assert.JSONEq(s.T(), tt.expected, w.Body.String())
+		})
+	}
+}
+
+func TestGraphQLTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLTestSuite))
+}
@@ -1,28 +1,183 @@
 package http
 
 import (
-	"regexp"
-
+	"github.com/alexduzi/labcloudrun/internal/apikey"
 	"github.com/alexduzi/labcloudrun/internal/client"
 	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	"github.com/alexduzi/labcloudrun/internal/graphql"
+	"github.com/alexduzi/labcloudrun/internal/history"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/alexduzi/labcloudrun/internal/prewarm"
+	"github.com/alexduzi/labcloudrun/internal/quota"
+	"github.com/alexduzi/labcloudrun/internal/ratelimit"
+	"github.com/alexduzi/labcloudrun/internal/service"
+	"github.com/alexduzi/labcloudrun/internal/stream"
+	"github.com/alexduzi/labcloudrun/internal/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 type HttpHandler struct {
 	config           *config.Config
 	cepApiClient     client.CepClientInterface
 	weatherApiClient client.WeatherClientInterface
-	cepRegex         *regexp.Regexp
+	municipalities   *geo.Dataset
+	service          *service.WeatherService
+	graphqlExecutor  *graphql.Executor
+	streams          *stream.Hub
+	webhookStore     *webhook.Store
+	webhooks         *webhook.Manager
+	historyStore     *history.Store
+	history          *history.Recorder
+	cepCache         *client.CachedCepClient
+	weatherCache     *client.CachedWeatherClient
+	prewarm          *prewarm.Scheduler
+	apiKeys          *apikey.Registry
+	tokens           *jwtauth.Verifier
+	ipLimits         ratelimit.Groups
+	keyLimits        ratelimit.Groups
+	weatherQuota     *quota.Tracker
+	metrics          *prometheus.Registry
+}
+
+// HandlerOption customizes optional dependencies of HttpHandler
+type HandlerOption func(*HttpHandler)
+
+// WithMunicipalities replaces the bundled municipality dataset
+func WithMunicipalities(dataset *geo.Dataset) HandlerOption {
+	return func(h *HttpHandler) {
+		h.municipalities = dataset
+	}
+}
+
+// WithWebhookStore enables the subscription endpoints, keeping the
+// subscriptions in store
+func WithWebhookStore(store *webhook.Store) HandlerOption {
+	return func(h *HttpHandler) {
+		h.webhookStore = store
+	}
+}
+
+// WithHistoryStore records every weather observation in store and enables
+// the observation history endpoint
+func WithHistoryStore(store *history.Store) HandlerOption {
+	return func(h *HttpHandler) {
+		h.historyStore = store
+	}
+}
+
+// WithAPIKeys requires an API key of keys on the API routes and enables the
+// usage endpoint
+func WithAPIKeys(keys *apikey.Registry) HandlerOption {
+	return func(h *HttpHandler) {
+		h.apiKeys = keys
+	}
+}
+
+// WithBearerTokens accepts bearer tokens of tokens on the API and admin
+// routes, as an alternative to API keys
+func WithBearerTokens(tokens *jwtauth.Verifier) HandlerOption {
+	return func(h *HttpHandler) {
+		h.tokens = tokens
+	}
+}
+
+// WithWeatherQuota reports the WeatherAPI quota on readiness and metrics
+// and, in the cache mode, serves expired observations past its soft limit
+func WithWeatherQuota(tracker *quota.Tracker) HandlerOption {
+	return func(h *HttpHandler) {
+		h.weatherQuota = tracker
+	}
+}
+
+// CloseStreams ends the live temperature streams, which would otherwise keep
+// the server from shutting down
+func (h *HttpHandler) CloseStreams() {
+	h.streams.Close()
+}
+
+// Webhooks returns the subscription manager, nil when webhooks are disabled
+func (h *HttpHandler) Webhooks() *webhook.Manager {
+	return h.webhooks
+}
+
+// History returns the observation recorder, nil when the history is disabled
+func (h *HttpHandler) History() *history.Recorder {
+	return h.history
+}
+
+// Prewarm returns the cache pre-warm scheduler, nil when no job is configured
+func (h *HttpHandler) Prewarm() *prewarm.Scheduler {
+	return h.prewarm
+}
+
+// Service returns the lookups the handlers share with the gRPC API
+func (h *HttpHandler) Service() *service.WeatherService {
+	return h.service
 }
 
 func NewHttpHandler(
 	cfg *config.Config,
 	cepApiClient client.CepClientInterface,
-	weatherApiClient client.WeatherClientInterface) *HttpHandler {
+	weatherApiClient client.WeatherClientInterface,
+	opts ...HandlerOption) *HttpHandler {
 
-	return &HttpHandler{
+	h := &HttpHandler{
 		config:           cfg,
 		cepApiClient:     cepApiClient,
 		weatherApiClient: weatherApiClient,
-		cepRegex:         regexp.MustCompile(`^\d{5}-?\d{3}$`),
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.municipalities == nil {
+		h.municipalities = geo.Default()
+	}
+
+	if h.historyStore != nil {
+		h.history = history.NewRecorder(cfg, h.historyStore, h.municipalities)
+		h.weatherApiClient = h.history.Wrap(weatherApiClient)
+	}
+
+	// The weather cache goes outside the recorder, so only observations
+	// actually fetched are recorded
+	if cfg.CepCacheTTL > 0 {
+		h.cepCache = client.NewCachedCepClient(cepApiClient, cfg.CepCacheTTL)
+		h.cepApiClient = h.cepCache
+	}
+	if cfg.WeatherCacheTTL > 0 {
+		h.weatherCache = client.NewCachedWeatherClient(h.weatherApiClient, cfg.WeatherCacheTTL)
+		h.weatherApiClient = h.weatherCache
+		if h.weatherQuota != nil && cfg.WeatherAPIQuotaSoftMode == config.WeatherQuotaSoftCache {
+			h.weatherCache.ServeStaleWhen(h.weatherQuota.Degraded)
+		}
+	}
+	if h.cepCache != nil && h.weatherCache != nil && (len(cfg.PrewarmCeps) > 0 || cfg.PrewarmTopN > 0) {
+		h.prewarm = prewarm.NewScheduler(cfg, h.cepCache, h.weatherCache)
+	}
+
+	h.service = service.NewWeatherService(cfg, h.cepApiClient, h.weatherApiClient, h.municipalities)
+	h.graphqlExecutor = graphql.NewExecutor(h.service, graphql.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
+	h.streams = stream.NewHub(cfg.StreamPollInterval)
+	h.ipLimits = ratelimit.NewGroups(cfg.RateLimitPerIP, cfg.RateLimitIdleTimeout)
+	h.keyLimits = ratelimit.NewGroups(cfg.RateLimitPerKey, cfg.RateLimitIdleTimeout)
+	if h.webhookStore != nil {
+		h.webhooks = webhook.NewManager(cfg, h.webhookStore, webhook.ObserverFunc(h.service.ObserveFresh))
+	}
+
+	// A registry of its own keeps handlers built by tests from registering
+	// the same metrics twice
+	h.metrics = prometheus.NewRegistry()
+	h.metrics.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	if h.weatherQuota != nil {
+		h.metrics.MustRegister(h.weatherQuota.Collectors()...)
+	}
+
+	return h
 }
@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/quota"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // HealthCheck godoc
@@ -28,7 +30,7 @@ func (h *HttpHandler) HealthCheck(c *gin.Context) {
 
 // ReadinessCheck godoc
 // @Summary Readiness Check
-// @Description Check if the service is ready to accept traffic
+// @Description Check if the service is ready to accept traffic. With the WeatherAPI quota guard the response carries the quota usage, and the status is degraded past its soft limit; the service still takes traffic then
 // @Tags health
 // @Accept json
 // @Produce json
@@ -41,5 +43,24 @@ func (h *HttpHandler) ReadinessCheck(c *gin.Context) {
 		Service:   "lab-cloudrun-api",
 	}
 
+	if h.weatherQuota != nil {
+		usage := h.weatherQuota.Status()
+		response.UpstreamQuota = &usage
+		if usage.State != quota.StateOK {
+			response.Status = "degraded"
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
+
+// Metrics godoc
+// @Summary Metrics
+// @Description Prometheus metrics: Go runtime and process metrics and, with the WeatherAPI quota guard, the quota usage in the current billing period
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "metrics in the Prometheus text format"
+// @Router /metrics [get]
+func (h *HttpHandler) Metrics(c *gin.Context) {
+	promhttp.HandlerFor(h.metrics, promhttp.HandlerOpts{}).ServeHTTP(c.Writer, c.Request)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/quota"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -108,6 +109,55 @@ func (h *HealthHandlerTestSuite) TestReadinessCheck_ResponseContentType() {
 	assert.Contains(h.Suite.T(), w.Header().Get("Content-Type"), "application/json")
 }
 
+func (h *HealthHandlerTestSuite) TestReadinessCheck_WeatherQuotaPastTheSoftLimit() {
+	// arrange
+	tracker := quota.NewTracker("weatherapi", 10, 8, 1)
+	for range 9 {
+		tracker.Take()
+	}
+	handler := NewHttpHandler(h.handler.config, nil, nil, WithWeatherQuota(tracker))
+	h.router.GET("/readiness", handler.ReadinessCheck)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/readiness", nil)
+
+	// act
+	h.router.ServeHTTP(w, req)
+
+	// assert: a degraded service still takes traffic
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+
+	var response model.StatusResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(h.Suite.T(), err)
+
+	assert.Equal(h.Suite.T(), "degraded", response.Status)
+	assert.Equal(h.Suite.T(), "weatherapi", response.UpstreamQuota.Provider)
+	assert.Equal(h.Suite.T(), quota.StateSoftLimited, response.UpstreamQuota.State)
+	assert.Equal(h.Suite.T(), int64(9), response.UpstreamQuota.Used)
+	assert.Equal(h.Suite.T(), int64(10), response.UpstreamQuota.Limit)
+}
+
+func (h *HealthHandlerTestSuite) TestMetrics_WeatherQuota() {
+	// arrange
+	tracker := quota.NewTracker("weatherapi", 10, 8, 1)
+	tracker.Take()
+	handler := NewHttpHandler(h.handler.config, nil, nil, WithWeatherQuota(tracker))
+	h.router.GET("/metrics", handler.Metrics)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+
+	// act
+	h.router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(h.Suite.T(), http.StatusOK, w.Code)
+	assert.Contains(h.Suite.T(), w.Body.String(), `upstream_quota_used_calls{provider="weatherapi"} 1`)
+	assert.Contains(h.Suite.T(), w.Body.String(), `upstream_quota_limit_calls{provider="weatherapi"} 10`)
+	assert.Contains(h.Suite.T(), w.Body.String(), "go_goroutines")
+}
+
 func TestHealthHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(HealthHandlerTestSuite))
 }
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/gin-gonic/gin"
+)
+
+// Where clients send their API key. The query parameter is there for
+// clients that can not set headers, such as browser WebSockets
+const (
+	APIKeyHeader = "X-API-Key"
+	APIKeyQuery  = "api_key"
+)
+
+// APIKeyNameKey is the context key holding the name of the request's API key
+const APIKeyNameKey = "api_key_name"
+
+// quotaKey is the context key holding the quotaCharge of the request's API
+// key or bearer token, which QuotaMiddleware runs
+const quotaKey = "quota_charge"
+
+// quotaCharge counts the request against the quotas of its caller
+type quotaCharge func() (apikey.Decision, error)
+
+// APIKeyMiddleware requires a valid API key allowed on the route. Its quotas
+// are counted by QuotaMiddleware, which goes after the per key rate limit so
+// throttled requests do not use them up. With a nil registry every request
+// passes
+func APIKeyMiddleware(keys *apikey.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keys == nil {
+			c.Next()
+			return
+		}
+
+		key, err := keys.Authenticate(apiKeyOf(c))
+		if err != nil {
+			abortWithAPIKeyError(c, err)
+			return
+		}
+
+		route := routeOf(c)
+		if _, err := keys.AllowN(key, route, 0); err != nil {
+			abortWithAPIKeyError(c, err)
+			return
+		}
+
+		c.Set(APIKeyNameKey, key.Name)
+		c.Set(quotaKey, quotaCharge(func() (apikey.Decision, error) {
+			return keys.Allow(key, route)
+		}))
+		c.Next()
+	}
+}
+
+// QuotaMiddleware counts the request against the quotas of the API key or
+// bearer token APIKeyMiddleware or AuthMiddleware accepted, and reports the
+// quota closest to running out in the X-RateLimit-* headers. Requests
+// without one pass
+func QuotaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(quotaKey)
+		charge, ok := value.(quotaCharge)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		decision, err := charge()
+		setQuotaHeaders(c, decision, err)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminMiddleware requires an admin API key. Admin requests are not counted
+// against quotas. With a nil registry every request passes
+func AdminMiddleware(keys *apikey.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keys == nil {
+			c.Next()
+			return
+		}
+
+		key, err := keys.Authenticate(apiKeyOf(c))
+		if err == nil && !key.Admin {
+			err = apikey.ErrForbidden
+		}
+		if err != nil {
+			abortWithAPIKeyError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setQuotaHeaders reports the quota window of decision, and when it ran out
+// how long until it resets
+func setQuotaHeaders(c *gin.Context, decision apikey.Decision, err error) {
+	if decision.Limit > 0 {
+		c.Header("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(decision.Reset.Unix(), 10))
+	}
+	if errors.Is(err, apikey.ErrQuotaExceeded) || errors.Is(err, jwtauth.ErrQuotaExceeded) {
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(decision.Reset).Seconds())+1))
+	}
+}
+
+func apiKeyOf(c *gin.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	return c.Query(APIKeyQuery)
+}
+
+func abortWithAPIKeyError(c *gin.Context, err error) {
+	if errors.Is(err, apikey.ErrMissing) || errors.Is(err, apikey.ErrInvalid) {
+		c.Header("WWW-Authenticate", `ApiKey header="`+APIKeyHeader+`"`)
+	}
+	_ = c.Error(err)
+	c.Abort()
+}
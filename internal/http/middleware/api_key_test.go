@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	partnerKey = "partner-key-0123456789"
+	opsKey     = "ops-key-0123456789abc"
+)
+
+func setupAPIKeyRouter(keys *apikey.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/temperature/:cep", APIKeyMiddleware(keys), QuotaMiddleware(), ok)
+	r.GET("/api/v1/convert", APIKeyMiddleware(keys), QuotaMiddleware(), ok)
+	r.GET("/admin/usage", AdminMiddleware(keys), ok)
+	return r
+}
+
+func newTestRegistry() *apikey.Registry {
+	return apikey.NewRegistry([]apikey.Key{
+		{Name: "partner", Secret: partnerKey, Routes: []string{"/api/v1/temperature/*"}, DailyQuota: 2},
+		{Name: "ops", Secret: opsKey, Admin: true},
+	})
+}
+
+func serveWithKey(router *gin.Engine, path, key string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyMiddleware_WithoutRegistry(t *testing.T) {
+	// arrange
+	router := setupAPIKeyRouter(nil)
+
+	// act
+	w := serveWithKey(router, "/api/v1/convert", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestAPIKeyMiddleware_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		key        string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "missing key", path: "/api/v1/convert", wantStatus: http.StatusUnauthorized, wantMsg: "api key required: send it in the X-API-Key header or the api_key query parameter"},
+		{name: "unknown key", path: "/api/v1/convert", key: "unknown-key-0123456789", wantStatus: http.StatusUnauthorized, wantMsg: "invalid api key"},
+		{name: "route not allowed", path: "/api/v1/convert", key: partnerKey, wantStatus: http.StatusForbidden, wantMsg: "api key not allowed on this route"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupAPIKeyRouter(newTestRegistry())
+
+			// act
+			w := serveWithKey(router, tt.path, tt.key)
+
+			// assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response model.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantMsg, response.Message)
+		})
+	}
+}
+
+func TestAPIKeyMiddleware_RateLimitHeaders(t *testing.T) {
+	// arrange
+	router := setupAPIKeyRouter(newTestRegistry())
+
+	// act
+	first := serveWithKey(router, "/api/v1/temperature/01001000", partnerKey)
+	serveWithKey(router, "/api/v1/temperature/01001000", partnerKey)
+	exceeded := serveWithKey(router, "/api/v1/temperature/01001000", partnerKey)
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+	_, err := strconv.ParseInt(first.Header().Get("X-RateLimit-Reset"), 10, 64)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusTooManyRequests, exceeded.Code)
+	assert.Equal(t, "0", exceeded.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, exceeded.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"api key quota exceeded"}`, exceeded.Body.String())
+}
+
+func TestAPIKeyMiddleware_QueryParameter(t *testing.T) {
+	// arrange
+	router := setupAPIKeyRouter(newTestRegistry())
+
+	// act
+	w := serveWithKey(router, "/api/v1/convert?api_key="+opsKey, "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAPIKeyMiddleware_LocalizedError(t *testing.T) {
+	// arrange
+	router := setupAPIKeyRouter(newTestRegistry())
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/convert", nil)
+	req.Header.Set("Accept-Language", "pt-BR")
+
+	// act
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `ApiKey header="X-API-Key"`, w.Header().Get("WWW-Authenticate"))
+	assert.Contains(t, w.Body.String(), "chave de API obrigatória")
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	// arrange
+	router := setupAPIKeyRouter(newTestRegistry())
+
+	// act
+	admin := serveWithKey(router, "/admin/usage", opsKey)
+	notAdmin := serveWithKey(router, "/admin/usage", partnerKey)
+	missing := serveWithKey(router, "/admin/usage", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, admin.Code)
+	assert.Empty(t, admin.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusForbidden, notAdmin.Code)
+	assert.Equal(t, http.StatusUnauthorized, missing.Code)
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/gin-gonic/gin"
+)
+
+// BearerQuery carries the bearer token of clients that can not set the
+// Authorization header, such as browser EventSource and WebSockets
+const BearerQuery = "access_token"
+
+// BearerSubjectPrefix sets the subjects of bearer tokens apart from API key
+// names in APIKeyNameKey, which the per key rate limits are keyed by
+const BearerSubjectPrefix = "jwt:"
+
+// AuthMiddleware accepts a bearer token of tokens allowed on the route, as an
+// alternative to an API key of keys, leaving the quotas of its subject to
+// QuotaMiddleware. Requests that send an API key and no token go through
+// APIKeyMiddleware. With a nil verifier only API keys are checked
+func AuthMiddleware(keys *apikey.Registry, tokens *jwtauth.Verifier) gin.HandlerFunc {
+	apiKey := APIKeyMiddleware(keys)
+
+	return func(c *gin.Context) {
+		token := bearerOf(c)
+		if tokens == nil || (token == "" && keys != nil && apiKeyOf(c) != "") {
+			apiKey(c)
+			return
+		}
+
+		principal, err := tokens.Verify(c.Request.Context(), token)
+		if err == nil {
+			err = tokens.Allow(principal, routeOf(c))
+		}
+		if err != nil {
+			abortWithBearerError(c, keys, err)
+			return
+		}
+
+		c.Set(APIKeyNameKey, BearerSubjectPrefix+principal.Subject)
+		c.Set(quotaKey, quotaCharge(func() (apikey.Decision, error) {
+			return tokens.Charge(principal, 1)
+		}))
+		c.Next()
+	}
+}
+
+// AdminAuthMiddleware accepts a bearer token with the admin scope, as an
+// alternative to an admin API key
+func AdminAuthMiddleware(keys *apikey.Registry, tokens *jwtauth.Verifier) gin.HandlerFunc {
+	admin := AdminMiddleware(keys)
+
+	return func(c *gin.Context) {
+		token := bearerOf(c)
+		if tokens == nil || (token == "" && keys != nil && apiKeyOf(c) != "") {
+			admin(c)
+			return
+		}
+
+		principal, err := tokens.Verify(c.Request.Context(), token)
+		if err == nil {
+			err = tokens.AllowAdmin(principal)
+		}
+		if err != nil {
+			abortWithBearerError(c, keys, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bearerOf returns the token of an "Authorization: Bearer" header or of the
+// access_token query parameter
+func bearerOf(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return c.Query(BearerQuery)
+}
+
+// routeOf is the gin route path, which scopes are matched against
+func routeOf(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return c.Request.URL.Path
+}
+
+// abortWithBearerError challenges the client as RFC 6750 describes, offering
+// API keys too when they are accepted
+func abortWithBearerError(c *gin.Context, keys *apikey.Registry, err error) {
+	switch {
+	case errors.Is(err, jwtauth.ErrMissing):
+		c.Writer.Header().Add("WWW-Authenticate", `Bearer`)
+		if keys != nil {
+			c.Writer.Header().Add("WWW-Authenticate", `ApiKey header="`+APIKeyHeader+`"`)
+		}
+	case errors.Is(err, jwtauth.ErrForbidden):
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+	default:
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BearerMiddlewareTestSuite struct {
+	suite.Suite
+	key    *rsa.PrivateKey
+	jwks   *httptest.Server
+	cfg    config.Config
+	tokens *jwtauth.Verifier
+}
+
+func (s *BearerMiddlewareTestSuite) SetupSuite() {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	s.jwks = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "test", "use": "sig", "n": encode(s.key.N), "e": encode(big.NewInt(int64(s.key.E)))},
+		}})
+	}))
+
+	s.cfg = config.Config{
+		JWTJWKSURL:             s.jwks.URL,
+		JWTIssuer:              "https://auth.example.com/",
+		JWTAudience:            []string{"lab-cloudrun"},
+		JWTAlgorithms:          []string{"RS256"},
+		JWTJWKSRefreshInterval: time.Hour,
+		JWTScopeRoutes:         map[string][]string{"weather:read": {"/api/v1/temperature/*"}},
+		JWTAdminScope:          "admin",
+	}
+	s.tokens, err = jwtauth.Load(&s.cfg)
+	require.NoError(s.T(), err)
+}
+
+func (s *BearerMiddlewareTestSuite) TearDownSuite() {
+	s.jwks.Close()
+}
+
+func (s *BearerMiddlewareTestSuite) token(scope string, expiresIn time.Duration) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   "https://auth.example.com/",
+		"aud":   "lab-cloudrun",
+		"sub":   "web-app",
+		"exp":   time.Now().Add(expiresIn).Unix(),
+		"scope": scope,
+	})
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(s.key)
+	require.NoError(s.T(), err)
+	return signed
+}
+
+func (s *BearerMiddlewareTestSuite) router(keys *apikey.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+
+	ok := func(c *gin.Context) { c.String(http.StatusOK, c.GetString(APIKeyNameKey)) }
+	r.GET("/api/v1/temperature/:cep", AuthMiddleware(keys, s.tokens), QuotaMiddleware(), ok)
+	r.GET("/api/v1/convert", AuthMiddleware(keys, s.tokens), QuotaMiddleware(), ok)
+	r.GET("/admin/usage", AdminAuthMiddleware(keys, s.tokens), ok)
+	return r
+}
+
+func serveWithBearer(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *BearerMiddlewareTestSuite) TestAuthMiddleware_ValidToken() {
+	// act
+	w := serveWithBearer(s.router(nil), "/api/v1/temperature/01001000", s.token("weather:read", time.Hour))
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Equal(s.T(), BearerSubjectPrefix+"web-app", w.Body.String())
+}
+
+func (s *BearerMiddlewareTestSuite) TestAuthMiddleware_TokenInQuery() {
+	// act
+	w := serveWithBearer(s.router(nil), "/api/v1/temperature/01001000?"+BearerQuery+"="+s.token("weather:read", time.Hour), "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *BearerMiddlewareTestSuite) TestAuthMiddleware_Errors() {
+	tests := []struct {
+		name      string
+		path      string
+		token     string
+		status    int
+		message   string
+		challenge string
+	}{
+		{"missing", "/api/v1/temperature/01001000", "", http.StatusUnauthorized, "authentication required", "Bearer"},
+		{"invalid", "/api/v1/temperature/01001000", "not-a-token", http.StatusUnauthorized, "invalid bearer token", `Bearer error="invalid_token"`},
+		{"expired", "/api/v1/temperature/01001000", s.token("weather:read", -time.Hour), http.StatusUnauthorized, "bearer token expired", `Bearer error="invalid_token"`},
+		{"scope without the route", "/api/v1/convert", s.token("weather:read", time.Hour), http.StatusForbidden, "bearer token not allowed on this route", `Bearer error="insufficient_scope"`},
+		{"admin without the admin scope", "/admin/usage", s.token("weather:read", time.Hour), http.StatusForbidden, "bearer token not allowed on this route", `Bearer error="insufficient_scope"`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := serveWithBearer(s.router(nil), tt.path, tt.token)
+
+			// assert
+			assert.Equal(s.T(), tt.status, w.Code)
+			assert.Equal(s.T(), tt.challenge, w.Header().Get("WWW-Authenticate"))
+
+			var response model.ErrorResponse
+			require.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(s.T(), tt.message, response.Message)
+		})
+	}
+}
+
+func (s *BearerMiddlewareTestSuite) TestAuthMiddleware_SubjectQuota() {
+	// arrange
+	cfg := s.cfg
+	cfg.JWTDailyQuota = 1
+	tokens, err := jwtauth.Load(&cfg)
+	require.NoError(s.T(), err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+	router.GET("/api/v1/temperature/:cep", AuthMiddleware(nil, tokens), QuotaMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	token := s.token("weather:read", time.Hour)
+
+	// act
+	first := serveWithBearer(router, "/api/v1/temperature/01001000", token)
+	second := serveWithBearer(router, "/api/v1/temperature/01001000", token)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, first.Code)
+	assert.Equal(s.T(), "1", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(s.T(), "0", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(s.T(), http.StatusTooManyRequests, second.Code)
+	assert.NotEmpty(s.T(), second.Header().Get("Retry-After"))
+	assert.Empty(s.T(), second.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(s.T(), `{"message": "bearer token quota exceeded"}`, second.Body.String())
+}
+
+func (s *BearerMiddlewareTestSuite) TestAdminAuthMiddleware_AdminScope() {
+	// act
+	w := serveWithBearer(s.router(nil), "/admin/usage", s.token("weather:read admin", time.Hour))
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *BearerMiddlewareTestSuite) TestAuthMiddleware_APIKeyStillAccepted() {
+	// arrange
+	router := s.router(newTestRegistry())
+
+	// act
+	withKey := serveWithKey(router, "/api/v1/temperature/01001000", partnerKey)
+	without := serveWithKey(router, "/api/v1/temperature/01001000", "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, withKey.Code)
+	assert.Equal(s.T(), "partner", withKey.Body.String())
+	assert.Equal(s.T(), http.StatusUnauthorized, without.Code)
+	assert.Equal(s.T(), []string{"Bearer", `ApiKey header="X-API-Key"`}, without.Header().Values("WWW-Authenticate"))
+}
+
+func TestBearerMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(BearerMiddlewareTestSuite))
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// ContentNegotiationMiddleware picks the response format from Accept and
+// stores it for render.Render, answering 406 Not Acceptable in JSON when
+// none of the requested media types is supported
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		format, ok := render.Negotiate(c.GetHeader("Accept"))
+		if !ok {
+			supported := make([]string, 0, len(render.Supported()))
+			for _, f := range render.Supported() {
+				supported = append(supported, string(f))
+			}
+
+			lang := i18n.FromContext(c.Request.Context())
+			c.AbortWithStatusJSON(http.StatusNotAcceptable, model.ErrorResponse{
+				Message: lang.Text("error.not_acceptable", strings.Join(supported, ", ")),
+			})
+			return
+		}
+
+		render.SetFormat(c, format)
+		c.Next()
+	}
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// corsPolicy is the CORS configuration, with the origins split into exact
+// origins and wildcard subdomains and the answers joined ahead of time
+type corsPolicy struct {
+	anyOrigin bool
+	origins   map[string]bool
+	wildcards []wildcardOrigin
+	methods   []string
+	headers   []string
+
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+// wildcardOrigin matches the subdomains of an origin such as
+// https://*.example.com: the scheme before the * and the domain, with the
+// port, after it
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+func newCORSPolicy(cfg *config.Config) *corsPolicy {
+	policy := &corsPolicy{
+		origins:       make(map[string]bool),
+		methods:       cfg.CORSAllowedMethods,
+		allowMethods:  strings.Join(cfg.CORSAllowedMethods, ", "),
+		allowHeaders:  strings.Join(cfg.CORSAllowedHeaders, ", "),
+		exposeHeaders: strings.Join(cfg.CORSExposedHeaders, ", "),
+		credentials:   cfg.CORSAllowCredentials,
+		maxAge:        strconv.Itoa(int(cfg.CORSMaxAge.Seconds())),
+	}
+	for _, header := range cfg.CORSAllowedHeaders {
+		policy.headers = append(policy.headers, strings.ToLower(header))
+	}
+
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if origin == "*" {
+			policy.anyOrigin = true
+		} else if prefix, suffix, ok := strings.Cut(origin, "://*."); ok {
+			policy.wildcards = append(policy.wildcards, wildcardOrigin{prefix: prefix + "://", suffix: "." + suffix})
+		} else {
+			policy.origins[origin] = true
+		}
+	}
+	return policy
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin answer to origin, or
+// "" when the origin is not allowed
+func (p *corsPolicy) allowOrigin(origin string) string {
+	if p.anyOrigin && !p.credentials {
+		return "*"
+	}
+
+	lower := strings.ToLower(origin)
+	if p.anyOrigin || p.origins[lower] {
+		return origin
+	}
+	for _, wildcard := range p.wildcards {
+		if wildcard.match(lower) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// match checks that origin is the wildcard with one or more subdomain labels
+// in place of the *
+func (w wildcardOrigin) match(origin string) bool {
+	if len(origin) <= len(w.prefix)+len(w.suffix) || !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+		return false
+	}
+
+	subdomain := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+	if strings.HasPrefix(subdomain, ".") || strings.HasSuffix(subdomain, ".") {
+		return false
+	}
+	for _, r := range subdomain {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// allowRequest checks the method and the headers a preflight request asks
+// for
+func (p *corsPolicy) allowRequest(method, headers string) bool {
+	if !slices.Contains(p.methods, method) {
+		return false
+	}
+	for header := range strings.SplitSeq(headers, ",") {
+		if header = strings.ToLower(strings.TrimSpace(header)); header != "" && !slices.Contains(p.headers, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// CORSMiddleware lets browser clients on the origins of cfg call the API.
+// Preflight requests are answered here, before routing, API keys and rate
+// limits: 204 with the allowed methods and headers, or 403 when the origin,
+// the method or a header is not allowed. Other requests from allowed origins
+// get Access-Control-Allow-Origin, errors included, so scripts can read
+// them. Without allowed origins CORS is off
+func CORSMiddleware(cfg *config.Config) gin.HandlerFunc {
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	policy := newCORSPolicy(cfg)
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+
+		requestMethod := c.GetHeader("Access-Control-Request-Method")
+		preflight := c.Request.Method == http.MethodOptions && requestMethod != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		allowed := policy.allowOrigin(origin)
+		if preflight {
+			if allowed == "" || !policy.allowRequest(strings.ToUpper(requestMethod), c.GetHeader("Access-Control-Request-Headers")) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Origin", allowed)
+			header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			if policy.allowHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+			}
+			if policy.credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			header.Set("Access-Control-Max-Age", policy.maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if allowed != "" {
+			header.Set("Access-Control-Allow-Origin", allowed)
+			if policy.credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if policy.exposeHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func corsConfig(origins ...string) *config.Config {
+	return &config.Config{
+		CORSAllowedOrigins: origins,
+		CORSAllowedMethods: []string{"GET", "POST"},
+		CORSAllowedHeaders: []string{"Content-Type", "X-API-Key"},
+		CORSExposedHeaders: []string{"ETag", "Retry-After"},
+		CORSMaxAge:         10 * time.Minute,
+	}
+}
+
+func setupCORSRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORSMiddleware(cfg))
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func serveCORS(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/test", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	// arrange
+	router := setupCORSRouter(corsConfig("https://app.example.com"))
+
+	// act
+	w := serveCORS(router, http.MethodOptions, "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "content-type, x-api-key",
+	})
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, X-API-Key", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, w.Header().Values("Vary"))
+}
+
+func TestCORSMiddleware_RejectedPreflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+	}{
+		{"unknown origin", "https://evil.example.org", "GET", ""},
+		{"other scheme", "http://app.example.com", "GET", ""},
+		{"method", "https://app.example.com", "DELETE", ""},
+		{"header", "https://app.example.com", "GET", "content-type, x-custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupCORSRouter(corsConfig("https://app.example.com"))
+
+			// act
+			w := serveCORS(router, http.MethodOptions, tt.origin, map[string]string{
+				"Access-Control-Request-Method":  tt.method,
+				"Access-Control-Request-Headers": tt.headers,
+			})
+
+			// assert
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+func TestCORSMiddleware_Origins(t *testing.T) {
+	tests := []struct {
+		name     string
+		origins  []string
+		origin   string
+		expected string
+	}{
+		{"exact", []string{"https://app.example.com"}, "https://app.example.com", "https://app.example.com"},
+		{"exact with port", []string{"http://localhost:3000"}, "http://localhost:3000", "http://localhost:3000"},
+		{"other port", []string{"http://localhost:3000"}, "http://localhost:5173", ""},
+		{"wildcard subdomain", []string{"https://*.example.com"}, "https://app.example.com", "https://app.example.com"},
+		{"wildcard nested subdomain", []string{"https://*.example.com"}, "https://eu.app.example.com", "https://eu.app.example.com"},
+		{"wildcard without subdomain", []string{"https://*.example.com"}, "https://example.com", ""},
+		{"wildcard other domain", []string{"https://*.example.com"}, "https://app.badexample.com", ""},
+		{"wildcard other scheme", []string{"https://*.example.com"}, "http://app.example.com", ""},
+		{"wildcard with port", []string{"https://*.example.com:8443"}, "https://app.example.com:8443", "https://app.example.com:8443"},
+		{"wildcard suffix trick", []string{"https://*.example.com"}, "https://evil.com/.example.com", ""},
+		{"any", []string{"*"}, "https://anything.example.org", "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupCORSRouter(corsConfig(tt.origins...))
+
+			// act
+			w := serveCORS(router, http.MethodGet, tt.origin, nil)
+
+			// assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+func TestCORSMiddleware_ActualRequest(t *testing.T) {
+	// arrange
+	cfg := corsConfig("https://app.example.com")
+	cfg.CORSAllowCredentials = true
+	router := setupCORSRouter(cfg)
+
+	// act
+	w := serveCORS(router, http.MethodGet, "https://app.example.com", nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "ETag, Retry-After", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+}
+
+func TestCORSMiddleware_Disabled(t *testing.T) {
+	// arrange
+	router := setupCORSRouter(corsConfig())
+
+	// act
+	w := serveCORS(router, http.MethodGet, "https://app.example.com", nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Values("Vary"))
+}
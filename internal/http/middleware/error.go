@@ -4,12 +4,48 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/alexduzi/labcloudrun/internal/apikey"
 	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
 	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/jwtauth"
 	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/ratelimit"
+	"github.com/alexduzi/labcloudrun/internal/webhook"
 	"github.com/gin-gonic/gin"
 )
 
+// invalidQueryMessages maps invalid query parameter errors to their message keys
+var invalidQueryMessages = map[error]string{
+	hErrors.AddressUFInvalid:        "error.uf_invalid",
+	hErrors.AddressCityTooShort:     "error.city_too_short",
+	hErrors.AddressStreetTooShort:   "error.street_too_short",
+	hErrors.PaginationInvalid:       "error.pagination_invalid",
+	hErrors.CoordinatesInvalid:      "error.coordinates_invalid",
+	hErrors.CoordinatesOutOfBounds:  "error.coordinates_out_of_bounds",
+	hErrors.ObservationRangeInvalid: "error.observation_range_invalid",
+}
+
+// apiKeyErrors maps the API key, bearer token and rate limit errors to their statuses and message keys
+var apiKeyErrors = []struct {
+	target error
+	status int
+	key    string
+}{
+	{apikey.ErrMissing, http.StatusUnauthorized, "error.api_key_missing"},
+	{apikey.ErrInvalid, http.StatusUnauthorized, "error.api_key_invalid"},
+	{apikey.ErrForbidden, http.StatusForbidden, "error.api_key_forbidden"},
+	{apikey.ErrQuotaExceeded, http.StatusTooManyRequests, "error.api_key_quota_exceeded"},
+	{jwtauth.ErrMissing, http.StatusUnauthorized, "error.bearer_token_missing"},
+	{jwtauth.ErrExpired, http.StatusUnauthorized, "error.bearer_token_expired"},
+	{jwtauth.ErrInvalid, http.StatusUnauthorized, "error.bearer_token_invalid"},
+	{jwtauth.ErrForbidden, http.StatusForbidden, "error.bearer_token_forbidden"},
+	{jwtauth.ErrQuotaExceeded, http.StatusTooManyRequests, "error.bearer_token_quota_exceeded"},
+	{ratelimit.ErrLimited, http.StatusTooManyRequests, "error.rate_limited"},
+}
+
 func ErrorHandlerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -17,24 +53,146 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 		// Only handle errors if response hasn't been written yet
 		if len(c.Errors) > 0 && !c.Writer.Written() {
 			err := c.Errors.Last().Err
+			lang := i18n.FromContext(c.Request.Context())
 
 			if errors.Is(err, hErrors.CepParamNotExists) {
-				c.JSON(http.StatusNotFound, model.ErrorResponse{
-					Message: "can not find zipcode",
+				render.Render(c, http.StatusNotFound, model.ErrorResponse{
+					Message: lang.Text("error.cep_not_found"),
 				})
 				return
 			}
 
 			if errors.Is(err, hErrors.CepCantFind) {
-				c.JSON(http.StatusNotFound, model.ErrorResponse{
-					Message: "can not find zipcode",
+				render.Render(c, http.StatusNotFound, model.ErrorResponse{
+					Message: lang.Text("error.cep_not_found"),
 				})
 				return
 			}
 
 			if errors.Is(err, hErrors.CepInvalid) {
-				c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{
-					Message: "invalid zipcode",
+				render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+					Message: lang.Text("error.cep_invalid"),
+				})
+				return
+			}
+
+			if errors.Is(err, hErrors.CepUFMismatch) {
+				render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+					Message: lang.Text("error.cep_uf_mismatch"),
+				})
+				return
+			}
+
+			// Handle invalid query parameters
+			for target, key := range invalidQueryMessages {
+				if errors.Is(err, target) {
+					render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+						Message: lang.Text(key),
+					})
+					return
+				}
+			}
+
+			if errors.Is(err, hErrors.ConvertRequestInvalid) {
+				render.Render(c, http.StatusBadRequest, model.ErrorResponse{
+					Message: lang.Text("error.convert_request_invalid"),
+				})
+				return
+			}
+
+			if errors.Is(err, hErrors.SubscriptionRequestInvalid) {
+				render.Render(c, http.StatusBadRequest, model.ErrorResponse{
+					Message: lang.Text("error.subscription_request_invalid"),
+				})
+				return
+			}
+
+			// Handle webhook subscription errors
+			if errors.Is(err, webhook.ErrNotFound) {
+				render.Render(c, http.StatusNotFound, model.ErrorResponse{
+					Message: lang.Text("error.subscription_not_found"),
+				})
+				return
+			}
+
+			if errors.Is(err, webhook.ErrConditionInvalid) {
+				render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+					Message: lang.Text("error.subscription_condition_invalid"),
+				})
+				return
+			}
+
+			if errors.Is(err, webhook.ErrURLInvalid) {
+				render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+					Message: lang.Text("error.subscription_url_invalid"),
+				})
+				return
+			}
+
+			if errors.Is(err, webhook.ErrLimitReached) {
+				render.Render(c, http.StatusConflict, model.ErrorResponse{
+					Message: lang.Text("error.subscription_limit_reached"),
+				})
+				return
+			}
+
+			if errors.Is(err, hErrors.SubscriptionsDisabled) {
+				render.Render(c, http.StatusNotImplemented, model.ErrorResponse{
+					Message: lang.Text("error.subscriptions_disabled"),
+				})
+				return
+			}
+
+			// Handle API key and rate limit errors
+			for _, mapping := range apiKeyErrors {
+				if errors.Is(err, mapping.target) {
+					render.Render(c, mapping.status, model.ErrorResponse{
+						Message: lang.Text(mapping.key),
+					})
+					return
+				}
+			}
+
+			if errors.Is(err, hErrors.APIKeysDisabled) {
+				render.Render(c, http.StatusNotImplemented, model.ErrorResponse{
+					Message: lang.Text("error.api_keys_disabled"),
+				})
+				return
+			}
+
+			if errors.Is(err, hErrors.HistoryDisabled) {
+				render.Render(c, http.StatusNotImplemented, model.ErrorResponse{
+					Message: lang.Text("error.history_disabled"),
+				})
+				return
+			}
+
+			// Handle unit conversion errors
+			if message, ok := conversionMessage(lang, err); ok {
+				render.Render(c, http.StatusUnprocessableEntity, model.ErrorResponse{
+					Message: message,
+				})
+				return
+			}
+
+			if errors.Is(err, cErrors.CepSearchUnsupported) {
+				render.Render(c, http.StatusNotImplemented, model.ErrorResponse{
+					Message: lang.Text("error.cep_search_unsupported"),
+				})
+				return
+			}
+
+			// Handle the upstream quota before the errors of the fallback
+			if errors.Is(err, cErrors.WeatherQuotaExhausted) {
+				render.Render(c, http.StatusServiceUnavailable, model.ErrorResponse{
+					Message: lang.Text("error.weather_quota_exhausted"),
+				})
+				return
+			}
+
+			if errors.Is(err, cErrors.WeatherQuotaSoftLimited) {
+				render.Render(c, http.StatusServiceUnavailable, model.ErrorResponse{
+					Message: lang.Text("error.weather_quota_soft_limited"),
 				})
 				return
 			}
@@ -44,8 +202,8 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 				errors.Is(err, cErrors.CepClientNotFound) ||
 				errors.Is(err, cErrors.CepClientInternalError) ||
 				errors.Is(err, cErrors.CepClientUnexpectedError) {
-				c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-					Message: "internal server error",
+				render.Render(c, http.StatusInternalServerError, model.ErrorResponse{
+					Message: lang.Text("error.internal"),
 				})
 				return
 			}
@@ -55,16 +213,49 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 				errors.Is(err, cErrors.WeatherClientNotFound) ||
 				errors.Is(err, cErrors.WeatherClientInternalError) ||
 				errors.Is(err, cErrors.WeatherClientUnexpectedError) {
-				c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-					Message: "internal server error",
+				render.Render(c, http.StatusInternalServerError, model.ErrorResponse{
+					Message: lang.Text("error.internal"),
 				})
 				return
 			}
 
 			// Handle unexpected errors
-			c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-				Message: "internal server error",
+			render.Render(c, http.StatusInternalServerError, model.ErrorResponse{
+				Message: lang.Text("error.internal"),
 			})
 		}
 	}
 }
+
+// conversionMessage localizes the errors of the conversor package
+func conversionMessage(lang i18n.Lang, err error) (string, bool) {
+	var (
+		unitErr     *conversor.UnitError
+		quantityErr *conversor.QuantityError
+		rangeErr    *conversor.RangeError
+	)
+
+	quantity := func(q conversor.Quantity) string {
+		return lang.Text("quantity." + string(q))
+	}
+
+	switch {
+	case errors.As(err, &unitErr):
+		return lang.Text("error.unknown_unit", unitErr.Unit), true
+	case errors.As(err, &quantityErr):
+		return lang.Text("error.incompatible_units",
+			quantityErr.From.Symbol, quantity(quantityErr.From.Quantity),
+			quantityErr.To.Symbol, quantity(quantityErr.To.Quantity)), true
+	case errors.As(err, &rangeErr) && !rangeErr.Finite():
+		return lang.Text("error.value_not_finite", rangeErr.Value), true
+	case errors.As(err, &rangeErr) && rangeErr.Below():
+		return lang.Text("error.value_below", quantity(rangeErr.Unit.Quantity), rangeErr.Unit.Min, rangeErr.Unit.Symbol), true
+	case errors.As(err, &rangeErr):
+		return lang.Text("error.value_above", quantity(rangeErr.Unit.Quantity), rangeErr.Unit.Max, rangeErr.Unit.Symbol), true
+	case errors.Is(err, conversor.ErrUnknownUnit),
+		errors.Is(err, conversor.ErrIncompatibleUnits),
+		errors.Is(err, conversor.ErrValueOutOfRange):
+		return err.Error(), true
+	}
+	return "", false
+}
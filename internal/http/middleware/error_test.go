@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
 	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
 	"github.com/alexduzi/labcloudrun/internal/model"
 	"github.com/gin-gonic/gin"
@@ -92,6 +95,141 @@ func TestErrorHandlerMiddleware_ZipCodeInvalid(t *testing.T) {
 	assert.Equal(t, "invalid zipcode", response.Message)
 }
 
+func TestErrorHandlerMiddleware_ZipCodeUFMismatch(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/test", func(c *gin.Context) {
+		_ = c.Error(hErrors.CepUFMismatch)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "zipcode does not match its state", response.Message)
+}
+
+func TestErrorHandlerMiddleware_InvalidQuery(t *testing.T) {
+	searchErrors := []error{
+		hErrors.AddressUFInvalid,
+		hErrors.AddressCityTooShort,
+		hErrors.AddressStreetTooShort,
+		hErrors.PaginationInvalid,
+		hErrors.CoordinatesInvalid,
+		hErrors.CoordinatesOutOfBounds,
+	}
+
+	for _, searchErr := range searchErrors {
+		t.Run(searchErr.Error(), func(t *testing.T) {
+			router := setupTestRouter()
+			router.GET("/test", func(c *gin.Context) {
+				_ = c.Error(searchErr)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, searchErr.Error(), response.Message)
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_ConvertErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{hErrors.ConvertRequestInvalid, http.StatusBadRequest},
+		{&conversor.UnitError{Unit: "parsec"}, http.StatusUnprocessableEntity},
+		{conversor.ErrIncompatibleUnits, http.StatusUnprocessableEntity},
+		{conversor.ErrValueOutOfRange, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			router := setupTestRouter()
+			router.GET("/test", func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.err.Error(), response.Message)
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_CepSearchUnsupported(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/test", func(c *gin.Context) {
+		_ = c.Error(cErrors.CepSearchUnsupported)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "address search is not available offline", response.Message)
+}
+
+func TestErrorHandlerMiddleware_WeatherQuotaExhausted(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/test", func(c *gin.Context) {
+		_ = c.Error(errors.Join(cErrors.WeatherQuotaExhausted, cErrors.WeatherClientInternalError))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "weather data is temporarily unavailable, the upstream quota is exhausted", response.Message)
+}
+
+func TestErrorHandlerMiddleware_WeatherQuotaSoftLimited(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/test", func(c *gin.Context) {
+		_ = c.Error(cErrors.WeatherQuotaSoftLimited)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response model.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "weather data for this location is not cached and the upstream quota is being saved, try again later", response.Message)
+}
+
 func TestErrorHandlerMiddleware_ZipCodeParamNotExists(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/test", func(c *gin.Context) {
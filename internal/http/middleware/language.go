@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageMiddleware negotiates the response language from Accept-Language
+// and stores it in the request context, where handlers, ErrorHandlerMiddleware
+// and the weather clients read it with i18n.FromContext
+func LanguageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		c.Request = c.Request.WithContext(i18n.WithLang(c.Request.Context(), lang))
+		c.Header("Content-Language", string(lang))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
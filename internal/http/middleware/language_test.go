@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLocalizedRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+	r.GET("/test", handler)
+	return r
+}
+
+func TestLanguageMiddleware_StoresLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected i18n.Lang
+	}{
+		{"", i18n.English},
+		{"pt-BR,pt;q=0.9", i18n.Portuguese},
+		{"es-MX", i18n.Spanish},
+		{"de", i18n.English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var lang i18n.Lang
+			router := setupLocalizedRouter(func(c *gin.Context) {
+				lang = i18n.FromContext(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Accept-Language", tt.header)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, lang)
+			assert.Equal(t, string(tt.expected), w.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_Localized(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		lang     string
+		expected string
+	}{
+		{"cep pt-BR", hErrors.CepCantFind, "pt-BR", "CEP não encontrado"},
+		{"cep es", hErrors.CepCantFind, "es", "no se encuentra el código postal"},
+		{"cep en", hErrors.CepCantFind, "en", "can not find zipcode"},
+		{"query pt-BR", hErrors.CoordinatesInvalid, "pt-BR", "coordenadas inválidas"},
+		{"second choice es", hErrors.CepParamNotExists, "fr,es;q=0.5", "no se encuentra el código postal"},
+		{"unknown unit pt-BR", &conversor.UnitError{Unit: "parsec"}, "pt-BR", `unidade desconhecida "parsec"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupLocalizedRouter(func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Accept-Language", tt.lang)
+			router.ServeHTTP(w, req)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, response.Message)
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_LocalizedConversion(t *testing.T) {
+	_, incompatible := conversor.Convert(1, "C", "mph")
+	_, below := conversor.Convert(-300, "C", "F")
+	_, above := conversor.Convert(13, "bft", "kph")
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{incompatible, "as unidades medem grandezas diferentes: C é temperatura, mph é velocidade"},
+		{below, "valor fora do intervalo: temperatura deve ser no mínimo -273.15 C"},
+		{above, "valor fora do intervalo: velocidade deve ser no máximo 12 bft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			router := setupLocalizedRouter(func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Accept-Language", "pt-BR")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, response.Message)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// secretQueries are the query parameters carrying credentials, which must
+// never reach the request log
+var secretQueries = []string{APIKeyQuery, BearerQuery}
+
+// LoggerMiddleware logs each request to out as gin's default logger does,
+// with the values of the credential query parameters redacted
+func LoggerMiddleware(out io.Writer) gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{Output: out, Formatter: redactedLogFormatter})
+}
+
+// redactedLogFormatter is gin's default log format over the redacted path
+func redactedLogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactQuery(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactQuery replaces the values of the secret query parameters of path,
+// leaving the rest of the query as sent
+func redactQuery(path string) string {
+	path, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if slices.Contains(secretQueries, name) {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerMiddleware_RedactsCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"api key", "api_key=partner-key-0123456789&lang=pt", "/test?api_key=REDACTED&lang=pt"},
+		{"bearer token", "format=sse&access_token=eyJhbGciOiJSUzI1NiJ9.payload.signature", "/test?format=sse&access_token=REDACTED"},
+		{"escaped name", "api%5Fkey=partner-key-0123456789", "/test?api_key=REDACTED"},
+		{"repeated", "api_key=first-secret-key&api_key=second-secret-key", "/test?api_key=REDACTED&api_key=REDACTED"},
+		{"other parameters", "lat=-23.55&lon=-46.63", "/test?lat=-23.55&lon=-46.63"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			gin.SetMode(gin.TestMode)
+			var out bytes.Buffer
+			r := gin.New()
+			r.Use(LoggerMiddleware(&out))
+			r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			// act
+			req, _ := http.NewRequest(http.MethodGet, "/test?"+tt.query, nil)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			// assert
+			assert.Contains(t, out.String(), `"`+tt.expected+`"`)
+			assert.NotContains(t, out.String(), "secret")
+			assert.NotContains(t, out.String(), "0123456789")
+			assert.NotContains(t, out.String(), "signature")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware takes a token from the bucket of the client of each
+// request, refusing it when the bucket is empty. The RateLimit headers
+// describe the bucket closest to running out among the limiters applied.
+// Requests without a client, or with a nil limiter, pass
+func RateLimitMiddleware(limiter *ratelimit.Limiter, client func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := client(c)
+		if limiter == nil || id == "" {
+			c.Next()
+			return
+		}
+
+		result := limiter.Allow(id)
+		writeRateLimitHeaders(c, result)
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			_ = c.Error(ratelimit.ErrLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ClientIP identifies clients by IP address, read from X-Forwarded-For only
+// when the request comes from a trusted proxy
+func ClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// APIKeyName identifies clients by the name of their API key, set by
+// APIKeyMiddleware
+func APIKeyName(c *gin.Context) string {
+	return c.GetString(APIKeyNameKey)
+}
+
+// writeRateLimitHeaders sets the RateLimit headers of the IETF draft, unless
+// a previous limiter left fewer requests
+func writeRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	header := c.Writer.Header()
+	if previous, err := strconv.Atoi(header.Get("RateLimit-Remaining")); err == nil && previous <= result.Remaining {
+		return
+	}
+
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	header.Set("RateLimit-Policy", strconv.Itoa(result.Limit)+";w="+strconv.Itoa(seconds(result.Window)))
+}
+
+// seconds rounds d up to whole seconds, as the headers need
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
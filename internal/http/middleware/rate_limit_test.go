@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRateLimitRouter(byIP, byKey *ratelimit.Limiter, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	_ = r.SetTrustedProxies(trustedProxies)
+	r.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "ops", Secret: opsKey}})
+	r.GET("/test",
+		RateLimitMiddleware(byIP, ClientIP),
+		APIKeyMiddleware(keys),
+		RateLimitMiddleware(byKey, APIKeyName),
+		QuotaMiddleware(),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func serveFrom(router *gin.Engine, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set(APIKeyHeader, opsKey)
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func newTestLimiter(burst int) *ratelimit.Limiter {
+	return ratelimit.NewLimiter(config.RateLimit{Rate: 1, Burst: burst}, time.Minute)
+}
+
+func TestRateLimitMiddleware_HeadersAndRetryAfter(t *testing.T) {
+	// arrange
+	router := setupRateLimitRouter(newTestLimiter(2), nil, nil)
+
+	// act
+	first := serveFrom(router, "203.0.113.7:4000", "")
+	serveFrom(router, "203.0.113.7:4000", "")
+	limited := serveFrom(router, "203.0.113.7:4000", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=2", first.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "0", limited.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", limited.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"too many requests, slow down"}`, limited.Body.String())
+}
+
+func TestRateLimitMiddleware_ForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantSecond     int
+	}{
+		// Behind a trusted proxy each forwarded client has its own bucket
+		{name: "trusted proxy", trustedProxies: []string{"10.0.0.0/8"}, wantSecond: http.StatusOK},
+		// Otherwise X-Forwarded-For is ignored and both share the proxy's
+		{name: "untrusted proxy", wantSecond: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			router := setupRateLimitRouter(newTestLimiter(1), nil, tt.trustedProxies)
+
+			// act
+			first := serveFrom(router, "10.0.0.2:4000", "203.0.113.7")
+			second := serveFrom(router, "10.0.0.2:4000", "198.51.100.20")
+
+			// assert
+			assert.Equal(t, http.StatusOK, first.Code)
+			assert.Equal(t, tt.wantSecond, second.Code)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_PerKey(t *testing.T) {
+	// arrange
+	router := setupRateLimitRouter(newTestLimiter(10), newTestLimiter(1), nil)
+
+	// act
+	first := serveFrom(router, "203.0.113.7:4000", "")
+	fromAnotherIP := serveFrom(router, "198.51.100.20:4000", "")
+
+	// assert: the key bucket is shared across IPs, and its headers win as
+	// it has fewer requests left
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusTooManyRequests, fromAnotherIP.Code)
+}
+
+func TestRateLimitMiddleware_WithoutLimiter(t *testing.T) {
+	// arrange
+	router := setupRateLimitRouter(nil, nil, nil)
+
+	// act
+	var codes []int
+	for range 3 {
+		codes = append(codes, serveFrom(router, "203.0.113.7:4000", "").Code)
+	}
+
+	// assert
+	require.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, codes)
+}
+
+func TestRateLimitMiddleware_ThrottledRequestsDoNotUseTheQuota(t *testing.T) {
+	// arrange
+	gin.SetMode(gin.TestMode)
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "ops", Secret: opsKey, DailyQuota: 10}})
+	router := gin.New()
+	router.Use(LanguageMiddleware(), ErrorHandlerMiddleware())
+	router.GET("/test",
+		APIKeyMiddleware(keys),
+		RateLimitMiddleware(newTestLimiter(1), APIKeyName),
+		QuotaMiddleware(),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// act
+	first := serveFrom(router, "203.0.113.7:4000", "")
+	throttled := serveFrom(router, "203.0.113.7:4000", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "9", first.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusTooManyRequests, throttled.Code)
+	assert.Equal(t, 1, keys.Usage()[0].DailyUsed)
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/ugorji/go/codec"
+)
+
+type NegotiationTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// endpoint is a request to the router under test and its expected status
+type endpoint struct {
+	name   string
+	method string
+	path   string
+	body   string
+	status int
+}
+
+var negotiatedEndpoints = []endpoint{
+	{"temperature by cep", http.MethodGet, "/api/v1/temperature/01001000", "", http.StatusOK},
+	{"temperature by cep expanded", http.MethodGet, "/api/v1/temperature/01001000?expand=all", "", http.StatusOK},
+	{"temperature by coordinates", http.MethodGet, "/api/v1/temperature?lat=-23.5505&lon=-46.6333", "", http.StatusOK},
+	{"temperature by city", http.MethodGet, "/api/v1/temperature/city/SP/Campinas", "", http.StatusOK},
+	{"temperature by unknown city", http.MethodGet, "/api/v1/temperature/city/SP/Campina", "", http.StatusNotFound},
+	{"convert", http.MethodPost, "/api/v1/convert", `{"value":28.5,"from":"C","to":["F","K"]}`, http.StatusOK},
+	{"address search", http.MethodGet, "/api/v1/cep/search?uf=SP&city=S%C3%A3o+Paulo&street=Paulista", "", http.StatusOK},
+	{"cep", http.MethodGet, "/api/v1/cep/01001000", "", http.StatusOK},
+	{"cep region", http.MethodGet, "/api/v1/cep/01310100/region", "", http.StatusOK},
+	{"error", http.MethodGet, "/api/v1/temperature/invalid-cep", "", http.StatusUnprocessableEntity},
+}
+
+func (s *NegotiationTestSuite) SetupTest() {
+	cfg := &config.Config{GinMode: gin.TestMode}
+
+	cepClient := client.NewCepClientStub(cfg)
+	cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	cepClient.On("SearchAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(searchResults(2), nil)
+
+	weatherClient := client.NewWeatherClientStub(cfg)
+	weatherClient.On("GetWeather", mock.Anything, mock.Anything).Return(model.GetObservationMock("São Paulo"), nil)
+	weatherClient.On("GetWeatherByCoordinates", mock.Anything, mock.Anything, mock.Anything).Return(model.GetObservationMock("São Paulo"), nil)
+
+	s.router = NewHttpHandler(cfg, cepClient, weatherClient).SetupRouter()
+}
+
+func (s *NegotiationTestSuite) serve(e endpoint, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(e.method, e.path, strings.NewReader(e.body))
+	if e.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_JSON() {
+	for _, e := range negotiatedEndpoints {
+		for _, accept := range []string{"", "application/json", "*/*"} {
+			s.Run(e.name+" "+accept, func() {
+				// act
+				w := s.serve(e, accept)
+
+				// assert
+				assert.Equal(s.T(), e.status, w.Code)
+				assert.Equal(s.T(), "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.True(s.T(), json.Valid(w.Body.Bytes()))
+				assert.Contains(s.T(), w.Header().Values("Vary"), "Accept")
+			})
+		}
+	}
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_XML() {
+	for _, e := range negotiatedEndpoints {
+		s.Run(e.name, func() {
+			// act
+			w := s.serve(e, "application/xml")
+
+			// assert
+			assert.Equal(s.T(), e.status, w.Code)
+			assert.Equal(s.T(), "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var root struct {
+				XMLName xml.Name
+			}
+			assert.NoError(s.T(), xml.Unmarshal(w.Body.Bytes(), &root))
+			assert.Equal(s.T(), "response", root.XMLName.Local)
+		})
+	}
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_CSV() {
+	for _, e := range negotiatedEndpoints {
+		s.Run(e.name, func() {
+			// act
+			w := s.serve(e, "text/csv")
+
+			// assert
+			assert.Equal(s.T(), e.status, w.Code)
+			assert.Equal(s.T(), "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+			records, err := csv.NewReader(w.Body).ReadAll()
+			assert.NoError(s.T(), err)
+			assert.GreaterOrEqual(s.T(), len(records), 2, "header and at least one row")
+		})
+	}
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_MsgPack() {
+	for _, e := range negotiatedEndpoints {
+		s.Run(e.name, func() {
+			// act
+			w := s.serve(e, "application/msgpack")
+
+			// assert
+			assert.Equal(s.T(), e.status, w.Code)
+			assert.Equal(s.T(), "application/msgpack", w.Header().Get("Content-Type"))
+
+			var handle codec.MsgpackHandle
+			handle.RawToString = true
+			var decoded map[string]any
+			assert.NoError(s.T(), codec.NewDecoderBytes(w.Body.Bytes(), &handle).Decode(&decoded))
+			assert.NotEmpty(s.T(), decoded)
+		})
+	}
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_NotAcceptable() {
+	for _, e := range negotiatedEndpoints {
+		s.Run(e.name, func() {
+			// act
+			w := s.serve(e, "text/html")
+
+			// assert
+			assert.Equal(s.T(), http.StatusNotAcceptable, w.Code)
+			assert.JSONEq(s.T(), `{"message":"supported response types are application/json, application/xml, text/csv, application/msgpack"}`, w.Body.String())
+		})
+	}
+}
+
+func (s *NegotiationTestSuite) TestNegotiation_CepETagPerFormat() {
+	// arrange
+	cep := endpoint{path: "/api/v1/cep/01001000", method: http.MethodGet}
+
+	// act
+	jsonResponse := s.serve(cep, "application/json")
+	csvResponse := s.serve(cep, "text/csv")
+
+	// assert
+	assert.NotEmpty(s.T(), jsonResponse.Header().Get("ETag"))
+	assert.NotEqual(s.T(), jsonResponse.Header().Get("ETag"), csvResponse.Header().Get("ETag"))
+}
+
+func TestNegotiationTestSuite(t *testing.T) {
+	suite.Run(t, new(NegotiationTestSuite))
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultObservationWindow is how far back from goes when it is not given
+const defaultObservationWindow = 24 * time.Hour
+
+// GetObservations godoc
+// @Summary Get the observation history of a CEP
+// @Description List the weather observations recorded for the city of a CEP, oldest first. Every observation fetched from the weather providers is recorded, with the provider that returned it, and kept for HISTORY_RETENTION, at most HISTORY_MAX_PER_LOCATION per location.
+// @Description from and to are RFC 3339 times matched against the observation time; to defaults to now and from to 24 hours before to.
+// @Tags weather
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param cep path string true "CEP" example(01001000)
+// @Param from query string false "Start of the period, RFC 3339" example(2026-01-09T17:30:00Z)
+// @Param to query string false "End of the period, RFC 3339" example(2026-01-10T17:30:00Z)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.ObservationHistoryResponse
+// @Failure 404 {object} model.ErrorResponse "can not find zipcode"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode or period"
+// @Failure 501 {object} model.ErrorResponse "observation history is disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/observations/{cep} [get]
+func (h *HttpHandler) GetObservations(c *gin.Context) {
+	if h.history == nil {
+		_ = c.Error(hErrors.HistoryDisabled)
+		return
+	}
+
+	from, to, err := observationRange(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		slog.Error("Invalid observation period", "from", c.Query("from"), "to", c.Query("to"))
+		_ = c.Error(err)
+		return
+	}
+
+	code, cepModel, err := h.service.ResolveCep(c.Request.Context(), c.Param("cep"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	observations, err := h.history.Query(cepModel.Uf, cepModel.Localidade, from, to)
+	if err != nil {
+		slog.Error("Failed to query observation history", "city", cepModel.Localidade, "uf", cepModel.Uf, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	render.Render(c, http.StatusOK, model.ObservationHistoryResponse{
+		Cep:          code.Formatted(),
+		City:         cepModel.Localidade,
+		UF:           cepModel.Uf,
+		From:         from,
+		To:           to,
+		Observations: observations,
+	})
+}
+
+// observationRange parses the from and to query parameters, defaulting to
+// the day before now
+func observationRange(rawFrom, rawTo string, now time.Time) (from, to time.Time, err error) {
+	to = now.UTC()
+	if rawTo != "" {
+		if to, err = time.Parse(time.RFC3339, rawTo); err != nil {
+			return from, to, hErrors.ObservationRangeInvalid
+		}
+	}
+
+	from = to.Add(-defaultObservationWindow)
+	if rawFrom != "" {
+		if from, err = time.Parse(time.RFC3339, rawFrom); err != nil {
+			return from, to, hErrors.ObservationRangeInvalid
+		}
+	}
+
+	if from.After(to) {
+		return from, to, hErrors.ObservationRangeInvalid
+	}
+	return from.UTC(), to.UTC(), nil
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/history"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func setupObservationsRouter(handler *HttpHandler) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
+
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.ContentNegotiationMiddleware())
+	v1.GET("/observations/:cep", handler.GetObservations)
+	v1.GET("/temperature/city/:uf/:city", handler.GetTemperatureByCity)
+	return router
+}
+
+type ObservationsTestSuite struct {
+	suite.Suite
+	cfg           *config.Config
+	handler       *HttpHandler
+	router        *gin.Engine
+	cepClientStub *client.CepClientStub
+}
+
+func (s *ObservationsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.cfg = &config.Config{
+		GinMode:               "test",
+		HistoryRetention:      time.Hour,
+		HistoryMaxPerLocation: 100,
+		HistoryPruneInterval:  time.Hour,
+	}
+	store, err := history.OpenStore(filepath.Join(s.T().TempDir(), "history.db"))
+	require.NoError(s.T(), err)
+
+	s.cepClientStub = client.NewCepClientStub(s.cfg)
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	weatherClientStub := client.NewWeatherClientStub(s.cfg)
+	weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("Sao Paulo"), nil)
+	weatherClientStub.On("GetWeatherByCoordinates", mock.Anything, -23.5505, -46.6333).Return(model.GetObservationMock("Sao Paulo"), nil)
+
+	s.handler = NewHttpHandler(s.cfg, s.cepClientStub, weatherClientStub, WithHistoryStore(store))
+	s.router = setupObservationsRouter(s.handler)
+}
+
+func (s *ObservationsTestSuite) TearDownTest() {
+	_ = s.handler.History().Close()
+}
+
+func (s *ObservationsTestSuite) get(path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *ObservationsTestSuite) TestGetObservations() {
+	// arrange
+	_, err := s.handler.Service().Observe(context.Background(), "SP", "São Paulo")
+	require.NoError(s.T(), err)
+
+	// act
+	w := s.get("/api/v1/observations/01001000?from=2026-01-10T17:00:00Z&to=2026-01-10T18:00:00Z")
+
+	// assert
+	require.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.ObservationHistoryResponse
+	require.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(s.T(), "01001-000", response.Cep)
+	assert.Equal(s.T(), "São Paulo", response.City)
+	assert.Equal(s.T(), "SP", response.UF)
+	assert.Equal(s.T(), time.Date(2026, 1, 10, 17, 0, 0, 0, time.UTC), response.From)
+	assert.Equal(s.T(), time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC), response.To)
+	require.Len(s.T(), response.Observations, 1)
+	assert.Equal(s.T(), "weatherapi", response.Observations[0].Provider)
+	assert.Equal(s.T(), 32.2, response.Observations[0].TemperatureC)
+	assert.Equal(s.T(), time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC), response.Observations[0].ObservedAt)
+}
+
+func (s *ObservationsTestSuite) TestGetObservations_RecordsCityLookupsUnderTheMunicipality() {
+	// arrange
+	require.Equal(s.T(), http.StatusOK, s.get("/api/v1/temperature/city/SP/sao%20paulo").Code)
+
+	// act
+	w := s.get("/api/v1/observations/01001000?from=2026-01-10T17:00:00Z&to=2026-01-10T18:00:00Z")
+
+	// assert
+	require.Equal(s.T(), http.StatusOK, w.Code)
+
+	var response model.ObservationHistoryResponse
+	require.NoError(s.T(), json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(s.T(), response.Observations, 1)
+	assert.Equal(s.T(), 32.2, response.Observations[0].TemperatureC)
+}
+
+func (s *ObservationsTestSuite) TestGetObservations_OutsideThePeriod() {
+	// arrange
+	_, err := s.handler.Service().Observe(context.Background(), "SP", "São Paulo")
+	require.NoError(s.T(), err)
+
+	// act
+	w := s.get("/api/v1/observations/01001000?from=2026-01-10T17:31:00Z&to=2026-01-10T18:00:00Z")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Contains(s.T(), w.Body.String(), `"observations":[]`)
+}
+
+func (s *ObservationsTestSuite) TestGetObservations_Invalid() {
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected string
+	}{
+		{"from after to", "/api/v1/observations/01001000?from=2026-01-10T18:00:00Z&to=2026-01-10T17:00:00Z", http.StatusUnprocessableEntity, `{"message": "from and to must be RFC 3339 times, with from not after to"}`},
+		{"malformed from", "/api/v1/observations/01001000?from=yesterday", http.StatusUnprocessableEntity, `{"message": "from and to must be RFC 3339 times, with from not after to"}`},
+		{"malformed to", "/api/v1/observations/01001000?to=2026-01-10", http.StatusUnprocessableEntity, `{"message": "from and to must be RFC 3339 times, with from not after to"}`},
+		{"invalid cep", "/api/v1/observations/123", http.StatusUnprocessableEntity, `{"message": "invalid zipcode"}`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := s.get(tt.path)
+
+			// assert
+			assert.Equal(s.T(), tt.status, w.Code)
+			assert.JSONEq(s.T(), tt.expected, w.Body.String())
+		})
+	}
+}
+
+func (s *ObservationsTestSuite) TestGetObservations_Disabled() {
+	// arrange
+	router := setupObservationsRouter(NewHttpHandler(s.cfg, s.cepClientStub, client.NewWeatherClientStub(s.cfg)))
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/observations/01001000", nil)
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotImplemented, w.Code)
+	assert.JSONEq(s.T(), `{"message": "observation history is disabled"}`, w.Body.String())
+}
+
+func TestObservationsTestSuite(t *testing.T) {
+	suite.Run(t, new(ObservationsTestSuite))
+}
+
+func TestObservationRange(t *testing.T) {
+	// arrange
+	now := time.Date(2026, 1, 10, 15, 0, 0, 0, time.FixedZone("BRT", -3*3600))
+
+	// act
+	from, to, err := observationRange("", "", now)
+	_, _, invalidErr := observationRange("2026-01-11T00:00:00Z", "", now)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC), to)
+	assert.Equal(t, to.Add(-24*time.Hour), from)
+	assert.ErrorIs(t, invalidErr, hErrors.ObservationRangeInvalid, "to defaults to now")
+}
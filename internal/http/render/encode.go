@@ -0,0 +1,315 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ugorji/go/codec"
+)
+
+// field is a key of a JSON object, kept in the order it was encoded
+type field struct {
+	key   string
+	value any
+}
+
+// object is a JSON object that preserves the key order of the struct tags
+type object []field
+
+// Encode serializes body in format. Every format is derived from the JSON
+// encoding, so the json tags of the model package name the XML elements,
+// CSV columns and MessagePack keys as well
+func Encode(format Format, body any) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if format == JSON {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tree, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case XML:
+		return encodeXML(tree)
+	case CSV:
+		return encodeCSV(tree)
+	case MsgPack:
+		return encodeMsgPack(tree)
+	}
+	return nil, fmt.Errorf("render: unsupported format %q", format)
+}
+
+// decodeValue reads the next JSON value as object, []any, string,
+// json.Number, bool or nil
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, field{keyTok.(string), value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// scalarText formats a leaf value; null becomes an empty string
+func scalarText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// invalidXMLName matches the characters not allowed in an element name
+var invalidXMLName = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// xmlName turns a JSON key into a valid element name
+func xmlName(key string) string {
+	name := invalidXMLName.ReplaceAllString(key, "_")
+	if name == "" || !(name[0] == '_' || (name[0] >= 'A' && name[0] <= 'Z') || (name[0] >= 'a' && name[0] <= 'z')) {
+		name = "_" + name
+	}
+	return name
+}
+
+// encodeXML writes the tree under a <response> root, naming array
+// elements <item>
+func encodeXML(tree any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := writeXML(&buf, "response", tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeXML(w *bytes.Buffer, name string, value any) error {
+	switch v := value.(type) {
+	case nil:
+		fmt.Fprintf(w, "<%s/>", name)
+		return nil
+	case object:
+		fmt.Fprintf(w, "<%s>", name)
+		for _, f := range v {
+			if err := writeXML(w, xmlName(f.key), f.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		fmt.Fprintf(w, "<%s>", name)
+		for _, item := range v {
+			if err := writeXML(w, "item", item); err != nil {
+				return err
+			}
+		}
+	default:
+		fmt.Fprintf(w, "<%s>", name)
+		if err := xml.EscapeText(w, []byte(scalarText(v))); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "</%s>", name)
+	return nil
+}
+
+// encodeCSV flattens the tree into a header and rows, nested keys joined
+// with dots. When the top-level object has exactly one array of objects
+// (search results, conversion results, suggestions) every element becomes
+// a row and the remaining fields are repeated on each of them; anything
+// else is written as a single row
+func encodeCSV(tree any) ([]byte, error) {
+	var rows [][]field
+
+	switch v := tree.(type) {
+	case object:
+		rows = objectRows(v)
+	case []any:
+		for _, item := range v {
+			rows = append(rows, flatten("", item, nil))
+		}
+	default:
+		rows = [][]field{flatten("value", v, nil)}
+	}
+
+	var header []string
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, f := range row {
+			if !seen[f.key] {
+				seen[f.key] = true
+				header = append(header, f.key)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		values := make(map[string]string, len(row))
+		for _, f := range row {
+			values[f.key] = scalarText(f.value)
+		}
+		record := make([]string, len(header))
+		for i, key := range header {
+			record[i] = values[key]
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// objectRows expands the single array of objects of obj into rows
+func objectRows(obj object) [][]field {
+	rowsAt := -1
+	for i, f := range obj {
+		if arr, ok := f.value.([]any); ok && len(arr) > 0 && isObjectList(arr) {
+			if rowsAt >= 0 {
+				return [][]field{flatten("", obj, nil)}
+			}
+			rowsAt = i
+		}
+	}
+	if rowsAt < 0 {
+		return [][]field{flatten("", obj, nil)}
+	}
+
+	var rows [][]field
+	for _, item := range obj[rowsAt].value.([]any) {
+		var row []field
+		for i, f := range obj {
+			if i == rowsAt {
+				row = flatten(f.key, item, row)
+				continue
+			}
+			row = flatten(f.key, f.value, row)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func isObjectList(arr []any) bool {
+	for _, item := range arr {
+		if _, ok := item.(object); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten appends the leaves of value to row with dotted keys; array
+// elements are keyed by their index
+func flatten(prefix string, value any, row []field) []field {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch v := value.(type) {
+	case object:
+		for _, f := range v {
+			row = flatten(join(f.key), f.value, row)
+		}
+	case []any:
+		for i, item := range v {
+			row = flatten(join(strconv.Itoa(i)), item, row)
+		}
+	default:
+		row = append(row, field{prefix, v})
+	}
+	return row
+}
+
+// encodeMsgPack writes the tree with maps keyed by the JSON names; numbers
+// become integers when they have no fractional part in the JSON encoding
+func encodeMsgPack(tree any) ([]byte, error) {
+	var (
+		buf    bytes.Buffer
+		handle codec.MsgpackHandle
+	)
+	handle.WriteExt = true
+	if err := codec.NewEncoder(&buf, &handle).Encode(msgPackValue(tree)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func msgPackValue(value any) any {
+	switch v := value.(type) {
+	case object:
+		m := make(map[string]any, len(v))
+		for _, f := range v {
+			m[f.key] = msgPackValue(f.value)
+		}
+		return m
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = msgPackValue(item)
+		}
+		return items
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			if i, err := v.Int64(); err == nil {
+				return i
+			}
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
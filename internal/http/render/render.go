@@ -0,0 +1,126 @@
+package render
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Format is a media type the API can answer with
+type Format string
+
+const (
+	JSON    Format = "application/json"
+	XML     Format = "application/xml"
+	CSV     Format = "text/csv"
+	MsgPack Format = "application/msgpack"
+)
+
+// formatKey is the gin context key where the negotiated format is stored
+const formatKey = "render.format"
+
+// mediaTypes maps the accepted media types, aliases and wildcards to a format
+var mediaTypes = map[string]Format{
+	"*/*":                   JSON,
+	"application/*":         JSON,
+	"application/json":      JSON,
+	"application/xml":       XML,
+	"text/xml":              XML,
+	"text/*":                CSV,
+	"text/csv":              CSV,
+	"application/msgpack":   MsgPack,
+	"application/x-msgpack": MsgPack,
+}
+
+// Supported lists the concrete formats, JSON first as the default
+func Supported() []Format {
+	return []Format{JSON, XML, CSV, MsgPack}
+}
+
+// ContentType is the Content-Type header sent with the format
+func (f Format) ContentType() string {
+	if f == MsgPack {
+		return string(f)
+	}
+	return string(f) + "; charset=utf-8"
+}
+
+// Negotiate picks the format for an Accept header. An empty header means
+// JSON; ok is false when none of the listed media types is supported
+func Negotiate(accept string) (Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return JSON, true
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []candidate
+	for _, item := range strings.Split(accept, ",") {
+		params := strings.Split(item, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{mediaType, q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, candidate := range candidates {
+		if format, ok := mediaTypes[candidate.mediaType]; ok {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// SetFormat stores the negotiated format for Render
+func SetFormat(c *gin.Context, format Format) {
+	c.Set(formatKey, format)
+}
+
+// FormatOf returns the format stored by SetFormat, negotiating it from the
+// Accept header when nothing was stored and falling back to JSON
+func FormatOf(c *gin.Context) Format {
+	if format, ok := c.Get(formatKey); ok {
+		return format.(Format)
+	}
+	if format, ok := Negotiate(c.GetHeader("Accept")); ok {
+		return format
+	}
+	return JSON
+}
+
+// Render writes body with status in the negotiated format
+func Render(c *gin.Context, status int, body any) {
+	format := FormatOf(c)
+
+	data, err := Encode(format, body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Data(status, format.ContentType(), data)
+}
@@ -0,0 +1,194 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+type sample struct {
+	Name    string   `json:"name"`
+	Temp    float64  `json:"temp_C"`
+	Missing *float64 `json:"missing"`
+	Skipped string   `json:"skipped,omitempty"`
+	Results []row    `json:"results"`
+}
+
+type row struct {
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+}
+
+func newSample() sample {
+	return sample{
+		Name:    "São Paulo & região",
+		Temp:    28.5,
+		Results: []row{{"F", 83.3}, {"K", 301.65}},
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected Format
+		ok       bool
+	}{
+		{"", JSON, true},
+		{"*/*", JSON, true},
+		{"application/json", JSON, true},
+		{"application/xml", XML, true},
+		{"text/xml", XML, true},
+		{"text/csv", CSV, true},
+		{"application/msgpack", MsgPack, true},
+		{"application/x-msgpack", MsgPack, true},
+		{"Application/XML; charset=utf-8", XML, true},
+		{"text/csv;q=0.5, application/xml;q=0.8", XML, true},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", JSON, true},
+		{"application/json;q=0, text/csv", CSV, true},
+		{"text/html", "", false},
+		{"application/json;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			// act
+			format, ok := Negotiate(tt.accept)
+
+			// assert
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestEncode_JSON(t *testing.T) {
+	// act
+	data, err := Encode(JSON, newSample())
+
+	// assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"São Paulo & região","temp_C":28.5,"missing":null,
+		"results":[{"unit":"F","value":83.3},{"unit":"K","value":301.65}]}`, string(data))
+}
+
+func TestEncode_XML(t *testing.T) {
+	// act
+	data, err := Encode(XML, newSample())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Header+`<response><name>São Paulo &amp; região</name><temp_C>28.5</temp_C><missing/>`+
+		`<results><item><unit>F</unit><value>83.3</value></item><item><unit>K</unit><value>301.65</value></item></results>`+
+		`</response>`, string(data))
+
+	var decoded struct {
+		Name    string `xml:"name"`
+		Results []row  `xml:"results>item"`
+	}
+	assert.NoError(t, xml.Unmarshal(data, &decoded))
+	assert.Equal(t, "São Paulo & região", decoded.Name)
+	assert.Len(t, decoded.Results, 2)
+}
+
+func TestEncode_XMLSanitizesNames(t *testing.T) {
+	// act
+	data, err := Encode(XML, map[string]int{"1st value": 1})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "<_1st_value>1</_1st_value>")
+}
+
+func TestEncode_CSVRowsFromArray(t *testing.T) {
+	// act
+	data, err := Encode(CSV, newSample())
+
+	// assert
+	assert.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "temp_C", "missing", "results.unit", "results.value"},
+		{"São Paulo & região", "28.5", "", "F", "83.3"},
+		{"São Paulo & região", "28.5", "", "K", "301.65"},
+	}, records)
+}
+
+func TestEncode_CSVSingleRow(t *testing.T) {
+	// arrange
+	body := map[string]any{
+		"city":   "Campinas",
+		"nested": map[string]any{"a": 1, "list": []int{7, 8}},
+	}
+
+	// act
+	data, err := Encode(CSV, body)
+
+	// assert
+	assert.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"city", "nested.a", "nested.list.0", "nested.list.1"},
+		{"Campinas", "1", "7", "8"},
+	}, records)
+}
+
+func TestEncode_MsgPack(t *testing.T) {
+	// act
+	data, err := Encode(MsgPack, newSample())
+
+	// assert
+	assert.NoError(t, err)
+
+	var handle codec.MsgpackHandle
+	handle.RawToString = true
+	var decoded map[string]any
+	assert.NoError(t, codec.NewDecoderBytes(data, &handle).Decode(&decoded))
+	assert.Equal(t, "São Paulo & região", decoded["name"])
+	assert.Equal(t, 28.5, decoded["temp_C"])
+	assert.Nil(t, decoded["missing"])
+	assert.NotContains(t, decoded, "skipped")
+	assert.Len(t, decoded["results"], 2)
+}
+
+func TestRender_UsesNegotiatedFormat(t *testing.T) {
+	tests := []struct {
+		accept      string
+		stored      Format
+		contentType string
+	}{
+		{"", "", "application/json; charset=utf-8"},
+		{"text/csv", "", "text/csv; charset=utf-8"},
+		{"text/html", "", "application/json; charset=utf-8"},
+		{"text/csv", MsgPack, "application/msgpack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept+string(tt.stored), func(t *testing.T) {
+			// arrange
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("Accept", tt.accept)
+			if tt.stored != "" {
+				SetFormat(c, tt.stored)
+			}
+
+			// act
+			Render(c, http.StatusCreated, newSample())
+
+			// assert
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package http
 
 import (
+	"log/slog"
+
 	_ "github.com/alexduzi/labcloudrun/docs"
+	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/alexduzi/labcloudrun/internal/http/middleware"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -12,9 +15,17 @@ func (h HttpHandler) SetupRouter() *gin.Engine {
 	// Set Gin mode based on configuration
 	gin.SetMode(h.config.GinMode)
 
-	router := gin.Default()
+	// gin.Default without its logger, which would write the API keys and
+	// tokens sent in the query string to the logs
+	router := gin.New()
+	router.Use(middleware.LoggerMiddleware(gin.DefaultWriter), gin.Recovery())
+	if err := router.SetTrustedProxies(h.config.TrustedProxies); err != nil {
+		slog.Error("Invalid trusted proxies", "proxies", h.config.TrustedProxies, "error", err)
+	}
 
-	router.Use(middleware.ErrorHandlerMiddleware())
+	// CORS comes first, so preflight requests are answered before routing
+	// and authentication, and errors carry the CORS headers too
+	router.Use(middleware.CORSMiddleware(h.config), middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -22,11 +33,61 @@ func (h HttpHandler) SetupRouter() *gin.Engine {
 	// Health and Readiness endpoints
 	router.GET("/health", h.HealthCheck)
 	router.GET("/readiness", h.ReadinessCheck)
+	router.GET("/metrics", h.Metrics)
+
+	// Each route group is rate limited per client IP before the API key or
+	// bearer token is checked, so guessing keys is limited too, and then per
+	// API key or token subject. Only requests both limits admit count
+	// against the quotas
+	limited := func(group string, auth gin.HandlerFunc) []gin.HandlerFunc {
+		return []gin.HandlerFunc{
+			middleware.RateLimitMiddleware(h.ipLimits[group], middleware.ClientIP),
+			auth,
+			middleware.RateLimitMiddleware(h.keyLimits[group], middleware.APIKeyName),
+			middleware.QuotaMiddleware(),
+		}
+	}
+	apiKey := middleware.AuthMiddleware(h.apiKeys, h.tokens)
+
+	// API key usage, for admin keys and tokens with the admin scope only
+	admin := router.Group("/admin")
+	admin.Use(middleware.ContentNegotiationMiddleware())
+	admin.Use(limited(config.RateLimitGroupAdmin, middleware.AdminAuthMiddleware(h.apiKeys, h.tokens))...)
+	admin.GET("/api-keys/usage", h.GetAPIKeyUsage)
+
+	// GraphQL endpoint
+	router.GET("/graphql", append(limited(config.RateLimitGroupGraphQL, apiKey), h.GraphQL)...)
+	router.POST("/graphql", append(limited(config.RateLimitGroupGraphQL, apiKey), h.GraphQL)...)
+
+	// Live temperature answers in SSE or WebSocket, outside content negotiation
+	router.GET("/api/v1/temperature/:cep/stream", append(limited(config.RateLimitGroupStream, apiKey), h.GetTemperatureStream)...)
 
 	// Weather endpoint
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.ContentNegotiationMiddleware())
+	v1.Use(limited(config.RateLimitGroupAPI, apiKey)...)
+	v1.GET("/temperature", h.GetTemperatureByCoordinates)
 	v1.GET("/temperature/", h.GetTemperatureWithoutCep)
 	v1.GET("/temperature/:cep", h.GetTemperatureByCep)
+	v1.GET("/temperature/city/:uf/:city", h.GetTemperatureByCity)
+
+	// Observation history
+	v1.GET("/observations/:cep", h.GetObservations)
+
+	// Unit conversion
+	v1.POST("/convert", h.Convert)
+
+	// Temperature threshold webhooks
+	v1.POST("/subscriptions", h.CreateSubscription)
+	v1.GET("/subscriptions", h.ListSubscriptions)
+	v1.GET("/subscriptions/dead-letters", h.ListDeadLetters)
+	v1.GET("/subscriptions/:id", h.GetSubscription)
+	v1.DELETE("/subscriptions/:id", h.DeleteSubscription)
+
+	// CEP endpoints
+	v1.GET("/cep/search", h.SearchAddress)
+	v1.GET("/cep/:cep", h.GetCep)
+	v1.GET("/cep/:cep/region", h.GetCepRegion)
 
 	return router
 }
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/alexduzi/labcloudrun/internal/apikey"
 	"github.com/alexduzi/labcloudrun/internal/client"
 	"github.com/alexduzi/labcloudrun/internal/config"
 	"github.com/gin-gonic/gin"
@@ -63,6 +66,14 @@ func (s *RouterTestSuite) TestSetupRouter_ReadinessEndpointRegistered() {
 	assert.Equal(s.T(), http.StatusOK, w.Code)
 }
 
+func (s *RouterTestSuite) TestSetupRouter_MetricsEndpointRegistered() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
 func (s *RouterTestSuite) TestSetupRouter_SwaggerEndpointRegistered() {
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/swagger/doc.json", nil)
@@ -177,6 +188,42 @@ func (s *RouterTestSuite) TestSetupRouter_AllEndpointsTableDriven() {
 			name:      "Temperature without CEP",
 			routePath: "/api/v1/temperature/",
 		},
+		{
+			name:      "Temperature by coordinates",
+			routePath: "/api/v1/temperature",
+		},
+		{
+			name:      "Temperature by city",
+			routePath: "/api/v1/temperature/city/:uf/:city",
+		},
+		{
+			name:      "Live temperature",
+			routePath: "/api/v1/temperature/:cep/stream",
+		},
+		{
+			name:      "Observation history",
+			routePath: "/api/v1/observations/:cep",
+		},
+		{
+			name:      "Unit conversion",
+			routePath: "/api/v1/convert",
+		},
+		{
+			name:      "CEP region",
+			routePath: "/api/v1/cep/:cep/region",
+		},
+		{
+			name:      "Address search",
+			routePath: "/api/v1/cep/search",
+		},
+		{
+			name:      "Address by CEP",
+			routePath: "/api/v1/cep/:cep",
+		},
+		{
+			name:      "API key usage",
+			routePath: "/admin/api-keys/usage",
+		},
 	}
 
 	routes := s.router.Routes()
@@ -192,6 +239,127 @@ func (s *RouterTestSuite) TestSetupRouter_AllEndpointsTableDriven() {
 	}
 }
 
+func (s *RouterTestSuite) TestSetupRouter_CityRouteDoesNotShadowCep() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/temperature/city", nil)
+	s.router.ServeHTTP(w, req)
+
+	// "city" segue para a rota de CEP, que o rejeita como CEP inválido
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/temperature/city/XX/Campinas", nil)
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+}
+
+func (s *RouterTestSuite) TestSetupRouter_LocalizedErrors() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/temperature/invalid-cep", nil)
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8")
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(s.T(), "pt-BR", w.Header().Get("Content-Language"))
+	assert.JSONEq(s.T(), `{"message":"CEP inválido"}`, w.Body.String())
+}
+
+func (s *RouterTestSuite) TestSetupRouter_CepSearchDoesNotShadowRegion() {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/cep/01310100/region", nil)
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/cep/search?uf=SP&city=SP&street=Paulista", nil)
+	s.router.ServeHTTP(w, req)
+
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+}
+
 func TestRouterTestSuite(t *testing.T) {
 	suite.Run(t, new(RouterTestSuite))
 }
+
+func (s *RouterTestSuite) TestSetupRouter_RateLimitPerGroup() {
+	// arrange
+	s.config.RateLimitPerIP = map[string]config.RateLimit{config.RateLimitGroupAPI: {Rate: 0.1, Burst: 1}}
+	s.config.RateLimitIdleTimeout = time.Minute
+	router := NewHttpHandler(s.config, s.cepClient, s.weatherClient).SetupRouter()
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	first := serve("/api/v1/cep/01310100/region")
+	second := serve("/api/v1/cep/01310100/region")
+	health := serve("/health")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, first.Code)
+	assert.Equal(s.T(), http.StatusTooManyRequests, second.Code)
+	assert.Equal(s.T(), "10", second.Header().Get("Retry-After"))
+	assert.Equal(s.T(), http.StatusOK, health.Code)
+}
+
+func (s *RouterTestSuite) TestSetupRouter_CORS() {
+	// arrange
+	s.config.CORSAllowedOrigins = []string{"https://*.example.com"}
+	s.config.CORSAllowedMethods = []string{"GET", "POST", "DELETE"}
+	s.config.CORSAllowedHeaders = []string{"Content-Type", "X-API-Key"}
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "web", Secret: "web-key-0123456789abc"}})
+	router := NewHttpHandler(s.config, s.cepClient, s.weatherClient, WithAPIKeys(keys)).SetupRouter()
+
+	serve := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/api/v1/convert", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	preflight := serve(http.MethodOptions, map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "content-type,x-api-key",
+	})
+	unauthorized := serve(http.MethodPost, nil)
+
+	// assert
+	assert.Equal(s.T(), http.StatusNoContent, preflight.Code)
+	assert.Equal(s.T(), "https://app.example.com", preflight.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(s.T(), "GET, POST, DELETE", preflight.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(s.T(), http.StatusUnauthorized, unauthorized.Code)
+	assert.Equal(s.T(), "https://app.example.com", unauthorized.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func (s *RouterTestSuite) TestSetupRouter_LogsWithoutQueryCredentials() {
+	// arrange
+	var out bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &out
+	defer func() { gin.DefaultWriter = previous }()
+
+	keys := apikey.NewRegistry([]apikey.Key{{Name: "web", Secret: "web-key-0123456789abc"}})
+	router := NewHttpHandler(s.config, s.cepClient, s.weatherClient, WithAPIKeys(keys)).SetupRouter()
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/cep/01310100/region?api_key=web-key-0123456789abc", nil)
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Contains(s.T(), out.String(), "/api/v1/cep/01310100/region?api_key=REDACTED")
+	assert.NotContains(s.T(), out.String(), "web-key-0123456789abc")
+}
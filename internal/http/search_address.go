@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ViaCEP rejects reverse lookups with shorter city or street names
+	minSearchTermLength = 3
+
+	defaultPageSize = 10
+	maxPageSize     = 50
+)
+
+// SearchAddress godoc
+// @Summary Search CEPs by address
+// @Description Find the CEPs of a street through ViaCEP's reverse lookup. City and street need at least 3 characters.
+// @Description With ?expand=temperature each result also carries its city's current temperature.
+// @Tags cep
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param uf query string true "State abbreviation (UF)" example(SP)
+// @Param city query string true "City name" example(São Paulo)
+// @Param street query string true "Street name, or part of it" example(Paulista)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param page_size query int false "Results per page (max 50)" default(10)
+// @Param expand query string false "Comma separated extra sections to include (temperature)" example(temperature)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.AddressSearchResponse
+// @Failure 406 {object} model.ErrorResponse "none of the Accept media types is supported"
+// @Failure 422 {object} model.ErrorResponse "invalid uf, city or street, or invalid pagination"
+// @Failure 501 {object} model.ErrorResponse "address search is not available offline (CEP_PROVIDER=offline)"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/cep/search [get]
+func (h *HttpHandler) SearchAddress(c *gin.Context) {
+	uf := strings.ToUpper(strings.TrimSpace(c.Query("uf")))
+	city := strings.TrimSpace(c.Query("city"))
+	street := strings.TrimSpace(c.Query("street"))
+
+	if err := validateAddressSearch(uf, city, street); err != nil {
+		slog.Error("Invalid address search", "uf", uf, "city", city, "street", street, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		slog.Error("Invalid pagination", "page", c.Query("page"), "page_size", c.Query("page_size"))
+		_ = c.Error(err)
+		return
+	}
+
+	results, err := h.cepApiClient.SearchAddress(c.Request.Context(), uf, city, street)
+	if err != nil {
+		slog.Error("Failed to search address", "uf", uf, "city", city, "street", street, "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	response := model.AddressSearchResponse{
+		Items:      make([]model.AddressSearchResult, 0, pageSize),
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      len(results),
+		TotalPages: (len(results) + pageSize - 1) / pageSize,
+	}
+
+	start := min((page-1)*pageSize, len(results))
+	end := min(start+pageSize, len(results))
+	for _, result := range results[start:end] {
+		response.Items = append(response.Items, model.AddressSearchResult{Address: result.ToAddress()})
+	}
+
+	if parseExpand(c.Query("expand"))[expandTemperatureSection] {
+		h.attachTemperatures(c.Request.Context(), response.Items)
+	}
+
+	render.Render(c, http.StatusOK, response)
+}
+
+func validateAddressSearch(uf, city, street string) error {
+	if _, ok := cep.StateOf(uf); !ok {
+		return hErrors.AddressUFInvalid
+	}
+	if utf8.RuneCountInString(city) < minSearchTermLength {
+		return hErrors.AddressCityTooShort
+	}
+	if utf8.RuneCountInString(street) < minSearchTermLength {
+		return hErrors.AddressStreetTooShort
+	}
+	return nil
+}
+
+// parsePagination reads ?page= and ?page_size=, defaulting to the first page
+func parsePagination(c *gin.Context) (int, int, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, hErrors.PaginationInvalid
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		return 0, 0, hErrors.PaginationInvalid
+	}
+
+	return page, pageSize, nil
+}
+
+// attachTemperatures looks the weather up once per city and UF on the page.
+// A failure only leaves that city's results without temperature
+func (h *HttpHandler) attachTemperatures(ctx context.Context, items []model.AddressSearchResult) {
+	temperatures := make(map[string]*model.TemperatureResponse)
+
+	for i := range items {
+		uf, city := items[i].UF, items[i].City
+		key := uf + "|" + city
+
+		temp, ok := temperatures[key]
+		if !ok {
+			observation, err := h.service.Observe(ctx, uf, city)
+			if err != nil {
+				slog.Warn("Failed to get weather for search result", "uf", uf, "location", city, "error", err)
+			} else {
+				converted := conversor.ConvertObservation(*observation)
+				temp = &converted
+			}
+			temperatures[key] = temp
+		}
+
+		items[i].Temperature = temp
+	}
+}
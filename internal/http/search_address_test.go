@@ -0,0 +1,259 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchAddressTestSuite struct {
+	suite.Suite
+	router            *gin.Engine
+	cepClientStub     *client.CepClientStub
+	weatherClientStub *client.WeatherClientStub
+}
+
+func (s *SearchAddressTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{GinMode: "test"}
+	s.cepClientStub = client.NewCepClientStub(cfg)
+	s.weatherClientStub = client.NewWeatherClientStub(cfg)
+
+	handler := NewHttpHandler(cfg, s.cepClientStub, s.weatherClientStub)
+
+	s.router = gin.New()
+	s.router.Use(middleware.ErrorHandlerMiddleware())
+	s.router.GET("/search", handler.SearchAddress)
+}
+
+// searchResults gera n endereços da Avenida Paulista com CEPs sequenciais
+func searchResults(n int) []model.ViacepResponse {
+	results := make([]model.ViacepResponse, n)
+	for i := range results {
+		result := model.GetViacepResponseMock(fmt.Sprintf("01310-%03d", i))
+		result.Logradouro = "Avenida Paulista"
+		results[i] = *result
+	}
+	return results
+}
+
+func (s *SearchAddressTestSuite) search(query url.Values) (*httptest.ResponseRecorder, model.AddressSearchResponse) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/search?"+query.Encode(), nil)
+	s.router.ServeHTTP(w, req)
+
+	var response model.AddressSearchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+	return w, response
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_Success() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(searchResults(3), nil)
+
+	// act
+	w, response := s.search(url.Values{"uf": {"sp"}, "city": {" São Paulo "}, "street": {"Paulista"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Equal(s.T(), 1, response.Page)
+	assert.Equal(s.T(), 10, response.PageSize)
+	assert.Equal(s.T(), 3, response.Total)
+	assert.Equal(s.T(), 1, response.TotalPages)
+	assert.Len(s.T(), response.Items, 3)
+
+	first := response.Items[0]
+	assert.Equal(s.T(), "01310-000", first.Cep)
+	assert.Equal(s.T(), "Avenida Paulista", first.Street)
+	assert.Equal(s.T(), "São Paulo", first.City)
+	assert.Equal(s.T(), "SP", first.UF)
+	assert.Nil(s.T(), first.Temperature)
+
+	s.weatherClientStub.AssertNotCalled(s.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_Pagination() {
+	tests := []struct {
+		name       string
+		page       string
+		pageSize   string
+		firstCep   string
+		items      int
+		totalPages int
+	}{
+		{"First page", "1", "10", "01310-000", 10, 3},
+		{"Last page", "3", "10", "01310-020", 3, 3},
+		{"Custom page size", "2", "5", "01310-005", 5, 5},
+		{"Beyond last page", "4", "10", "", 0, 3},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// arrange
+			s.SetupTest()
+			s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(searchResults(23), nil)
+
+			// act
+			w, response := s.search(url.Values{
+				"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"},
+				"page": {tt.page}, "page_size": {tt.pageSize},
+			})
+
+			// assert
+			assert.Equal(s.T(), http.StatusOK, w.Code)
+			assert.Equal(s.T(), 23, response.Total)
+			assert.Equal(s.T(), tt.totalPages, response.TotalPages)
+			assert.Len(s.T(), response.Items, tt.items)
+			if tt.items > 0 {
+				assert.Equal(s.T(), tt.firstCep, response.Items[0].Cep)
+			}
+		})
+	}
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_NoResults() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Rua Inexistente").Return([]model.ViacepResponse{}, nil)
+
+	// act
+	w, _ := s.search(url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Rua Inexistente"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `{"items":[],"page":1,"page_size":10,"total":0,"total_pages":0}`, w.Body.String())
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_InvalidParameters() {
+	tests := []struct {
+		name    string
+		query   url.Values
+		message string
+	}{
+		{"Missing UF", url.Values{"city": {"São Paulo"}, "street": {"Paulista"}}, "invalid uf"},
+		{"Unknown UF", url.Values{"uf": {"XX"}, "city": {"São Paulo"}, "street": {"Paulista"}}, "invalid uf"},
+		{"Short city", url.Values{"uf": {"SP"}, "city": {"SP"}, "street": {"Paulista"}}, "city must have at least 3 characters"},
+		{"Short street", url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Av"}}, "street must have at least 3 characters"},
+		{"Blank street", url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"    "}}, "street must have at least 3 characters"},
+		{"Page zero", url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "page": {"0"}}, hErrors.PaginationInvalid.Error()},
+		{"Page not a number", url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "page": {"two"}}, hErrors.PaginationInvalid.Error()},
+		{"Page size too large", url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "page_size": {"51"}}, hErrors.PaginationInvalid.Error()},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/search?"+tt.query.Encode(), nil)
+			s.router.ServeHTTP(w, req)
+
+			// assert
+			assert.Equal(s.T(), http.StatusUnprocessableEntity, w.Code)
+
+			var response model.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tt.message, response.Message)
+
+			s.cepClientStub.AssertNotCalled(s.T(), "SearchAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// Três caracteres acentuados contam como três caracteres, não seis bytes
+func (s *SearchAddressTestSuite) TestSearchAddress_MinimumLengthCountsCharacters() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "PR", "Açu", "Rua").Return([]model.ViacepResponse{}, nil)
+
+	// act
+	w, _ := s.search(url.Values{"uf": {"PR"}, "city": {"Açu"}, "street": {"Rua"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_CepClientError() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(nil, cErrors.CepClientInternalError)
+
+	// act
+	w, _ := s.search(url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusInternalServerError, w.Code)
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_ExpandTemperature() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(searchResults(3), nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil).Once()
+
+	// act
+	w, response := s.search(url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "expand": {"temperature"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Len(s.T(), response.Items, 3)
+	for _, item := range response.Items {
+		if assert.NotNil(s.T(), item.Temperature) {
+			assert.Equal(s.T(), 32.2, item.Temperature.Celsius)
+			assert.Equal(s.T(), 89.96, item.Temperature.Fahrenheit)
+		}
+	}
+
+	// a mesma cidade é consultada apenas uma vez
+	s.weatherClientStub.AssertNumberOfCalls(s.T(), "GetWeather", 1)
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_ExpandTemperatureKeepsStatesApart() {
+	// arrange
+	results := searchResults(3)
+	results[2].Uf = "MG"
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(results, nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	w, response := s.search(url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "expand": {"temperature"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Len(s.T(), response.Items, 3)
+
+	// a cidade homônima de outra UF é consultada à parte
+	s.weatherClientStub.AssertNumberOfCalls(s.T(), "GetWeather", 2)
+}
+
+func (s *SearchAddressTestSuite) TestSearchAddress_ExpandTemperatureWeatherError() {
+	// arrange
+	s.cepClientStub.On("SearchAddress", mock.Anything, "SP", "São Paulo", "Paulista").Return(searchResults(2), nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherClientInternalError)
+
+	// act
+	w, response := s.search(url.Values{"uf": {"SP"}, "city": {"São Paulo"}, "street": {"Paulista"}, "expand": {"temperature"}})
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.Len(s.T(), response.Items, 2)
+	assert.Nil(s.T(), response.Items[0].Temperature)
+	assert.Nil(s.T(), response.Items[1].Temperature)
+}
+
+func TestSearchAddressTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchAddressTestSuite))
+}
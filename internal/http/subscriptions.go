@@ -0,0 +1,166 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/http/render"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSubscription godoc
+// @Summary Subscribe to a temperature condition
+// @Description Register a webhook called when the temperature at the city of a CEP meets a condition: above or below threshold °C (notified when the condition starts to hold, including on the first check), or delta, a change of at least threshold °C since the last notification.
+// @Description Conditions are checked every WEBHOOK_EVAL_INTERVAL. Each delivery is a POST of model.WebhookPayload signed with the returned secret: X-Webhook-Signature is sha256= and the hex HMAC-SHA256 of X-Webhook-Timestamp, a dot and the body.
+// @Description Failed deliveries are retried with exponential backoff up to WEBHOOK_MAX_ATTEMPTS times, then listed in /api/v1/subscriptions/dead-letters.
+// @Tags subscriptions
+// @Accept json
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param request body model.SubscriptionRequest true "CEP, condition, threshold in °C and webhook URL"
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 201 {object} model.Subscription "Subscription, with the secret that signs its deliveries"
+// @Failure 400 {object} model.ErrorResponse "malformed body"
+// @Failure 404 {object} model.ErrorResponse "can not find zipcode"
+// @Failure 409 {object} model.ErrorResponse "subscription limit reached"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode, condition or url"
+// @Failure 501 {object} model.ErrorResponse "webhook subscriptions are disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/subscriptions [post]
+func (h *HttpHandler) CreateSubscription(c *gin.Context) {
+	if h.webhooks == nil {
+		_ = c.Error(hErrors.SubscriptionsDisabled)
+		return
+	}
+
+	var request model.SubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Invalid subscription request", "error", err)
+		_ = c.Error(hErrors.SubscriptionRequestInvalid)
+		return
+	}
+
+	code, cepModel, err := h.service.ResolveCep(c.Request.Context(), request.Cep)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	sub, err := h.webhooks.Create(c.GetString(middleware.APIKeyNameKey), model.Subscription{
+		Cep:       code.Formatted(),
+		City:      cepModel.Localidade,
+		UF:        cepModel.Uf,
+		Condition: request.Condition,
+		Threshold: *request.Threshold,
+		URL:       request.URL,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	render.Render(c, http.StatusCreated, sub)
+}
+
+// ListSubscriptions godoc
+// @Summary List subscriptions
+// @Description List the webhook subscriptions of the caller's API key or token, oldest first, with the temperature of their last check. Secrets are not included.
+// @Tags subscriptions
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Success 200 {array} model.Subscription
+// @Failure 501 {object} model.ErrorResponse "webhook subscriptions are disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/subscriptions [get]
+func (h *HttpHandler) ListSubscriptions(c *gin.Context) {
+	if h.webhooks == nil {
+		_ = c.Error(hErrors.SubscriptionsDisabled)
+		return
+	}
+
+	subs, err := h.webhooks.List(c.GetString(middleware.APIKeyNameKey))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	render.Render(c, http.StatusOK, subs)
+}
+
+// GetSubscription godoc
+// @Summary Get a subscription
+// @Tags subscriptions
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} model.Subscription
+// @Failure 404 {object} model.ErrorResponse "subscription not found"
+// @Failure 501 {object} model.ErrorResponse "webhook subscriptions are disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/subscriptions/{id} [get]
+func (h *HttpHandler) GetSubscription(c *gin.Context) {
+	if h.webhooks == nil {
+		_ = c.Error(hErrors.SubscriptionsDisabled)
+		return
+	}
+
+	sub, err := h.webhooks.Get(c.GetString(middleware.APIKeyNameKey), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	render.Render(c, http.StatusOK, sub)
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a subscription
+// @Tags subscriptions
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse "subscription not found"
+// @Failure 501 {object} model.ErrorResponse "webhook subscriptions are disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/subscriptions/{id} [delete]
+func (h *HttpHandler) DeleteSubscription(c *gin.Context) {
+	if h.webhooks == nil {
+		_ = c.Error(hErrors.SubscriptionsDisabled)
+		return
+	}
+
+	if err := h.webhooks.Delete(c.GetString(middleware.APIKeyNameKey), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeadLetters godoc
+// @Summary List failed webhook deliveries
+// @Description List the deliveries of the caller's subscriptions given up after their last attempt, oldest first, with the payload, the number of attempts and the last error. Only the latest 1000 are kept.
+// @Tags subscriptions
+// @Produce json,application/xml,text/csv,application/msgpack
+// @Success 200 {array} model.DeadLetter
+// @Failure 501 {object} model.ErrorResponse "webhook subscriptions are disabled"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/subscriptions/dead-letters [get]
+func (h *HttpHandler) ListDeadLetters(c *gin.Context) {
+	if h.webhooks == nil {
+		_ = c.Error(hErrors.SubscriptionsDisabled)
+		return
+	}
+
+	letters, err := h.webhooks.DeadLetters(c.GetString(middleware.APIKeyNameKey))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	render.Render(c, http.StatusOK, letters)
+}
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func setupSubscriptionRouter(handler *HttpHandler) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
+
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.ContentNegotiationMiddleware())
+	v1.POST("/subscriptions", handler.CreateSubscription)
+	v1.GET("/subscriptions", handler.ListSubscriptions)
+	v1.GET("/subscriptions/dead-letters", handler.ListDeadLetters)
+	v1.GET("/subscriptions/:id", handler.GetSubscription)
+	v1.DELETE("/subscriptions/:id", handler.DeleteSubscription)
+	return router
+}
+
+type SubscriptionsTestSuite struct {
+	suite.Suite
+	handler       *HttpHandler
+	router        *gin.Engine
+	cepClientStub *client.CepClientStub
+}
+
+func (s *SubscriptionsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		GinMode:             "test",
+		WebhookEvalInterval: time.Hour,
+		WebhookTimeout:      time.Second,
+		WebhookMaxAttempts:  1,
+		WebhookRetryBackoff: time.Millisecond,
+		WebhookMaxPerOwner:  1,
+	}
+	store, err := webhook.OpenStore(filepath.Join(s.T().TempDir(), "webhooks.db"))
+	require.NoError(s.T(), err)
+
+	s.cepClientStub = client.NewCepClientStub(cfg)
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.handler = NewHttpHandler(cfg, s.cepClientStub, client.NewWeatherClientStub(cfg), WithWebhookStore(store))
+	s.router = setupSubscriptionRouter(s.handler)
+}
+
+func (s *SubscriptionsTestSuite) TearDownTest() {
+	_ = s.handler.Webhooks().Close()
+}
+
+func (s *SubscriptionsTestSuite) request(method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *SubscriptionsTestSuite) TestSubscriptionLifecycle() {
+	// act
+	created := s.request(http.MethodPost, "/api/v1/subscriptions",
+		`{"cep": "01001000", "condition": "below", "threshold": 2, "url": "https://example.com/hooks"}`)
+
+	// assert
+	require.Equal(s.T(), http.StatusCreated, created.Code)
+
+	var sub model.Subscription
+	require.NoError(s.T(), json.Unmarshal(created.Body.Bytes(), &sub))
+	assert.NotEmpty(s.T(), sub.ID)
+	assert.NotEmpty(s.T(), sub.Secret)
+	assert.Equal(s.T(), "01001-000", sub.Cep)
+	assert.Equal(s.T(), "São Paulo", sub.City)
+	assert.Equal(s.T(), "SP", sub.UF)
+	assert.Equal(s.T(), "below", sub.Condition)
+	assert.Equal(s.T(), 2.0, sub.Threshold)
+
+	listed := s.request(http.MethodGet, "/api/v1/subscriptions", "")
+	assert.Equal(s.T(), http.StatusOK, listed.Code)
+	assert.Contains(s.T(), listed.Body.String(), sub.ID)
+	assert.NotContains(s.T(), listed.Body.String(), sub.Secret, "secrets are only returned on creation")
+
+	fetched := s.request(http.MethodGet, "/api/v1/subscriptions/"+sub.ID, "")
+	assert.Equal(s.T(), http.StatusOK, fetched.Code)
+	assert.NotContains(s.T(), fetched.Body.String(), `"secret"`)
+
+	deleted := s.request(http.MethodDelete, "/api/v1/subscriptions/"+sub.ID, "")
+	assert.Equal(s.T(), http.StatusNoContent, deleted.Code)
+
+	missing := s.request(http.MethodGet, "/api/v1/subscriptions/"+sub.ID, "")
+	assert.Equal(s.T(), http.StatusNotFound, missing.Code)
+	assert.JSONEq(s.T(), `{"message": "subscription not found"}`, missing.Body.String())
+}
+
+func (s *SubscriptionsTestSuite) TestCreateSubscription_Invalid() {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected string
+	}{
+		{"malformed body", `{"cep": `, http.StatusBadRequest, `{"message": "body must have a cep, a condition, a numeric threshold and a url"}`},
+		{"missing threshold", `{"cep": "01001000", "condition": "above", "url": "https://example.com"}`, http.StatusBadRequest, `{"message": "body must have a cep, a condition, a numeric threshold and a url"}`},
+		{"invalid cep", `{"cep": "123", "condition": "above", "threshold": 8, "url": "https://example.com"}`, http.StatusUnprocessableEntity, `{"message": "invalid zipcode"}`},
+		{"unknown condition", `{"cep": "01001000", "condition": "between", "threshold": 8, "url": "https://example.com"}`, http.StatusUnprocessableEntity, `{"message": "condition must be above, below or delta, with a finite threshold (positive for delta)"}`},
+		{"non-positive delta", `{"cep": "01001000", "condition": "delta", "threshold": 0, "url": "https://example.com"}`, http.StatusUnprocessableEntity, `{"message": "condition must be above, below or delta, with a finite threshold (positive for delta)"}`},
+		{"private url", `{"cep": "01001000", "condition": "above", "threshold": 8, "url": "http://169.254.169.254/"}`, http.StatusUnprocessableEntity, `{"message": "url must be an absolute http or https URL to a public host"}`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			w := s.request(http.MethodPost, "/api/v1/subscriptions", tt.body)
+
+			// assert
+			assert.Equal(s.T(), tt.status, w.Code)
+			assert.JSONEq(s.T(), tt.expected, w.Body.String())
+		})
+	}
+}
+
+func (s *SubscriptionsTestSuite) TestCreateSubscription_LimitReached() {
+	// arrange
+	body := `{"cep": "01001000", "condition": "above", "threshold": 8, "url": "https://example.com/hooks"}`
+	require.Equal(s.T(), http.StatusCreated, s.request(http.MethodPost, "/api/v1/subscriptions", body).Code)
+
+	// act
+	w := s.request(http.MethodPost, "/api/v1/subscriptions", body)
+
+	// assert
+	assert.Equal(s.T(), http.StatusConflict, w.Code)
+	assert.JSONEq(s.T(), `{"message": "subscription limit reached, delete a subscription before creating another"}`, w.Body.String())
+}
+
+func (s *SubscriptionsTestSuite) TestListDeadLetters_Empty() {
+	// act
+	w := s.request(http.MethodGet, "/api/v1/subscriptions/dead-letters", "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, w.Code)
+	assert.JSONEq(s.T(), `[]`, w.Body.String())
+}
+
+func (s *SubscriptionsTestSuite) TestSubscriptions_Disabled() {
+	// arrange
+	cfg := &config.Config{GinMode: "test"}
+	router := setupSubscriptionRouter(NewHttpHandler(cfg, s.cepClientStub, client.NewWeatherClientStub(cfg)))
+
+	// act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/subscriptions", nil)
+	router.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(s.T(), http.StatusNotImplemented, w.Code)
+	assert.JSONEq(s.T(), `{"message": "webhook subscriptions are disabled"}`, w.Body.String())
+}
+
+func (s *SubscriptionsTestSuite) TestSubscriptions_OwnedByTheAPIKey() {
+	// arrange
+	keys := apikey.NewRegistry([]apikey.Key{
+		{Name: "web", Secret: "web-key-0123456789abc"},
+		{Name: "mobile", Secret: "mobile-key-0123456789"},
+	})
+	router := gin.New()
+	router.Use(middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
+	v1 := router.Group("/api/v1", middleware.ContentNegotiationMiddleware(), middleware.APIKeyMiddleware(keys))
+	v1.POST("/subscriptions", s.handler.CreateSubscription)
+	v1.GET("/subscriptions", s.handler.ListSubscriptions)
+	v1.GET("/subscriptions/:id", s.handler.GetSubscription)
+	v1.DELETE("/subscriptions/:id", s.handler.DeleteSubscription)
+
+	request := func(method, path, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.APIKeyHeader, key)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	created := request(http.MethodPost, "/api/v1/subscriptions", "web-key-0123456789abc",
+		`{"cep": "01001000", "condition": "below", "threshold": 2, "url": "https://example.com/hooks"}`)
+	require.Equal(s.T(), http.StatusCreated, created.Code)
+	var sub model.Subscription
+	require.NoError(s.T(), json.Unmarshal(created.Body.Bytes(), &sub))
+
+	// act
+	otherList := request(http.MethodGet, "/api/v1/subscriptions", "mobile-key-0123456789", "")
+	otherGet := request(http.MethodGet, "/api/v1/subscriptions/"+sub.ID, "mobile-key-0123456789", "")
+	otherDelete := request(http.MethodDelete, "/api/v1/subscriptions/"+sub.ID, "mobile-key-0123456789", "")
+	ownGet := request(http.MethodGet, "/api/v1/subscriptions/"+sub.ID, "web-key-0123456789abc", "")
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, otherList.Code)
+	assert.JSONEq(s.T(), `[]`, otherList.Body.String())
+	assert.Equal(s.T(), http.StatusNotFound, otherGet.Code)
+	assert.Equal(s.T(), http.StatusNotFound, otherDelete.Code)
+	assert.Equal(s.T(), http.StatusOK, ownGet.Code)
+}
+
+func TestSubscriptionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionsTestSuite))
+}
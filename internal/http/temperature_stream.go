@@ -0,0 +1,208 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/alexduzi/labcloudrun/internal/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Event types of a live stream
+const (
+	streamEventTemperature = "temperature"
+	streamEventError       = "error"
+	streamEventHeartbeat   = "heartbeat"
+)
+
+// streamWriteTimeout bounds each WebSocket write, so a stalled client does
+// not hold the stream open
+const streamWriteTimeout = 10 * time.Second
+
+// streamUpgrader accepts any origin: the stream carries the same public data
+// as GET /api/v1/temperature/{cep}
+var streamUpgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// streamMessage is a stream event as sent over WebSocket
+type streamMessage struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// eventWriter sends the events of a stream over SSE or WebSocket
+type eventWriter interface {
+	write(event string, data any) error
+}
+
+type sseWriter struct {
+	c *gin.Context
+}
+
+func (w sseWriter) write(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w.c.Writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+type webSocketWriter struct {
+	conn *websocket.Conn
+}
+
+func (w webSocketWriter) write(event string, data any) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return w.conn.WriteJSON(streamMessage{Type: event, Data: data})
+}
+
+// GetTemperatureStream godoc
+// @Summary Live temperature by CEP
+// @Description Streams the temperature at the city of a CEP as Server-Sent Events, or as WebSocket messages when the request is a WebSocket upgrade.
+// @Description A `temperature` event is sent on connect and then only when the observation changes; the city is polled every STREAM_POLL_INTERVAL by a single poller shared by all its subscribers. An `error` event reports a failed poll, and a `heartbeat` event is sent every STREAM_HEARTBEAT_INTERVAL.
+// @Description Over WebSocket each message is `{"type": "<event>", "data": {...}}`.
+// @Tags weather
+// @Produce text/event-stream
+// @Param cep path string true "Brazilian postal code (CEP)" example(01310100)
+// @Param Accept-Language header string false "Response language: en (default), pt-BR or es" example(pt-BR)
+// @Success 200 {object} model.TemperatureUpdate "temperature events; error events carry a model.ErrorResponse and heartbeat events a model.StreamHeartbeat"
+// @Failure 404 {object} model.ErrorResponse "can not find zipcode"
+// @Failure 422 {object} model.ErrorResponse "invalid zipcode, or zipcode does not match its state (CEP_UF_MISMATCH=reject)"
+// @Failure 503 {object} model.ErrorResponse "server is shutting down"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/v1/temperature/{cep}/stream [get]
+func (h *HttpHandler) GetTemperatureStream(c *gin.Context) {
+	rawCep, _ := c.Params.Get("cep")
+	lang := i18n.FromContext(c.Request.Context())
+
+	code, cepModel, err := h.service.ResolveCep(c.Request.Context(), rawCep)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	city := cepModel.Localidade
+	sub, err := h.streams.Subscribe(streamKey(cepModel.Uf, city), func(ctx context.Context) (*model.Observation, error) {
+		return h.service.ObserveFresh(ctx, cepModel.Uf, city)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, model.ErrorResponse{
+			Message: lang.Text("error.stream_closed"),
+		})
+		return
+	}
+	defer sub.Close()
+
+	event := func(update stream.Update) (string, any) {
+		if update.Err != nil {
+			return streamEventError, model.ErrorResponse{Message: lang.Text(streamErrorKey(update.Err))}
+		}
+		return streamEventTemperature, model.TemperatureUpdate{
+			TemperatureResponse: conversor.ConvertObservation(*update.Observation),
+			Cep:                 code.Formatted(),
+			City:                city,
+			UF:                  cepModel.Uf,
+			ObservedAt:          update.Observation.ObservedAt,
+			HumidityPct:         update.Observation.HumidityPct,
+		}
+	}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		conn, err := streamUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade has already answered with an error
+			slog.Error("Failed to upgrade temperature stream", "cep", code, "error", err)
+			return
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+
+		// Incoming messages are ignored; reading only notices the client leaving
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		h.pumpStream(ctx, sub, webSocketWriter{conn: conn}, event)
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	h.pumpStream(c.Request.Context(), sub, sseWriter{c: c}, event)
+}
+
+// pumpStream writes the updates of sub, and heartbeats in between, until the
+// client leaves, a write fails or the hub closes
+func (h *HttpHandler) pumpStream(ctx context.Context, sub *stream.Subscription, w eventWriter, event func(stream.Update) (string, any)) {
+	heartbeat := time.NewTicker(h.config.StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			err = w.write(event(update))
+		case now := <-heartbeat.C:
+			err = w.write(streamEventHeartbeat, model.StreamHeartbeat{Time: now.UTC()})
+		}
+
+		if err != nil {
+			slog.Debug("Temperature stream closed", "error", err)
+			return
+		}
+	}
+}
+
+// streamKey identifies the location a stream follows, so CEPs of the same
+// city share its poller
+func streamKey(uf, city string) string {
+	return strings.ToUpper(uf) + "/" + strings.ToLower(city)
+}
+
+// streamErrorKey is the message key of a failed poll
+func streamErrorKey(err error) string {
+	if errors.Is(err, cErrors.WeatherClientNotFound) {
+		return "error.city_not_found"
+	}
+	return "error.upstream_unavailable"
+}
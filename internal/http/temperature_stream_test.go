@@ -0,0 +1,183 @@
+package http
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/http/middleware"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TemperatureStreamTestSuite struct {
+	suite.Suite
+	handler           *HttpHandler
+	server            *httptest.Server
+	cepClientStub     *client.CepClientStub
+	weatherClientStub *client.WeatherClientStub
+}
+
+func (s *TemperatureStreamTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		GinMode:                 "test",
+		StreamPollInterval:      10 * time.Millisecond,
+		StreamHeartbeatInterval: 50 * time.Millisecond,
+	}
+	s.cepClientStub = client.NewCepClientStub(cfg)
+	s.weatherClientStub = client.NewWeatherClientStub(cfg)
+	s.handler = NewHttpHandler(cfg, s.cepClientStub, s.weatherClientStub)
+
+	router := gin.New()
+	router.Use(middleware.LanguageMiddleware(), middleware.ErrorHandlerMiddleware())
+	router.GET("/api/v1/temperature/:cep/stream", s.handler.GetTemperatureStream)
+	s.server = httptest.NewServer(router)
+}
+
+func (s *TemperatureStreamTestSuite) TearDownTest() {
+	s.handler.CloseStreams()
+	s.server.Close()
+}
+
+// sseEvent reads the next event of an SSE stream
+func sseEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event != "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func (s *TemperatureStreamTestSuite) TestGetTemperatureStream_SSE() {
+	// arrange
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	resp, err := http.Get(s.server.URL + "/api/v1/temperature/01001000/stream")
+	require.NoError(s.T(), err)
+	reader := bufio.NewReader(resp.Body)
+
+	firstEvent, firstData := sseEvent(s.T(), reader)
+	secondEvent, _ := sseEvent(s.T(), reader)
+
+	// assert
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(s.T(), "temperature", firstEvent)
+	assert.JSONEq(s.T(), `{
+		"temp_C": 32.2, "temp_F": 89.96, "temp_K": 305.35,
+		"cep": "01001-000", "city": "São Paulo", "uf": "SP",
+		"observed_at": "2026-01-10T17:30:00Z", "humidity_pct": 36
+	}`, firstData)
+	assert.Equal(s.T(), "heartbeat", secondEvent, "an unchanged observation is not sent again")
+
+	resp.Body.Close()
+	assert.Eventually(s.T(), func() bool {
+		return s.handler.streams.Subscribers(streamKey("SP", "São Paulo")) == 0
+	}, time.Second, 10*time.Millisecond, "the subscription ends with the connection")
+}
+
+func (s *TemperatureStreamTestSuite) TestGetTemperatureStream_WebSocket() {
+	// arrange
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(nil, cErrors.WeatherProvidersUnavailable)
+
+	header := http.Header{}
+	header.Set("Accept-Language", "pt-BR")
+
+	// act
+	conn, _, err := websocket.DefaultDialer.Dial(
+		"ws"+strings.TrimPrefix(s.server.URL, "http")+"/api/v1/temperature/01001000/stream", header)
+	require.NoError(s.T(), err)
+
+	var first, second map[string]any
+	require.NoError(s.T(), conn.ReadJSON(&first))
+	require.NoError(s.T(), conn.ReadJSON(&second))
+
+	// assert
+	assert.Equal(s.T(), map[string]any{
+		"type": "error",
+		"data": map[string]any{"message": "serviço externo indisponível"},
+	}, first)
+	assert.Equal(s.T(), "heartbeat", second["type"], "a repeated failure is not sent again")
+
+	conn.Close()
+	assert.Eventually(s.T(), func() bool {
+		return s.handler.streams.Subscribers(streamKey("SP", "São Paulo")) == 0
+	}, time.Second, 10*time.Millisecond, "the subscription ends with the connection")
+}
+
+func (s *TemperatureStreamTestSuite) TestGetTemperatureStream_SharedPoller() {
+	// arrange
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClientStub.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	first, err := http.Get(s.server.URL + "/api/v1/temperature/01001000/stream")
+	require.NoError(s.T(), err)
+	defer first.Body.Close()
+	second, err := http.Get(s.server.URL + "/api/v1/temperature/01310100/stream")
+	require.NoError(s.T(), err)
+	defer second.Body.Close()
+
+	sseEvent(s.T(), bufio.NewReader(first.Body))
+	sseEvent(s.T(), bufio.NewReader(second.Body))
+
+	// assert
+	assert.Equal(s.T(), 2, s.handler.streams.Subscribers(streamKey("SP", "São Paulo")))
+}
+
+func (s *TemperatureStreamTestSuite) TestGetTemperatureStream_InvalidCep() {
+	// act
+	resp, err := http.Get(s.server.URL + "/api/v1/temperature/123/stream")
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	// assert
+	assert.Equal(s.T(), http.StatusUnprocessableEntity, resp.StatusCode)
+	s.cepClientStub.AssertNotCalled(s.T(), "GetCep", mock.Anything, mock.Anything)
+}
+
+func (s *TemperatureStreamTestSuite) TestGetTemperatureStream_Closed() {
+	// arrange
+	s.cepClientStub.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.handler.CloseStreams()
+
+	// act
+	resp, err := http.Get(s.server.URL + "/api/v1/temperature/01001000/stream")
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	// assert
+	assert.Equal(s.T(), http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestTemperatureStreamTestSuite(t *testing.T) {
+	suite.Run(t, new(TemperatureStreamTestSuite))
+}
@@ -0,0 +1,132 @@
+// Package i18n negotiates the response language and holds the embedded
+// message catalogues. English is the default and the last step of every
+// fallback chain, so a key missing from another catalogue still renders
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"golang.org/x/text/language"
+)
+
+// Lang is a supported language, as a BCP 47 tag
+type Lang string
+
+const (
+	English    Lang = "en"
+	Portuguese Lang = "pt-BR"
+	Spanish    Lang = "es"
+)
+
+// Default is used when the client accepts none of the supported languages
+const Default = English
+
+// supported lists the languages in the order the matcher prefers them on a
+// tie; the first one is the matcher's default
+var supported = []Lang{English, Portuguese, Spanish}
+
+// fallbacks is the chain tried after a language's own catalogue
+var fallbacks = map[Lang][]Lang{
+	Portuguese: {English},
+	Spanish:    {English},
+}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	catalogues = loadCatalogues()
+	matcher    = language.NewMatcher(tags())
+)
+
+func loadCatalogues() map[Lang]map[string]string {
+	loaded := make(map[Lang]map[string]string, len(supported))
+	for _, lang := range supported {
+		data, err := locales.ReadFile(path.Join("locales", string(lang)+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalogue for %s: %v", lang, err))
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalogue for %s: %v", lang, err))
+		}
+		loaded[lang] = messages
+	}
+	return loaded
+}
+
+func tags() []language.Tag {
+	result := make([]language.Tag, len(supported))
+	for i, lang := range supported {
+		result[i] = language.MustParse(string(lang))
+	}
+	return result
+}
+
+// Supported returns the supported languages, default first
+func Supported() []Lang {
+	return append([]Lang(nil), supported...)
+}
+
+// Negotiate picks the supported language that best matches an
+// Accept-Language header, honouring q-values. "pt" and "pt-PT" resolve to
+// pt-BR and "es-AR" to es; an empty or unsupported header yields Default
+func Negotiate(acceptLanguage string) Lang {
+	wanted, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(wanted) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(wanted...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// Lookup returns the message for key, walking the fallback chain. The
+// boolean is false when no catalogue has the key
+func (l Lang) Lookup(key string) (string, bool) {
+	for _, lang := range append([]Lang{l}, fallbacks[l]...) {
+		if message, ok := catalogues[lang][key]; ok {
+			return message, true
+		}
+	}
+	if message, ok := catalogues[Default][key]; ok {
+		return message, true
+	}
+	return "", false
+}
+
+// Text returns the message for key formatted with args, or the key itself
+// when no catalogue has it
+func (l Lang) Text(key string, args ...any) string {
+	message, ok := l.Lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+type contextKey struct{}
+
+// WithLang returns a copy of ctx carrying lang
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language stored by WithLang, or Default
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected Lang
+	}{
+		{"", English},
+		{"en", English},
+		{"en-US,en;q=0.9", English},
+		{"pt-BR", Portuguese},
+		{"pt-BR,pt;q=0.9,en;q=0.8", Portuguese},
+		{"pt", Portuguese},
+		{"pt-PT", Portuguese},
+		{"es", Spanish},
+		{"es-AR", Spanish},
+		{"fr-FR,es;q=0.5", Spanish},
+		{"en;q=0.3,es;q=0.9", Spanish},
+		{"fr", English},
+		{"*", English},
+		{"not a header;;;", English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "can not find zipcode", English.Text("error.cep_not_found"))
+	assert.Equal(t, "CEP não encontrado", Portuguese.Text("error.cep_not_found"))
+	assert.Equal(t, "no se encuentra el código postal", Spanish.Text("error.cep_not_found"))
+
+	assert.Equal(t, `unidade desconhecida "parsec"`, Portuguese.Text("error.unknown_unit", "parsec"))
+}
+
+func TestText_Fallback(t *testing.T) {
+	// arrange: uma chave presente só no catálogo em inglês
+	catalogues[English]["test.only_english"] = "only in English"
+	defer delete(catalogues[English], "test.only_english")
+
+	// act / assert
+	assert.Equal(t, "only in English", Portuguese.Text("test.only_english"))
+	assert.Equal(t, "only in English", Lang("fr").Text("test.only_english"))
+	assert.Equal(t, "missing.key", Spanish.Text("missing.key"))
+
+	_, ok := Spanish.Lookup("missing.key")
+	assert.False(t, ok)
+}
+
+// Todos os catálogos precisam ter as mesmas chaves que o inglês
+func TestCatalogues_Complete(t *testing.T) {
+	for _, lang := range Supported() {
+		for key := range catalogues[English] {
+			_, ok := catalogues[lang][key]
+			assert.True(t, ok, "%s is missing %s", lang, key)
+		}
+		for key := range catalogues[lang] {
+			_, ok := catalogues[English][key]
+			assert.True(t, ok, "%s has %s, which English lacks", lang, key)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, Spanish, FromContext(WithLang(context.Background(), Spanish)))
+}
@@ -0,0 +1,275 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minRefetchInterval bounds how often an unknown key id fetches the JWKS
+// again, so tokens with made-up key ids can not hammer the provider
+const minRefetchInterval = time.Minute
+
+// fetchTimeout bounds a JWKS fetch
+const fetchTimeout = 10 * time.Second
+
+// keySource returns the public key a token signed with key id kid is
+// checked against
+type keySource interface {
+	key(ctx context.Context, kid string) (any, error)
+}
+
+// staticKey is a single public key, used whatever the key id
+type staticKey struct {
+	public any
+}
+
+// loadKeyFile reads a PEM public key or certificate, RSA or ECDSA
+func loadKeyFile(path string) (*staticKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("parse jwt key %s: no PEM block", path)
+	}
+
+	var public any
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			public = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("parse jwt key %s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt key %s: %w", path, err)
+	}
+
+	switch public.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return &staticKey{public: public}, nil
+	default:
+		return nil, fmt.Errorf("parse jwt key %s: only RSA and ECDSA keys are supported", path)
+	}
+}
+
+func (s *staticKey) key(context.Context, string) (any, error) {
+	return s.public, nil
+}
+
+// jwks keeps the signing keys published at a JWKS URL, fetched again every
+// refresh interval and when a token names a key id it does not know, as
+// providers publish new keys before signing with them. One fetch runs at a
+// time, outside the lock, and requests waiting on it share its result
+type jwks struct {
+	url      string
+	client   *http.Client
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]any
+	fetchedAt time.Time
+	triedAt   time.Time
+	// refreshed is closed when the fetch in flight ends; nil when none is
+	refreshed chan struct{}
+}
+
+func newJWKS(url string, interval time.Duration) *jwks {
+	return &jwks{
+		url:      url,
+		client:   &http.Client{Timeout: fetchTimeout},
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// key returns the key kid at once when it is known, even while stale keys
+// are fetched again, and otherwise waits for the fetch, or for ctx
+func (j *jwks) key(ctx context.Context, kid string) (any, error) {
+	j.mu.Lock()
+	public, known := j.keys[kid]
+	refreshed := j.refresh(known)
+	j.mu.Unlock()
+
+	if !known && refreshed != nil {
+		select {
+		case <-refreshed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		j.mu.Lock()
+		public, known = j.keys[kid]
+		j.mu.Unlock()
+	}
+
+	if !known {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return public, nil
+}
+
+// refresh starts fetching the JWKS when the keys are stale or kid is not
+// known, at most once every minRefetchInterval, and returns a channel closed
+// when the fetch in flight ends, or nil when there is none. The fetch does
+// not use the context of the request that started it, so a client going
+// away does not fail it for the others. j.mu must be held
+func (j *jwks) refresh(known bool) <-chan struct{} {
+	if j.refreshed != nil {
+		return j.refreshed
+	}
+
+	now := j.now()
+	stale := now.Sub(j.fetchedAt) >= j.interval
+	if (known && !stale) || now.Sub(j.triedAt) < minRefetchInterval {
+		return nil
+	}
+	j.triedAt = now
+
+	refreshed := make(chan struct{})
+	j.refreshed = refreshed
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+		keys, err := j.fetch(ctx)
+
+		j.mu.Lock()
+		if err != nil {
+			// Keep the keys fetched before; the provider may be back soon
+			slog.Warn("Failed to fetch JWKS", "url", j.url, "error", err)
+		} else {
+			j.keys = keys
+			j.fetchedAt = now
+		}
+		j.refreshed = nil
+		j.mu.Unlock()
+		close(refreshed)
+	}()
+	return refreshed
+}
+
+// jsonWebKey is a key of a JWKS, as in RFC 7517 and RFC 7518
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch reads the keys of the JWKS. Keys of other types or meant for
+// encryption are skipped
+func (j *jwks) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		public, err := jwk.publicKey()
+		if err != nil {
+			slog.Warn("Skipping JWKS key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = public
+	}
+
+	return keys, nil
+}
+
+// curves are the elliptic curves of the ES256, ES384 and ES512 keys
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, nErr := decodeInt(k.N)
+		e, eErr := decodeInt(k.E)
+		if err := errors.Join(nErr, eErr); err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, xErr := decodeInt(k.X)
+		y, yErr := decodeInt(k.Y)
+		if err := errors.Join(xErr, yErr); err != nil {
+			return nil, err
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := public.ECDH(); err != nil {
+			return nil, err
+		}
+		return public, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer %q", value)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,161 @@
+package jwtauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/alexduzi/labcloudrun/internal/apikey"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissing   = errors.New("bearer token or api key required")
+	ErrInvalid   = errors.New("invalid bearer token")
+	ErrExpired   = errors.New("bearer token expired")
+	ErrForbidden = errors.New("bearer token not allowed on this route")
+
+	ErrQuotaExceeded = errors.New("bearer token quota exceeded")
+)
+
+// Principal is the caller a valid token names, with the scopes it was
+// granted
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// Verifier checks bearer tokens: their signature, issuer, audience and
+// expiry, and the routes their scopes allow, and counts the requests of
+// each subject against its quotas
+type Verifier struct {
+	keys        keySource
+	parser      *jwt.Parser
+	scopeRoutes map[string][]string
+	adminScope  string
+	quotas      *apikey.Meter
+}
+
+// Load builds the verifier from the JWT_* settings. It returns nil when
+// neither JWT_JWKS_URL nor JWT_KEY_FILE is set, leaving bearer tokens off
+func Load(cfg *config.Config) (*Verifier, error) {
+	var keys keySource
+	switch {
+	case cfg.JWTJWKSURL != "":
+		keys = newJWKS(cfg.JWTJWKSURL, cfg.JWTJWKSRefreshInterval)
+	case cfg.JWTKeyFile != "":
+		key, err := loadKeyFile(cfg.JWTKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = key
+	default:
+		return nil, nil
+	}
+
+	return &Verifier{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(cfg.JWTAlgorithms),
+			jwt.WithIssuer(cfg.JWTIssuer),
+			jwt.WithAudience(cfg.JWTAudience...),
+			jwt.WithLeeway(cfg.JWTLeeway),
+			jwt.WithExpirationRequired(),
+		),
+		scopeRoutes: cfg.JWTScopeRoutes,
+		adminScope:  cfg.JWTAdminScope,
+		quotas:      apikey.NewMeter(cfg.JWTDailyQuota, cfg.JWTMonthlyQuota),
+	}, nil
+}
+
+// claims are the registered claims plus the scopes, sent either as the
+// space separated scope claim of OAuth 2.0 or as the scp claim some
+// providers use, a list or a string
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string    `json:"scope"`
+	Scp   scopeList `json:"scp"`
+}
+
+type scopeList []string
+
+func (s *scopeList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = strings.Fields(value)
+	return nil
+}
+
+// Verify checks token and returns the caller it names
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrMissing
+	}
+
+	var parsed claims
+	_, err := v.parser.ParseWithClaims(token, &parsed, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	})
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return Principal{}, ErrExpired
+	case err != nil:
+		return Principal{}, errors.Join(ErrInvalid, err)
+	}
+
+	scopes := append(strings.Fields(parsed.Scope), parsed.Scp...)
+	return Principal{Subject: parsed.Subject, Scopes: scopes}, nil
+}
+
+// Allow checks that principal may call route: one of its scopes must list
+// the route in JWT_SCOPE_ROUTES. Without scope routes any valid token may
+// call every route
+func (v *Verifier) Allow(principal Principal, route string) error {
+	if len(v.scopeRoutes) == 0 {
+		return nil
+	}
+
+	for _, scope := range principal.Scopes {
+		if apikey.MatchRoute(v.scopeRoutes[scope], route) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// Charge counts n requests of principal against the JWT_DAILY_QUOTA and
+// JWT_MONTHLY_QUOTA of its subject and returns the window closest to
+// running out. Rejected requests are not counted, and with n zero it only
+// reports the quotas
+func (v *Verifier) Charge(principal Principal, n int) (apikey.Decision, error) {
+	if v.quotas == nil {
+		return apikey.Decision{}, nil
+	}
+
+	decision, err := v.quotas.AllowN(principal.Subject, n)
+	if errors.Is(err, apikey.ErrQuotaExceeded) {
+		err = ErrQuotaExceeded
+	}
+	return decision, err
+}
+
+// AllowAdmin checks that principal has the admin scope. Without
+// JWT_ADMIN_SCOPE no token may call the admin routes
+func (v *Verifier) AllowAdmin(principal Principal) error {
+	if v.adminScope == "" || !slices.Contains(principal.Scopes, v.adminScope) {
+		return ErrForbidden
+	}
+	return nil
+}
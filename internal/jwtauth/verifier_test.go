@@ -0,0 +1,338 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testIssuer   = "https://auth.example.com/"
+	testAudience = "lab-cloudrun"
+)
+
+type VerifierTestSuite struct {
+	suite.Suite
+	rsaKey   *rsa.PrivateKey
+	ecKey    *ecdsa.PrivateKey
+	server   *httptest.Server
+	fetches  atomic.Int64
+	cfg      *config.Config
+	verifier *Verifier
+}
+
+func (s *VerifierTestSuite) SetupSuite() {
+	var err error
+	s.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+	s.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(s.T(), err)
+}
+
+func (s *VerifierTestSuite) SetupTest() {
+	s.fetches.Store(0)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(encodeJWKS(map[string]any{
+			"rsa-1": &s.rsaKey.PublicKey,
+			"ec-1":  &s.ecKey.PublicKey,
+		}))
+	}))
+
+	s.cfg = &config.Config{
+		JWTJWKSURL:             s.server.URL,
+		JWTIssuer:              testIssuer,
+		JWTAudience:            []string{testAudience},
+		JWTAlgorithms:          []string{"RS256", "ES256"},
+		JWTLeeway:              30 * time.Second,
+		JWTJWKSRefreshInterval: time.Hour,
+		JWTScopeRoutes: map[string][]string{
+			"weather:read": {"/api/v1/temperature*"},
+			"cep:read":     {"/api/v1/cep/*"},
+		},
+		JWTAdminScope:   "admin",
+		JWTDailyQuota:   2,
+		JWTMonthlyQuota: 10,
+	}
+
+	var err error
+	s.verifier, err = Load(s.cfg)
+	require.NoError(s.T(), err)
+}
+
+func (s *VerifierTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// claimsFor are valid claims for subject, changed by edit
+func claimsFor(subject string, edit func(jwt.MapClaims)) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   subject,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "weather:read",
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	return claims
+}
+
+func (s *VerifierTestSuite) sign(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(s.T(), err)
+	return signed
+}
+
+func (s *VerifierTestSuite) TestVerify_RS256AndES256() {
+	// arrange
+	rsaToken := s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, claimsFor("web-app", nil))
+	ecToken := s.sign(jwt.SigningMethodES256, "ec-1", s.ecKey, claimsFor("mobile-app", func(c jwt.MapClaims) {
+		delete(c, "scope")
+		c["scp"] = []string{"cep:read", "admin"}
+	}))
+
+	// act
+	rsaPrincipal, rsaErr := s.verifier.Verify(context.Background(), rsaToken)
+	ecPrincipal, ecErr := s.verifier.Verify(context.Background(), ecToken)
+
+	// assert
+	require.NoError(s.T(), rsaErr)
+	require.NoError(s.T(), ecErr)
+	assert.Equal(s.T(), Principal{Subject: "web-app", Scopes: []string{"weather:read"}}, rsaPrincipal)
+	assert.Equal(s.T(), Principal{Subject: "mobile-app", Scopes: []string{"cep:read", "admin"}}, ecPrincipal)
+	assert.Equal(s.T(), int64(1), s.fetches.Load())
+}
+
+func (s *VerifierTestSuite) TestVerify_RejectsInvalidTokens() {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+
+	tests := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"missing", "", ErrMissing},
+		{"malformed", "not-a-token", ErrInvalid},
+		{"wrong signature", s.sign(jwt.SigningMethodRS256, "rsa-1", otherKey, claimsFor("web-app", nil)), ErrInvalid},
+		{"unknown key id", s.sign(jwt.SigningMethodRS256, "rsa-2", s.rsaKey, claimsFor("web-app", nil)), ErrInvalid},
+		{"algorithm not allowed", s.sign(jwt.SigningMethodRS512, "rsa-1", s.rsaKey, claimsFor("web-app", nil)), ErrInvalid},
+		{"wrong issuer", s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, claimsFor("web-app", func(c jwt.MapClaims) {
+			c["iss"] = "https://other.example.com/"
+		})), ErrInvalid},
+		{"wrong audience", s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, claimsFor("web-app", func(c jwt.MapClaims) {
+			c["aud"] = []string{"other-api"}
+		})), ErrInvalid},
+		{"without expiry", s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, claimsFor("web-app", func(c jwt.MapClaims) {
+			delete(c, "exp")
+		})), ErrInvalid},
+		{"expired", s.sign(jwt.SigningMethodES256, "ec-1", s.ecKey, claimsFor("web-app", func(c jwt.MapClaims) {
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+		})), ErrExpired},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			_, err := s.verifier.Verify(context.Background(), tt.token)
+
+			// assert
+			assert.ErrorIs(s.T(), err, tt.expected)
+		})
+	}
+}
+
+func (s *VerifierTestSuite) TestVerify_ExpiryWithinTheLeeway() {
+	// arrange
+	token := s.sign(jwt.SigningMethodRS256, "rsa-1", s.rsaKey, claimsFor("web-app", func(c jwt.MapClaims) {
+		c["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	}))
+
+	// act
+	_, err := s.verifier.Verify(context.Background(), token)
+
+	// assert
+	assert.NoError(s.T(), err)
+}
+
+func (s *VerifierTestSuite) TestVerify_UnknownKeyIDRefetchesAtMostOncePerMinute() {
+	// arrange
+	now := time.Now()
+	source := s.verifier.keys.(*jwks)
+	source.now = func() time.Time { return now }
+	token := s.sign(jwt.SigningMethodRS256, "rsa-2", s.rsaKey, claimsFor("web-app", nil))
+
+	// act
+	_, first := s.verifier.Verify(context.Background(), token)
+	_, second := s.verifier.Verify(context.Background(), token)
+	now = now.Add(minRefetchInterval)
+	_, third := s.verifier.Verify(context.Background(), token)
+
+	// assert
+	assert.ErrorIs(s.T(), first, ErrInvalid)
+	assert.ErrorIs(s.T(), second, ErrInvalid)
+	assert.ErrorIs(s.T(), third, ErrInvalid)
+	assert.Equal(s.T(), int64(2), s.fetches.Load())
+}
+
+func (s *VerifierTestSuite) TestJWKS_ConcurrentLookupsShareOneFetch() {
+	// arrange
+	release := make(chan struct{})
+	var fetches atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		_ = json.NewEncoder(w).Encode(encodeJWKS(map[string]any{"rsa-1": &s.rsaKey.PublicKey}))
+	}))
+	defer server.Close()
+	source := newJWKS(server.URL, time.Hour)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act: the first lookup starts the fetch and gives up with its context
+	_, cancelledErr := source.key(cancelled, "rsa-1")
+	errs := make([]error, 5)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Go(func() {
+			_, errs[i] = source.key(context.Background(), "rsa-1")
+		})
+	}
+	close(release)
+	wg.Wait()
+
+	// assert
+	assert.ErrorIs(s.T(), cancelledErr, context.Canceled)
+	for _, err := range errs {
+		assert.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), int64(1), fetches.Load())
+}
+
+func (s *VerifierTestSuite) TestAllow_MapsScopesToRoutes() {
+	// arrange
+	weather := Principal{Subject: "web-app", Scopes: []string{"weather:read"}}
+	admin := Principal{Subject: "ops", Scopes: []string{"admin"}}
+
+	// act / assert
+	assert.NoError(s.T(), s.verifier.Allow(weather, "/api/v1/temperature/:cep"))
+	assert.ErrorIs(s.T(), s.verifier.Allow(weather, "/api/v1/cep/:cep"), ErrForbidden)
+	assert.ErrorIs(s.T(), s.verifier.AllowAdmin(weather), ErrForbidden)
+	assert.NoError(s.T(), s.verifier.AllowAdmin(admin))
+}
+
+func (s *VerifierTestSuite) TestCharge_CountsPerSubject() {
+	// arrange
+	webApp := Principal{Subject: "web-app"}
+	ops := Principal{Subject: "ops"}
+
+	// act
+	decision, err := s.verifier.Charge(webApp, 2)
+	_, exceededErr := s.verifier.Charge(webApp, 1)
+	_, opsErr := s.verifier.Charge(ops, 1)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, decision.Limit)
+	assert.Equal(s.T(), 0, decision.Remaining)
+	assert.ErrorIs(s.T(), exceededErr, ErrQuotaExceeded)
+	assert.NoError(s.T(), opsErr)
+}
+
+func TestVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifierTestSuite))
+}
+
+func TestLoad_DisabledWithoutKeys(t *testing.T) {
+	// act
+	verifier, err := Load(&config.Config{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+}
+
+func TestLoad_StaticKeyFile(t *testing.T) {
+	// arrange
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	verifier, err := Load(&config.Config{
+		JWTKeyFile:    path,
+		JWTIssuer:     testIssuer,
+		JWTAudience:   []string{testAudience},
+		JWTAlgorithms: []string{"ES256"},
+	})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claimsFor("web-app", nil)).SignedString(key)
+	require.NoError(t, err)
+
+	// act
+	principal, err := verifier.Verify(context.Background(), token)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "web-app", principal.Subject)
+	assert.NoError(t, verifier.Allow(principal, "/api/v1/cep/:cep"))
+}
+
+func TestLoad_InvalidKeyFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+
+	// act
+	verifier, err := Load(&config.Config{JWTKeyFile: path})
+
+	// assert
+	assert.Nil(t, verifier)
+	assert.ErrorContains(t, err, "no PEM block")
+}
+
+// encodeJWKS encodes public keys, by key id, as a JWKS
+func encodeJWKS(keys map[string]any) map[string]any {
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+
+	var set []map[string]string
+	for kid, public := range keys {
+		switch key := public.(type) {
+		case *rsa.PublicKey:
+			set = append(set, map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))})
+		case *ecdsa.PublicKey:
+			set = append(set, map[string]string{"kty": "EC", "kid": kid, "crv": key.Curve.Params().Name, "x": encode(key.X), "y": encode(key.Y)})
+		}
+	}
+	return map[string]any{"keys": set}
+}
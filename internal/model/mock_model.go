@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 func GetViacepResponseMock(zipCode string) *ViacepResponse {
 	return &ViacepResponse{
 		Erro:        nil,
@@ -67,3 +69,59 @@ func GetWeatherResponseMock(city string) *WeatherResponse {
 	response.Current.Gti = 972
 	return response
 }
+
+func GetObservationMock(city string) *Observation {
+	return &Observation{
+		Source:     "weatherapi",
+		ObservedAt: time.Unix(1768066200, 0).UTC(),
+		Location: ObservationLocation{
+			Name:     city,
+			Region:   "Sao Paulo",
+			Country:  "Brazil",
+			Lat:      -23.5333,
+			Lon:      -46.6167,
+			Timezone: "America/Sao_Paulo",
+		},
+		TemperatureC: 32.2,
+		HumidityPct:  36,
+		Wind: Wind{
+			SpeedKph:     8.6,
+			DirectionDeg: 309,
+			GustKph:      10.7,
+		},
+		PressureMb:      1015,
+		PrecipitationMm: 0.02,
+		Condition: Condition{
+			Text:  "Partly cloudy",
+			Code:  1003,
+			IsDay: true,
+		},
+	}
+}
+
+func GetForecastMock(city string, days int) *Forecast {
+	forecast := &Forecast{
+		Source: "openmeteo",
+		Location: ObservationLocation{
+			Name:     city,
+			Lat:      -23.5333,
+			Lon:      -46.6167,
+			Timezone: "America/Sao_Paulo",
+		},
+	}
+
+	for i := range days {
+		forecast.Days = append(forecast.Days, ForecastDay{
+			Date:            time.Date(2026, 1, 11+i, 0, 0, 0, 0, time.UTC).Format(time.DateOnly),
+			MinC:            19.4,
+			MaxC:            29.8 + float64(i),
+			PrecipitationMm: 3.2,
+			Condition: Condition{
+				Text: "Slight rain",
+				Code: 61,
+			},
+		})
+	}
+
+	return forecast
+}
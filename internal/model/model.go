@@ -20,6 +20,36 @@ type ViacepResponse struct {
 	Siafi       string  `json:"siafi" example:"7107"`
 }
 
+// Address is the normalized, provider-neutral form of a postal address
+type Address struct {
+	Cep          string `json:"cep" example:"01310-100"`
+	Street       string `json:"street" example:"Avenida Paulista"`
+	Complement   string `json:"complement,omitempty" example:"de 612 a 1510 - lado par"`
+	Neighborhood string `json:"neighborhood" example:"Bela Vista"`
+	City         string `json:"city" example:"São Paulo"`
+	UF           string `json:"uf" example:"SP"`
+	State        string `json:"state" example:"São Paulo"`
+	Region       string `json:"region" example:"Sudeste"`
+	Ibge         string `json:"ibge" example:"3550308"`
+	Ddd          string `json:"ddd" example:"11"`
+}
+
+// ToAddress normalizes a ViaCEP response into an Address
+func (v ViacepResponse) ToAddress() Address {
+	return Address{
+		Cep:          v.Cep,
+		Street:       v.Logradouro,
+		Complement:   v.Complemento,
+		Neighborhood: v.Bairro,
+		City:         v.Localidade,
+		UF:           v.Uf,
+		State:        v.Estado,
+		Region:       v.Regiao,
+		Ibge:         v.Ibge,
+		Ddd:          v.Ddd,
+	}
+}
+
 type WeatherResponse struct {
 	Location struct {
 		Name           string  `json:"name"`
@@ -72,6 +102,187 @@ type WeatherResponse struct {
 	} `json:"current"`
 }
 
+// OpenMeteoGeocodingResponse represents the response from Open-Meteo geocoding API
+type OpenMeteoGeocodingResponse struct {
+	Results []OpenMeteoPlace `json:"results"`
+}
+
+// OpenMeteoPlace is a single match from Open-Meteo geocoding API
+type OpenMeteoPlace struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	CountryCode string  `json:"country_code"`
+	Country     string  `json:"country"`
+	Admin1      string  `json:"admin1"`
+	Timezone    string  `json:"timezone"`
+}
+
+// OpenMeteoForecastResponse represents the response from Open-Meteo forecast API
+type OpenMeteoForecastResponse struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UtcOffsetSeconds int     `json:"utc_offset_seconds"`
+	Current          struct {
+		Time               string  `json:"time"`
+		Interval           int     `json:"interval"`
+		Temperature2m      float64 `json:"temperature_2m"`
+		RelativeHumidity2m float64 `json:"relative_humidity_2m"`
+		IsDay              int     `json:"is_day"`
+		Precipitation      float64 `json:"precipitation"`
+		WeatherCode        int     `json:"weather_code"`
+		PressureMsl        float64 `json:"pressure_msl"`
+		WindSpeed10m       float64 `json:"wind_speed_10m"`
+		WindDirection10m   float64 `json:"wind_direction_10m"`
+		WindGusts10m       float64 `json:"wind_gusts_10m"`
+	} `json:"current"`
+}
+
+// OpenMeteoDailyResponse represents the daily variables of the Open-Meteo
+// forecast API, one array entry per day
+type OpenMeteoDailyResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Daily     struct {
+		Time             []string  `json:"time"`
+		Temperature2mMax []float64 `json:"temperature_2m_max"`
+		Temperature2mMin []float64 `json:"temperature_2m_min"`
+		PrecipitationSum []float64 `json:"precipitation_sum"`
+		WeatherCode      []int     `json:"weather_code"`
+	} `json:"daily"`
+}
+
+// WeatherForecastResponse represents the response from WeatherAPI forecast.json
+type WeatherForecastResponse struct {
+	Location struct {
+		Name    string  `json:"name"`
+		Region  string  `json:"region"`
+		Country string  `json:"country"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
+		TzID    string  `json:"tz_id"`
+	} `json:"location"`
+	Forecast struct {
+		Forecastday []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxtempC      float64 `json:"maxtemp_c"`
+				MintempC      float64 `json:"mintemp_c"`
+				TotalprecipMm float64 `json:"totalprecip_mm"`
+				Condition     struct {
+					Text string `json:"text"`
+					Code int    `json:"code"`
+				} `json:"condition"`
+			} `json:"day"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+	Alerts struct {
+		Alert []struct {
+			Headline    string `json:"headline"`
+			Severity    string `json:"severity"`
+			Urgency     string `json:"urgency"`
+			Areas       string `json:"areas"`
+			Event       string `json:"event"`
+			Effective   string `json:"effective"`
+			Expires     string `json:"expires"`
+			Desc        string `json:"desc"`
+			Instruction string `json:"instruction"`
+		} `json:"alert"`
+	} `json:"alerts"`
+}
+
+// Forecast is the provider-neutral daily forecast for a location. Alerts is
+// nil when the provider does not report weather alerts
+type Forecast struct {
+	Source   string              `json:"source" example:"openmeteo"`
+	Location ObservationLocation `json:"location"`
+	Days     []ForecastDay       `json:"days"`
+	Alerts   []Alert             `json:"alerts,omitempty"`
+}
+
+// ForecastDay is the forecast for one local date
+type ForecastDay struct {
+	Date            string    `json:"date" example:"2026-01-11"`
+	MinC            float64   `json:"min_C" example:"19.4"`
+	MaxC            float64   `json:"max_C" example:"29.8"`
+	PrecipitationMm float64   `json:"precip_mm" example:"3.2"`
+	Condition       Condition `json:"condition"`
+}
+
+// Alert is a weather warning in effect for a location
+type Alert struct {
+	Event       string     `json:"event" example:"Heavy rain warning"`
+	Headline    string     `json:"headline" example:"Heavy rain warning issued for São Paulo"`
+	Severity    string     `json:"severity" example:"Moderate"`
+	Urgency     string     `json:"urgency" example:"Expected"`
+	Areas       string     `json:"areas" example:"São Paulo"`
+	Description string     `json:"description" example:"Rain of 30 to 60 mm/day"`
+	Instruction string     `json:"instruction,omitempty"`
+	Effective   *time.Time `json:"effective,omitempty" example:"2026-01-11T09:00:00-03:00"`
+	Expires     *time.Time `json:"expires,omitempty" example:"2026-01-12T09:00:00-03:00"`
+}
+
+// Observation is the provider-neutral representation of a weather reading.
+// Provider clients adapt their own wire format into it, so handlers and
+// conversion never depend on a specific upstream schema
+type Observation struct {
+	Source          string              `json:"source" example:"weatherapi"`
+	ObservedAt      time.Time           `json:"observed_at" example:"2026-01-10T17:30:00Z"`
+	Location        ObservationLocation `json:"location"`
+	TemperatureC    float64             `json:"temp_C" example:"28.5"`
+	HumidityPct     float64             `json:"humidity_pct" example:"36"`
+	Wind            Wind                `json:"wind"`
+	PressureMb      float64             `json:"pressure_mb" example:"1015"`
+	PrecipitationMm float64             `json:"precip_mm" example:"0.02"`
+	Condition       Condition           `json:"condition"`
+	Consensus       *Consensus          `json:"consensus,omitempty"`
+}
+
+// Wind holds wind measurements in km/h and degrees
+type Wind struct {
+	SpeedKph     float64 `json:"speed_kph" example:"8.6"`
+	DirectionDeg float64 `json:"direction_deg" example:"309"`
+	GustKph      float64 `json:"gust_kph" example:"10.7"`
+}
+
+// Condition describes the sky condition. Code is provider specific
+// (WeatherAPI condition code or WMO weather code for Open-Meteo)
+type Condition struct {
+	Text  string `json:"text" example:"Partly cloudy"`
+	Code  int    `json:"code" example:"1003"`
+	IsDay bool   `json:"is_day" example:"true"`
+}
+
+// Consensus describes how an observation was aggregated from several providers
+type Consensus struct {
+	Strategy      string                 `json:"strategy" example:"consensus"`
+	SpreadC       float64                `json:"spread_C" example:"1.2"`
+	Contributions []ProviderContribution `json:"contributions"`
+}
+
+// ProviderContribution is the outcome of querying a single weather provider
+type ProviderContribution struct {
+	Provider     string   `json:"provider" example:"openmeteo"`
+	TemperatureC *float64 `json:"temp_C,omitempty" example:"28.1"`
+	Weight       float64  `json:"weight" example:"1"`
+	Outlier      bool     `json:"outlier"`
+	Error        string   `json:"error,omitempty" example:"Weather API internal error"`
+	LatencyMs    int64    `json:"latency_ms" example:"142"`
+}
+
+// ObservationLocation identifies where an observation was taken
+type ObservationLocation struct {
+	Name     string  `json:"name" example:"São Paulo"`
+	Region   string  `json:"region" example:"São Paulo"`
+	Country  string  `json:"country" example:"Brazil"`
+	Lat      float64 `json:"lat" example:"-23.5475"`
+	Lon      float64 `json:"lon" example:"-46.6361"`
+	Timezone string  `json:"timezone" example:"America/Sao_Paulo"`
+}
+
 // TemperatureResponse represents temperature in different units
 type TemperatureResponse struct {
 	Celsius    float64 `json:"temp_C" example:"28.5"`
@@ -79,11 +290,219 @@ type TemperatureResponse struct {
 	Kelvin     float64 `json:"temp_K" example:"301.65"`
 }
 
+// ExpandedTemperatureResponse adds the sections requested through ?expand= to TemperatureResponse
+type ExpandedTemperatureResponse struct {
+	TemperatureResponse
+	Source       string          `json:"source" example:"consensus"`
+	Providers    *Consensus      `json:"providers,omitempty"`
+	Municipality *Municipality   `json:"municipality,omitempty"`
+	Comfort      *ComfortIndices `json:"comfort,omitempty"`
+	Condition    *Condition      `json:"condition,omitempty"`
+}
+
+// TemperatureUpdate is the temperature event of a live stream, pushed when
+// the observation at the CEP's city changes
+type TemperatureUpdate struct {
+	TemperatureResponse
+	Cep         string    `json:"cep" example:"01310-100"`
+	City        string    `json:"city" example:"São Paulo"`
+	UF          string    `json:"uf" example:"SP"`
+	ObservedAt  time.Time `json:"observed_at" example:"2026-01-10T17:30:00Z"`
+	HumidityPct float64   `json:"humidity_pct" example:"36"`
+}
+
+// StreamHeartbeat is sent on idle live streams so clients and proxies keep
+// the connection open
+type StreamHeartbeat struct {
+	Time time.Time `json:"time" example:"2026-01-10T17:30:15Z"`
+}
+
+// ComfortIndices are computed locally from temperature, humidity and wind, so
+// they do not depend on which provider answered. An index is omitted when its
+// inputs are missing or outside the range where it is defined
+type ComfortIndices struct {
+	HeatIndexC *float64 `json:"heat_index_C,omitempty" example:"31.88"`
+	WindChillC *float64 `json:"wind_chill_C,omitempty" example:"-3.3"`
+	Humidex    *float64 `json:"humidex,omitempty" example:"36.32"`
+	DewPointC  *float64 `json:"dew_point_C,omitempty" example:"15.24"`
+	WBGTC      *float64 `json:"wbgt_C,omitempty" example:"28.98"`
+}
+
+// CityLookupResponse lists the candidates for an unknown city name
+type CityLookupResponse struct {
+	Message     string           `json:"message" example:"can not find city"`
+	Suggestions []CitySuggestion `json:"suggestions"`
+}
+
+// CitySuggestion is a municipality the caller may have meant
+type CitySuggestion struct {
+	Name string `json:"name" example:"Campinas"`
+	UF   string `json:"uf" example:"SP"`
+	Ibge string `json:"ibge" example:"3509502"`
+	URL  string `json:"url" example:"/api/v1/temperature/city/SP/Campinas"`
+}
+
+// Municipality is the municipality nearest to a coordinate lookup
+type Municipality struct {
+	Ibge       string  `json:"ibge" example:"3550308"`
+	Name       string  `json:"name" example:"São Paulo"`
+	UF         string  `json:"uf" example:"SP"`
+	CepPrefix  string  `json:"cep_prefix" example:"01001"`
+	DistanceKm float64 `json:"distance_km" example:"2.6"`
+}
+
+// ConvertRequest is the body of POST /api/v1/convert
+type ConvertRequest struct {
+	Value *float64 `json:"value" binding:"required" example:"-40"`
+	From  string   `json:"from" binding:"required" example:"C"`
+	To    []string `json:"to" binding:"required,min=1,max=20" example:"F,K"`
+}
+
+// ConvertResponse is a value converted into each requested unit
+type ConvertResponse struct {
+	Quantity string           `json:"quantity" example:"temperature"`
+	Value    float64          `json:"value" example:"-40"`
+	From     string           `json:"from" example:"C"`
+	Results  []ConvertedValue `json:"results"`
+}
+
+// ConvertedValue is the converted value in one unit
+type ConvertedValue struct {
+	Unit  string  `json:"unit" example:"F"`
+	Value float64 `json:"value" example:"-40"`
+}
+
+// CepRegionResponse represents the UF and region that own a CEP range
+type CepRegionResponse struct {
+	Cep    string `json:"cep" example:"01310-100"`
+	UF     string `json:"uf" example:"SP"`
+	State  string `json:"state" example:"São Paulo"`
+	Region string `json:"region" example:"Sudeste"`
+}
+
+// AddressSearchResult is an address found by the reverse CEP search, with
+// its current temperature when requested through ?expand=temperature
+type AddressSearchResult struct {
+	Address
+	Temperature *TemperatureResponse `json:"temperature,omitempty"`
+}
+
+// AddressSearchResponse is a page of reverse CEP search results
+type AddressSearchResponse struct {
+	Items      []AddressSearchResult `json:"items"`
+	Page       int                   `json:"page" example:"1"`
+	PageSize   int                   `json:"page_size" example:"10"`
+	Total      int                   `json:"total" example:"23"`
+	TotalPages int                   `json:"total_pages" example:"3"`
+}
+
+// SubscriptionRequest is the body of POST /api/v1/subscriptions
+type SubscriptionRequest struct {
+	Cep       string   `json:"cep" binding:"required" example:"01310100"`
+	Condition string   `json:"condition" binding:"required" enums:"above,below,delta" example:"above"`
+	Threshold *float64 `json:"threshold" binding:"required" example:"8"`
+	URL       string   `json:"url" binding:"required" example:"https://example.com/hooks/temperature"`
+}
+
+// Subscription is a webhook notified when the temperature at a CEP meets its
+// condition. Secret signs the deliveries and is only returned on creation
+type Subscription struct {
+	ID             string     `json:"id" example:"5f0c3a9e2b7d4c18"`
+	Cep            string     `json:"cep" example:"01310-100"`
+	City           string     `json:"city" example:"São Paulo"`
+	UF             string     `json:"uf" example:"SP"`
+	Condition      string     `json:"condition" example:"above"`
+	Threshold      float64    `json:"threshold" example:"8"`
+	URL            string     `json:"url" example:"https://example.com/hooks/temperature"`
+	Secret         string     `json:"secret,omitempty" example:"whsec_6b1f0e8d5c4a39271e0f8d7c6b5a4938"`
+	CreatedAt      time.Time  `json:"created_at" example:"2026-01-10T17:30:00Z"`
+	LastTempC      *float64   `json:"last_temp_C,omitempty" example:"7.5"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty" example:"2026-01-10T17:35:00Z"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty" example:"2026-01-10T17:35:00Z"`
+}
+
+// WebhookPayload is the body posted to a subscription's URL
+type WebhookPayload struct {
+	ID             string    `json:"id" example:"9d2e7b1c4a5f3e60"`
+	Event          string    `json:"event" example:"temperature.above"`
+	SubscriptionID string    `json:"subscription_id" example:"5f0c3a9e2b7d4c18"`
+	Cep            string    `json:"cep" example:"01310-100"`
+	City           string    `json:"city" example:"São Paulo"`
+	UF             string    `json:"uf" example:"SP"`
+	Condition      string    `json:"condition" example:"above"`
+	Threshold      float64   `json:"threshold" example:"8"`
+	TemperatureC   float64   `json:"temp_C" example:"8.4"`
+	PreviousTempC  *float64  `json:"previous_temp_C,omitempty" example:"7.5"`
+	ObservedAt     time.Time `json:"observed_at" example:"2026-01-10T17:30:00Z"`
+}
+
+// DeadLetter is a webhook delivery given up after its last attempt
+type DeadLetter struct {
+	Payload   WebhookPayload `json:"payload"`
+	URL       string         `json:"url" example:"https://example.com/hooks/temperature"`
+	Attempts  int            `json:"attempts" example:"5"`
+	LastError string         `json:"last_error" example:"status 503"`
+	FailedAt  time.Time      `json:"failed_at" example:"2026-01-10T17:36:02Z"`
+}
+
+// RecordedObservation is an observation kept in the history, with the
+// provider that returned it and when it was fetched
+type RecordedObservation struct {
+	Provider        string              `json:"provider" example:"weatherapi"`
+	ObservedAt      time.Time           `json:"observed_at" example:"2026-01-10T17:30:00Z"`
+	FetchedAt       time.Time           `json:"fetched_at" example:"2026-01-10T17:32:11Z"`
+	Location        ObservationLocation `json:"location"`
+	TemperatureC    float64             `json:"temp_C" example:"28.5"`
+	HumidityPct     float64             `json:"humidity_pct" example:"36"`
+	Wind            Wind                `json:"wind"`
+	PressureMb      float64             `json:"pressure_mb" example:"1015"`
+	PrecipitationMm float64             `json:"precip_mm" example:"0.02"`
+	Condition       Condition           `json:"condition"`
+}
+
+// ObservationHistoryResponse lists the observations recorded for the city of
+// a CEP between From and To, oldest first
+type ObservationHistoryResponse struct {
+	Cep          string                `json:"cep" example:"01001-000"`
+	City         string                `json:"city" example:"São Paulo"`
+	UF           string                `json:"uf" example:"SP"`
+	From         time.Time             `json:"from" example:"2026-01-09T17:30:00Z"`
+	To           time.Time             `json:"to" example:"2026-01-10T17:30:00Z"`
+	Observations []RecordedObservation `json:"observations"`
+}
+
+// APIKeyUsage is the quotas and the usage of an API key in the current UTC
+// day and month. Zero quotas are unlimited
+type APIKeyUsage struct {
+	Name         string     `json:"name" example:"partner"`
+	Routes       []string   `json:"routes,omitempty" example:"/api/v1/temperature/*"`
+	Admin        bool       `json:"admin"`
+	DailyQuota   int        `json:"daily_quota" example:"1000"`
+	DailyUsed    int        `json:"daily_used" example:"312"`
+	MonthlyQuota int        `json:"monthly_quota" example:"20000"`
+	MonthlyUsed  int        `json:"monthly_used" example:"4821"`
+	Total        int64      `json:"total" example:"18230"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty" example:"2026-01-10T17:32:11Z"`
+}
+
+// UpstreamQuota is the usage of an upstream API monthly call quota in the
+// current billing period. State is ok, soft_limited or exhausted
+type UpstreamQuota struct {
+	Provider    string    `json:"provider" example:"weatherapi"`
+	State       string    `json:"state" example:"ok"`
+	Used        int64     `json:"used" example:"412093"`
+	Limit       int64     `json:"limit" example:"1000000"`
+	SoftLimit   int64     `json:"soft_limit" example:"800000"`
+	PeriodStart time.Time `json:"period_start" example:"2026-01-01T00:00:00Z"`
+	PeriodEnd   time.Time `json:"period_end" example:"2026-02-01T00:00:00Z"`
+}
+
 // StatusResponse represents the health/readiness status response
 type StatusResponse struct {
-	Status    string    `json:"status" example:"healthy"`
-	Timestamp time.Time `json:"timestamp" example:"2024-01-01T00:00:00Z"`
-	Service   string    `json:"service" example:"lab-cloudrun-api"`
+	Status        string         `json:"status" example:"healthy"`
+	Timestamp     time.Time      `json:"timestamp" example:"2024-01-01T00:00:00Z"`
+	Service       string         `json:"service" example:"lab-cloudrun-api"`
+	UpstreamQuota *UpstreamQuota `json:"upstream_quota,omitempty"`
 }
 
 // ErrorResponse represents an error response
@@ -0,0 +1,173 @@
+package prewarm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/robfig/cron/v3"
+)
+
+// Pre-warm jobs: the configured list of CEPs and the most requested ones
+const (
+	JobCeps = "ceps"
+	JobTop  = "top"
+)
+
+// Scheduler refreshes the CEP and weather caches on cron schedules, so the
+// first request for a popular CEP does not wait for the upstream APIs
+type Scheduler struct {
+	cron        *cron.Cron
+	ceps        *client.CachedCepClient
+	weather     *client.CachedWeatherClient
+	languages   []i18n.Lang
+	jitter      time.Duration
+	concurrency int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler schedules a job for PREWARM_CEPS when the list is not empty
+// and one for the PREWARM_TOP_N most requested CEPs when N is positive
+func NewScheduler(cfg *config.Config, ceps *client.CachedCepClient, weather *client.CachedWeatherClient) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := slogLogger{}
+
+	s := &Scheduler{
+		cron:        cron.New(cron.WithLogger(logger), cron.WithChain(cron.Recover(logger), cron.SkipIfStillRunning(logger))),
+		ceps:        ceps,
+		weather:     weather,
+		languages:   cfg.PrewarmLanguages,
+		jitter:      cfg.PrewarmJitter,
+		concurrency: cfg.PrewarmConcurrency,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	if list := cfg.PrewarmCeps; len(list) > 0 {
+		s.schedule(JobCeps, cfg.PrewarmCepsSchedule, func() []cep.CEP { return list })
+	}
+	if n := cfg.PrewarmTopN; n > 0 {
+		s.schedule(JobTop, cfg.PrewarmTopSchedule, func() []cep.CEP { return ceps.TopRequested(n) })
+	}
+
+	return s
+}
+
+func (s *Scheduler) schedule(job, spec string, codes func() []cep.CEP) {
+	if _, err := s.cron.AddFunc(spec, func() { s.run(job, codes) }); err != nil {
+		slog.Error("Invalid pre-warm schedule", "job", job, "schedule", spec, "error", err)
+	}
+}
+
+// Start runs the jobs on their schedules, until Stop
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop cancels the running jobs and waits for them to return, or for ctx
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+	done := s.cron.Stop()
+
+	select {
+	case <-done.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run waits a random delay of up to the jitter, so instances sharing a
+// schedule do not hit the upstream APIs together, then warms the CEPs with at
+// most concurrency in flight
+func (s *Scheduler) run(job string, codes func() []cep.CEP) {
+	if s.jitter > 0 && !s.sleep(rand.N(s.jitter)) {
+		return
+	}
+
+	start := time.Now()
+	targets := codes()
+
+	var wg sync.WaitGroup
+	var failed atomic.Int64
+	slots := make(chan struct{}, s.concurrency)
+
+dispatch:
+	for _, code := range targets {
+		select {
+		case slots <- struct{}{}:
+		case <-s.ctx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := s.warm(s.ctx, code); err != nil {
+				failed.Add(1)
+				slog.Warn("Failed to pre-warm CEP", "job", job, "cep", code, "error", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	slog.Info("Pre-warm job finished", "job", job, "ceps", len(targets), "failed", failed.Load(), "duration", time.Since(start))
+}
+
+// warm refreshes the address of code and the weather of its city in each
+// pre-warmed language
+func (s *Scheduler) warm(ctx context.Context, code cep.CEP) error {
+	address, err := s.ceps.Refresh(ctx, code)
+	if err != nil {
+		return err
+	}
+	if address.Erro != nil {
+		return fmt.Errorf("cep %s not found", code.Formatted())
+	}
+
+	var errs []error
+	for _, lang := range s.languages {
+		if _, err := s.weather.Refresh(i18n.WithLang(ctx, lang), address.Uf, address.Localidade); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", lang, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sleep waits for d, returning false if the scheduler stops first
+func (s *Scheduler) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// slogLogger routes the cron library's logs to slog; its routine messages go
+// to debug
+type slogLogger struct{}
+
+func (slogLogger) Info(msg string, keysAndValues ...any) {
+	slog.Debug("cron: "+msg, keysAndValues...)
+}
+
+func (slogLogger) Error(err error, msg string, keysAndValues ...any) {
+	slog.Error("cron: "+msg, append(keysAndValues, "error", err)...)
+}
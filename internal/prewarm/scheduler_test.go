@@ -0,0 +1,180 @@
+package prewarm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/i18n"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeCepClient answers every CEP with São Paulo, after delay, and tracks
+// how many lookups ran at once
+type fakeCepClient struct {
+	delay time.Duration
+
+	mu       sync.Mutex
+	calls    []cep.CEP
+	inFlight int
+	peak     int
+}
+
+func (f *fakeCepClient) GetCep(ctx context.Context, code cep.CEP) (*model.ViacepResponse, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, code)
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return model.GetViacepResponseMock(code.Formatted()), nil
+}
+
+func (f *fakeCepClient) SearchAddress(context.Context, string, string, string) ([]model.ViacepResponse, error) {
+	return nil, errors.New("not supported")
+}
+
+// fakeWeatherClient records the language of each lookup
+type fakeWeatherClient struct {
+	mu    sync.Mutex
+	langs []i18n.Lang
+}
+
+func (f *fakeWeatherClient) GetWeather(ctx context.Context, city string) (*model.Observation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.langs = append(f.langs, i18n.FromContext(ctx))
+	return model.GetObservationMock(city), nil
+}
+
+func (f *fakeWeatherClient) GetWeatherByCoordinates(context.Context, float64, float64) (*model.Observation, error) {
+	return nil, errors.New("not supported")
+}
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	cfg       *config.Config
+	cepClient *fakeCepClient
+	weather   *fakeWeatherClient
+	cepCache  *client.CachedCepClient
+	scheduler *Scheduler
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.cfg = &config.Config{
+		PrewarmCeps:         []cep.CEP{cep.MustParse("01001000"), cep.MustParse("20040020")},
+		PrewarmCepsSchedule: "*/10 * * * *",
+		PrewarmTopN:         2,
+		PrewarmTopSchedule:  "@hourly",
+		PrewarmLanguages:    []i18n.Lang{i18n.English, i18n.Portuguese},
+		PrewarmConcurrency:  2,
+	}
+	s.cepClient = &fakeCepClient{}
+	s.weather = &fakeWeatherClient{}
+	s.cepCache = client.NewCachedCepClient(s.cepClient, time.Hour)
+	s.scheduler = NewScheduler(s.cfg, s.cepCache, client.NewCachedWeatherClient(s.weather, time.Hour))
+}
+
+func (s *SchedulerTestSuite) TearDownTest() {
+	_ = s.scheduler.Stop(context.Background())
+}
+
+func (s *SchedulerTestSuite) TestNewScheduler_SchedulesConfiguredJobs() {
+	// act
+	entries := s.scheduler.cron.Entries()
+	withoutJobs := NewScheduler(&config.Config{PrewarmConcurrency: 1}, s.cepCache, nil)
+
+	// assert
+	assert.Len(s.T(), entries, 2)
+	assert.Empty(s.T(), withoutJobs.cron.Entries())
+}
+
+func (s *SchedulerTestSuite) TestRun_WarmsCepsInEachLanguage() {
+	// act
+	s.scheduler.run(JobCeps, func() []cep.CEP { return s.cfg.PrewarmCeps })
+
+	// assert
+	assert.ElementsMatch(s.T(), s.cfg.PrewarmCeps, s.cepClient.calls)
+	assert.ElementsMatch(s.T(), []i18n.Lang{i18n.English, i18n.Portuguese, i18n.English, i18n.Portuguese}, s.weather.langs)
+
+	_, err := s.cepCache.GetCep(context.Background(), cep.MustParse("01001000"))
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), s.cepClient.calls, 2, "the warmed CEP is served from the cache")
+}
+
+func (s *SchedulerTestSuite) TestRun_WarmsTheMostRequestedCeps() {
+	// arrange
+	for code, requests := range map[string]int{"01001000": 1, "20040020": 3, "30130010": 2} {
+		for range requests {
+			_, _ = s.cepCache.GetCep(context.Background(), cep.MustParse(code))
+		}
+	}
+	s.cepClient.calls = nil
+
+	// act
+	s.scheduler.run(JobTop, func() []cep.CEP { return s.cepCache.TopRequested(s.cfg.PrewarmTopN) })
+
+	// assert
+	assert.ElementsMatch(s.T(), []cep.CEP{cep.MustParse("20040020"), cep.MustParse("30130010")}, s.cepClient.calls)
+}
+
+func (s *SchedulerTestSuite) TestRun_LimitsConcurrency() {
+	// arrange
+	s.cepClient.delay = 20 * time.Millisecond
+	codes := []cep.CEP{
+		cep.MustParse("01001000"), cep.MustParse("20040020"), cep.MustParse("30130010"),
+		cep.MustParse("40020000"), cep.MustParse("70040010"),
+	}
+
+	// act
+	s.scheduler.run(JobCeps, func() []cep.CEP { return codes })
+
+	// assert
+	assert.Len(s.T(), s.cepClient.calls, len(codes))
+	assert.Equal(s.T(), s.cfg.PrewarmConcurrency, s.cepClient.peak)
+}
+
+func (s *SchedulerTestSuite) TestStop_CancelsTheJitterWait() {
+	// arrange
+	s.scheduler.jitter = time.Hour
+	done := make(chan struct{})
+	go func() {
+		s.scheduler.run(JobCeps, func() []cep.CEP { return s.cfg.PrewarmCeps })
+		close(done)
+	}()
+
+	// act
+	err := s.scheduler.Stop(context.Background())
+
+	// assert
+	require.NoError(s.T(), err)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("the job kept waiting after Stop")
+	}
+	assert.Empty(s.T(), s.cepClient.calls)
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// states numbers the quota states for the state gauge
+var states = map[string]float64{
+	StateOK:          0,
+	StateSoftLimited: 1,
+	StateExhausted:   2,
+}
+
+// Collectors exposes the quota usage as metrics read from the tracker on
+// every scrape, labelled with the provider
+func (t *Tracker) Collectors() []prometheus.Collector {
+	labels := prometheus.Labels{"provider": t.provider}
+	gauge := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        name,
+			Help:        help,
+			ConstLabels: labels,
+		}, value)
+	}
+
+	return []prometheus.Collector{
+		gauge("upstream_quota_used_calls", "Upstream calls made in the current billing period.", func() float64 {
+			return float64(t.Status().Used)
+		}),
+		gauge("upstream_quota_limit_calls", "Upstream calls allowed per billing period.", func() float64 {
+			return float64(t.limit)
+		}),
+		gauge("upstream_quota_soft_limit_calls", "Upstream calls past which the calls degrade.", func() float64 {
+			return float64(t.softLimit)
+		}),
+		gauge("upstream_quota_state", "Quota state: 0 ok, 1 soft limited, 2 exhausted.", func() float64 {
+			return states[t.State()]
+		}),
+		gauge("upstream_quota_period_end_timestamp_seconds", "End of the current billing period.", func() float64 {
+			return float64(t.Status().PeriodEnd.Unix())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "upstream_quota_rejected_calls_total",
+			Help:        "Upstream calls refused because the quota was exhausted.",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(t.Rejected())
+		}),
+	}
+}
@@ -0,0 +1,238 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/atomicfile"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Quota states: under the soft limit, past it, and at the quota
+const (
+	StateOK          = "ok"
+	StateSoftLimited = "soft_limited"
+	StateExhausted   = "exhausted"
+)
+
+// usage is the count of the current billing period, as persisted in
+// WEATHERAPI_QUOTA_FILE
+type usage struct {
+	PeriodStart time.Time `json:"period_start"`
+	Calls       int64     `json:"calls"`
+}
+
+// Tracker counts the calls made to an upstream API in the current billing
+// period against its monthly quota. The count is kept in memory and, with a
+// quota file, saved every interval and on Close, so restarts keep it
+type Tracker struct {
+	mu         sync.Mutex
+	provider   string
+	limit      int64
+	softLimit  int64
+	billingDay int
+	usage      usage
+	rejected   uint64
+	dirty      bool
+	now        func() time.Time
+
+	path     string
+	interval time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Load builds the WeatherAPI tracker from the WEATHERAPI_* settings,
+// restoring the count saved in WEATHERAPI_QUOTA_FILE. It returns nil when
+// WEATHERAPI_MONTHLY_QUOTA is zero
+func Load(cfg *config.Config) (*Tracker, error) {
+	if cfg.WeatherAPIMonthlyQuota == 0 {
+		return nil, nil
+	}
+
+	softLimit := cfg.WeatherAPIMonthlyQuota * int64(cfg.WeatherAPIQuotaSoftPercent) / 100
+	tracker := NewTracker("weatherapi", cfg.WeatherAPIMonthlyQuota, softLimit, cfg.WeatherAPIBillingDay)
+	tracker.path = cfg.WeatherAPIQuotaFile
+	tracker.interval = cfg.WeatherAPIQuotaFlushInterval
+
+	if tracker.path != "" {
+		if err := tracker.restore(); err != nil {
+			return nil, err
+		}
+	}
+	return tracker, nil
+}
+
+// NewTracker builds a tracker without persistence. Billing periods start on
+// billingDay at 00:00 UTC
+func NewTracker(provider string, limit, softLimit int64, billingDay int) *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Tracker{
+		provider:   provider,
+		limit:      limit,
+		softLimit:  softLimit,
+		billingDay: billingDay,
+		now:        time.Now,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Take counts a call, unless the quota is exhausted; refused calls are
+// counted apart, as rejected
+func (t *Tracker) Take() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.roll()
+	if t.usage.Calls >= t.limit {
+		t.rejected++
+		return false
+	}
+
+	t.usage.Calls++
+	t.dirty = true
+	return true
+}
+
+// State is the state of the quota in the current billing period
+func (t *Tracker) State() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.roll()
+	return t.state()
+}
+
+// Degraded reports whether the soft limit was reached
+func (t *Tracker) Degraded() bool {
+	return t.State() != StateOK
+}
+
+func (t *Tracker) state() string {
+	switch {
+	case t.usage.Calls >= t.limit:
+		return StateExhausted
+	case t.usage.Calls >= t.softLimit:
+		return StateSoftLimited
+	default:
+		return StateOK
+	}
+}
+
+// Status returns the usage of the quota in the current billing period
+func (t *Tracker) Status() model.UpstreamQuota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.roll()
+	return model.UpstreamQuota{
+		Provider:    t.provider,
+		State:       t.state(),
+		Used:        t.usage.Calls,
+		Limit:       t.limit,
+		SoftLimit:   t.softLimit,
+		PeriodStart: t.usage.PeriodStart,
+		PeriodEnd:   t.usage.PeriodStart.AddDate(0, 1, 0),
+	}
+}
+
+// Rejected is how many calls were refused at the quota since the start
+func (t *Tracker) Rejected() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rejected
+}
+
+// roll starts a new count when the billing period changed
+func (t *Tracker) roll() {
+	if start := t.periodStart(t.now().UTC()); !t.usage.PeriodStart.Equal(start) {
+		t.usage = usage{PeriodStart: start}
+		t.dirty = true
+	}
+}
+
+// periodStart is the start of the billing period holding now
+func (t *Tracker) periodStart(now time.Time) time.Time {
+	year, month, day := now.Date()
+	if day < t.billingDay {
+		month--
+	}
+	return time.Date(year, month, t.billingDay, 0, 0, 0, 0, time.UTC)
+}
+
+// Start saves the count every interval, until Close. Without a quota file
+// it does nothing
+func (t *Tracker) Start() {
+	if t.path == "" {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.save(); err != nil {
+					slog.Error("Failed to save upstream quota usage", "provider", t.provider, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the periodic saves and saves the count a last time
+func (t *Tracker) Close() error {
+	t.cancel()
+	t.wg.Wait()
+
+	if t.path == "" {
+		return nil
+	}
+	return t.save()
+}
+
+// restore reads the quota file. A missing or unreadable file is an empty
+// count, and a count of a past period is dropped by the next roll
+func (t *Tracker) restore() error {
+	saved, err := atomicfile.ReadJSON[usage](t.path)
+	if err != nil {
+		return fmt.Errorf("read upstream quota usage: %w", err)
+	}
+	t.usage = saved
+	return nil
+}
+
+// save writes the quota file if the count changed since the last save
+func (t *Tracker) save() error {
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	saved := t.usage
+	t.dirty = false
+	t.mu.Unlock()
+
+	if err := atomicfile.WriteJSON(t.path, saved); err != nil {
+		t.mu.Lock()
+		t.dirty = true
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrackerTestSuite struct {
+	suite.Suite
+	now     time.Time
+	tracker *Tracker
+}
+
+func (s *TrackerTestSuite) SetupTest() {
+	s.now = time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)
+	s.tracker = NewTracker("weatherapi", 4, 2, 15)
+	s.tracker.now = func() time.Time { return s.now }
+}
+
+func (s *TrackerTestSuite) take(n int) {
+	for range n {
+		require.True(s.T(), s.tracker.Take())
+	}
+}
+
+func (s *TrackerTestSuite) TestTake_MovesThroughTheStates() {
+	// act
+	initial := s.tracker.State()
+	s.take(2)
+	soft := s.tracker.State()
+	s.take(2)
+	exhausted := s.tracker.State()
+	refused := s.tracker.Take()
+
+	// assert
+	assert.Equal(s.T(), StateOK, initial)
+	assert.Equal(s.T(), StateSoftLimited, soft)
+	assert.Equal(s.T(), StateExhausted, exhausted)
+	assert.False(s.T(), refused)
+	assert.Equal(s.T(), int64(4), s.tracker.Status().Used)
+	assert.Equal(s.T(), uint64(1), s.tracker.Rejected())
+}
+
+func (s *TrackerTestSuite) TestStatus_BillingPeriodStartsOnTheBillingDay() {
+	// arrange
+	s.take(4)
+
+	// act
+	before := s.tracker.Status()
+	s.now = s.now.Add(time.Hour)
+	after := s.tracker.Status()
+
+	// assert
+	assert.Equal(s.T(), time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), before.PeriodStart)
+	assert.Equal(s.T(), time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), before.PeriodEnd)
+	assert.Equal(s.T(), StateExhausted, before.State)
+
+	assert.Equal(s.T(), time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), after.PeriodStart)
+	assert.Equal(s.T(), int64(0), after.Used)
+	assert.Equal(s.T(), StateOK, after.State)
+}
+
+func (s *TrackerTestSuite) TestCollectors() {
+	// arrange
+	s.take(3)
+	s.tracker.Take()
+	s.tracker.Take()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(s.tracker.Collectors()...)
+
+	// act
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP upstream_quota_used_calls Upstream calls made in the current billing period.
+# TYPE upstream_quota_used_calls gauge
+upstream_quota_used_calls{provider="weatherapi"} 4
+# HELP upstream_quota_state Quota state: 0 ok, 1 soft limited, 2 exhausted.
+# TYPE upstream_quota_state gauge
+upstream_quota_state{provider="weatherapi"} 2
+# HELP upstream_quota_rejected_calls_total Upstream calls refused because the quota was exhausted.
+# TYPE upstream_quota_rejected_calls_total counter
+upstream_quota_rejected_calls_total{provider="weatherapi"} 1
+`), "upstream_quota_used_calls", "upstream_quota_state", "upstream_quota_rejected_calls_total")
+
+	// assert
+	assert.NoError(s.T(), err)
+}
+
+func TestTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
+
+func TestLoad_DisabledWithoutQuota(t *testing.T) {
+	// act
+	tracker, err := Load(&config.Config{WeatherAPIMonthlyQuota: 0})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Nil(t, tracker)
+}
+
+func TestLoad_RestoresTheSavedCount(t *testing.T) {
+	// arrange
+	cfg := &config.Config{
+		WeatherAPIMonthlyQuota:       10,
+		WeatherAPIQuotaSoftPercent:   80,
+		WeatherAPIBillingDay:         1,
+		WeatherAPIQuotaFile:          filepath.Join(t.TempDir(), "quota.json"),
+		WeatherAPIQuotaFlushInterval: time.Minute,
+	}
+	tracker, err := Load(cfg)
+	require.NoError(t, err)
+	tracker.Start()
+	for range 8 {
+		tracker.Take()
+	}
+	require.NoError(t, tracker.Close())
+
+	// act
+	restored, err := Load(cfg)
+
+	// assert
+	require.NoError(t, err)
+	status := restored.Status()
+	assert.Equal(t, int64(8), status.Used)
+	assert.Equal(t, int64(8), status.SoftLimit)
+	assert.Equal(t, StateSoftLimited, status.State)
+}
+
+func TestLoad_UnreadableQuotaFileStartsEmpty(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "quota.json")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	// act
+	tracker, err := Load(&config.Config{
+		WeatherAPIMonthlyQuota:       10,
+		WeatherAPIQuotaSoftPercent:   80,
+		WeatherAPIBillingDay:         1,
+		WeatherAPIQuotaFile:          path,
+		WeatherAPIQuotaFlushInterval: time.Minute,
+	})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), tracker.Status().Used)
+	assert.Equal(t, StateOK, tracker.Status().State)
+}
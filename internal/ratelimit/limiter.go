@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"golang.org/x/time/rate"
+)
+
+var ErrLimited = errors.New("rate limit exceeded")
+
+// Result is the state of a bucket after a request: its size, the whole
+// tokens left, how long until it is full again and, when the request was
+// refused, how long until the next token
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+	// Window is how long an empty bucket takes to fill up
+	Window time.Duration
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps a token bucket per client, such as an IP address or an API
+// key name. Buckets idle for the idle timeout are swept while serving
+// requests; they are full again by then, so dropping them changes nothing
+type Limiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	idle      time.Duration
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewLimiter(limit config.RateLimit, idle time.Duration) *Limiter {
+	return &Limiter{
+		limit:   rate.Limit(limit.Rate),
+		burst:   limit.Burst,
+		idle:    idle,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of client
+func (l *Limiter) Allow(client string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= l.idle {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[client] = b
+	}
+	b.lastSeen = now
+
+	allowed := b.limiter.AllowN(now, 1)
+	tokens := b.limiter.TokensAt(now)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     l.burst,
+		Remaining: max(int(math.Floor(tokens)), 0),
+		Reset:     l.wait(float64(l.burst) - tokens),
+		Window:    l.wait(float64(l.burst)),
+	}
+	if !allowed {
+		result.RetryAfter = l.wait(1 - tokens)
+	}
+	return result
+}
+
+// wait is how long the bucket takes to gain tokens
+func (l *Limiter) wait(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / float64(l.limit) * float64(time.Second))
+}
+
+// sweep drops the buckets idle for the idle timeout that are full again
+func (l *Limiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.idle && b.limiter.TokensAt(now) >= float64(l.burst) {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Groups keeps a limiter per route group. Groups without a limit have no
+// limiter, and a nil limiter lets every request through
+type Groups map[string]*Limiter
+
+func NewGroups(limits map[string]config.RateLimit, idle time.Duration) Groups {
+	groups := make(Groups, len(limits))
+	for group, limit := range limits {
+		groups[group] = NewLimiter(limit, idle)
+	}
+	return groups
+}
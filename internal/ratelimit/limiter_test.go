@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+	now     time.Time
+	limiter *Limiter
+}
+
+func (s *LimiterTestSuite) SetupTest() {
+	s.now = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	s.limiter = NewLimiter(config.RateLimit{Rate: 2, Burst: 3}, time.Minute)
+	s.limiter.now = func() time.Time { return s.now }
+}
+
+func (s *LimiterTestSuite) TestAllow_UpToTheBurst() {
+	// act
+	var results []Result
+	for range 4 {
+		results = append(results, s.limiter.Allow("203.0.113.7"))
+	}
+
+	// assert
+	assert.Equal(s.T(), Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond, Window: 1500 * time.Millisecond}, results[0])
+	assert.True(s.T(), results[2].Allowed)
+	assert.Equal(s.T(), 0, results[2].Remaining)
+	assert.Equal(s.T(), Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond, Window: 1500 * time.Millisecond}, results[3])
+}
+
+func (s *LimiterTestSuite) TestAllow_Refills() {
+	// arrange
+	for range 3 {
+		s.limiter.Allow("203.0.113.7")
+	}
+	s.now = s.now.Add(time.Second)
+
+	// act
+	result := s.limiter.Allow("203.0.113.7")
+
+	// assert: a second brings two tokens back
+	assert.True(s.T(), result.Allowed)
+	assert.Equal(s.T(), 1, result.Remaining)
+}
+
+func (s *LimiterTestSuite) TestAllow_BucketPerClient() {
+	// arrange
+	for range 3 {
+		s.limiter.Allow("203.0.113.7")
+	}
+
+	// act
+	result := s.limiter.Allow("198.51.100.20")
+
+	// assert
+	assert.True(s.T(), result.Allowed)
+	assert.Equal(s.T(), 2, result.Remaining)
+}
+
+func (s *LimiterTestSuite) TestAllow_SweepsIdleBuckets() {
+	// arrange
+	s.limiter.Allow("203.0.113.7")
+	s.now = s.now.Add(50 * time.Second)
+	s.limiter.Allow("198.51.100.20")
+	s.now = s.now.Add(20 * time.Second)
+
+	// act
+	s.limiter.Allow("192.0.2.1")
+
+	// assert: only the bucket idle for a minute is gone
+	assert.Len(s.T(), s.limiter.buckets, 2)
+	assert.NotContains(s.T(), s.limiter.buckets, "203.0.113.7")
+}
+
+func (s *LimiterTestSuite) TestAllow_KeepsBucketsNotFull() {
+	// arrange
+	slow := NewLimiter(config.RateLimit{Rate: 0.01, Burst: 2}, time.Minute)
+	slow.now = func() time.Time { return s.now }
+	slow.Allow("203.0.113.7")
+	s.now = s.now.Add(61 * time.Second)
+
+	// act
+	slow.Allow("198.51.100.20")
+
+	// assert: its token is not back yet, so dropping it would hand one out
+	assert.Contains(s.T(), slow.buckets, "203.0.113.7")
+}
+
+func TestLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
+
+func TestNewGroups(t *testing.T) {
+	// act
+	groups := NewGroups(map[string]config.RateLimit{"api": {Rate: 1, Burst: 1}}, time.Minute)
+
+	// assert
+	assert.NotNil(t, groups["api"])
+	assert.Nil(t, groups["graphql"])
+}
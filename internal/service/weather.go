@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alexduzi/labcloudrun/internal/cep"
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/conversor"
+	"github.com/alexduzi/labcloudrun/internal/geo"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Forecast length accepted by Forecast and ForecastByCep
+const (
+	MinForecastDays     = 1
+	MaxForecastDays     = 7
+	DefaultForecastDays = 3
+)
+
+// WeatherService holds the lookups shared by the REST and gRPC APIs, so both
+// validate, call the providers and convert temperatures the same way
+type WeatherService struct {
+	config         *config.Config
+	cepClient      client.CepClientInterface
+	weatherClient  client.WeatherClientInterface
+	municipalities *geo.Dataset
+}
+
+// NewWeatherService uses the bundled municipalities when none are given
+func NewWeatherService(
+	cfg *config.Config,
+	cepClient client.CepClientInterface,
+	weatherClient client.WeatherClientInterface,
+	municipalities *geo.Dataset) *WeatherService {
+
+	if municipalities == nil {
+		municipalities = geo.Default()
+	}
+
+	return &WeatherService{
+		config:         cfg,
+		cepClient:      cepClient,
+		weatherClient:  weatherClient,
+		municipalities: municipalities,
+	}
+}
+
+// CepTemperature is the current temperature at the city of a CEP
+type CepTemperature struct {
+	Cep         cep.CEP
+	Address     *model.ViacepResponse
+	Observation *model.Observation
+	Temperature model.TemperatureResponse
+}
+
+// CepForecast is the daily forecast for the city of a CEP
+type CepForecast struct {
+	Cep      cep.CEP
+	Address  *model.ViacepResponse
+	Forecast *model.Forecast
+}
+
+// ResolveCep parses raw, looks it up and checks the returned UF against the
+// CEP range
+func (s *WeatherService) ResolveCep(ctx context.Context, raw string) (cep.CEP, *model.ViacepResponse, error) {
+	code, err := cep.Parse(raw)
+	if err != nil {
+		slog.Error("Invalid CEP", "cep", raw, "error", err)
+		return code, nil, hErrors.CepInvalid
+	}
+
+	cepModel, err := s.cepClient.GetCep(ctx, code)
+	if err != nil {
+		slog.Error("Failed to get CEP information", "cep", code, "error", err)
+		return code, nil, err
+	}
+
+	if cepModel.Erro != nil {
+		slog.Error("CEP not found", "cep", code)
+		return code, nil, hErrors.CepCantFind
+	}
+
+	if err := s.checkUF(code, cepModel.Uf); err != nil {
+		return code, nil, err
+	}
+
+	return code, cepModel, nil
+}
+
+// AddressOf normalizes a ViaCEP response, filling the state and region that
+// older ViaCEP payloads lack from the UF
+func AddressOf(cepModel *model.ViacepResponse) model.Address {
+	address := cepModel.ToAddress()
+
+	if state, ok := cep.StateOf(address.UF); ok {
+		if address.State == "" {
+			address.State = state.Name
+		}
+		if address.Region == "" {
+			address.Region = state.Region
+		}
+	}
+
+	return address
+}
+
+// TemperatureByCep returns the current temperature at the city of raw
+func (s *WeatherService) TemperatureByCep(ctx context.Context, raw string) (*CepTemperature, error) {
+	code, cepModel, err := s.ResolveCep(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	observation, err := s.Observe(ctx, cepModel.Uf, cepModel.Localidade)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CepTemperature{
+		Cep:         code,
+		Address:     cepModel,
+		Observation: observation,
+		Temperature: conversor.ConvertObservation(*observation),
+	}, nil
+}
+
+// Observe returns the current weather in city, in the state uf
+func (s *WeatherService) Observe(ctx context.Context, uf, city string) (*model.Observation, error) {
+	observation, err := client.GetCityWeather(ctx, s.weatherClient, uf, city)
+	if err != nil {
+		slog.Error("Failed to get weather information", "location", city, "uf", uf, "error", err)
+		return nil, err
+	}
+
+	return observation, nil
+}
+
+// ObserveFresh returns the current weather in city, in the state uf, without
+// the cached observation, for the streams and webhooks that poll it
+func (s *WeatherService) ObserveFresh(ctx context.Context, uf, city string) (*model.Observation, error) {
+	fresh, ok := s.weatherClient.(client.FreshWeatherClientInterface)
+	if !ok {
+		return s.Observe(ctx, uf, city)
+	}
+
+	observation, err := fresh.GetFreshCityWeather(ctx, uf, city)
+	if err != nil {
+		slog.Error("Failed to get weather information", "location", city, "uf", uf, "error", err)
+		return nil, err
+	}
+
+	return observation, nil
+}
+
+// CityCoordinates places a city with the municipality dataset; ok is false
+// when the name does not match exactly one municipality of uf
+func (s *WeatherService) CityCoordinates(uf, city string) (lat, lon float64, ok bool) {
+	match := s.municipalities.FindCity(uf, city)
+	if len(match.Matches) != 1 {
+		return 0, 0, false
+	}
+
+	return match.Matches[0].Lat, match.Matches[0].Lon, true
+}
+
+// Forecast returns days of daily forecast at the coordinates
+func (s *WeatherService) Forecast(ctx context.Context, lat, lon float64, days int) (*model.Forecast, error) {
+	if !geo.ValidCoordinates(lat, lon) {
+		return nil, hErrors.CoordinatesInvalid
+	}
+	if days < MinForecastDays || days > MaxForecastDays {
+		return nil, hErrors.ForecastDaysInvalid
+	}
+
+	forecaster, ok := s.weatherClient.(client.ForecastClientInterface)
+	if !ok {
+		return nil, cErrors.ForecastUnsupported
+	}
+
+	forecast, err := forecaster.GetForecast(ctx, lat, lon, days)
+	if err != nil {
+		slog.Error("Failed to get forecast", "lat", lat, "lon", lon, "days", days, "error", err)
+		return nil, err
+	}
+
+	return forecast, nil
+}
+
+// ForecastByCep returns days of daily forecast at the city of raw. The city
+// is placed with the municipality dataset, falling back to the location the
+// weather provider reports for its name
+func (s *WeatherService) ForecastByCep(ctx context.Context, raw string, days int) (*CepForecast, error) {
+	if days < MinForecastDays || days > MaxForecastDays {
+		return nil, hErrors.ForecastDaysInvalid
+	}
+
+	code, cepModel, err := s.ResolveCep(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	lat, lon, ok := s.CityCoordinates(cepModel.Uf, cepModel.Localidade)
+	if !ok {
+		observation, err := s.Observe(ctx, cepModel.Uf, cepModel.Localidade)
+		if err != nil {
+			return nil, err
+		}
+		lat, lon = observation.Location.Lat, observation.Location.Lon
+	}
+
+	forecast, err := s.Forecast(ctx, lat, lon, days)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CepForecast{Cep: code, Address: cepModel, Forecast: forecast}, nil
+}
+
+// checkUF compares the UF returned by ViaCEP with the one expected for the
+// CEP range. Mismatches are logged, and rejected when CEP_UF_MISMATCH=reject
+func (s *WeatherService) checkUF(code cep.CEP, uf string) error {
+	if uf == "" || code.MatchesUF(uf) {
+		return nil
+	}
+
+	slog.Warn("ViaCEP UF does not match CEP range", "cep", code, "expected_uf", code.UF(), "viacep_uf", uf)
+
+	if s.config.CepUFMismatch == config.CepUFMismatchReject {
+		return hErrors.CepUFMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/client"
+	cErrors "github.com/alexduzi/labcloudrun/internal/client/error"
+	"github.com/alexduzi/labcloudrun/internal/config"
+	hErrors "github.com/alexduzi/labcloudrun/internal/http/error"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WeatherServiceTestSuite struct {
+	suite.Suite
+	config        *config.Config
+	cepClient     *client.CepClientStub
+	weatherClient *client.WeatherClientStub
+	service       *WeatherService
+}
+
+func (s *WeatherServiceTestSuite) SetupTest() {
+	s.config = &config.Config{CepUFMismatch: config.CepUFMismatchWarn}
+	s.cepClient = client.NewCepClientStub(s.config)
+	s.weatherClient = client.NewWeatherClientStub(s.config)
+	s.service = NewWeatherService(s.config, s.cepClient, s.weatherClient, nil)
+}
+
+func (s *WeatherServiceTestSuite) TestResolveCep_UFMismatchRejected() {
+	// arrange
+	s.config.CepUFMismatch = config.CepUFMismatchReject
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("20040-002"), nil)
+
+	// act
+	_, address, err := s.service.ResolveCep(context.Background(), "20040002")
+
+	// assert
+	assert.Nil(s.T(), address)
+	assert.ErrorIs(s.T(), err, hErrors.CepUFMismatch)
+}
+
+func (s *WeatherServiceTestSuite) TestTemperatureByCep() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+
+	// act
+	result, err := s.service.TemperatureByCep(context.Background(), "01001-000")
+
+	// assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "01001-000", result.Cep.Formatted())
+	assert.Equal(s.T(), model.TemperatureResponse{Celsius: 32.2, Fahrenheit: 89.96, Kelvin: 305.35}, result.Temperature)
+}
+
+func (s *WeatherServiceTestSuite) TestObserveFresh_SkipsTheWeatherCache() {
+	// arrange
+	s.weatherClient.On("GetWeather", mock.Anything, "São Paulo").Return(model.GetObservationMock("São Paulo"), nil)
+	service := NewWeatherService(s.config, s.cepClient, client.NewCachedWeatherClient(s.weatherClient, time.Hour), nil)
+
+	// act
+	_, err := service.Observe(context.Background(), "SP", "São Paulo")
+	_, cachedErr := service.Observe(context.Background(), "SP", "São Paulo")
+	_, freshErr := service.ObserveFresh(context.Background(), "SP", "São Paulo")
+
+	// assert
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), cachedErr)
+	assert.NoError(s.T(), freshErr)
+	s.weatherClient.AssertNumberOfCalls(s.T(), "GetWeather", 2)
+}
+
+func (s *WeatherServiceTestSuite) TestForecast_Validation() {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		days     int
+		err      error
+	}{
+		{"zero days", -23.55, -46.63, 0, hErrors.ForecastDaysInvalid},
+		{"too many days", -23.55, -46.63, MaxForecastDays + 1, hErrors.ForecastDaysInvalid},
+		{"invalid latitude", -95, -46.63, 3, hErrors.CoordinatesInvalid},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// act
+			forecast, err := s.service.Forecast(context.Background(), tt.lat, tt.lon, tt.days)
+
+			// assert
+			assert.Nil(s.T(), forecast)
+			assert.ErrorIs(s.T(), err, tt.err)
+		})
+	}
+}
+
+func (s *WeatherServiceTestSuite) TestForecast_ProviderWithoutForecast() {
+	// arrange
+	service := NewWeatherService(s.config, s.cepClient, currentOnly{s.weatherClient}, nil)
+
+	// act
+	forecast, err := service.Forecast(context.Background(), -23.55, -46.63, 3)
+
+	// assert
+	assert.Nil(s.T(), forecast)
+	assert.ErrorIs(s.T(), err, cErrors.ForecastUnsupported)
+}
+
+func (s *WeatherServiceTestSuite) TestForecastByCep_UsesMunicipalityCoordinates() {
+	// arrange
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(model.GetViacepResponseMock("01001-000"), nil)
+	s.weatherClient.On("GetForecast", mock.Anything, mock.Anything, mock.Anything, 3).Return(model.GetForecastMock("", 3), nil)
+
+	// act
+	result, err := s.service.ForecastByCep(context.Background(), "01001000", 3)
+
+	// assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Forecast.Days, 3)
+	s.weatherClient.AssertNotCalled(s.T(), "GetWeather", mock.Anything, mock.Anything)
+}
+
+func (s *WeatherServiceTestSuite) TestForecastByCep_FallsBackToProviderLocation() {
+	// arrange
+	address := model.GetViacepResponseMock("01001-000")
+	address.Localidade = "Cidade Inexistente"
+	observation := model.GetObservationMock("Cidade Inexistente")
+
+	s.cepClient.On("GetCep", mock.Anything, mock.Anything).Return(address, nil)
+	s.weatherClient.On("GetWeather", mock.Anything, "Cidade Inexistente").Return(observation, nil)
+	s.weatherClient.On("GetForecast", mock.Anything, observation.Location.Lat, observation.Location.Lon, 2).Return(model.GetForecastMock("", 2), nil)
+
+	// act
+	result, err := s.service.ForecastByCep(context.Background(), "01001000", 2)
+
+	// assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.Forecast.Days, 2)
+}
+
+// currentOnly hides GetForecast from a stub, like a provider without forecasts
+type currentOnly struct {
+	client.WeatherClientInterface
+}
+
+func TestWeatherServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WeatherServiceTestSuite))
+}
@@ -0,0 +1,201 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// ErrClosed is returned by Subscribe once the hub is closed
+var ErrClosed = errors.New("stream hub closed")
+
+// FetchFunc returns the current observation at a location
+type FetchFunc func(ctx context.Context) (*model.Observation, error)
+
+// Update is pushed to subscribers when a location's observation changes, or
+// with Err when polling it fails
+type Update struct {
+	Observation *model.Observation
+	Err         error
+}
+
+// Hub polls each subscribed location once, however many subscribers it has,
+// and fans the updates out to all of them. A location stops being polled when
+// its last subscriber leaves
+type Hub struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	pollers map[string]*poller
+	closed  bool
+}
+
+// Subscription receives the updates of a location on C. C is closed when the
+// hub is closed
+type Subscription struct {
+	C <-chan Update
+
+	ch     chan Update
+	hub    *Hub
+	poller *poller
+	once   sync.Once
+}
+
+type poller struct {
+	key         string
+	cancel      context.CancelFunc
+	subscribers map[*Subscription]struct{}
+	last        *Update
+}
+
+// NewHub polls every interval
+func NewHub(interval time.Duration) *Hub {
+	return &Hub{
+		interval: interval,
+		pollers:  make(map[string]*poller),
+	}
+}
+
+// Subscribe starts receiving the updates of the location identified by key.
+// The first subscriber of a key starts polling it with fetch; later ones get
+// the last update right away and share the poller
+func (h *Hub) Subscribe(key string, fetch FetchFunc) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrClosed
+	}
+
+	p, ok := h.pollers[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		p = &poller{
+			key:         key,
+			cancel:      cancel,
+			subscribers: make(map[*Subscription]struct{}),
+		}
+		h.pollers[key] = p
+		go h.poll(ctx, p, fetch)
+	}
+
+	// One pending update is enough: a slow subscriber skips to the latest
+	ch := make(chan Update, 1)
+	sub := &Subscription{C: ch, ch: ch, hub: h, poller: p}
+	p.subscribers[sub] = struct{}{}
+	if p.last != nil {
+		ch <- *p.last
+	}
+
+	return sub, nil
+}
+
+// Subscribers is the number of subscribers of key
+func (h *Hub) Subscribers(key string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p, ok := h.pollers[key]; ok {
+		return len(p.subscribers)
+	}
+	return 0
+}
+
+// Close stops every poller and closes the channel of every subscription
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for key, p := range h.pollers {
+		p.cancel()
+		for sub := range p.subscribers {
+			close(sub.ch)
+			delete(p.subscribers, sub)
+		}
+		delete(h.pollers, key)
+	}
+}
+
+// Close leaves the location, stopping its poller if nobody else follows it
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		h := s.hub
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		p := s.poller
+		if _, ok := p.subscribers[s]; !ok {
+			// Already dropped by Hub.Close
+			return
+		}
+
+		delete(p.subscribers, s)
+		close(s.ch)
+
+		if len(p.subscribers) == 0 {
+			p.cancel()
+			delete(h.pollers, p.key)
+		}
+	})
+}
+
+func (h *Hub) poll(ctx context.Context, p *poller, fetch FetchFunc) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		observation, err := fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		h.publish(p, Update{Observation: observation, Err: err})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish sends update to the subscribers of p unless it repeats the last
+// one: the same observation, or a failure right after another failure
+func (h *Hub) publish(p *poller, update Update) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p.last != nil && !changed(*p.last, update) {
+		return
+	}
+	p.last = &update
+
+	for sub := range p.subscribers {
+		select {
+		case sub.ch <- update:
+		default:
+			// Replace the update the subscriber has not read yet
+			select {
+			case <-sub.ch:
+			default:
+			}
+			sub.ch <- update
+		}
+	}
+}
+
+// changed compares the observation time and the measurements shown to
+// subscribers
+func changed(last, update Update) bool {
+	if last.Err != nil || update.Err != nil {
+		return (last.Err == nil) != (update.Err == nil)
+	}
+
+	a, b := last.Observation, update.Observation
+	return !a.ObservedAt.Equal(b.ObservedAt) ||
+		a.TemperatureC != b.TemperatureC ||
+		a.HumidityPct != b.HumidityPct
+}
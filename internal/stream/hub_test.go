@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scripted answers each fetch with the next reading, repeating the last one
+type scripted struct {
+	mu       sync.Mutex
+	calls    int
+	readings []Update
+}
+
+func (s *scripted) fetch(context.Context) (*model.Observation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reading := s.readings[min(s.calls, len(s.readings)-1)]
+	s.calls++
+	return reading.Observation, reading.Err
+}
+
+func (s *scripted) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func observation(tempC float64) Update {
+	return Update{Observation: &model.Observation{
+		ObservedAt:   time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC),
+		TemperatureC: tempC,
+	}}
+}
+
+func receive(t *testing.T, sub *Subscription) Update {
+	t.Helper()
+	select {
+	case update, ok := <-sub.C:
+		require.True(t, ok, "subscription closed")
+		return update
+	case <-time.After(time.Second):
+		require.FailNow(t, "no update received")
+		return Update{}
+	}
+}
+
+func TestHub_FansOutOnePoller(t *testing.T) {
+	// arrange
+	source := &scripted{readings: []Update{observation(25)}}
+	hub := NewHub(10 * time.Millisecond)
+	defer hub.Close()
+
+	// act
+	first, err := hub.Subscribe("SP/são paulo", source.fetch)
+	require.NoError(t, err)
+	firstUpdate := receive(t, first)
+
+	second, err := hub.Subscribe("SP/são paulo", source.fetch)
+	require.NoError(t, err)
+	secondUpdate := receive(t, second)
+
+	// assert
+	assert.Equal(t, 25.0, firstUpdate.Observation.TemperatureC)
+	assert.Equal(t, 25.0, secondUpdate.Observation.TemperatureC, "a late subscriber gets the last update")
+	assert.Equal(t, 2, hub.Subscribers("SP/são paulo"))
+
+	time.Sleep(50 * time.Millisecond)
+	calls := source.Calls()
+	assert.Less(t, calls, 10, "one poller for both subscribers")
+	assert.Empty(t, first.C, "an unchanged observation is not pushed again")
+}
+
+func TestHub_PushesOnlyChanges(t *testing.T) {
+	// arrange
+	failure := errors.New("upstream down")
+	source := &scripted{readings: []Update{
+		observation(25), observation(25), observation(26),
+		{Err: failure}, {Err: failure}, observation(26),
+	}}
+	hub := NewHub(time.Millisecond)
+	defer hub.Close()
+
+	// act
+	sub, err := hub.Subscribe("RJ/rio de janeiro", source.fetch)
+	require.NoError(t, err)
+
+	var updates []Update
+	for range 4 {
+		updates = append(updates, receive(t, sub))
+	}
+
+	// assert
+	assert.Equal(t, 25.0, updates[0].Observation.TemperatureC)
+	assert.Equal(t, 26.0, updates[1].Observation.TemperatureC)
+	assert.ErrorIs(t, updates[2].Err, failure)
+	assert.Equal(t, 26.0, updates[3].Observation.TemperatureC, "recovery is pushed even when the value is the same")
+}
+
+func TestHub_StopsPollingAfterLastSubscriber(t *testing.T) {
+	// arrange
+	source := &scripted{readings: []Update{observation(25)}}
+	hub := NewHub(time.Millisecond)
+	defer hub.Close()
+
+	first, err := hub.Subscribe("SP/santos", source.fetch)
+	require.NoError(t, err)
+	second, err := hub.Subscribe("SP/santos", source.fetch)
+	require.NoError(t, err)
+	receive(t, first)
+
+	// act
+	first.Close()
+	first.Close()
+	remaining := hub.Subscribers("SP/santos")
+	second.Close()
+
+	// assert
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, 0, hub.Subscribers("SP/santos"))
+
+	time.Sleep(10 * time.Millisecond)
+	calls := source.Calls()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, calls, source.Calls(), "the poller stopped")
+}
+
+func TestHub_Close(t *testing.T) {
+	// arrange
+	source := &scripted{readings: []Update{observation(25)}}
+	hub := NewHub(time.Millisecond)
+	sub, err := hub.Subscribe("SP/campinas", source.fetch)
+	require.NoError(t, err)
+
+	// act
+	hub.Close()
+	_, errAfterClose := hub.Subscribe("SP/campinas", source.fetch)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		_, ok := <-sub.C
+		return !ok
+	}, time.Second, time.Millisecond)
+	assert.ErrorIs(t, errAfterClose, ErrClosed)
+	assert.NotPanics(t, sub.Close)
+}
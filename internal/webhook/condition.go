@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Subscription conditions: the temperature rising above or falling below the
+// threshold, or moving by at least the threshold since the last notification
+const (
+	ConditionAbove = "above"
+	ConditionBelow = "below"
+	ConditionDelta = "delta"
+)
+
+var (
+	ErrNotFound         = errors.New("subscription not found")
+	ErrConditionInvalid = errors.New("condition must be above, below or delta, with a finite threshold (positive for delta)")
+	ErrURLInvalid       = errors.New("url must be an absolute http or https URL to a public host")
+	ErrLimitReached     = errors.New("subscription limit reached")
+)
+
+// validateCondition checks the condition and threshold of a subscription
+func validateCondition(condition string, threshold float64) error {
+	if math.IsNaN(threshold) || math.IsInf(threshold, 0) {
+		return ErrConditionInvalid
+	}
+
+	switch condition {
+	case ConditionAbove, ConditionBelow:
+		return nil
+	case ConditionDelta:
+		if threshold <= 0 {
+			return ErrConditionInvalid
+		}
+		return nil
+	}
+	return ErrConditionInvalid
+}
+
+// validateURL checks that raw is an absolute http(s) URL. Unless
+// allowPrivate, hosts that are obviously local are refused up front; the
+// sender checks the resolved address again on every delivery
+func validateURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrURLInvalid
+	}
+
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrURLInvalid
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return ErrURLInvalid
+	}
+	return nil
+}
+
+// nonPublicNetworks are the ranges publicIP rejects besides those the net
+// package classifies: "this network" and the carrier-grade NAT space
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// publicIP is false for loopback, private, link-local, multicast,
+// unspecified, "this network" and carrier-grade NAT addresses
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate records a new temperature on r and reports whether it triggers a
+// notification. above and below notify when the condition starts to hold,
+// including on the first check; delta compares with the temperature of the
+// last notification, or of the first check
+func evaluate(r *record, tempC float64) (string, bool) {
+	switch r.Condition {
+	case ConditionAbove, ConditionBelow:
+		met := tempC > r.Threshold
+		if r.Condition == ConditionBelow {
+			met = tempC < r.Threshold
+		}
+
+		fire := met && !r.ConditionMet
+		r.ConditionMet = met
+		return "temperature." + r.Condition, fire
+
+	case ConditionDelta:
+		fire := r.ReferenceC != nil && math.Abs(tempC-*r.ReferenceC) >= r.Threshold
+		if r.ReferenceC == nil || fire {
+			r.ReferenceC = &tempC
+		}
+		return "temperature.delta", fire
+	}
+
+	return "", false
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		threshold float64
+		readings  []float64
+		fired     []bool
+	}{
+		{"above fires when crossing", ConditionAbove, 8, []float64{6, 7.9, 8.5, 9, 7, 8.1}, []bool{false, false, true, false, false, true}},
+		{"above fires on the first check", ConditionAbove, 8, []float64{10, 11}, []bool{true, false}},
+		{"at the threshold is not above", ConditionAbove, 8, []float64{8}, []bool{false}},
+		{"below fires when crossing", ConditionBelow, 2, []float64{4, 1.5, 0, 3, 1}, []bool{false, true, false, false, true}},
+		{"delta compares with the last notification", ConditionDelta, 3, []float64{20, 21, 22.9, 23, 21, 20}, []bool{false, false, false, true, false, true}},
+		{"delta fires on drops", ConditionDelta, 5, []float64{30, 24.5}, []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			r := &record{Subscription: model.Subscription{Condition: tt.condition, Threshold: tt.threshold}}
+
+			// act
+			fired := make([]bool, 0, len(tt.readings))
+			for _, reading := range tt.readings {
+				event, fire := evaluate(r, reading)
+				assert.Equal(t, "temperature."+tt.condition, event)
+				fired = append(fired, fire)
+			}
+
+			// assert
+			assert.Equal(t, tt.fired, fired)
+		})
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		threshold float64
+		valid     bool
+	}{
+		{ConditionAbove, 8, true},
+		{ConditionBelow, -10, true},
+		{ConditionBelow, 0, true},
+		{ConditionDelta, 2.5, true},
+		{ConditionDelta, 0, false},
+		{ConditionDelta, -1, false},
+		{ConditionAbove, math.NaN(), false},
+		{ConditionAbove, math.Inf(1), false},
+		{"between", 8, false},
+		{"", 8, false},
+	}
+
+	for _, tt := range tests {
+		err := validateCondition(tt.condition, tt.threshold)
+		if tt.valid {
+			assert.NoError(t, err, "%s %v", tt.condition, tt.threshold)
+		} else {
+			assert.ErrorIs(t, err, ErrConditionInvalid, "%s %v", tt.condition, tt.threshold)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		valid        bool
+	}{
+		{"https://example.com/hooks", false, true},
+		{"http://203.0.113.10:8080/hook", false, true},
+		{"ftp://example.com/hooks", false, false},
+		{"/hooks", false, false},
+		{"https://", false, false},
+		{"http://localhost:8080/hook", false, false},
+		{"http://api.localhost/hook", false, false},
+		{"http://127.0.0.1/hook", false, false},
+		{"http://10.0.0.5/hook", false, false},
+		{"http://169.254.169.254/computeMetadata/v1", false, false},
+		{"http://[::1]/hook", false, false},
+		{"http://100.64.0.1/hook", false, false},
+		{"http://0.1.2.3/hook", false, false},
+		{"http://224.0.0.251/hook", false, false},
+		{"http://[ff0e::1]/hook", false, false},
+		{"http://127.0.0.1/hook", true, true},
+		{"http://localhost:8080/hook", true, true},
+	}
+
+	for _, tt := range tests {
+		err := validateURL(tt.url, tt.allowPrivate)
+		if tt.valid {
+			assert.NoError(t, err, tt.url)
+		} else {
+			assert.ErrorIs(t, err, ErrURLInvalid, tt.url)
+		}
+	}
+}
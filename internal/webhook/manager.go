@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Observer returns the current weather in a city of a state
+type Observer interface {
+	Observe(ctx context.Context, uf, city string) (*model.Observation, error)
+}
+
+// ObserverFunc is a function used as an Observer
+type ObserverFunc func(ctx context.Context, uf, city string) (*model.Observation, error)
+
+func (f ObserverFunc) Observe(ctx context.Context, uf, city string) (*model.Observation, error) {
+	return f(ctx, uf, city)
+}
+
+// Manager keeps the subscriptions, checks their conditions every interval
+// and delivers the webhooks they trigger
+type Manager struct {
+	store        *Store
+	sender       *Sender
+	observer     Observer
+	interval     time.Duration
+	maxPerOwner  int
+	allowPrivate bool
+	now          func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewManager(cfg *config.Config, store *Store, observer Observer) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{
+		store:        store,
+		sender:       NewSender(cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookRetryBackoff, cfg.WebhookAllowPrivateTargets),
+		observer:     observer,
+		interval:     cfg.WebhookEvalInterval,
+		maxPerOwner:  cfg.WebhookMaxPerOwner,
+		allowPrivate: cfg.WebhookAllowPrivateTargets,
+		now:          time.Now,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Create validates and stores a subscription of owner, returning it with
+// its ID and the secret that signs its deliveries. Each owner has at most
+// WEBHOOK_MAX_PER_OWNER subscriptions
+func (m *Manager) Create(owner string, sub model.Subscription) (model.Subscription, error) {
+	if err := validateCondition(sub.Condition, sub.Threshold); err != nil {
+		return model.Subscription{}, err
+	}
+	if err := validateURL(sub.URL, m.allowPrivate); err != nil {
+		return model.Subscription{}, err
+	}
+
+	sub.ID = randomID(8)
+	sub.Secret = "whsec_" + randomID(16)
+	sub.CreatedAt = m.now().UTC()
+	sub.LastTempC, sub.LastCheckedAt, sub.LastNotifiedAt = nil, nil, nil
+
+	if err := m.store.Add(record{Subscription: sub, Owner: owner}, m.maxPerOwner); err != nil {
+		return model.Subscription{}, err
+	}
+	return sub, nil
+}
+
+// List returns the subscriptions of owner without their secrets
+func (m *Manager) List(owner string) ([]model.Subscription, error) {
+	records, err := m.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]model.Subscription, 0, len(records))
+	for _, r := range records {
+		if r.Owner == owner {
+			subs = append(subs, r.public())
+		}
+	}
+	return subs, nil
+}
+
+// Get returns a subscription of owner without its secret. Subscriptions of
+// other owners are not found
+func (m *Manager) Get(owner, id string) (model.Subscription, error) {
+	r, err := m.store.Get(id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if r.Owner != owner {
+		return model.Subscription{}, ErrNotFound
+	}
+	return r.public(), nil
+}
+
+func (m *Manager) Delete(owner, id string) error {
+	return m.store.Delete(owner, id)
+}
+
+// DeadLetters returns the deliveries of owner given up after their last
+// attempt
+func (m *Manager) DeadLetters(owner string) ([]model.DeadLetter, error) {
+	return m.store.DeadLetters(owner)
+}
+
+// Start checks the subscriptions now and then every interval, until Close
+func (m *Manager) Start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.check(m.ctx)
+
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops checking, waits for the deliveries in flight, dead-lettering
+// the ones interrupted, and closes the store
+func (m *Manager) Close() error {
+	m.cancel()
+	m.wg.Wait()
+	return m.store.Close()
+}
+
+// check observes each city with subscriptions once and notifies the
+// subscriptions whose condition triggers
+func (m *Manager) check(ctx context.Context) {
+	records, err := m.store.List()
+	if err != nil {
+		slog.Error("Failed to list webhook subscriptions", "error", err)
+		return
+	}
+
+	cities := make(map[string][]record)
+	for _, r := range records {
+		key := r.UF + "/" + strings.ToLower(r.City)
+		cities[key] = append(cities[key], r)
+	}
+
+	for _, subs := range cities {
+		if ctx.Err() != nil {
+			return
+		}
+
+		observation, err := m.observer.Observe(ctx, subs[0].UF, subs[0].City)
+		if err != nil {
+			slog.Error("Failed to check webhook subscriptions", "city", subs[0].City, "uf", subs[0].UF, "error", err)
+			continue
+		}
+
+		for _, r := range subs {
+			m.apply(r.ID, observation)
+		}
+	}
+}
+
+// apply records an observation on a subscription and delivers its webhook
+// when the condition triggers
+func (m *Manager) apply(id string, observation *model.Observation) {
+	var (
+		target  record
+		payload model.WebhookPayload
+		fire    bool
+	)
+
+	now := m.now().UTC()
+	err := m.store.Update(id, func(r *record) {
+		previous := r.LastTempC
+		tempC := observation.TemperatureC
+
+		var event string
+		event, fire = evaluate(r, tempC)
+		r.LastTempC = &tempC
+		r.LastCheckedAt = &now
+		if !fire {
+			return
+		}
+
+		r.LastNotifiedAt = &now
+		target = *r
+		payload = model.WebhookPayload{
+			ID:             randomID(8),
+			Event:          event,
+			SubscriptionID: r.ID,
+			Cep:            r.Cep,
+			City:           r.City,
+			UF:             r.UF,
+			Condition:      r.Condition,
+			Threshold:      r.Threshold,
+			TemperatureC:   tempC,
+			PreviousTempC:  previous,
+			ObservedAt:     observation.ObservedAt,
+		}
+	})
+	if errors.Is(err, ErrNotFound) {
+		// Deleted while being checked
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to update webhook subscription", "id", id, "error", err)
+		return
+	}
+
+	if fire {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.deliver(target, payload)
+		}()
+	}
+}
+
+// deliver sends a payload, keeping it as a dead letter when every attempt fails
+func (m *Manager) deliver(r record, payload model.WebhookPayload) {
+	attempts, err := m.sender.Send(m.ctx, r.URL, r.Secret, payload)
+	if err == nil {
+		slog.Info("Webhook delivered", "subscription", r.ID, "event", payload.Event, "attempts", attempts)
+		return
+	}
+
+	slog.Error("Webhook delivery failed", "subscription", r.ID, "event", payload.Event, "attempts", attempts, "error", err)
+	letter := model.DeadLetter{
+		Payload:   payload,
+		URL:       r.URL,
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  m.now().UTC(),
+	}
+	if err := m.store.AddDeadLetter(r.Owner, letter); err != nil {
+		slog.Error("Failed to store webhook dead letter", "subscription", r.ID, "error", err)
+	}
+}
+
+// public is the subscription as the API shows it, without the secret
+func (r record) public() model.Subscription {
+	sub := r.Subscription
+	sub.Secret = ""
+	return sub
+}
+
+// randomID returns n random bytes in hex
+func randomID(n int) string {
+	b := make([]byte, n)
+	// crypto/rand.Read does not fail on supported platforms
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,398 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/config"
+	"github.com/alexduzi/labcloudrun/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeObserver reports a fixed temperature per city
+type fakeObserver struct {
+	mu    sync.Mutex
+	temps map[string]float64
+	calls map[string]int
+}
+
+func (o *fakeObserver) Observe(_ context.Context, _, city string) (*model.Observation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.calls[city]++
+	temp, ok := o.temps[city]
+	if !ok {
+		return nil, errors.New("unknown city")
+	}
+	return &model.Observation{
+		ObservedAt:   time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC),
+		TemperatureC: temp,
+	}, nil
+}
+
+func (o *fakeObserver) set(city string, temp float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.temps[city] = temp
+}
+
+// receiver records the deliveries and answers each with the next status
+type receiver struct {
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []*http.Request
+	bodies     [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.deliveries = append(r.deliveries, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(status)
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.deliveries)
+}
+
+// testOwner is the API key name the test subscriptions belong to
+const testOwner = "partner"
+
+type ManagerTestSuite struct {
+	suite.Suite
+	path     string
+	cfg      *config.Config
+	observer *fakeObserver
+	receiver *receiver
+	server   *httptest.Server
+	manager  *Manager
+}
+
+func (s *ManagerTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "webhooks.db")
+	s.cfg = &config.Config{
+		WebhookEvalInterval:        time.Hour,
+		WebhookTimeout:             time.Second,
+		WebhookMaxAttempts:         3,
+		WebhookRetryBackoff:        time.Millisecond,
+		WebhookMaxPerOwner:         3,
+		WebhookAllowPrivateTargets: true,
+	}
+	s.observer = &fakeObserver{temps: map[string]float64{}, calls: map[string]int{}}
+	s.receiver = &receiver{}
+	s.server = httptest.NewServer(s.receiver)
+	s.manager = s.open()
+}
+
+func (s *ManagerTestSuite) TearDownTest() {
+	s.server.Close()
+	_ = s.manager.Close()
+}
+
+func (s *ManagerTestSuite) open() *Manager {
+	store, err := OpenStore(s.path)
+	require.NoError(s.T(), err)
+	return NewManager(s.cfg, store, s.observer)
+}
+
+// round checks the subscriptions once and waits for the deliveries
+func (s *ManagerTestSuite) round() {
+	s.manager.check(context.Background())
+	s.manager.wg.Wait()
+}
+
+func (s *ManagerTestSuite) subscribe(city, condition string, threshold float64) model.Subscription {
+	sub, err := s.manager.Create(testOwner, model.Subscription{
+		Cep:       "01001-000",
+		City:      city,
+		UF:        "SP",
+		Condition: condition,
+		Threshold: threshold,
+		URL:       s.server.URL + "/hooks",
+	})
+	require.NoError(s.T(), err)
+	return sub
+}
+
+func (s *ManagerTestSuite) TestCreate() {
+	// act
+	sub := s.subscribe("São Paulo", ConditionAbove, 8)
+	listed, err := s.manager.List(testOwner)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), sub.ID, 16)
+	assert.Regexp(s.T(), `^whsec_[0-9a-f]{32}$`, sub.Secret)
+	require.Len(s.T(), listed, 1)
+	assert.Equal(s.T(), sub.ID, listed[0].ID)
+	assert.Empty(s.T(), listed[0].Secret, "secrets are only returned on creation")
+}
+
+func (s *ManagerTestSuite) TestCreate_Invalid() {
+	// act
+	_, conditionErr := s.manager.Create(testOwner, model.Subscription{Condition: "between", URL: "https://example.com"})
+	_, urlErr := s.manager.Create(testOwner, model.Subscription{Condition: ConditionAbove, URL: "example.com/hooks"})
+
+	// assert
+	assert.ErrorIs(s.T(), conditionErr, ErrConditionInvalid)
+	assert.ErrorIs(s.T(), urlErr, ErrURLInvalid)
+}
+
+func (s *ManagerTestSuite) TestCreate_LimitPerOwner() {
+	// arrange
+	s.subscribe("São Paulo", ConditionAbove, 8)
+	s.subscribe("Campinas", ConditionAbove, 8)
+	s.subscribe("Santos", ConditionAbove, 8)
+	sub := model.Subscription{Condition: ConditionAbove, URL: s.server.URL + "/hooks"}
+
+	// act
+	_, limitErr := s.manager.Create(testOwner, sub)
+	_, otherErr := s.manager.Create("other", sub)
+
+	// assert
+	assert.ErrorIs(s.T(), limitErr, ErrLimitReached)
+	assert.NoError(s.T(), otherErr, "the limit is per owner")
+}
+
+func (s *ManagerTestSuite) TestCheck_DeliversSignedPayload() {
+	// arrange
+	sub := s.subscribe("São Paulo", ConditionAbove, 8)
+	s.observer.set("São Paulo", 7.5)
+	s.round()
+
+	// act
+	s.observer.set("São Paulo", 8.4)
+	s.round()
+	s.round()
+
+	// assert
+	require.Equal(s.T(), 1, s.receiver.count(), "only the crossing is notified")
+
+	req, body := s.receiver.deliveries[0], s.receiver.bodies[0]
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), Sign(sub.Secret, timestamp, body), req.Header.Get(HeaderSignature))
+	assert.Equal(s.T(), "application/json", req.Header.Get("Content-Type"))
+
+	var payload model.WebhookPayload
+	require.NoError(s.T(), json.Unmarshal(body, &payload))
+	assert.Equal(s.T(), req.Header.Get(HeaderID), payload.ID)
+	assert.Equal(s.T(), "temperature.above", payload.Event)
+	assert.Equal(s.T(), sub.ID, payload.SubscriptionID)
+	assert.Equal(s.T(), 8.4, payload.TemperatureC)
+	assert.Equal(s.T(), 7.5, *payload.PreviousTempC)
+
+	stored, err := s.manager.Get(testOwner, sub.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 8.4, *stored.LastTempC)
+	assert.NotNil(s.T(), stored.LastNotifiedAt)
+}
+
+func (s *ManagerTestSuite) TestCheck_ObservesEachCityOnce() {
+	// arrange
+	s.subscribe("São Paulo", ConditionAbove, 30)
+	s.subscribe("São Paulo", ConditionBelow, 5)
+	s.subscribe("Campinas", ConditionDelta, 2)
+	s.observer.set("São Paulo", 20)
+	s.observer.set("Campinas", 20)
+
+	// act
+	s.round()
+
+	// assert
+	assert.Equal(s.T(), map[string]int{"São Paulo": 1, "Campinas": 1}, s.observer.calls)
+	assert.Equal(s.T(), 0, s.receiver.count())
+}
+
+func (s *ManagerTestSuite) TestCheck_RetriesThenDeadLetters() {
+	// arrange
+	s.receiver.statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway}
+	sub := s.subscribe("São Paulo", ConditionBelow, 2)
+	s.observer.set("São Paulo", 1)
+
+	// act
+	s.round()
+	letters, err := s.manager.DeadLetters(testOwner)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, s.receiver.count())
+	require.Len(s.T(), letters, 1)
+	assert.Equal(s.T(), sub.ID, letters[0].Payload.SubscriptionID)
+	assert.Equal(s.T(), 3, letters[0].Attempts)
+	assert.Equal(s.T(), "status 502", letters[0].LastError)
+}
+
+func (s *ManagerTestSuite) TestCheck_RetriesUntilDelivered() {
+	// arrange
+	s.receiver.statuses = []int{http.StatusInternalServerError}
+	s.subscribe("São Paulo", ConditionBelow, 2)
+	s.observer.set("São Paulo", 1)
+
+	// act
+	s.round()
+	letters, err := s.manager.DeadLetters(testOwner)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, s.receiver.count())
+	assert.Empty(s.T(), letters)
+}
+
+func (s *ManagerTestSuite) TestCheck_ClientErrorsAreNotRetried() {
+	// arrange
+	s.receiver.statuses = []int{http.StatusGone}
+	s.subscribe("São Paulo", ConditionBelow, 2)
+	s.observer.set("São Paulo", 1)
+
+	// act
+	s.round()
+	letters, err := s.manager.DeadLetters(testOwner)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, s.receiver.count())
+	require.Len(s.T(), letters, 1)
+	assert.Equal(s.T(), 1, letters[0].Attempts)
+}
+
+func (s *ManagerTestSuite) TestCheck_RefusesPrivateTargets() {
+	// arrange
+	s.manager.sender = NewSender(time.Second, 3, time.Millisecond, false)
+	require.NoError(s.T(), s.manager.store.Put(record{Subscription: model.Subscription{
+		ID: "internal", City: "São Paulo", UF: "SP", Condition: ConditionBelow, Threshold: 2, URL: s.server.URL,
+	}, Owner: testOwner}))
+	s.observer.set("São Paulo", 1)
+
+	// act
+	s.round()
+	letters, err := s.manager.DeadLetters(testOwner)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, s.receiver.count())
+	require.Len(s.T(), letters, 1)
+	assert.Equal(s.T(), 1, letters[0].Attempts)
+	assert.Contains(s.T(), letters[0].LastError, errTargetPrivate.Error())
+}
+
+func (s *ManagerTestSuite) TestDelete() {
+	// arrange
+	sub := s.subscribe("São Paulo", ConditionAbove, 8)
+
+	// act
+	err := s.manager.Delete(testOwner, sub.ID)
+	_, getErr := s.manager.Get(testOwner, sub.ID)
+	deleteAgainErr := s.manager.Delete(testOwner, sub.ID)
+
+	// assert
+	assert.NoError(s.T(), err)
+	assert.ErrorIs(s.T(), getErr, ErrNotFound)
+	assert.ErrorIs(s.T(), deleteAgainErr, ErrNotFound)
+}
+
+func (s *ManagerTestSuite) TestOwnersSeeOnlyTheirSubscriptions() {
+	// arrange
+	s.receiver.statuses = []int{http.StatusGone}
+	sub := s.subscribe("São Paulo", ConditionBelow, 2)
+	s.observer.set("São Paulo", 1)
+	s.round()
+
+	// act
+	listed, listErr := s.manager.List("other")
+	_, getErr := s.manager.Get("other", sub.ID)
+	deleteErr := s.manager.Delete("other", sub.ID)
+	letters, lettersErr := s.manager.DeadLetters("other")
+	ownLetters, _ := s.manager.DeadLetters(testOwner)
+	_, ownErr := s.manager.Get(testOwner, sub.ID)
+
+	// assert
+	require.NoError(s.T(), listErr)
+	require.NoError(s.T(), lettersErr)
+	assert.Empty(s.T(), listed)
+	assert.ErrorIs(s.T(), getErr, ErrNotFound)
+	assert.ErrorIs(s.T(), deleteErr, ErrNotFound)
+	assert.Empty(s.T(), letters)
+	assert.Len(s.T(), ownLetters, 1)
+	assert.NoError(s.T(), ownErr, "the subscription survives the other owner's delete")
+}
+
+func (s *ManagerTestSuite) TestDeadLettersAreCappedPerOwner() {
+	// arrange
+	failedAt := time.Date(2026, 1, 10, 17, 30, 0, 0, time.UTC)
+	require.NoError(s.T(), s.manager.store.AddDeadLetter("other", model.DeadLetter{FailedAt: failedAt}))
+
+	// act
+	for i := range maxDeadLetters + 1 {
+		letter := model.DeadLetter{FailedAt: failedAt.Add(time.Duration(i+1) * time.Second)}
+		letter.Payload.ID = strconv.Itoa(i)
+		require.NoError(s.T(), s.manager.store.AddDeadLetter(testOwner, letter))
+	}
+	letters, err := s.manager.DeadLetters(testOwner)
+	otherLetters, otherErr := s.manager.DeadLetters("other")
+
+	// assert
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), otherErr)
+	require.Len(s.T(), letters, maxDeadLetters)
+	assert.Equal(s.T(), "1", letters[0].Payload.ID, "the oldest is dropped")
+	assert.Len(s.T(), otherLetters, 1, "another owner's letters are kept")
+}
+
+func (s *ManagerTestSuite) TestPersistsAcrossRestarts() {
+	// arrange
+	sub := s.subscribe("São Paulo", ConditionAbove, 8)
+	s.observer.set("São Paulo", 9)
+	s.round()
+	require.NoError(s.T(), s.manager.Close())
+
+	// act
+	s.manager = s.open()
+	s.round()
+	restored, err := s.manager.Get(testOwner, sub.ID)
+
+	// assert
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), sub.URL, restored.URL)
+	assert.Equal(s.T(), 1, s.receiver.count(), "the condition state survives the restart")
+}
+
+func (s *ManagerTestSuite) TestStartAndClose() {
+	// arrange
+	s.subscribe("São Paulo", ConditionAbove, 8)
+	s.observer.set("São Paulo", 9)
+
+	// act
+	s.manager.Start()
+
+	// assert
+	assert.Eventually(s.T(), func() bool {
+		return s.receiver.count() == 1
+	}, time.Second, 5*time.Millisecond, "the first check runs on start")
+}
+
+func TestManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(ManagerTestSuite))
+}
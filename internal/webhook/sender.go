@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+)
+
+// Headers of a webhook delivery. The signature is the hex HMAC-SHA256, keyed
+// with the subscription secret, of the timestamp, a dot and the body
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// errTargetPrivate is returned when a webhook host resolves to a local address
+var errTargetPrivate = errors.New("webhook target resolves to a non-public address")
+
+// Sign returns the X-Webhook-Signature of a delivery
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sender posts signed payloads, retrying with exponential backoff
+type Sender struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+// NewSender gives each attempt timeout. Unless allowPrivate, connections to
+// loopback, private and link-local addresses are refused after resolving the
+// host, so a public name pointing inside the network is caught too
+func NewSender(timeout time.Duration, maxAttempts int, backoff time.Duration, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errTargetPrivate
+			}
+			return nil
+		}
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			// No proxy, so the address check applies to the webhook host itself
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		now:         time.Now,
+	}
+}
+
+// Send delivers payload to url, trying up to maxAttempts times. Network
+// errors, 408, 429 and 5xx are retried; other non-2xx answers are final. It
+// returns the number of attempts made
+func (s *Sender) Send(ctx context.Context, url, secret string, payload model.WebhookPayload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := s.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := s.post(ctx, url, secret, payload.ID, body)
+		if err == nil {
+			return attempt, nil
+		}
+		if !retry || attempt >= s.maxAttempts {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("%w (after %v)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post makes one attempt, reporting whether a failure is worth retrying
+func (s *Sender) post(ctx context.Context, url, secret, id string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lab-cloudrun-webhooks/1.0")
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return !errors.Is(err, errTargetPrivate), err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
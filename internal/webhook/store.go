@@ -0,0 +1,265 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/alexduzi/labcloudrun/internal/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxDeadLetters is how many dead letters are kept per owner; older ones are
+// dropped
+const maxDeadLetters = 1000
+
+var (
+	subscriptionsBucket = []byte("subscriptions")
+	deadLettersBucket   = []byte("dead_letters")
+)
+
+// record is a subscription as persisted, with the state its condition needs
+type record struct {
+	model.Subscription
+
+	// Owner is the API key, or token subject, that created the subscription;
+	// only it sees the subscription and its dead letters. Empty without keys
+	Owner string `json:"owner,omitempty"`
+
+	// ConditionMet is whether an above/below condition held at the last check
+	ConditionMet bool `json:"condition_met,omitempty"`
+	// ReferenceC is the temperature a delta condition compares with
+	ReferenceC *float64 `json:"reference_C,omitempty"`
+}
+
+// deadLetter is a dead letter as persisted, with the owner of its
+// subscription
+type deadLetter struct {
+	model.DeadLetter
+	Owner string `json:"owner,omitempty"`
+}
+
+// Store persists subscriptions and dead letters in a bbolt file
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens, or creates, the store at path. It fails if another
+// process holds the file
+func OpenStore(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open webhook store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{subscriptionsBucket, deadLettersBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add creates a subscription, failing with ErrLimitReached when its owner
+// already has limit subscriptions
+func (s *Store) Add(r record, limit int) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(subscriptionsBucket)
+
+		owned := 0
+		err := bucket.ForEach(func(_, data []byte) error {
+			var other record
+			if err := json.Unmarshal(data, &other); err != nil {
+				return err
+			}
+			if other.Owner == r.Owner {
+				owned++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if owned >= limit {
+			return ErrLimitReached
+		}
+
+		return bucket.Put([]byte(r.ID), data)
+	})
+}
+
+// Put creates or replaces a subscription
+func (s *Store) Put(r record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).Put([]byte(r.ID), data)
+	})
+}
+
+func (s *Store) Get(id string) (record, error) {
+	var r record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(subscriptionsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &r)
+	})
+	return r, err
+}
+
+// List returns the subscriptions, oldest first
+func (s *Store) List() ([]record, error) {
+	records := []record{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(subscriptionsBucket).ForEach(func(_, data []byte) error {
+			var r record
+			if err := json.Unmarshal(data, &r); err != nil {
+				return err
+			}
+			records = append(records, r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// Update applies fn to a subscription in a single transaction, so a check
+// does not bring back a subscription deleted meanwhile
+func (s *Store) Update(id string, fn func(*record)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(subscriptionsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		var r record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		fn(&r)
+
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), data)
+	})
+}
+
+// Delete removes the subscription id of owner. Subscriptions of other
+// owners are not found
+func (s *Store) Delete(owner, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(subscriptionsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		var r record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		if r.Owner != owner {
+			return ErrNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// AddDeadLetter keeps a failed delivery, dropping the oldest of owner past
+// maxDeadLetters, so one owner's failures do not push out the others'. Keys
+// start with the failure time, so they sort by it
+func (s *Store) AddDeadLetter(owner string, letter model.DeadLetter) error {
+	data, err := json.Marshal(deadLetter{DeadLetter: letter, Owner: owner})
+	if err != nil {
+		return err
+	}
+	key := []byte(letter.FailedAt.UTC().Format("20060102T150405.000000000Z") + "-" + letter.Payload.ID)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(deadLettersBucket)
+		if err := bucket.Put(key, data); err != nil {
+			return err
+		}
+
+		var keys [][]byte
+		cursor := bucket.Cursor()
+		for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+			var other deadLetter
+			if err := json.Unmarshal(data, &other); err != nil {
+				return err
+			}
+			if other.Owner == owner {
+				keys = append(keys, bytes.Clone(k))
+			}
+		}
+		for _, k := range keys[:max(len(keys)-maxDeadLetters, 0)] {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeadLetters returns the failed deliveries of owner, oldest first
+func (s *Store) DeadLetters(owner string) ([]model.DeadLetter, error) {
+	letters := []model.DeadLetter{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadLettersBucket).ForEach(func(_, data []byte) error {
+			var letter deadLetter
+			if err := json.Unmarshal(data, &letter); err != nil {
+				return err
+			}
+			if letter.Owner == owner {
+				letters = append(letters, letter.DeadLetter)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return letters, nil
+}